@@ -12,17 +12,23 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	n := node.CreateWorkerNode()
-	defer node.DeleteNode(n)
-
-	heartbeatSender := heartbeat.NewSender(n.UID)
-	heartbeatSender.Run(ctx, cancel)
+	n := node.LoadWorkerNode()
 
 	k, err := kubelet.New(n)
-	defer k.Close()
 	if err != nil {
 		log.Fatal(err.Error())
 	}
+	defer k.Close()
+
+	// static pods run before node is registered, as ApiServer may be one of them
+	k.StartStaticPods(ctx)
+
+	node.RegisterNode(n)
+	defer node.DeleteNode(n)
+
+	heartbeatSender := heartbeat.NewSender(n.UID)
+	heartbeatSender.Run(ctx, cancel)
+
 	k.Run()
 
 	<-ctx.Done()
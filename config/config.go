@@ -37,6 +37,17 @@ const (
 	EtcdPort = ":2379"
 )
 
+// DefaultEncryptionProviderConfig is the default file configuring encryption of
+// resources in etcd, resources are stored as plain text if it does not exist
+const DefaultEncryptionProviderConfig = "/etc/minik8s/encryption-config.yaml"
+
+func EncryptionProviderConfig() string {
+	if path := os.Getenv("ENCRYPTION_PROVIDER_CONFIG"); path != "" {
+		return path
+	}
+	return DefaultEncryptionProviderConfig
+}
+
 /*--------------- Kubelet ---------------*/
 // cadvisor config
 const (
@@ -48,6 +59,131 @@ func CadvisorUrl(HostAddress string) string {
 	return HttpScheme + HostAddress + CadvisorPort
 }
 
+// Kubelet http server config, which serves container logs
+const KubeletPort = ":10250"
+
+func KubeletUrl(HostAddress string) string {
+	return HttpScheme + HostAddress + KubeletPort
+}
+
+// Container runtimes kubelet runs pods with, chosen by containerRuntime in spec
+// of node config, containerd is reached through its CRI plugin on the endpoint
+const (
+	DockerRuntime             = "docker"
+	ContainerdRuntime         = "containerd"
+	DefaultContainerdEndpoint = "unix:///run/containerd/containerd.sock"
+)
+
+// PodLogsDir is the directory CRI runtimes write container logs in
+const PodLogsDir = "/var/log/pods"
+
+// Cgroup drivers of node config, which name cgroups of pods under
+// CgroupRoot as paths of cgroupfs or as systemd slices
+const (
+	CgroupfsDriver = "cgroupfs"
+	SystemdDriver  = "systemd"
+	CgroupRoot     = "/sys/fs/cgroup"
+)
+
+// Resources reserved for system daemons, which are
+// excluded from allocatable resources of node
+const (
+	SystemReservedCPU              = "100m"
+	SystemReservedMemory           = "256M"
+	SystemReservedEphemeralStorage = "1024M"
+)
+
+// MaxPods is the number of pods that can run on a node
+const MaxPods = 110
+
+// Default values of container probe, used when the field is not set
+const (
+	ProbeDefaultTimeoutSeconds   = 1
+	ProbeDefaultPeriodSeconds    = 10
+	ProbeDefaultSuccessThreshold = 1
+	ProbeDefaultFailureThreshold = 3
+)
+
+// KubeletRootDir is the directory kubelet keeps pod volumes in
+const KubeletRootDir = "/var/lib/minik8s/kubelet"
+
+// Static pods are read from json and yaml files in StaticPodPath, unless staticPodPath is
+// set in spec of node config, and the directory is checked every StaticPodCheckPeriod
+const (
+	StaticPodPath        = "/etc/minik8s/manifests"
+	StaticPodCheckPeriod = time.Duration(20) * time.Second
+)
+
+// ApiServerRetryInterval is the interval node waits for ApiServer to be reachable
+// before it is registered
+const ApiServerRetryInterval = time.Duration(5) * time.Second
+
+// VolumeSetUpRetryInterval is the interval to retry setting up volumes of pod
+const VolumeSetUpRetryInterval = time.Duration(5) * time.Second
+
+// ContainerConfigRetryInterval is the interval to retry resolving environment
+// variables of containers from ConfigMaps and Secrets
+const ContainerConfigRetryInterval = time.Duration(5) * time.Second
+
+// PodStartRetryInterval is the interval to retry creating sandbox and containers of pod
+const PodStartRetryInterval = time.Duration(5) * time.Second
+
+// ConfigVolumeSyncPeriod is the period to refresh volumes projected from ConfigMaps and Secrets
+const ConfigVolumeSyncPeriod = time.Duration(10) * time.Second
+
+// Back-off of restarting failed containers, the delay doubles after each restart,
+// and is reset if container runs longer than CrashLoopBackOffResetPeriod
+const (
+	CrashLoopBackOffInitial     = time.Duration(10) * time.Second
+	CrashLoopBackOffMax         = time.Duration(5) * time.Minute
+	CrashLoopBackOffResetPeriod = time.Duration(10) * time.Minute
+)
+
+// Back-off of retrying failed image pulls, the delay doubles after each failure.
+// Containers are reported ErrImagePull for at most ImagePullErrorPeriod, or half of
+// the delay, then ImagePullBackOff, and pull progress is reported at most once in
+// ImagePullProgressInterval
+const (
+	ImagePullBackOffInitial   = time.Duration(10) * time.Second
+	ImagePullBackOffMax       = time.Duration(5) * time.Minute
+	ImagePullErrorPeriod      = time.Duration(10) * time.Second
+	ImagePullProgressInterval = time.Duration(5) * time.Second
+)
+
+// Image garbage collection, unused images are removed least recently used first
+// when disk usage of image filesystem reaches high threshold, until it is below low
+// threshold, images younger than ImageMinimumGCAge are kept
+const (
+	ImageGCPeriod               = time.Duration(5) * time.Minute
+	ImageGCHighThresholdPercent = 85
+	ImageGCLowThresholdPercent  = 80
+	ImageMinimumGCAge           = time.Duration(2) * time.Minute
+)
+
+// Node-pressure eviction, thresholds are checked every EvictionMonitoringPeriod, and node
+// reports pressure until thresholds are not met for EvictionPressureTransitionPeriod. Pods
+// evicted by soft thresholds have grace period of at most EvictionMaxPodGracePeriod.
+const (
+	EvictionMonitoringPeriod         = time.Duration(10) * time.Second
+	EvictionPressureTransitionPeriod = time.Duration(5) * time.Minute
+	EvictionMaxPodGracePeriod        = time.Duration(30) * time.Second
+)
+
+// DefaultEvictionHard are hard eviction thresholds used if evictionHard is not set in node config
+var DefaultEvictionHard = map[string]string{
+	"memory.available":  "100M",
+	"nodefs.available":  "10%",
+	"imagefs.available": "15%",
+}
+
+// Grace period of stopping containers, the stop signal is sent after preStop hook,
+// and containers are killed if they are still running after grace period
+const (
+	DefaultTerminationGracePeriod = time.Duration(30) * time.Second
+	MinimumGracePeriod            = time.Duration(2) * time.Second  // Containers always have this time to stop after signal
+	PostStartHookTimeout          = time.Duration(30) * time.Second // Container is killed if postStart hook does not finish in time
+)
+
 /*--------------- GPU ---------------*/
 // HPC config
 const (
@@ -68,6 +204,36 @@ const (
 	HeartbeatCheckInterval = time.Duration(15) * time.Second
 )
 
+/*--------------- Node Lifecycle ---------------*/
+const (
+	NodeStatusUpdateInterval = time.Duration(10) * time.Second // Interval kubelet computes node conditions
+	NodeStatusReportInterval = time.Duration(60) * time.Second // Interval kubelet reports node status even if unchanged
+	NodeMonitorPeriod        = HeartbeatCheckInterval          // Interval node lifecycle controller checks node health
+	NodeMonitorGracePeriod   = HeartbeatDeadInterval           // Node is Unknown if no heartbeat received in this period
+	PodEvictionTimeout       = time.Duration(5) * time.Minute  // Pods not tolerating NoExecute taints of node are evicted after it
+)
+
+/*--------------- Disruption ---------------*/
+const (
+	DisruptionResyncPeriod      = time.Duration(30) * time.Second // Interval disruption controller recomputes all budgets
+	DisruptedPodDeletionTimeout = time.Duration(2) * time.Minute  // Evicted pod still seen after it is counted as not disrupted
+)
+
+/*--------------- PersistentVolume ---------------*/
+const (
+	PersistentVolumeResyncPeriod = time.Duration(15) * time.Second // Interval binder controller syncs all volumes and claims
+	VolumeReclaimPeriod          = time.Duration(15) * time.Second // Interval kubelet deletes released local-path volumes on its node
+)
+
+// LocalPathProvisionerDir is the directory on node holding volumes of local-path provisioner
+const LocalPathProvisionerDir = "/var/lib/minik8s/local-path-provisioner"
+
+/*--------------- Event ---------------*/
+const (
+	EventTTL      = time.Duration(1) * time.Hour   // Events not updated in this period are deleted
+	EventGCPeriod = time.Duration(1) * time.Minute // Interval event gc controller deletes expired events
+)
+
 /*--------------- Serverless ---------------*/
 const (
 	FuncDefaultInitInstanceNum = 0  // Default instance number when func template is created
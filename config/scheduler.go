@@ -0,0 +1,11 @@
+package config
+
+import "os"
+
+/*--------------- Scheduler ---------------*/
+
+// SchedulerConfig returns path of scheduler profile config file,
+// default profile is used if it is empty
+func SchedulerConfig() string {
+	return os.Getenv("SCHEDULER_CONFIG")
+}
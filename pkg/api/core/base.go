@@ -76,6 +76,18 @@ func CreateApiObject(ty types.ApiObjectType) IApiObject {
 		return &Heartbeat{}
 	case types.DnsObjectType:
 		return &DNS{}
+	case types.PodDisruptionBudgetObjectType:
+		return &PodDisruptionBudget{}
+	case types.ConfigMapObjectType:
+		return &ConfigMap{}
+	case types.SecretObjectType:
+		return &Secret{}
+	case types.PersistentVolumeObjectType:
+		return &PersistentVolume{}
+	case types.PersistentVolumeClaimObjectType:
+		return &PersistentVolumeClaim{}
+	case types.EventObjectType:
+		return &Event{}
 	default:
 		panic(fmt.Sprintf("No ApiObjectType %v", ty))
 	}
@@ -101,6 +113,18 @@ func CreateApiObjectList(ty types.ApiObjectType) IApiObjectList {
 		return &FuncList{}
 	case types.DnsObjectType:
 		return &DnsList{}
+	case types.PodDisruptionBudgetObjectType:
+		return &PodDisruptionBudgetList{}
+	case types.ConfigMapObjectType:
+		return &ConfigMapList{}
+	case types.SecretObjectType:
+		return &SecretList{}
+	case types.PersistentVolumeObjectType:
+		return &PersistentVolumeList{}
+	case types.PersistentVolumeClaimObjectType:
+		return &PersistentVolumeClaimList{}
+	case types.EventObjectType:
+		return &EventList{}
 	default:
 		panic(fmt.Sprintf("No ApiObjectType %v", ty))
 	}
@@ -126,34 +150,54 @@ func CreateApiObjectStatus(ty types.ApiObjectType) IApiObjectStatus {
 		return &HeartbeatStatus{}
 	case types.DnsObjectType:
 		return &DnsStatus{}
+	case types.PodDisruptionBudgetObjectType:
+		return &PodDisruptionBudgetStatus{}
+	case types.ConfigMapObjectType:
+		return &ConfigMapStatus{}
+	case types.SecretObjectType:
+		return &SecretStatus{}
+	case types.PersistentVolumeObjectType:
+		return &PersistentVolumeStatus{}
+	case types.PersistentVolumeClaimObjectType:
+		return &PersistentVolumeClaimStatus{}
+	case types.EventObjectType:
+		return &EventStatus{}
 	default:
 		panic(fmt.Sprintf("No ApiObjectType %v", ty))
 	}
 }
 
+// apiObjectsURLs are urls of objects of each ApiObjectType
+var apiObjectsURLs = map[types.ApiObjectType]string{
+	types.PodObjectType:                     api.PodsURL,
+	types.ServiceObjectType:                 api.ServicesURL,
+	types.NodeObjectType:                    api.NodesURL,
+	types.ReplicasetObjectType:              api.ReplicaSetsURL,
+	types.HorizontalPodAutoscalerObjectType: api.HorizontalPodAutoscalersURL,
+	types.FuncTemplateObjectType:            api.FuncTemplatesURL,
+	types.JobObjectType:                     api.JobsURL,
+	types.HeartbeatObjectType:               api.HeartbeatsURL,
+	types.DnsObjectType:                     api.DNSsURL,
+	types.PodDisruptionBudgetObjectType:     api.PodDisruptionBudgetsURL,
+	types.ConfigMapObjectType:               api.ConfigMapsURL,
+	types.SecretObjectType:                  api.SecretsURL,
+	types.PersistentVolumeObjectType:        api.PersistentVolumesURL,
+	types.PersistentVolumeClaimObjectType:   api.PersistentVolumeClaimsURL,
+	types.EventObjectType:                   api.EventsURL,
+}
+
+// LookupApiObjectsURL returns url of objects of ty, and false if ty is unknown
+func LookupApiObjectsURL(ty types.ApiObjectType) (string, bool) {
+	url, ok := apiObjectsURLs[ty]
+	return url, ok
+}
+
 func GetApiObjectsURL(ty types.ApiObjectType) string {
-	switch ty {
-	case types.PodObjectType:
-		return api.PodsURL
-	case types.ServiceObjectType:
-		return api.ServicesURL
-	case types.NodeObjectType:
-		return api.NodesURL
-	case types.ReplicasetObjectType:
-		return api.ReplicaSetsURL
-	case types.HorizontalPodAutoscalerObjectType:
-		return api.HorizontalPodAutoscalersURL
-	case types.FuncTemplateObjectType:
-		return api.FuncTemplatesURL
-	case types.JobObjectType:
-		return api.JobsURL
-	case types.HeartbeatObjectType:
-		return api.HeartbeatsURL
-	case types.DnsObjectType:
-		return api.DNSsURL
-	default:
+	url, ok := LookupApiObjectsURL(ty)
+	if !ok {
 		panic(fmt.Sprintf("No ApiObjectType %v", ty))
 	}
+	return url
 }
 
 func GetWatchApiObjectsURL(ty types.ApiObjectType) string {
@@ -176,6 +220,18 @@ func GetWatchApiObjectsURL(ty types.ApiObjectType) string {
 		return api.WatchDNSsURL
 	case types.FuncTemplateObjectType:
 		return api.WatchFuncTemplatesURL
+	case types.PodDisruptionBudgetObjectType:
+		return api.WatchPodDisruptionBudgetsURL
+	case types.ConfigMapObjectType:
+		return api.WatchConfigMapsURL
+	case types.SecretObjectType:
+		return api.WatchSecretsURL
+	case types.PersistentVolumeObjectType:
+		return api.WatchPersistentVolumesURL
+	case types.PersistentVolumeClaimObjectType:
+		return api.WatchPersistentVolumeClaimsURL
+	case types.EventObjectType:
+		return api.WatchEventsURL
 	default:
 		panic(fmt.Sprintf("No ApiObjectType %v", ty))
 	}
@@ -0,0 +1,205 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"minik8s/pkg/api/meta"
+	"minik8s/pkg/api/types"
+	"strconv"
+)
+
+// ConfigMap holds configuration data for pods to consume.
+// It is stored and referenced by name.
+type ConfigMap struct {
+	meta.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	meta.ObjectMeta `json:"metadata,omitempty"`
+
+	// Data contains the configuration data.
+	// Each key must consist of alphanumeric characters, '-', '_' or '.'.
+	// Values with non-UTF-8 byte sequences must use the BinaryData field.
+	// The keys stored in Data must not overlap with the keys in
+	// the BinaryData field.
+	// +optional
+	Data map[string]string `json:"data,omitempty"`
+
+	// BinaryData contains the binary data.
+	// Each key must consist of alphanumeric characters, '-', '_' or '.'.
+	// BinaryData can contain byte sequences that are not in the UTF-8 range.
+	// +optional
+	BinaryData map[string][]byte `json:"binaryData,omitempty"`
+}
+
+// ConfigMapStatus is empty, ConfigMap has no status
+type ConfigMapStatus struct{}
+
+func (c *ConfigMapStatus) JsonUnmarshal(data []byte) error {
+	return json.Unmarshal(data, &c)
+}
+
+func (c *ConfigMapStatus) JsonMarshal() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (c *ConfigMap) PrintBrief() {
+	fmt.Printf("%-20s\t%-40s\t%-10s\n", "NAME", "UID", "DATA")
+	fmt.Printf("%-20s\t%-40s\t%-10d\n", c.Name, c.UID, len(c.Data)+len(c.BinaryData))
+}
+
+func (c *ConfigMap) SetUID(uid types.UID) {
+	c.ObjectMeta.UID = uid
+}
+
+func (c *ConfigMap) GetUID() types.UID {
+	return c.ObjectMeta.UID
+}
+
+func (c *ConfigMap) JsonUnmarshal(data []byte) error {
+	return json.Unmarshal(data, &c)
+}
+
+func (c *ConfigMap) JsonMarshal() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (c *ConfigMap) JsonUnmarshalStatus(data []byte) error {
+	return nil
+}
+
+func (c *ConfigMap) JsonMarshalStatus() ([]byte, error) {
+	return json.Marshal(ConfigMapStatus{})
+}
+
+func (c *ConfigMap) SetStatus(s IApiObjectStatus) bool {
+	_, ok := s.(*ConfigMapStatus)
+	return ok
+}
+
+func (c *ConfigMap) GetStatus() IApiObjectStatus {
+	return &ConfigMapStatus{}
+}
+
+func (c *ConfigMap) GetResourceVersion() string {
+	return c.ObjectMeta.ResourceVersion
+}
+
+func (c *ConfigMap) SetResourceVersion(version string) {
+	c.ObjectMeta.ResourceVersion = version
+}
+
+func (c *ConfigMap) CreateFromEtcdString(str string) error {
+	return c.JsonUnmarshal([]byte(str))
+}
+
+func (c *ConfigMap) GenerateOwnerReference() meta.OwnerReference {
+	return meta.OwnerReference{
+		APIVersion: c.APIVersion,
+		Kind:       c.Kind,
+		Name:       c.Name,
+		UID:        c.UID,
+		Controller: false,
+	}
+}
+
+func (c *ConfigMap) AppendOwnerReference(reference meta.OwnerReference) {
+	c.OwnerReferences = append(c.OwnerReferences, reference)
+}
+
+func (c *ConfigMap) DeleteOwnerReference(uid types.UID) {
+	has := false
+	idx := 0
+	for i, o := range c.OwnerReferences {
+		if o.UID == uid {
+			has = true
+			idx = i
+			break
+		}
+	}
+	if has {
+		c.OwnerReferences = append(c.OwnerReferences[:idx], c.OwnerReferences[idx+1:]...)
+	}
+}
+
+// ConfigMapList is a resource containing a list of ConfigMap objects.
+type ConfigMapList struct {
+	meta.TypeMeta `json:",inline"`
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	meta.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of ConfigMaps.
+	Items []ConfigMap `json:"items"`
+}
+
+func (c *ConfigMapList) PrintBrief() {
+	fmt.Printf("%-20s\t%-40s\t%-10s\n", "NAME", "UID", "DATA")
+	for _, item := range c.Items {
+		fmt.Printf("%-20s\t%-40s\t%-10d\n", item.Name, item.UID, len(item.Data)+len(item.BinaryData))
+	}
+}
+
+func (c *ConfigMapList) JsonUnmarshal(data []byte) error {
+	return json.Unmarshal(data, &c)
+}
+
+func (c *ConfigMapList) JsonMarshal() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (c *ConfigMapList) AddItemFromStr(objectStr string) error {
+	object := &ConfigMap{}
+	buf, err := strconv.Unquote(objectStr)
+	err = object.JsonUnmarshal([]byte(buf))
+	if err != nil {
+		return err
+	}
+	c.Items = append(c.Items, *object)
+	return nil
+}
+
+func (c *ConfigMapList) AppendItemsFromStr(objectStrs []string) error {
+	for _, obj := range objectStrs {
+		object := &ConfigMap{}
+		err := object.JsonUnmarshal([]byte(obj))
+		if err != nil {
+			return err
+		}
+		c.Items = append(c.Items, *object)
+	}
+	return nil
+}
+
+func (c *ConfigMapList) GetItems() any {
+	return c.Items
+}
+
+func (c *ConfigMapList) GetIApiObjectArr() (res []IApiObject) {
+	for _, item := range c.Items {
+		itemTemp := item
+		res = append(res, &itemTemp)
+	}
+	return res
+}
+
+// Get returns value of key in Data or BinaryData
+func (c *ConfigMap) Get(key string) ([]byte, bool) {
+	if value, found := c.Data[key]; found {
+		return []byte(value), true
+	}
+	value, found := c.BinaryData[key]
+	return value, found
+}
+
+// Keys returns all keys in Data and BinaryData
+func (c *ConfigMap) Keys() []string {
+	keys := make([]string, 0, len(c.Data)+len(c.BinaryData))
+	for key := range c.Data {
+		keys = append(keys, key)
+	}
+	for key := range c.BinaryData {
+		keys = append(keys, key)
+	}
+	return keys
+}
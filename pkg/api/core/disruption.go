@@ -0,0 +1,249 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"minik8s/pkg/api/meta"
+	"minik8s/pkg/api/types"
+	"strconv"
+)
+
+// PodDisruptionBudget is an object to define the max disruption that can be caused to a collection of pods
+type PodDisruptionBudget struct {
+	meta.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	meta.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of the PodDisruptionBudget.
+	// +optional
+	Spec PodDisruptionBudgetSpec `json:"spec,omitempty"`
+
+	// Most recently observed status of the PodDisruptionBudget.
+	// +optional
+	Status PodDisruptionBudgetStatus `json:"status,omitempty"`
+}
+
+func (p *PodDisruptionBudget) PrintBrief() {
+	fmt.Printf("%-20s\t%-40s\t%-15s\t%-15s\t%-20s\n", "NAME", "UID", "MIN AVAILABLE", "MAX UNAVAILABLE", "ALLOWED DISRUPTIONS")
+	fmt.Printf("%-20s\t%-40s\t%-15s\t%-15s\t%-20d\n", p.Name, p.UID, formatBudget(p.Spec.MinAvailable), formatBudget(p.Spec.MaxUnavailable), p.Status.DisruptionsAllowed)
+}
+
+func formatBudget(v *int32) string {
+	if v == nil {
+		return "N/A"
+	}
+	return strconv.Itoa(int(*v))
+}
+
+func (p *PodDisruptionBudget) SetUID(uid types.UID) {
+	p.ObjectMeta.UID = uid
+}
+
+func (p *PodDisruptionBudget) GetUID() types.UID {
+	return p.ObjectMeta.UID
+}
+
+func (p *PodDisruptionBudget) JsonUnmarshal(data []byte) error {
+	return json.Unmarshal(data, &p)
+}
+
+func (p *PodDisruptionBudget) JsonMarshal() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (p *PodDisruptionBudget) JsonUnmarshalStatus(data []byte) error {
+	return json.Unmarshal(data, &(p.Status))
+}
+
+func (p *PodDisruptionBudget) JsonMarshalStatus() ([]byte, error) {
+	return json.Marshal(p.Status)
+}
+
+func (p *PodDisruptionBudget) SetStatus(s IApiObjectStatus) bool {
+	status, ok := s.(*PodDisruptionBudgetStatus)
+	if ok {
+		p.Status = *status
+	}
+	return ok
+}
+
+func (p *PodDisruptionBudget) GetStatus() IApiObjectStatus {
+	return &p.Status
+}
+
+func (p *PodDisruptionBudget) GetResourceVersion() string {
+	return p.ObjectMeta.ResourceVersion
+}
+
+func (p *PodDisruptionBudget) SetResourceVersion(version string) {
+	p.ObjectMeta.ResourceVersion = version
+}
+
+func (p *PodDisruptionBudget) CreateFromEtcdString(str string) error {
+	return p.JsonUnmarshal([]byte(str))
+}
+
+func (p *PodDisruptionBudget) GenerateOwnerReference() meta.OwnerReference {
+	return meta.OwnerReference{
+		APIVersion: p.APIVersion,
+		Kind:       p.Kind,
+		Name:       p.Name,
+		UID:        p.UID,
+		Controller: false,
+	}
+}
+
+func (p *PodDisruptionBudget) AppendOwnerReference(reference meta.OwnerReference) {
+	p.OwnerReferences = append(p.OwnerReferences, reference)
+}
+
+func (p *PodDisruptionBudget) DeleteOwnerReference(uid types.UID) {
+	has := false
+	idx := 0
+	for i, o := range p.OwnerReferences {
+		if o.UID == uid {
+			has = true
+			idx = i
+			break
+		}
+	}
+	if has {
+		p.OwnerReferences = append(p.OwnerReferences[:idx], p.OwnerReferences[idx+1:]...)
+	}
+}
+
+// PodDisruptionBudgetSpec is a description of a PodDisruptionBudget.
+type PodDisruptionBudgetSpec struct {
+	// An eviction is allowed if at least "minAvailable" pods selected by
+	// "selector" will still be available after the eviction, i.e. even in the
+	// absence of the evicted pod.
+	// Mutually exclusive with "maxUnavailable".
+	// +optional
+	MinAvailable *int32 `json:"minAvailable,omitempty"`
+
+	// Label query over pods whose evictions are managed by the disruption
+	// budget.
+	Selector meta.LabelSelector `json:"selector"`
+
+	// An eviction is allowed if at most "maxUnavailable" pods selected by
+	// "selector" are unavailable after the eviction, i.e. even in absence of
+	// the evicted pod. The number of pods expected is the sum of replicas of
+	// the ReplicaSets owning the selected pods.
+	// Mutually exclusive with "minAvailable".
+	// +optional
+	MaxUnavailable *int32 `json:"maxUnavailable,omitempty"`
+}
+
+// PodDisruptionBudgetStatus represents information about the status of a
+// PodDisruptionBudget. Status may trail the actual state of a system.
+type PodDisruptionBudgetStatus struct {
+	// DisruptedPods contains information about pods whose eviction was
+	// processed by the API server eviction subresource handler but has not
+	// yet been observed by the PodDisruptionBudget controller.
+	// A pod will be in this map from the time when the API server processed the
+	// eviction request to the time when the pod is seen by PDB controller
+	// as having been deleted (or after a timeout). The key in the map is the
+	// uid of the pod and the value is the time when the API server processed
+	// the eviction request.
+	// +optional
+	DisruptedPods map[string]types.Time `json:"disruptedPods,omitempty"`
+
+	// Number of pod disruptions that are currently allowed.
+	DisruptionsAllowed int32 `json:"disruptionsAllowed"`
+
+	// current number of healthy pods
+	CurrentHealthy int32 `json:"currentHealthy"`
+
+	// minimum desired number of healthy pods
+	DesiredHealthy int32 `json:"desiredHealthy"`
+
+	// total number of pods counted by this disruption budget
+	ExpectedPods int32 `json:"expectedPods"`
+}
+
+func (p *PodDisruptionBudgetStatus) JsonUnmarshal(data []byte) error {
+	return json.Unmarshal(data, &p)
+}
+
+func (p *PodDisruptionBudgetStatus) JsonMarshal() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// PodDisruptionBudgetList is a collection of PodDisruptionBudgets.
+type PodDisruptionBudgetList struct {
+	meta.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	// +optional
+	meta.ListMeta `json:"metadata,omitempty"`
+
+	// Items is a list of PodDisruptionBudgets
+	Items []PodDisruptionBudget `json:"items"`
+}
+
+func (p *PodDisruptionBudgetList) PrintBrief() {
+	fmt.Printf("%-20s\t%-40s\t%-15s\t%-15s\t%-20s\n", "NAME", "UID", "MIN AVAILABLE", "MAX UNAVAILABLE", "ALLOWED DISRUPTIONS")
+	for _, item := range p.Items {
+		fmt.Printf("%-20s\t%-40s\t%-15s\t%-15s\t%-20d\n", item.Name, item.UID, formatBudget(item.Spec.MinAvailable), formatBudget(item.Spec.MaxUnavailable), item.Status.DisruptionsAllowed)
+	}
+}
+
+func (p *PodDisruptionBudgetList) JsonUnmarshal(data []byte) error {
+	return json.Unmarshal(data, &p)
+}
+
+func (p *PodDisruptionBudgetList) JsonMarshal() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (p *PodDisruptionBudgetList) AddItemFromStr(objectStr string) error {
+	object := &PodDisruptionBudget{}
+	buf, err := strconv.Unquote(objectStr)
+	err = object.JsonUnmarshal([]byte(buf))
+	if err != nil {
+		return err
+	}
+	p.Items = append(p.Items, *object)
+	return nil
+}
+
+func (p *PodDisruptionBudgetList) AppendItemsFromStr(objectStrs []string) error {
+	for _, obj := range objectStrs {
+		object := &PodDisruptionBudget{}
+		err := object.JsonUnmarshal([]byte(obj))
+		if err != nil {
+			return err
+		}
+		p.Items = append(p.Items, *object)
+	}
+	return nil
+}
+
+func (p *PodDisruptionBudgetList) GetItems() any {
+	return p.Items
+}
+
+func (p *PodDisruptionBudgetList) GetIApiObjectArr() (res []IApiObject) {
+	for _, item := range p.Items {
+		itemTemp := item
+		res = append(res, &itemTemp)
+	}
+	return res
+}
+
+// MatchPod returns true if pod is selected by the budget, an empty selector
+// selects all pods in the namespace of the budget
+func (p *PodDisruptionBudget) MatchPod(pod *Pod) bool {
+	return namespaceOrDefault(p.Namespace) == namespaceOrDefault(pod.Namespace) && meta.MatchLabelSelector(p.Spec.Selector, pod.Labels)
+}
+
+// namespaceOrDefault treats empty namespace as "default", pods created from
+// templates without namespace are in "default"
+func namespaceOrDefault(namespace string) string {
+	if namespace == "" {
+		return "default"
+	}
+	return namespace
+}
@@ -0,0 +1,289 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"minik8s/pkg/api/meta"
+	"minik8s/pkg/api/types"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Valid values for event types
+const (
+	// EventTypeNormal is for information only and will not cause any problems
+	EventTypeNormal string = "Normal"
+	// EventTypeWarning means something is not working as expected
+	EventTypeWarning string = "Warning"
+)
+
+// Event is a report of an event somewhere in the cluster, e.g. a pod fails
+// to be scheduled or an image fails to be pulled. Events of the same object,
+// reason and message are aggregated by recorders into one Event with Count.
+type Event struct {
+	meta.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	meta.ObjectMeta `json:"metadata"`
+
+	// The object that this event is about.
+	InvolvedObject ObjectReference `json:"involvedObject" protobuf:"bytes,2,opt,name=involvedObject"`
+
+	// This should be a short, machine understandable string that gives the reason
+	// for the transition into the object's current status.
+	// TODO: provide exact specification for format.
+	// +optional
+	Reason string `json:"reason,omitempty" protobuf:"bytes,3,opt,name=reason"`
+
+	// A human-readable description of the status of this operation.
+	// TODO: decide on maximum length.
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,4,opt,name=message"`
+
+	// The component reporting this event. Should be a short machine understandable string.
+	// +optional
+	Source EventSource `json:"source,omitempty" protobuf:"bytes,5,opt,name=source"`
+
+	// The time at which the event was first recorded. (Time of server receipt is in TypeMeta.)
+	// +optional
+	FirstTimestamp types.Time `json:"firstTimestamp,omitempty" protobuf:"bytes,6,opt,name=firstTimestamp"`
+
+	// The time at which the most recent occurrence of this event was recorded.
+	// +optional
+	LastTimestamp types.Time `json:"lastTimestamp,omitempty" protobuf:"bytes,7,opt,name=lastTimestamp"`
+
+	// The number of times this event has occurred.
+	// +optional
+	Count int32 `json:"count,omitempty" protobuf:"varint,8,opt,name=count"`
+
+	// Type of this event (Normal, Warning), new types could be added in the future
+	// +optional
+	Type string `json:"type,omitempty" protobuf:"bytes,9,opt,name=type"`
+}
+
+// ObjectReference contains enough information to let you inspect or modify the referred object.
+type ObjectReference struct {
+	// Kind of the referent, one of types.ApiObjectType.
+	// +optional
+	Kind types.ApiObjectType `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
+	// Namespace of the referent.
+	// +optional
+	Namespace string `json:"namespace,omitempty" protobuf:"bytes,2,opt,name=namespace"`
+	// Name of the referent.
+	// +optional
+	Name string `json:"name,omitempty" protobuf:"bytes,3,opt,name=name"`
+	// UID of the referent.
+	// +optional
+	UID types.UID `json:"uid,omitempty" protobuf:"bytes,4,opt,name=uid,casttype=k8s.io/apimachinery/pkg/types.UID"`
+}
+
+// EventSource contains information for an event.
+type EventSource struct {
+	// Component from which the event is generated.
+	// +optional
+	Component string `json:"component,omitempty" protobuf:"bytes,1,opt,name=component"`
+	// Node name on which the event is generated.
+	// +optional
+	Host string `json:"host,omitempty" protobuf:"bytes,2,opt,name=host"`
+}
+
+// GetObjectReference returns reference of object that events are about
+func GetObjectReference(object IApiObject) ObjectReference {
+	switch o := object.(type) {
+	case *Pod:
+		return ObjectReference{Kind: types.PodObjectType, Namespace: o.Namespace, Name: o.Name, UID: o.UID}
+	case *Node:
+		return ObjectReference{Kind: types.NodeObjectType, Name: o.Name, UID: o.UID}
+	case *ReplicaSet:
+		return ObjectReference{Kind: types.ReplicasetObjectType, Namespace: o.Namespace, Name: o.Name, UID: o.UID}
+	case *HorizontalPodAutoscaler:
+		return ObjectReference{Kind: types.HorizontalPodAutoscalerObjectType, Namespace: o.Namespace, Name: o.Name, UID: o.UID}
+	case *Service:
+		return ObjectReference{Kind: types.ServiceObjectType, Namespace: o.Namespace, Name: o.Name, UID: o.UID}
+	case *DNS:
+		return ObjectReference{Kind: types.DnsObjectType, Namespace: o.Namespace, Name: o.Name, UID: o.UID}
+	case *Func:
+		return ObjectReference{Kind: types.FuncTemplateObjectType, Name: o.Spec.Name, UID: o.UID}
+	case *PersistentVolumeClaim:
+		return ObjectReference{Kind: types.PersistentVolumeClaimObjectType, Namespace: o.Namespace, Name: o.Name, UID: o.UID}
+	default:
+		ref := object.GenerateOwnerReference()
+		return ObjectReference{Kind: types.ApiObjectType(ref.Kind), Name: ref.Name, UID: ref.UID}
+	}
+}
+
+// EventStatus is empty, Event has no status
+type EventStatus struct{}
+
+func (e *EventStatus) JsonUnmarshal(data []byte) error {
+	return json.Unmarshal(data, &e)
+}
+
+func (e *EventStatus) JsonMarshal() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *Event) PrintBrief() {
+	printEventsHeader()
+	printEvent(e)
+}
+
+func printEventsHeader() {
+	fmt.Printf("%-10s\t%-8s\t%-20s\t%-30s\t%s\n", "LAST SEEN", "TYPE", "REASON", "OBJECT", "MESSAGE")
+}
+
+func printEvent(e *Event) {
+	object := fmt.Sprintf("%v/%v", e.InvolvedObject.Kind, e.InvolvedObject.Name)
+	lastSeen := time.Since(e.LastTimestamp).Round(time.Second).String()
+	if e.Count > 1 {
+		lastSeen = fmt.Sprintf("%v (x%d)", lastSeen, e.Count)
+	}
+	fmt.Printf("%-10s\t%-8s\t%-20s\t%-30s\t%s\n", lastSeen, e.Type, e.Reason, object, e.Message)
+}
+
+func (e *Event) SetUID(uid types.UID) {
+	e.ObjectMeta.UID = uid
+}
+
+func (e *Event) GetUID() types.UID {
+	return e.ObjectMeta.UID
+}
+
+func (e *Event) JsonUnmarshal(data []byte) error {
+	return json.Unmarshal(data, &e)
+}
+
+func (e *Event) JsonMarshal() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *Event) JsonUnmarshalStatus(data []byte) error {
+	return nil
+}
+
+func (e *Event) JsonMarshalStatus() ([]byte, error) {
+	return json.Marshal(EventStatus{})
+}
+
+func (e *Event) SetStatus(s IApiObjectStatus) bool {
+	_, ok := s.(*EventStatus)
+	return ok
+}
+
+func (e *Event) GetStatus() IApiObjectStatus {
+	return &EventStatus{}
+}
+
+func (e *Event) GetResourceVersion() string {
+	return e.ObjectMeta.ResourceVersion
+}
+
+func (e *Event) SetResourceVersion(version string) {
+	e.ObjectMeta.ResourceVersion = version
+}
+
+func (e *Event) CreateFromEtcdString(str string) error {
+	return e.JsonUnmarshal([]byte(str))
+}
+
+func (e *Event) GenerateOwnerReference() meta.OwnerReference {
+	return meta.OwnerReference{
+		APIVersion: e.APIVersion,
+		Kind:       e.Kind,
+		Name:       e.Name,
+		UID:        e.UID,
+		Controller: false,
+	}
+}
+
+func (e *Event) AppendOwnerReference(reference meta.OwnerReference) {
+	e.OwnerReferences = append(e.OwnerReferences, reference)
+}
+
+func (e *Event) DeleteOwnerReference(uid types.UID) {
+	has := false
+	idx := 0
+	for i, o := range e.OwnerReferences {
+		if o.UID == uid {
+			has = true
+			idx = i
+			break
+		}
+	}
+	if has {
+		e.OwnerReferences = append(e.OwnerReferences[:idx], e.OwnerReferences[idx+1:]...)
+	}
+}
+
+// EventList is a list of events.
+type EventList struct {
+	meta.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	// +optional
+	meta.ListMeta `json:"metadata,omitempty"`
+
+	// List of events
+	Items []Event `json:"items"`
+}
+
+// PrintBrief prints events from the oldest to the latest
+func (e *EventList) PrintBrief() {
+	printEventsHeader()
+	for _, item := range e.SortedItems() {
+		printEvent(&item)
+	}
+}
+
+// SortedItems returns items sorted by their last timestamps
+func (e *EventList) SortedItems() []Event {
+	items := append([]Event{}, e.Items...)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].LastTimestamp.Before(items[j].LastTimestamp)
+	})
+	return items
+}
+
+func (e *EventList) JsonUnmarshal(data []byte) error {
+	return json.Unmarshal(data, &e)
+}
+
+func (e *EventList) JsonMarshal() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *EventList) AddItemFromStr(objectStr string) error {
+	object := &Event{}
+	buf, err := strconv.Unquote(objectStr)
+	err = object.JsonUnmarshal([]byte(buf))
+	if err != nil {
+		return err
+	}
+	e.Items = append(e.Items, *object)
+	return nil
+}
+
+func (e *EventList) AppendItemsFromStr(objectStrs []string) error {
+	for _, obj := range objectStrs {
+		object := &Event{}
+		err := object.JsonUnmarshal([]byte(obj))
+		if err != nil {
+			return err
+		}
+		e.Items = append(e.Items, *object)
+	}
+	return nil
+}
+
+func (e *EventList) GetItems() any {
+	return e.Items
+}
+
+func (e *EventList) GetIApiObjectArr() (res []IApiObject) {
+	for _, item := range e.Items {
+		itemTemp := item
+		res = append(res, &itemTemp)
+	}
+	return res
+}
@@ -127,8 +127,103 @@ type NodeSpec struct {
 
 	// Address represents the node IP address
 	Address string `json:"address,omitempty"`
+
+	// ContainerRuntime is the runtime kubelet runs pods with, "docker" or
+	// "containerd", docker is used if empty
+	// +optional
+	ContainerRuntime string `json:"containerRuntime,omitempty"`
+
+	// ContainerRuntimeEndpoint is the socket of container runtime, default
+	// endpoint of the runtime is used if empty
+	// +optional
+	ContainerRuntimeEndpoint string `json:"containerRuntimeEndpoint,omitempty"`
+
+	// CgroupDriver is how kubelet names cgroups of pods, "cgroupfs" or "systemd",
+	// it must match cgroup driver of container runtime, cgroupfs is used if empty
+	// +optional
+	CgroupDriver string `json:"cgroupDriver,omitempty"`
+
+	// EvictionHard are thresholds of eviction signals, e.g. "memory.available": "100M"
+	// or "nodefs.available": "10%", pods are evicted once any of them is met.
+	// Default thresholds are used if nil.
+	// +optional
+	EvictionHard map[string]string `json:"evictionHard,omitempty"`
+
+	// EvictionSoft are thresholds of eviction signals, pods are evicted once any of
+	// them is met for its grace period in EvictionSoftGracePeriod, e.g. "memory.available": "1m30s"
+	// +optional
+	EvictionSoft            map[string]string `json:"evictionSoft,omitempty"`
+	EvictionSoftGracePeriod map[string]string `json:"evictionSoftGracePeriod,omitempty"`
+
+	// StaticPodPath is the directory of manifests of static pods, which kubelet runs
+	// without ApiServer, default directory is used if empty
+	// +optional
+	StaticPodPath string `json:"staticPodPath,omitempty"`
+
+	// Unschedulable controls node schedulability of new pods. By default, node is schedulable.
+	// More info: https://kubernetes.io/docs/concepts/nodes/node/#manual-node-administration
+	// +optional
+	Unschedulable bool `json:"unschedulable,omitempty" protobuf:"varint,4,opt,name=unschedulable"`
+
+	// If specified, the node's taints.
+	// +optional
+	Taints []Taint `json:"taints,omitempty" protobuf:"bytes,5,opt,name=taints"`
+}
+
+// Taint is attached to a node and makes the node repel pods
+// that do not tolerate the taint.
+type Taint struct {
+	// Required. The taint key to be applied to a node.
+	Key string `json:"key" protobuf:"bytes,1,opt,name=key"`
+	// The taint value corresponding to the taint key.
+	// +optional
+	Value string `json:"value,omitempty" protobuf:"bytes,2,opt,name=value"`
+	// Required. The effect of the taint on pods
+	// that do not tolerate the taint.
+	// Valid effects are NoSchedule, PreferNoSchedule and NoExecute.
+	Effect TaintEffect `json:"effect" protobuf:"bytes,3,opt,name=effect,casttype=TaintEffect"`
+	// TimeAdded represents the time at which the taint was added.
+	// It is only written for NoExecute taints.
+	// +optional
+	TimeAdded *types.Time `json:"timeAdded,omitempty" protobuf:"bytes,4,opt,name=timeAdded"`
+}
+
+// MatchTaint checks if the taint matches taintToMatch. Taints are unique by key:effect,
+// if the two taints have same key:effect, regard as they match.
+func (t *Taint) MatchTaint(taintToMatch *Taint) bool {
+	return t.Key == taintToMatch.Key && t.Effect == taintToMatch.Effect
 }
 
+type TaintEffect string
+
+const (
+	// TaintEffectNoSchedule means do not allow new pods to schedule onto the node unless they tolerate the taint,
+	// but allow all pods submitted to Kubelet without going through the scheduler to start,
+	// and allow all already-running pods to continue running.
+	TaintEffectNoSchedule TaintEffect = "NoSchedule"
+	// TaintEffectPreferNoSchedule is like TaintEffectNoSchedule, but the scheduler tries
+	// not to schedule new pods onto the node, rather than prohibiting new pods from
+	// scheduling onto the node entirely.
+	TaintEffectPreferNoSchedule TaintEffect = "PreferNoSchedule"
+	// TaintEffectNoExecute means evict any already-running pods that do not tolerate the taint.
+	TaintEffectNoExecute TaintEffect = "NoExecute"
+)
+
+// Well-known taint keys
+const (
+	// TaintNodeNotReady will be added when node is not ready
+	// and removed when node becomes ready.
+	TaintNodeNotReady = "node.kubernetes.io/not-ready"
+	// TaintNodeUnreachable will be added when node becomes unreachable
+	// (corresponding to NodeReady status ConditionUnknown)
+	// and removed when node becomes reachable (NodeReady status ConditionTrue).
+	TaintNodeUnreachable = "node.kubernetes.io/unreachable"
+
+	// TaintNodeUnschedulable is the key of taint tolerated by pods which
+	// can be scheduled onto nodes with spec.unschedulable set.
+	TaintNodeUnschedulable = "node.kubernetes.io/unschedulable"
+)
+
 // NodeStatus is information about the current status of a node.
 type NodeStatus struct {
 	// NodePhase is the recently observed lifecycle phase of the node.
@@ -136,6 +231,14 @@ type NodeStatus struct {
 	// The field is never populated, and now is deprecated.
 	// +optional
 	Phase NodePhase `json:"phase,omitempty" protobuf:"bytes,3,opt,name=phase,casttype=NodePhase"`
+	// Capacity represents the total resources of a node, reported by kubelet.
+	// More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#capacity
+	// +optional
+	Capacity ResourceList `json:"capacity,omitempty" protobuf:"bytes,1,rep,name=capacity,casttype=ResourceList,castkey=ResourceName"`
+	// Allocatable represents the resources of a node that are available for scheduling.
+	// Defaults to Capacity minus resources reserved for system daemons.
+	// +optional
+	Allocatable ResourceList `json:"allocatable,omitempty" protobuf:"bytes,2,rep,name=allocatable,casttype=ResourceList,castkey=ResourceName"`
 	// List of addresses reachable to the node.
 	// Queried from cloud provider, if available.
 	// More info: https://kubernetes.io/docs/concepts/nodes/node/#addresses
@@ -150,6 +253,59 @@ type NodeStatus struct {
 	// +patchMergeKey=type
 	// +patchStrategy=merge
 	Addresses []NodeAddress `json:"addresses,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,5,rep,name=addresses"`
+	// Conditions is an array of current observed node conditions.
+	// More info: https://kubernetes.io/docs/concepts/nodes/node/#condition
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []NodeCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,4,rep,name=conditions"`
+}
+
+// GetCondition returns the condition of type conditionType, nil if not found
+func (n *NodeStatus) GetCondition(conditionType NodeConditionType) *NodeCondition {
+	for i := range n.Conditions {
+		if n.Conditions[i].Type == conditionType {
+			return &n.Conditions[i]
+		}
+	}
+	return nil
+}
+
+type NodeConditionType string
+
+// These are valid conditions of node, reported by kubelet
+// and set to Unknown by node lifecycle controller.
+const (
+	// NodeReady means kubelet is healthy and ready to accept pods.
+	NodeReady NodeConditionType = "Ready"
+	// NodeMemoryPressure means the kubelet is under pressure due to insufficient available memory.
+	NodeMemoryPressure NodeConditionType = "MemoryPressure"
+	// NodeDiskPressure means the kubelet is under pressure due to insufficient available disk.
+	NodeDiskPressure NodeConditionType = "DiskPressure"
+	// NodePIDPressure means the kubelet is under pressure due to insufficient available PID.
+	NodePIDPressure NodeConditionType = "PIDPressure"
+	// NodeNetworkUnavailable means that network for the node is not correctly configured.
+	NodeNetworkUnavailable NodeConditionType = "NetworkUnavailable"
+)
+
+// NodeCondition contains condition information for a node.
+type NodeCondition struct {
+	// Type of node condition.
+	Type NodeConditionType `json:"type" protobuf:"bytes,1,opt,name=type,casttype=NodeConditionType"`
+	// Status of the condition, one of True, False, Unknown.
+	Status ConditionStatus `json:"status" protobuf:"bytes,2,opt,name=status,casttype=ConditionStatus"`
+	// Last time we got an update on a given condition.
+	// +optional
+	LastHeartbeatTime types.Time `json:"lastHeartbeatTime,omitempty" protobuf:"bytes,3,opt,name=lastHeartbeatTime"`
+	// Last time the condition transit from one status to another.
+	// +optional
+	LastTransitionTime types.Time `json:"lastTransitionTime,omitempty" protobuf:"bytes,4,opt,name=lastTransitionTime"`
+	// (brief) reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty" protobuf:"bytes,5,opt,name=reason"`
+	// Human readable message indicating details about last transition.
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,6,opt,name=message"`
 }
 
 func (n *NodeStatus) JsonUnmarshal(data []byte) error {
@@ -0,0 +1,728 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"minik8s/pkg/api/meta"
+	"minik8s/pkg/api/types"
+	"strconv"
+	"strings"
+)
+
+/*--------------------- PersistentVolume ---------------------*/
+
+// PersistentVolume (PV) is a storage resource provisioned by an administrator
+// or dynamically by a provisioner. It is cluster scoped, stored and referenced by name.
+// More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes
+type PersistentVolume struct {
+	meta.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	meta.ObjectMeta `json:"metadata,omitempty"`
+
+	// spec defines a specification of a persistent volume owned by the cluster.
+	// +optional
+	Spec PersistentVolumeSpec `json:"spec,omitempty"`
+
+	// status represents the current information/status for the persistent volume.
+	// Populated by the system.
+	// +optional
+	Status PersistentVolumeStatus `json:"status,omitempty"`
+}
+
+// PersistentVolumeSpec is the specification of a persistent volume.
+type PersistentVolumeSpec struct {
+	// capacity is the description of the persistent volume's resources and capacity,
+	// only storage is used.
+	// +optional
+	Capacity ResourceList `json:"capacity,omitempty"`
+	// persistentVolumeSource is the actual volume backing the persistent volume.
+	PersistentVolumeSource `json:",inline"`
+	// accessModes contains all ways the volume can be mounted.
+	// +optional
+	AccessModes []PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+	// claimRef is part of a bi-directional binding between PersistentVolume and PersistentVolumeClaim.
+	// Expected to be non-nil when bound. A volume created with claimRef of which
+	// uid is empty is reserved for the claim of that name.
+	// +optional
+	ClaimRef *PersistentVolumeClaimReference `json:"claimRef,omitempty"`
+	// persistentVolumeReclaimPolicy defines what happens to a persistent volume when released from its claim.
+	// Defaults to Retain for manually created volumes and Delete for dynamically provisioned ones.
+	// +optional
+	PersistentVolumeReclaimPolicy PersistentVolumeReclaimPolicy `json:"persistentVolumeReclaimPolicy,omitempty"`
+	// storageClassName is the name of StorageClass to which this persistent volume belongs. Empty value
+	// means that this volume does not belong to any StorageClass.
+	// +optional
+	StorageClassName string `json:"storageClassName,omitempty"`
+	// nodeAffinity defines constraints that limit what nodes this volume can be accessed from.
+	// Pods using this volume are only scheduled to nodes that satisfy it.
+	// +optional
+	NodeAffinity *VolumeNodeAffinity `json:"nodeAffinity,omitempty"`
+}
+
+// PersistentVolumeSource is similar to VolumeSource but meant for the
+// administrator who creates PVs. Exactly one of its members must be set.
+type PersistentVolumeSource struct {
+	// hostPath represents a directory on the host.
+	// +optional
+	HostPath *HostPathVolumeSource `json:"hostPath,omitempty"`
+	// local represents directly-attached storage with node affinity
+	// +optional
+	Local *LocalVolumeSource `json:"local,omitempty"`
+}
+
+// LocalVolumeSource represents directly-attached storage with node affinity
+type LocalVolumeSource struct {
+	// path of the full path to the volume on the node.
+	// It can be either a directory or block device (disk, partition, ...).
+	Path string `json:"path"`
+}
+
+// PersistentVolumeClaimReference identifies the claim a volume is bound to
+type PersistentVolumeClaimReference struct {
+	// Name of the claim
+	Name string `json:"name"`
+	// UID of the claim, empty if the volume is reserved for a claim not created yet
+	// +optional
+	UID types.UID `json:"uid,omitempty"`
+}
+
+// VolumeNodeAffinity defines constraints that limit what nodes this volume can be accessed from.
+type VolumeNodeAffinity struct {
+	// required specifies hard node constraints that must be met.
+	Required *NodeSelector `json:"required,omitempty"`
+}
+
+// NodeSelector represents the union of the results of one or more label queries
+// over a set of nodes; that is, it represents the OR of the selectors represented
+// by the node selector terms.
+type NodeSelector struct {
+	// Required. A list of node selector terms. The terms are ORed.
+	NodeSelectorTerms []NodeSelectorTerm `json:"nodeSelectorTerms"`
+}
+
+// NodeSelectorTerm represents expressions of node labels, they are ANDed.
+// A null or empty node selector term matches no objects.
+type NodeSelectorTerm struct {
+	// A list of node selector requirements by node's labels.
+	// +optional
+	MatchExpressions []NodeSelectorRequirement `json:"matchExpressions,omitempty"`
+}
+
+// NodeSelectorRequirement is a selector that contains values, a key, and an operator
+// that relates the key and values.
+type NodeSelectorRequirement struct {
+	// The label key that the selector applies to.
+	Key string `json:"key"`
+	// Represents a key's relationship to a set of values.
+	// Valid operators are In, NotIn, Exists, DoesNotExist.
+	Operator NodeSelectorOperator `json:"operator"`
+	// An array of string values. If the operator is In or NotIn,
+	// the values array must be non-empty. If the operator is Exists or DoesNotExist,
+	// the values array must be empty.
+	// +optional
+	Values []string `json:"values,omitempty"`
+}
+
+// NodeSelectorOperator is the set of operators that can be used in
+// a node selector requirement.
+type NodeSelectorOperator string
+
+const (
+	NodeSelectorOpIn           NodeSelectorOperator = "In"
+	NodeSelectorOpNotIn        NodeSelectorOperator = "NotIn"
+	NodeSelectorOpExists       NodeSelectorOperator = "Exists"
+	NodeSelectorOpDoesNotExist NodeSelectorOperator = "DoesNotExist"
+)
+
+// LabelHostname is the well-known node label of node name, node without
+// the label is treated as labeled by its name
+const LabelHostname = "kubernetes.io/hostname"
+
+// PersistentVolumeAccessMode describes how a volume can be mounted
+type PersistentVolumeAccessMode string
+
+const (
+	// ReadWriteOnce can be mounted in read/write mode to exactly 1 host
+	ReadWriteOnce PersistentVolumeAccessMode = "ReadWriteOnce"
+	// ReadOnlyMany can be mounted in read-only mode to many hosts
+	ReadOnlyMany PersistentVolumeAccessMode = "ReadOnlyMany"
+	// ReadWriteMany can be mounted in read/write mode to many hosts
+	ReadWriteMany PersistentVolumeAccessMode = "ReadWriteMany"
+)
+
+// PersistentVolumeReclaimPolicy describes a policy for end-of-life maintenance of persistent volumes.
+type PersistentVolumeReclaimPolicy string
+
+const (
+	// PersistentVolumeReclaimDelete means the volume will be deleted from the cluster on release from its claim.
+	PersistentVolumeReclaimDelete PersistentVolumeReclaimPolicy = "Delete"
+	// PersistentVolumeReclaimRetain means the volume will be left in its current phase (Released) for manual
+	// reclamation by the administrator. The default policy is Retain.
+	PersistentVolumeReclaimRetain PersistentVolumeReclaimPolicy = "Retain"
+)
+
+// PersistentVolumeStatus is the current status of a persistent volume.
+type PersistentVolumeStatus struct {
+	// phase indicates if a volume is available, bound to a claim, or released by a claim.
+	// +optional
+	Phase PersistentVolumePhase `json:"phase,omitempty"`
+	// message is a human-readable message indicating details about why the volume is in this state.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type PersistentVolumePhase string
+
+const (
+	// VolumePending is used for PersistentVolumes that are not available
+	VolumePending PersistentVolumePhase = "Pending"
+	// VolumeAvailable is used for PersistentVolumes that are not yet bound
+	// Available volumes are held by the binder and matched to PersistentVolumeClaims
+	VolumeAvailable PersistentVolumePhase = "Available"
+	// VolumeBound is used for PersistentVolumes that are bound
+	VolumeBound PersistentVolumePhase = "Bound"
+	// VolumeReleased is used for PersistentVolumes where the bound PersistentVolumeClaim was deleted
+	// released volumes must be recycled before becoming available again
+	VolumeReleased PersistentVolumePhase = "Released"
+	// VolumeFailed is used for PersistentVolumes that failed to be correctly recycled or deleted after being released from a claim
+	VolumeFailed PersistentVolumePhase = "Failed"
+)
+
+const (
+	// AnnDynamicallyProvisioned is the annotation added to a PV that has been
+	// dynamically provisioned, its value is the name of provisioner
+	AnnDynamicallyProvisioned = "pv.kubernetes.io/provisioned-by"
+	// AnnSelectedNode is added to a PVC whose binding is delayed until the
+	// first pod using it is scheduled, its value is the node selected by scheduler
+	AnnSelectedNode = "volume.kubernetes.io/selected-node"
+)
+
+// LocalPathStorageClassName is the storage class of the built-in local-path
+// provisioner. Binding of claims of this class waits for the first consumer,
+// volumes are provisioned as directories on the node selected by scheduler.
+const LocalPathStorageClassName = "local-path"
+
+// LocalPathProvisionerName is the value of AnnDynamicallyProvisioned of volumes
+// provisioned by the local-path provisioner
+const LocalPathProvisionerName = "minik8s.io/local-path"
+
+func (pv *PersistentVolume) PrintBrief() {
+	fmt.Printf("%-20s\t%-10s\t%-20s\t%-10s\t%-10s\t%-20s\t%-15s\n", "NAME", "CAPACITY", "ACCESS MODES", "RECLAIM", "STATUS", "CLAIM", "STORAGECLASS")
+	pv.printRow()
+}
+
+func (pv *PersistentVolume) printRow() {
+	claim := ""
+	if pv.Spec.ClaimRef != nil {
+		claim = pv.Spec.ClaimRef.Name
+	}
+	fmt.Printf("%-20s\t%-10s\t%-20s\t%-10s\t%-10s\t%-20s\t%-15s\n", pv.Name, pv.Spec.Capacity[types.ResourceStorage],
+		formatAccessModes(pv.Spec.AccessModes), pv.Spec.PersistentVolumeReclaimPolicy, pv.Status.Phase, claim, pv.Spec.StorageClassName)
+}
+
+// formatAccessModes prints access modes in short form like RWO,ROX
+func formatAccessModes(modes []PersistentVolumeAccessMode) string {
+	short := map[PersistentVolumeAccessMode]string{ReadWriteOnce: "RWO", ReadOnlyMany: "ROX", ReadWriteMany: "RWX"}
+	var s []string
+	for _, mode := range modes {
+		s = append(s, short[mode])
+	}
+	return strings.Join(s, ",")
+}
+
+func (pv *PersistentVolume) SetUID(uid types.UID) {
+	pv.ObjectMeta.UID = uid
+}
+
+func (pv *PersistentVolume) GetUID() types.UID {
+	return pv.ObjectMeta.UID
+}
+
+func (pv *PersistentVolume) JsonUnmarshal(data []byte) error {
+	return json.Unmarshal(data, &pv)
+}
+
+func (pv *PersistentVolume) JsonMarshal() ([]byte, error) {
+	return json.Marshal(pv)
+}
+
+func (pv *PersistentVolume) JsonUnmarshalStatus(data []byte) error {
+	return json.Unmarshal(data, &(pv.Status))
+}
+
+func (pv *PersistentVolume) JsonMarshalStatus() ([]byte, error) {
+	return json.Marshal(pv.Status)
+}
+
+func (pv *PersistentVolume) SetStatus(s IApiObjectStatus) bool {
+	status, ok := s.(*PersistentVolumeStatus)
+	if ok {
+		pv.Status = *status
+	}
+	return ok
+}
+
+func (pv *PersistentVolume) GetStatus() IApiObjectStatus {
+	return &pv.Status
+}
+
+func (pv *PersistentVolume) GetResourceVersion() string {
+	return pv.ObjectMeta.ResourceVersion
+}
+
+func (pv *PersistentVolume) SetResourceVersion(version string) {
+	pv.ObjectMeta.ResourceVersion = version
+}
+
+func (pv *PersistentVolume) CreateFromEtcdString(str string) error {
+	return pv.JsonUnmarshal([]byte(str))
+}
+
+func (pv *PersistentVolume) GenerateOwnerReference() meta.OwnerReference {
+	return meta.OwnerReference{
+		APIVersion: pv.APIVersion,
+		Kind:       pv.Kind,
+		Name:       pv.Name,
+		UID:        pv.UID,
+		Controller: false,
+	}
+}
+
+func (pv *PersistentVolume) AppendOwnerReference(reference meta.OwnerReference) {
+	pv.OwnerReferences = append(pv.OwnerReferences, reference)
+}
+
+func (pv *PersistentVolume) DeleteOwnerReference(uid types.UID) {
+	has := false
+	idx := 0
+	for i, o := range pv.OwnerReferences {
+		if o.UID == uid {
+			has = true
+			idx = i
+			break
+		}
+	}
+	if has {
+		pv.OwnerReferences = append(pv.OwnerReferences[:idx], pv.OwnerReferences[idx+1:]...)
+	}
+}
+
+func (s *PersistentVolumeStatus) JsonUnmarshal(data []byte) error {
+	return json.Unmarshal(data, &s)
+}
+
+func (s *PersistentVolumeStatus) JsonMarshal() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// PersistentVolumeList is a list of PersistentVolume items.
+type PersistentVolumeList struct {
+	meta.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// +optional
+	meta.ListMeta `json:"metadata,omitempty"`
+
+	// items is a list of persistent volumes.
+	Items []PersistentVolume `json:"items"`
+}
+
+func (l *PersistentVolumeList) PrintBrief() {
+	fmt.Printf("%-20s\t%-10s\t%-20s\t%-10s\t%-10s\t%-20s\t%-15s\n", "NAME", "CAPACITY", "ACCESS MODES", "RECLAIM", "STATUS", "CLAIM", "STORAGECLASS")
+	for _, item := range l.Items {
+		item.printRow()
+	}
+}
+
+func (l *PersistentVolumeList) JsonUnmarshal(data []byte) error {
+	return json.Unmarshal(data, &l)
+}
+
+func (l *PersistentVolumeList) JsonMarshal() ([]byte, error) {
+	return json.Marshal(l)
+}
+
+func (l *PersistentVolumeList) AddItemFromStr(objectStr string) error {
+	object := &PersistentVolume{}
+	buf, err := strconv.Unquote(objectStr)
+	err = object.JsonUnmarshal([]byte(buf))
+	if err != nil {
+		return err
+	}
+	l.Items = append(l.Items, *object)
+	return nil
+}
+
+func (l *PersistentVolumeList) AppendItemsFromStr(objectStrs []string) error {
+	for _, obj := range objectStrs {
+		object := &PersistentVolume{}
+		err := object.JsonUnmarshal([]byte(obj))
+		if err != nil {
+			return err
+		}
+		l.Items = append(l.Items, *object)
+	}
+	return nil
+}
+
+func (l *PersistentVolumeList) GetItems() any {
+	return l.Items
+}
+
+func (l *PersistentVolumeList) GetIApiObjectArr() (res []IApiObject) {
+	for _, item := range l.Items {
+		itemTemp := item
+		res = append(res, &itemTemp)
+	}
+	return res
+}
+
+// Path returns the path of volume on node
+func (pv *PersistentVolume) Path() string {
+	switch {
+	case pv.Spec.Local != nil:
+		return pv.Spec.Local.Path
+	case pv.Spec.HostPath != nil:
+		return pv.Spec.HostPath.Path
+	}
+	return ""
+}
+
+// StorageCapacity returns the parsed storage capacity of volume
+func (pv *PersistentVolume) StorageCapacity() (uint64, error) {
+	return types.ParseQuantity(types.ResourceStorage, pv.Spec.Capacity[types.ResourceStorage])
+}
+
+// HasAccessModes returns true if volume supports all of modes
+func (pv *PersistentVolume) HasAccessModes(modes []PersistentVolumeAccessMode) bool {
+	for _, mode := range modes {
+		found := false
+		for _, m := range pv.Spec.AccessModes {
+			if m == mode {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// IsProvisionedBy returns true if volume is dynamically provisioned by provisioner
+func (pv *PersistentVolume) IsProvisionedBy(provisioner string) bool {
+	return pv.Annotations[AnnDynamicallyProvisioned] == provisioner
+}
+
+// MatchNode returns true if volume can be accessed from node
+func (pv *PersistentVolume) MatchNode(node *Node) bool {
+	if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return true
+	}
+	return pv.Spec.NodeAffinity.Required.Match(node)
+}
+
+// Match returns true if node satisfies any of the terms
+func (s *NodeSelector) Match(node *Node) bool {
+	labels := make(map[string]string, len(node.Labels)+1)
+	labels[LabelHostname] = node.Name
+	for k, v := range node.Labels {
+		labels[k] = v
+	}
+	for _, term := range s.NodeSelectorTerms {
+		if term.match(labels) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *NodeSelectorTerm) match(labels map[string]string) bool {
+	if len(t.MatchExpressions) == 0 {
+		return false
+	}
+	for _, req := range t.MatchExpressions {
+		value, exists := labels[req.Key]
+		switch req.Operator {
+		case NodeSelectorOpIn:
+			if !exists || !containsString(req.Values, value) {
+				return false
+			}
+		case NodeSelectorOpNotIn:
+			if exists && containsString(req.Values, value) {
+				return false
+			}
+		case NodeSelectorOpExists:
+			if !exists {
+				return false
+			}
+		case NodeSelectorOpDoesNotExist:
+			if exists {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// NewNodeNameAffinity creates a node affinity which is satisfied by node named nodeName only
+func NewNodeNameAffinity(nodeName string) *VolumeNodeAffinity {
+	return &VolumeNodeAffinity{
+		Required: &NodeSelector{
+			NodeSelectorTerms: []NodeSelectorTerm{{
+				MatchExpressions: []NodeSelectorRequirement{{
+					Key:      LabelHostname,
+					Operator: NodeSelectorOpIn,
+					Values:   []string{nodeName},
+				}},
+			}},
+		},
+	}
+}
+
+/*--------------------- PersistentVolumeClaim ---------------------*/
+
+// PersistentVolumeClaim is a user's request for and claim to a persistent volume,
+// it is stored and referenced by name.
+type PersistentVolumeClaim struct {
+	meta.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	meta.ObjectMeta `json:"metadata,omitempty"`
+
+	// spec defines the desired characteristics of a volume requested by a pod author.
+	// +optional
+	Spec PersistentVolumeClaimSpec `json:"spec,omitempty"`
+
+	// status represents the current information/status of a persistent volume claim.
+	// Read-only.
+	// +optional
+	Status PersistentVolumeClaimStatus `json:"status,omitempty"`
+}
+
+// PersistentVolumeClaimSpec describes the common attributes of storage devices
+// and allows a Source for provider-specific attributes
+type PersistentVolumeClaimSpec struct {
+	// accessModes contains the desired access modes the volume should have.
+	// +optional
+	AccessModes []PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+	// resources represents the minimum resources the volume should have,
+	// only requests of storage is used.
+	// +optional
+	Resources ResourceRequirements `json:"resources,omitempty"`
+	// volumeName is the binding reference to the PersistentVolume backing this claim.
+	// The claim is bound to the volume of this name only if it is set by user.
+	// +optional
+	VolumeName string `json:"volumeName,omitempty"`
+	// storageClassName is the name of the StorageClass required by the claim,
+	// only volumes of the same class are bound to the claim.
+	// +optional
+	StorageClassName string `json:"storageClassName,omitempty"`
+}
+
+// PersistentVolumeClaimStatus is the current status of a persistent volume claim.
+type PersistentVolumeClaimStatus struct {
+	// phase represents the current phase of PersistentVolumeClaim.
+	// +optional
+	Phase PersistentVolumeClaimPhase `json:"phase,omitempty"`
+	// accessModes contains the actual access modes the volume backing the PVC has.
+	// +optional
+	AccessModes []PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+	// capacity represents the actual resources of the underlying volume.
+	// +optional
+	Capacity ResourceList `json:"capacity,omitempty"`
+}
+
+type PersistentVolumeClaimPhase string
+
+const (
+	// ClaimPending used for PersistentVolumeClaims that are not yet bound
+	ClaimPending PersistentVolumeClaimPhase = "Pending"
+	// ClaimBound used for PersistentVolumeClaims that are bound
+	ClaimBound PersistentVolumeClaimPhase = "Bound"
+	// ClaimLost used for PersistentVolumeClaims that lost their underlying
+	// PersistentVolume. The claim was bound to a PersistentVolume and this
+	// volume does not exist any longer and all data on it was lost.
+	ClaimLost PersistentVolumeClaimPhase = "Lost"
+)
+
+func (pvc *PersistentVolumeClaim) PrintBrief() {
+	fmt.Printf("%-20s\t%-10s\t%-20s\t%-10s\t%-20s\t%-15s\n", "NAME", "STATUS", "VOLUME", "CAPACITY", "ACCESS MODES", "STORAGECLASS")
+	pvc.printRow()
+}
+
+func (pvc *PersistentVolumeClaim) printRow() {
+	fmt.Printf("%-20s\t%-10s\t%-20s\t%-10s\t%-20s\t%-15s\n", pvc.Name, pvc.Status.Phase, pvc.Spec.VolumeName,
+		pvc.Status.Capacity[types.ResourceStorage], formatAccessModes(pvc.Status.AccessModes), pvc.Spec.StorageClassName)
+}
+
+func (pvc *PersistentVolumeClaim) SetUID(uid types.UID) {
+	pvc.ObjectMeta.UID = uid
+}
+
+func (pvc *PersistentVolumeClaim) GetUID() types.UID {
+	return pvc.ObjectMeta.UID
+}
+
+func (pvc *PersistentVolumeClaim) JsonUnmarshal(data []byte) error {
+	return json.Unmarshal(data, &pvc)
+}
+
+func (pvc *PersistentVolumeClaim) JsonMarshal() ([]byte, error) {
+	return json.Marshal(pvc)
+}
+
+func (pvc *PersistentVolumeClaim) JsonUnmarshalStatus(data []byte) error {
+	return json.Unmarshal(data, &(pvc.Status))
+}
+
+func (pvc *PersistentVolumeClaim) JsonMarshalStatus() ([]byte, error) {
+	return json.Marshal(pvc.Status)
+}
+
+func (pvc *PersistentVolumeClaim) SetStatus(s IApiObjectStatus) bool {
+	status, ok := s.(*PersistentVolumeClaimStatus)
+	if ok {
+		pvc.Status = *status
+	}
+	return ok
+}
+
+func (pvc *PersistentVolumeClaim) GetStatus() IApiObjectStatus {
+	return &pvc.Status
+}
+
+func (pvc *PersistentVolumeClaim) GetResourceVersion() string {
+	return pvc.ObjectMeta.ResourceVersion
+}
+
+func (pvc *PersistentVolumeClaim) SetResourceVersion(version string) {
+	pvc.ObjectMeta.ResourceVersion = version
+}
+
+func (pvc *PersistentVolumeClaim) CreateFromEtcdString(str string) error {
+	return pvc.JsonUnmarshal([]byte(str))
+}
+
+func (pvc *PersistentVolumeClaim) GenerateOwnerReference() meta.OwnerReference {
+	return meta.OwnerReference{
+		APIVersion: pvc.APIVersion,
+		Kind:       pvc.Kind,
+		Name:       pvc.Name,
+		UID:        pvc.UID,
+		Controller: false,
+	}
+}
+
+func (pvc *PersistentVolumeClaim) AppendOwnerReference(reference meta.OwnerReference) {
+	pvc.OwnerReferences = append(pvc.OwnerReferences, reference)
+}
+
+func (pvc *PersistentVolumeClaim) DeleteOwnerReference(uid types.UID) {
+	has := false
+	idx := 0
+	for i, o := range pvc.OwnerReferences {
+		if o.UID == uid {
+			has = true
+			idx = i
+			break
+		}
+	}
+	if has {
+		pvc.OwnerReferences = append(pvc.OwnerReferences[:idx], pvc.OwnerReferences[idx+1:]...)
+	}
+}
+
+func (s *PersistentVolumeClaimStatus) JsonUnmarshal(data []byte) error {
+	return json.Unmarshal(data, &s)
+}
+
+func (s *PersistentVolumeClaimStatus) JsonMarshal() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// PersistentVolumeClaimList is a list of PersistentVolumeClaim items.
+type PersistentVolumeClaimList struct {
+	meta.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// +optional
+	meta.ListMeta `json:"metadata,omitempty"`
+
+	// items is a list of persistent volume claims.
+	Items []PersistentVolumeClaim `json:"items"`
+}
+
+func (l *PersistentVolumeClaimList) PrintBrief() {
+	fmt.Printf("%-20s\t%-10s\t%-20s\t%-10s\t%-20s\t%-15s\n", "NAME", "STATUS", "VOLUME", "CAPACITY", "ACCESS MODES", "STORAGECLASS")
+	for _, item := range l.Items {
+		item.printRow()
+	}
+}
+
+func (l *PersistentVolumeClaimList) JsonUnmarshal(data []byte) error {
+	return json.Unmarshal(data, &l)
+}
+
+func (l *PersistentVolumeClaimList) JsonMarshal() ([]byte, error) {
+	return json.Marshal(l)
+}
+
+func (l *PersistentVolumeClaimList) AddItemFromStr(objectStr string) error {
+	object := &PersistentVolumeClaim{}
+	buf, err := strconv.Unquote(objectStr)
+	err = object.JsonUnmarshal([]byte(buf))
+	if err != nil {
+		return err
+	}
+	l.Items = append(l.Items, *object)
+	return nil
+}
+
+func (l *PersistentVolumeClaimList) AppendItemsFromStr(objectStrs []string) error {
+	for _, obj := range objectStrs {
+		object := &PersistentVolumeClaim{}
+		err := object.JsonUnmarshal([]byte(obj))
+		if err != nil {
+			return err
+		}
+		l.Items = append(l.Items, *object)
+	}
+	return nil
+}
+
+func (l *PersistentVolumeClaimList) GetItems() any {
+	return l.Items
+}
+
+func (l *PersistentVolumeClaimList) GetIApiObjectArr() (res []IApiObject) {
+	for _, item := range l.Items {
+		itemTemp := item
+		res = append(res, &itemTemp)
+	}
+	return res
+}
+
+// StorageRequest returns the parsed storage requested by claim
+func (pvc *PersistentVolumeClaim) StorageRequest() (uint64, error) {
+	return types.ParseQuantity(types.ResourceStorage, pvc.Spec.Resources.Requests[types.ResourceStorage])
+}
+
+// IsBindingDelayed returns true if binding of claim waits for the first pod
+// using it to be scheduled, which is the case of local-path claims
+func (pvc *PersistentVolumeClaim) IsBindingDelayed() bool {
+	return pvc.Spec.StorageClassName == LocalPathStorageClassName
+}
@@ -6,6 +6,7 @@ import (
 	"minik8s/pkg/api/meta"
 	"minik8s/pkg/api/types"
 	"strconv"
+	"time"
 )
 
 // Pod is a collection of containers that can run on a host. This resource is created
@@ -32,8 +33,27 @@ type Pod struct {
 }
 
 func (p *Pod) PrintBrief() {
-	fmt.Printf("%-20s\t%-40s\t%-8s\t%-15s\t%-15s\n", "NAME", "UID", "NODE", "STATUS", "IP")
-	fmt.Printf("%-20s\t%-40s\t%-8s\t%-15s\t%-15s\n", p.Name, p.UID, p.Spec.NodeName, p.Status.Phase, p.Status.PodIP)
+	fmt.Printf("%-20s\t%-40s\t%-8s\t%-18s\t%-8s\t%-15s\n", "NAME", "UID", "NODE", "STATUS", "RESTARTS", "IP")
+	fmt.Printf("%-20s\t%-40s\t%-8s\t%-18s\t%-8d\t%-15s\n", p.Name, p.UID, p.Spec.NodeName, p.displayStatus(), p.restartCount(), p.Status.PodIP)
+}
+
+// displayStatus is the waiting reason of container if any, e.g. CrashLoopBackOff,
+// otherwise the phase of pod
+func (p *Pod) displayStatus() string {
+	for _, cs := range p.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			return cs.State.Waiting.Reason
+		}
+	}
+	return string(p.Status.Phase)
+}
+
+func (p *Pod) restartCount() int32 {
+	var count int32 = 0
+	for _, cs := range p.Status.ContainerStatuses {
+		count += cs.RestartCount
+	}
+	return count
 }
 
 func (p *Pod) DeleteOwnerReference(uid types.UID) {
@@ -150,6 +170,13 @@ type PodSpec struct {
 	// More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle/#restart-policy
 	// +optional
 	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty" protobuf:"bytes,3,opt,name=restartPolicy,casttype=RestartPolicy"`
+	// Optional duration in seconds the pod needs to terminate gracefully.
+	// The grace period is the duration in seconds after the processes running in the pod are sent
+	// a termination signal and the time when the processes are forcibly halted with a kill signal.
+	// Set this value longer than the expected cleanup time for your process.
+	// Defaults to 30 seconds.
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty" protobuf:"varint,4,opt,name=terminationGracePeriodSeconds"`
 
 	// NodeName is a request to schedule this pod onto a specific node. If it is non-empty,
 	// the scheduler simply schedules this pod onto that node, assuming that it fits resource
@@ -166,6 +193,112 @@ type PodSpec struct {
 	// If specified, the pod's scheduling constraints
 	// +optional
 	Affinity *Affinity `json:"affinity,omitempty" protobuf:"bytes,18,opt,name=affinity"`
+
+	// If specified, the pod will be dispatched by specified scheduler profile.
+	// If not specified, the pod will be dispatched by default scheduler profile.
+	// Pods naming a scheduler that no running scheduler knows are left pending.
+	// +optional
+	SchedulerName string `json:"schedulerName,omitempty" protobuf:"bytes,19,opt,name=schedulerName"`
+
+	// TopologySpreadConstraints describes how a group of pods ought to spread across topology
+	// domains. Scheduler will schedule pods in a way which abides by the constraints.
+	// All topologySpreadConstraints are ANDed.
+	// +optional
+	TopologySpreadConstraints []TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty" protobuf:"bytes,33,opt,name=topologySpreadConstraints"`
+
+	// If specified, the pod's tolerations.
+	// +optional
+	Tolerations []Toleration `json:"tolerations,omitempty" protobuf:"bytes,22,opt,name=tolerations"`
+
+	// ImagePullSecrets is an optional list of references to secrets in the same namespace to use for pulling any of the images used by this PodSpec.
+	// If specified, these secrets will be passed to individual puller implementations for them to use.
+	// Secrets must be of type kubernetes.io/dockerconfigjson.
+	// More info: https://kubernetes.io/docs/concepts/containers/images#specifying-imagepullsecrets-on-a-pod
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	ImagePullSecrets []LocalObjectReference `json:"imagePullSecrets,omitempty" patchStrategy:"merge" patchMergeKey:"name" protobuf:"bytes,15,rep,name=imagePullSecrets"`
+
+	// The priority value. The higher the value, the higher the priority.
+	// Pods of the same QoS class with lower priority are evicted first when
+	// node is under resource pressure. Defaults to zero.
+	// +optional
+	Priority *int32 `json:"priority,omitempty" protobuf:"bytes,25,opt,name=priority"`
+}
+
+// DefaultSchedulerName is the name of the scheduler profile used
+// when PodSpec.SchedulerName is not specified
+const DefaultSchedulerName = "default-scheduler"
+
+// GetSchedulerName returns the scheduler profile name of the pod,
+// DefaultSchedulerName is returned when not specified
+func (p *PodSpec) GetSchedulerName() string {
+	if p.SchedulerName == "" {
+		return DefaultSchedulerName
+	}
+	return p.SchedulerName
+}
+
+// IsReady returns true if pod is running and its Ready condition is true,
+// meaning all containers pass their readiness probes
+func (p *Pod) IsReady() bool {
+	if p.Status.Phase != PodRunning {
+		return false
+	}
+	c := p.Status.GetCondition(PodReady)
+	return c != nil && c.Status == ConditionTrue
+}
+
+// IsTerminated returns true if all containers of pod have terminated and will
+// not be restarted, such pods use no resources of their node
+func (p *Pod) IsTerminated() bool {
+	return p.Status.Phase == PodSucceeded || p.Status.Phase == PodFailed
+}
+
+// ComputeResourceRequests returns resource requests of pod, parsed by
+// types.ParseQuantity. Limits of a container are used if its requests are
+// omitted, unrecognized quantities are ignored. Init containers run one by
+// one before app containers, so each resource is the larger of the maximum
+// of init containers and the sum of app containers, as kubelet enforces.
+func (p *Pod) ComputeResourceRequests() map[types.ResourceName]uint64 {
+	res := make(map[types.ResourceName]uint64)
+	for _, c := range p.Spec.Containers {
+		for name, v := range containerResourceRequests(c) {
+			res[name] += v
+		}
+	}
+	for _, c := range p.Spec.InitContainers {
+		for name, v := range containerResourceRequests(c) {
+			if v > res[name] {
+				res[name] = v
+			}
+		}
+	}
+	return res
+}
+
+func containerResourceRequests(c Container) map[types.ResourceName]uint64 {
+	res := make(map[types.ResourceName]uint64)
+	list := c.Resources.Requests
+	if len(list) == 0 {
+		list = c.Resources.Limits
+	}
+	for name, q := range list {
+		v, err := types.ParseQuantity(name, q)
+		if err != nil {
+			continue
+		}
+		res[name] = v
+	}
+	return res
+}
+
+// GetPriority returns priority of pod, zero if not specified
+func (p *PodSpec) GetPriority() int32 {
+	if p.Priority == nil {
+		return 0
+	}
+	return *p.Priority
 }
 
 // RestartPolicy describes how the container should be restarted.
@@ -206,6 +339,14 @@ type PodStatus struct {
 	// +optional
 	Phase PodPhase `json:"phase,omitempty" protobuf:"bytes,1,opt,name=phase,casttype=PodPhase"`
 
+	// A human readable message indicating details about why the pod is in this condition.
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,3,opt,name=message"`
+	// A brief CamelCase message indicating details about why the pod is in this state.
+	// e.g. 'Evicted'
+	// +optional
+	Reason string `json:"reason,omitempty" protobuf:"bytes,4,opt,name=reason"`
+
 	// IP address of the host to which the pod is assigned. Empty if not yet scheduled.
 	// +optional
 	HostIP string `json:"hostIP,omitempty" protobuf:"bytes,5,opt,name=hostIP"`
@@ -214,10 +355,157 @@ type PodStatus struct {
 	// +optional
 	PodIP string `json:"podIP,omitempty" protobuf:"bytes,6,opt,name=podIP"`
 
+	// RFC 3339 date and time at which the object was acknowledged by the Kubelet.
+	// This is before the Kubelet pulled the container image(s) for the pod.
+	// +optional
+	StartTime *types.Time `json:"startTime,omitempty" protobuf:"bytes,7,opt,name=startTime"`
+
+	// The list has one entry per init container in the manifest. The most recent successful
+	// init container will have ready = true, the most recently started container will have
+	// startTime set.
+	// More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#pod-and-container-status
+	InitContainerStatuses []ContainerStatus `json:"initContainerStatuses,omitempty" protobuf:"bytes,10,rep,name=initContainerStatuses"`
+
 	// The list has one entry per container in the manifest.
 	// More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#pod-and-container-status
 	// +optional
 	ContainerStatuses []ContainerStatus `json:"containerStatuses,omitempty" protobuf:"bytes,8,rep,name=containerStatuses"`
+
+	// Current service state of pod.
+	// More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#pod-conditions
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []PodCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,2,rep,name=conditions"`
+
+	// The Quality of Service (QOS) classification assigned to the pod based on resource requirements
+	// See PodQOSClass type for available QOS classes
+	// More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-qos/#quality-of-service-classes
+	// +optional
+	QOSClass PodQOSClass `json:"qosClass,omitempty" protobuf:"bytes,9,rep,name=qosClass"`
+}
+
+// PodReasonEvicted is the reason of pods evicted by kubelet under node pressure
+const PodReasonEvicted = "Evicted"
+
+// IsEvicted returns true if pod is evicted by kubelet, which is not started again
+func (p *Pod) IsEvicted() bool {
+	return p.Status.Phase == PodFailed && p.Status.Reason == PodReasonEvicted
+}
+
+// Annotations of static pods run by kubelet from manifest files, and of their
+// mirror pods published to ApiServer
+const (
+	// ConfigSourceAnnotationKey is where pod comes from, ConfigSourceFile for static pods
+	ConfigSourceAnnotationKey = "kubernetes.io/config.source"
+	ConfigSourceFile          = "file"
+	// ConfigHashAnnotationKey is hash of static pod, which is also its uid
+	ConfigHashAnnotationKey = "kubernetes.io/config.hash"
+	// ConfigMirrorAnnotationKey marks mirror pods, whose value is hash of static pod
+	ConfigMirrorAnnotationKey = "kubernetes.io/config.mirror"
+)
+
+// IsStaticPod returns true if pod is read from manifest file by kubelet
+func (p *Pod) IsStaticPod() bool {
+	return p.Annotations[ConfigSourceAnnotationKey] == ConfigSourceFile
+}
+
+// IsMirrorPod returns true if pod is mirror of a static pod, which is read-only
+// in ApiServer and not run by kubelet
+func (p *Pod) IsMirrorPod() bool {
+	_, found := p.Annotations[ConfigMirrorAnnotationKey]
+	return found
+}
+
+// PodQOSClass defines the supported qos classes of Pods.
+type PodQOSClass string
+
+const (
+	// PodQOSGuaranteed is the Guaranteed qos class.
+	PodQOSGuaranteed PodQOSClass = "Guaranteed"
+	// PodQOSBurstable is the Burstable qos class.
+	PodQOSBurstable PodQOSClass = "Burstable"
+	// PodQOSBestEffort is the BestEffort qos class.
+	PodQOSBestEffort PodQOSClass = "BestEffort"
+)
+
+// GetCondition returns the condition of conditionType, nil if not found
+func (p *PodStatus) GetCondition(conditionType PodConditionType) *PodCondition {
+	for i := range p.Conditions {
+		if p.Conditions[i].Type == conditionType {
+			return &p.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition sets condition of its type, transition time of condition is kept if
+// its status is not changed, and now otherwise. Conditions are copied before set,
+// so that statuses sharing them are not changed
+func (p *PodStatus) SetCondition(condition PodCondition) {
+	conditions := make([]PodCondition, 0, len(p.Conditions)+1)
+	found := false
+	for _, c := range p.Conditions {
+		if c.Type != condition.Type {
+			conditions = append(conditions, c)
+			continue
+		}
+		found = true
+		if c.Status == condition.Status {
+			condition.LastTransitionTime = c.LastTransitionTime
+		} else {
+			condition.LastTransitionTime = time.Now()
+		}
+		conditions = append(conditions, condition)
+	}
+	if !found {
+		condition.LastTransitionTime = time.Now()
+		conditions = append(conditions, condition)
+	}
+	p.Conditions = conditions
+}
+
+// PodConditionType is a valid value for PodCondition.Type
+type PodConditionType string
+
+// These are valid conditions of pod.
+const (
+	// PodScheduled represents status of the scheduling process for this pod.
+	PodScheduled PodConditionType = "PodScheduled"
+	// PodInitialized means that all init containers in the pod have started successfully.
+	PodInitialized PodConditionType = "Initialized"
+	// ContainersReady indicates whether all containers in the pod are ready.
+	ContainersReady PodConditionType = "ContainersReady"
+	// PodReady means the pod is able to service requests and should be added to the
+	// load balancing pools of all matching services.
+	PodReady PodConditionType = "Ready"
+)
+
+// PodReasonUnschedulable is reason of PodScheduled condition false, which
+// means the scheduler can not schedule the pod right now
+const PodReasonUnschedulable = "Unschedulable"
+
+// PodCondition contains details for the current condition of this pod.
+type PodCondition struct {
+	// Type is the type of the condition.
+	// More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#pod-conditions
+	Type PodConditionType `json:"type" protobuf:"bytes,1,opt,name=type,casttype=PodConditionType"`
+	// Status is the status of the condition.
+	// Can be True, False, Unknown.
+	// More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#pod-conditions
+	Status ConditionStatus `json:"status" protobuf:"bytes,2,opt,name=status,casttype=ConditionStatus"`
+	// Last time we probed the condition.
+	// +optional
+	LastProbeTime types.Time `json:"lastProbeTime,omitempty" protobuf:"bytes,3,opt,name=lastProbeTime"`
+	// Last time the condition transitioned from one status to another.
+	// +optional
+	LastTransitionTime types.Time `json:"lastTransitionTime,omitempty" protobuf:"bytes,4,opt,name=lastTransitionTime"`
+	// Unique, one-word, CamelCase reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty" protobuf:"bytes,5,opt,name=reason"`
+	// Human-readable message indicating details about last transition.
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,6,opt,name=message"`
 }
 
 func DefaultPosStatus() PodStatus {
@@ -289,9 +577,9 @@ type PodList struct {
 }
 
 func (p *PodList) PrintBrief() {
-	fmt.Printf("%-20s\t%-40s\t%-8s\t%-15s\t%-15s\n", "NAME", "UID", "NODE", "STATUS", "IP")
+	fmt.Printf("%-20s\t%-40s\t%-8s\t%-18s\t%-8s\t%-15s\n", "NAME", "UID", "NODE", "STATUS", "RESTARTS", "IP")
 	for _, item := range p.Items {
-		fmt.Printf("%-20s\t%-40s\t%-8s\t%-15s\t%-15s\n", item.Name, item.UID, item.Spec.NodeName, item.Status.Phase, item.Status.PodIP)
+		fmt.Printf("%-20s\t%-40s\t%-8s\t%-18s\t%-8d\t%-15s\n", item.Name, item.UID, item.Spec.NodeName, item.displayStatus(), item.restartCount(), item.Status.PodIP)
 	}
 }
 
@@ -369,3 +657,108 @@ type PodAffinityTerm struct {
 	// Empty topologyKey is not allowed.
 	TopologyKey string `json:"topologyKey" protobuf:"bytes,3,opt,name=topologyKey"`
 }
+
+// Toleration is attached to a pod to tolerate any taint that matches
+// the triple <key,value,effect> using the matching operator <operator>.
+type Toleration struct {
+	// Key is the taint key that the toleration applies to. Empty means match all taint keys.
+	// If the key is empty, operator must be Exists; this combination means to match all values and all keys.
+	// +optional
+	Key string `json:"key,omitempty" protobuf:"bytes,1,opt,name=key"`
+	// Operator represents a key's relationship to the value.
+	// Valid operators are Exists and Equal. Defaults to Equal.
+	// Exists is equivalent to wildcard for value, so that a pod can
+	// tolerate all taints of a particular category.
+	// +optional
+	Operator TolerationOperator `json:"operator,omitempty" protobuf:"bytes,2,opt,name=operator,casttype=TolerationOperator"`
+	// Value is the taint value the toleration matches to.
+	// If the operator is Exists, the value should be empty, otherwise just a regular string.
+	// +optional
+	Value string `json:"value,omitempty" protobuf:"bytes,3,opt,name=value"`
+	// Effect indicates the taint effect to match. Empty means match all taint effects.
+	// +optional
+	Effect TaintEffect `json:"effect,omitempty" protobuf:"bytes,4,opt,name=effect,casttype=TaintEffect"`
+	// TolerationSeconds represents the period of time the toleration (which must be
+	// of effect NoExecute, otherwise this field is ignored) tolerates the taint. By default,
+	// it is not set, which means tolerate the taint forever (do not evict).
+	// +optional
+	TolerationSeconds *int64 `json:"tolerationSeconds,omitempty" protobuf:"varint,5,opt,name=tolerationSeconds"`
+}
+
+// ToleratesTaint checks if the toleration tolerates the taint.
+func (t *Toleration) ToleratesTaint(taint *Taint) bool {
+	if len(t.Effect) > 0 && t.Effect != taint.Effect {
+		return false
+	}
+	if len(t.Key) > 0 && t.Key != taint.Key {
+		return false
+	}
+	switch t.Operator {
+	// empty operator means Equal
+	case "", TolerationOpEqual:
+		return t.Value == taint.Value
+	case TolerationOpExists:
+		return true
+	default:
+		return false
+	}
+}
+
+// FindMatchingToleration returns the toleration of pod tolerating taint, nil if not tolerated
+func (p *PodSpec) FindMatchingToleration(taint *Taint) *Toleration {
+	for i := range p.Tolerations {
+		if p.Tolerations[i].ToleratesTaint(taint) {
+			return &p.Tolerations[i]
+		}
+	}
+	return nil
+}
+
+type TolerationOperator string
+
+const (
+	TolerationOpExists TolerationOperator = "Exists"
+	TolerationOpEqual  TolerationOperator = "Equal"
+)
+
+type UnsatisfiableConstraintAction string
+
+const (
+	// DoNotSchedule instructs the scheduler not to schedule the pod
+	// when constraints are not satisfied.
+	DoNotSchedule UnsatisfiableConstraintAction = "DoNotSchedule"
+	// ScheduleAnyway instructs the scheduler to schedule the pod
+	// even if constraints are not satisfied.
+	ScheduleAnyway UnsatisfiableConstraintAction = "ScheduleAnyway"
+)
+
+// TopologySpreadConstraint specifies how to spread matching pods among the given topology.
+type TopologySpreadConstraint struct {
+	// MaxSkew describes the degree to which pods may be unevenly distributed.
+	// It's the maximum permitted difference between the number of matching pods in
+	// any two topology domains of a given topology type.
+	// For example, in a 3-zone cluster, MaxSkew is set to 1, and pods with the same
+	// labelSelector spread as 1/1/0, the incoming pod can only be scheduled to zone3
+	// to become 1/1/1; scheduling it onto zone1(zone2) would make the ActualSkew(2-0)
+	// on zone1(zone2) violate MaxSkew(1).
+	// It's a required field. Default value is 1 and 0 is not allowed.
+	MaxSkew int32 `json:"maxSkew" protobuf:"varint,1,opt,name=maxSkew"`
+	// TopologyKey is the key of node labels. Nodes that have a label with this key
+	// and identical values are considered to be in the same topology.
+	// We consider each <key, value> as a "bucket", and try to put balanced number
+	// of pods into each bucket.
+	// It's a required field.
+	TopologyKey string `json:"topologyKey" protobuf:"bytes,2,opt,name=topologyKey"`
+	// WhenUnsatisfiable indicates how to deal with a pod if it doesn't satisfy
+	// the spread constraint.
+	// - DoNotSchedule (default) tells the scheduler not to schedule it.
+	// - ScheduleAnyway tells the scheduler to schedule the pod in any location,
+	//   but giving higher precedence to topologies that would help reduce the skew.
+	// +optional
+	WhenUnsatisfiable UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty" protobuf:"bytes,3,opt,name=whenUnsatisfiable,casttype=UnsatisfiableConstraintAction"`
+	// LabelSelector is used to find matching pods.
+	// Pods that match this label selector are counted to determine the number of pods
+	// in their corresponding topology domain.
+	// +optional
+	LabelSelector *meta.LabelSelector `json:"labelSelector,omitempty" protobuf:"bytes,4,opt,name=labelSelector"`
+}
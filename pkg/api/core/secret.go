@@ -0,0 +1,233 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"minik8s/pkg/api/meta"
+	"minik8s/pkg/api/types"
+	"strconv"
+)
+
+// Secret holds secret data of a certain type. The total bytes of the values in
+// the Data field must be less than MaxSecretSize bytes.
+// It is stored and referenced by name.
+type Secret struct {
+	meta.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	meta.ObjectMeta `json:"metadata,omitempty"`
+
+	// Data contains the secret data. Each key must consist of alphanumeric
+	// characters, '-', '_' or '.'. The serialized form of the secret data is a
+	// base64 encoded string, representing the arbitrary (possibly non-string)
+	// data value here.
+	// +optional
+	Data map[string][]byte `json:"data,omitempty"`
+
+	// stringData allows specifying non-binary secret data in string form.
+	// It is provided as a write-only input field for convenience.
+	// All keys and values are merged into the data field on write, overwriting any existing values.
+	// The stringData field is never output when reading from the API.
+	// +optional
+	StringData map[string]string `json:"stringData,omitempty"`
+
+	// Used to facilitate programmatic handling of secret data.
+	// +optional
+	Type SecretType `json:"type,omitempty"`
+}
+
+// MaxSecretSize is the max total bytes of values of a Secret
+const MaxSecretSize = 1 * 1024 * 1024
+
+type SecretType string
+
+const (
+	// SecretTypeOpaque is the default. Arbitrary user-defined data
+	SecretTypeOpaque SecretType = "Opaque"
+
+	// SecretTypeDockerConfigJson contains a dockercfg file that follows the same format rules as ~/.docker/config.json
+	//
+	// Required fields:
+	// - Secret.Data[".dockerconfigjson"] - a serialized ~/.docker/config.json file
+	SecretTypeDockerConfigJson SecretType = "kubernetes.io/dockerconfigjson"
+
+	// DockerConfigJsonKey is the key of the required data for SecretTypeDockerConfigJson secrets
+	DockerConfigJsonKey = ".dockerconfigjson"
+)
+
+// MergeStringData merges StringData into Data and clears it, it is called by
+// api server on write so that StringData is never stored
+func (s *Secret) MergeStringData() {
+	if len(s.StringData) != 0 && s.Data == nil {
+		s.Data = make(map[string][]byte, len(s.StringData))
+	}
+	for key, value := range s.StringData {
+		s.Data[key] = []byte(value)
+	}
+	s.StringData = nil
+	if s.Type == "" {
+		s.Type = SecretTypeOpaque
+	}
+}
+
+// Size returns total bytes of values in Data
+func (s *Secret) Size() int {
+	size := 0
+	for _, value := range s.Data {
+		size += len(value)
+	}
+	return size
+}
+
+// SecretStatus is empty, Secret has no status
+type SecretStatus struct{}
+
+func (s *SecretStatus) JsonUnmarshal(data []byte) error {
+	return json.Unmarshal(data, &s)
+}
+
+func (s *SecretStatus) JsonMarshal() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+func (s *Secret) PrintBrief() {
+	fmt.Printf("%-20s\t%-40s\t%-10s\t%-10s\n", "NAME", "UID", "TYPE", "DATA")
+	fmt.Printf("%-20s\t%-40s\t%-10s\t%-10d\n", s.Name, s.UID, s.Type, len(s.Data))
+}
+
+func (s *Secret) SetUID(uid types.UID) {
+	s.ObjectMeta.UID = uid
+}
+
+func (s *Secret) GetUID() types.UID {
+	return s.ObjectMeta.UID
+}
+
+func (s *Secret) JsonUnmarshal(data []byte) error {
+	return json.Unmarshal(data, &s)
+}
+
+func (s *Secret) JsonMarshal() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+func (s *Secret) JsonUnmarshalStatus(data []byte) error {
+	return nil
+}
+
+func (s *Secret) JsonMarshalStatus() ([]byte, error) {
+	return json.Marshal(SecretStatus{})
+}
+
+func (s *Secret) SetStatus(status IApiObjectStatus) bool {
+	_, ok := status.(*SecretStatus)
+	return ok
+}
+
+func (s *Secret) GetStatus() IApiObjectStatus {
+	return &SecretStatus{}
+}
+
+func (s *Secret) GetResourceVersion() string {
+	return s.ObjectMeta.ResourceVersion
+}
+
+func (s *Secret) SetResourceVersion(version string) {
+	s.ObjectMeta.ResourceVersion = version
+}
+
+func (s *Secret) CreateFromEtcdString(str string) error {
+	return s.JsonUnmarshal([]byte(str))
+}
+
+func (s *Secret) GenerateOwnerReference() meta.OwnerReference {
+	return meta.OwnerReference{
+		APIVersion: s.APIVersion,
+		Kind:       s.Kind,
+		Name:       s.Name,
+		UID:        s.UID,
+		Controller: false,
+	}
+}
+
+func (s *Secret) AppendOwnerReference(reference meta.OwnerReference) {
+	s.OwnerReferences = append(s.OwnerReferences, reference)
+}
+
+func (s *Secret) DeleteOwnerReference(uid types.UID) {
+	has := false
+	idx := 0
+	for i, o := range s.OwnerReferences {
+		if o.UID == uid {
+			has = true
+			idx = i
+			break
+		}
+	}
+	if has {
+		s.OwnerReferences = append(s.OwnerReferences[:idx], s.OwnerReferences[idx+1:]...)
+	}
+}
+
+// SecretList is a list of Secret.
+type SecretList struct {
+	meta.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	// +optional
+	meta.ListMeta `json:"metadata,omitempty"`
+
+	// Items is a list of secret objects.
+	Items []Secret `json:"items"`
+}
+
+func (s *SecretList) PrintBrief() {
+	fmt.Printf("%-20s\t%-40s\t%-10s\t%-10s\n", "NAME", "UID", "TYPE", "DATA")
+	for _, item := range s.Items {
+		fmt.Printf("%-20s\t%-40s\t%-10s\t%-10d\n", item.Name, item.UID, item.Type, len(item.Data))
+	}
+}
+
+func (s *SecretList) JsonUnmarshal(data []byte) error {
+	return json.Unmarshal(data, &s)
+}
+
+func (s *SecretList) JsonMarshal() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+func (s *SecretList) AddItemFromStr(objectStr string) error {
+	object := &Secret{}
+	buf, err := strconv.Unquote(objectStr)
+	err = object.JsonUnmarshal([]byte(buf))
+	if err != nil {
+		return err
+	}
+	s.Items = append(s.Items, *object)
+	return nil
+}
+
+func (s *SecretList) AppendItemsFromStr(objectStrs []string) error {
+	for _, obj := range objectStrs {
+		object := &Secret{}
+		err := object.JsonUnmarshal([]byte(obj))
+		if err != nil {
+			return err
+		}
+		s.Items = append(s.Items, *object)
+	}
+	return nil
+}
+
+func (s *SecretList) GetItems() any {
+	return s.Items
+}
+
+func (s *SecretList) GetIApiObjectArr() (res []IApiObject) {
+	for _, item := range s.Items {
+		itemTemp := item
+		res = append(res, &itemTemp)
+	}
+	return res
+}
@@ -36,6 +36,17 @@ func (s *Service) PrintBrief() {
 	fmt.Printf("%-20s\t%-40s\t%-8s\t%-15s\n", s.Name, s.UID, s.Spec.Type, s.Spec.ClusterIP)
 }
 
+// SelectsPod returns true if any label of pod is selected by service,
+// such pods are endpoints of service once they are ready
+func (s *Service) SelectsPod(pod *Pod) bool {
+	for label, val := range pod.Labels {
+		if v, f := s.Spec.Selector[label]; f && v == val {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Service) DeleteOwnerReference(uid types.UID) {
 	has := false
 	idx := 0
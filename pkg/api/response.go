@@ -79,3 +79,12 @@ func (r *PutResponse) FillResponse(resp *http.Response) error {
 type DeleteResponse struct {
 	Response `json:",inline"`
 }
+
+type EvictionResponse struct {
+	Response `json:",inline"`
+}
+
+type ReencryptResponse struct {
+	Response    `json:",inline"`
+	Reencrypted int `json:"reencrypted"`
+}
@@ -34,12 +34,16 @@ func ParseQuantity(name ResourceName, q Quantity) (uint64, error) {
 		} else if suf == "" {
 			return strconv.ParseUint(value+"000", 10, 64)
 		}
-	case ResourceMemory:
+	case ResourceMemory, ResourceStorage, ResourceEphemeralStorage:
 		if suf == "m" || suf == "M" {
 			return strconv.ParseUint(value, 10, 64)
 		} else if suf == "" {
 			return strconv.ParseUint(value, 10, 64)
 		}
+	case ResourcePods:
+		if suf == "" {
+			return strconv.ParseUint(value, 10, 64)
+		}
 	default:
 		return 0, errors.New(fmt.Sprintf("resource type %v unsupported", name))
 	}
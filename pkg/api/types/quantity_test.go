@@ -53,6 +53,21 @@ func TestParseQuantity(t *testing.T) {
 			args: args{name: ResourceMemory, q: "300m"},
 			want: 300,
 		},
+		{
+			name: "success9",
+			args: args{name: ResourceEphemeralStorage, q: "2048M"},
+			want: 2048,
+		},
+		{
+			name: "success10",
+			args: args{name: ResourcePods, q: "110"},
+			want: 110,
+		},
+		{
+			name:    "fail1",
+			args:    args{name: ResourcePods, q: "110M"},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
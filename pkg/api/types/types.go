@@ -22,6 +22,12 @@ const (
 	HeartbeatObjectType               ApiObjectType = "Heartbeat"
 	FuncTemplateObjectType            ApiObjectType = "Func"
 	DnsObjectType                     ApiObjectType = "DNS"
+	PodDisruptionBudgetObjectType     ApiObjectType = "PodDisruptionBudget"
+	ConfigMapObjectType               ApiObjectType = "ConfigMap"
+	SecretObjectType                  ApiObjectType = "Secret"
+	PersistentVolumeObjectType        ApiObjectType = "PersistentVolume"
+	PersistentVolumeClaimObjectType   ApiObjectType = "PersistentVolumeClaim"
+	EventObjectType                   ApiObjectType = "Event"
 )
 
 // ResourceName is the name identifying various resources in a ResourceList.
@@ -42,4 +48,6 @@ const (
 	// ResourceEphemeralStorage Local ephemeral storage, in bytes. (500Gi = 500GiB = 500 * 1024 * 1024 * 1024)
 	// The resource name for ResourceEphemeralStorage is alpha and it can change across releases.
 	ResourceEphemeralStorage ResourceName = "ephemeral-storage"
+	// ResourcePods Number of Pods
+	ResourcePods ResourceName = "pods"
 )
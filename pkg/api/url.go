@@ -1,6 +1,11 @@
 package api
 
 const StatusSuffix = "/status"
+const EvictionSuffix = "/eviction"
+const LogSuffix = "/log"
+const ExecSuffix = "/exec"
+const AttachSuffix = "/attach"
+const PortForwardSuffix = "/portforward"
 
 // Clear all
 
@@ -16,6 +21,11 @@ const (
 	WatchPodsURL           = "/api/watch/pods/"
 	WatchPodURL            = "/api/watch/pods/:name"
 	PodStatusURL           = "/api/pods/:name/status"
+	PodEvictionURL         = "/api/pods/:name/eviction"
+	PodLogURL              = "/api/pods/:name/log"
+	PodExecURL             = "/api/pods/:name/exec"
+	PodAttachURL           = "/api/pods/:name/attach"
+	PodPortForwardURL      = "/api/pods/:name/portforward"
 	PodsOnSpecifiedNodeURL = "/api/pods/nodes/:node"
 )
 
@@ -82,6 +92,61 @@ const (
 	HeartbeatStatusURL = "/api/heartbeats/:name/status"
 )
 
+// PodDisruptionBudget
+const (
+	PodDisruptionBudgetsURL      = "/api/pdb/"
+	PodDisruptionBudgetURL       = "/api/pdb/:name"
+	WatchPodDisruptionBudgetsURL = "/api/watch/pdb/"
+	WatchPodDisruptionBudgetURL  = "/api/watch/pdb/:name"
+	PodDisruptionBudgetStatusURL = "/api/pdb/:name/status"
+)
+
+// ConfigMap, name here is configmap name, not uid
+const (
+	ConfigMapsURL      = "/api/configmaps/"
+	ConfigMapURL       = "/api/configmaps/:name"
+	WatchConfigMapsURL = "/api/watch/configmaps/"
+	WatchConfigMapURL  = "/api/watch/configmaps/:name"
+)
+
+// ReencryptURL rewrites objects of type not encrypted by the current key,
+// type here is ApiObjectType, e.g. Secret
+const ReencryptURL = "/api/reencrypt/:type"
+
+// Secret, name here is secret name, not uid
+const (
+	SecretsURL      = "/api/secrets/"
+	SecretURL       = "/api/secrets/:name"
+	WatchSecretsURL = "/api/watch/secrets/"
+	WatchSecretURL  = "/api/watch/secrets/:name"
+)
+
+// PersistentVolume, name here is volume name, not uid
+const (
+	PersistentVolumesURL      = "/api/persistentvolumes/"
+	PersistentVolumeURL       = "/api/persistentvolumes/:name"
+	WatchPersistentVolumesURL = "/api/watch/persistentvolumes/"
+	WatchPersistentVolumeURL  = "/api/watch/persistentvolumes/:name"
+	PersistentVolumeStatusURL = "/api/persistentvolumes/:name/status"
+)
+
+// PersistentVolumeClaim, name here is claim name, not uid
+const (
+	PersistentVolumeClaimsURL      = "/api/persistentvolumeclaims/"
+	PersistentVolumeClaimURL       = "/api/persistentvolumeclaims/:name"
+	WatchPersistentVolumeClaimsURL = "/api/watch/persistentvolumeclaims/"
+	WatchPersistentVolumeClaimURL  = "/api/watch/persistentvolumeclaims/:name"
+	PersistentVolumeClaimStatusURL = "/api/persistentvolumeclaims/:name/status"
+)
+
+// Event
+const (
+	EventsURL      = "/api/events/"
+	EventURL       = "/api/events/:name"
+	WatchEventsURL = "/api/watch/events/"
+	WatchEventURL  = "/api/watch/events/:name"
+)
+
 // Serverless
 const (
 	// FuncTemplate(s)URL Function Template
@@ -99,6 +164,44 @@ const (
 	ReturnPreservedName = "RETURN"
 )
 
+// ------------------ Kubelet API ---------------------
+
+// URLs served by kubelet on each node, pod here is pod uid
+const (
+	ContainerLogsPrefix   = "/containerLogs/"
+	ContainerLogsURL      = "/containerLogs/:pod/:container"
+	ContainerExecPrefix   = "/exec/"
+	ContainerExecURL      = "/exec/:pod/:container"
+	ContainerAttachPrefix = "/attach/"
+	ContainerAttachURL    = "/attach/:pod/:container"
+	PortForwardPrefix     = "/portForward/"
+	PortForwardURL        = "/portForward/:pod"
+)
+
+// Query parameters of container logs
+const (
+	LogContainerParam    = "container"
+	LogFollowParam       = "follow"
+	LogTailLinesParam    = "tailLines"
+	LogSinceSecondsParam = "sinceSeconds"
+	LogPreviousParam     = "previous"
+)
+
+// Query parameters of exec and attach, command is repeated for each argument,
+// connection is upgraded to websocket multiplexing streams requested
+const (
+	ExecContainerParam = "container"
+	ExecCommandParam   = "command"
+	ExecStdinParam     = "stdin"
+	ExecStdoutParam    = "stdout"
+	ExecStderrParam    = "stderr"
+	ExecTTYParam       = "tty"
+)
+
+// PortForwardPortParam is the port of pod to forward one connection to, data
+// of connection is sent on stdin and stdout channels of websocket
+const PortForwardPortParam = "port"
+
 // ------------------ Test API ---------------------
 
 const (
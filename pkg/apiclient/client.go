@@ -11,6 +11,7 @@ import (
 	httpclient "minik8s/pkg/apiclient/http"
 	"minik8s/pkg/logger"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -265,6 +266,70 @@ func (c *RESTClient) Delete(name string) (int, *api.DeleteResponse, error) {
 	}
 }
 
+// Evict begins a POST request to the eviction subresource, only Pod supports it.
+// StatusCode is http.StatusTooManyRequests if the eviction is refused because
+// of PodDisruptionBudget, caller may retry later.
+func (c *RESTClient) Evict(name string) (int, *api.EvictionResponse, error) {
+	resourceURL := c.URL() + name + api.EvictionSuffix
+
+	resp, err := httpclient.PostBytes(resourceURL, nil)
+	if err != nil {
+		logger.ApiClientLogger.Println("[RESTClient] http.Evict failed", err)
+		return HttpStatusNotSend, nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.ApiClientLogger.Println("[RESTClient] http.Evict body close failed", err)
+		}
+	}(resp.Body)
+
+	evictResp := &api.EvictionResponse{}
+	err = evictResp.FillResponse(resp)
+	if err != nil {
+		return resp.StatusCode, nil, err
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		return resp.StatusCode, evictResp, nil
+	} else {
+		logger.ApiClientLogger.Println("[RESTClient] http.Evict StatusCode not http.StatusOK, ", evictResp.ErrorMsg)
+		return resp.StatusCode, evictResp, errors.New("StatusCode not 200")
+	}
+}
+
+// Logs begins a GET request to the log subresource, only Pod supports it.
+// The returned body is plain text logs, which is streamed until container
+// stops if follow is set in query, caller must close it.
+func (c *RESTClient) Logs(name string, query url.Values) (io.ReadCloser, error) {
+	resourceURL := c.URL() + name + api.LogSuffix
+	if len(query) > 0 {
+		resourceURL += "?" + query.Encode()
+	}
+
+	resp, err := http.Get(resourceURL)
+	if err != nil {
+		logger.ApiClientLogger.Println("[RESTClient] http.Logs failed", err)
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp.Body, nil
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.ApiClientLogger.Println("[RESTClient] http.Logs body close failed", err)
+		}
+	}(resp.Body)
+
+	logsResp := &api.Response{}
+	err = logsResp.FillResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	return nil, errors.New(logsResp.ErrorMsg)
+}
+
 func (c *RESTClient) WatchAll() (watch.Interface, error) {
 	resourceURL := c.WatchURL()
 	resp, err := http.Get(resourceURL)
@@ -1,9 +1,11 @@
 package client
 
 import (
+	"io"
 	"minik8s/pkg/api"
 	"minik8s/pkg/api/core"
 	"minik8s/pkg/api/watch"
+	"net/url"
 )
 
 // Interface captures the set of operations for generically interacting with Kubernetes REST apis.
@@ -15,6 +17,8 @@ type Interface interface {
 	PutStatus(name string, object core.IApiObjectStatus) (int, *api.PutResponse, error)
 	GetAll() (objectList core.IApiObjectList, err error)
 	Delete(name string) (int, *api.DeleteResponse, error)
+	Evict(name string) (int, *api.EvictionResponse, error)
+	Logs(name string, query url.Values) (io.ReadCloser, error)
 	WatchAll() (watch.Interface, error)
 	Watch(name string) (watch.Interface, error)
 	URL() string
@@ -0,0 +1,34 @@
+package record
+
+import (
+	"fmt"
+	"minik8s/pkg/api/core"
+)
+
+// FakeRecorder is used as a fake during tests. It is thread safe. It is usable
+// when created manually and not by NewFakeRecorder, however all events may be
+// thrown away in this case.
+type FakeRecorder struct {
+	Events chan string
+}
+
+func (f *FakeRecorder) Event(object core.IApiObject, eventType, reason, message string) {
+	if f.Events != nil {
+		select {
+		case f.Events <- fmt.Sprintf("%s %s %s", eventType, reason, message):
+		default:
+		}
+	}
+}
+
+func (f *FakeRecorder) Eventf(object core.IApiObject, eventType, reason, messageFmt string, args ...interface{}) {
+	f.Event(object, eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// NewFakeRecorder creates new fake event recorder with event channel with
+// buffer of given size, events are dropped when the buffer is full
+func NewFakeRecorder(bufferSize int) *FakeRecorder {
+	return &FakeRecorder{
+		Events: make(chan string, bufferSize),
+	}
+}
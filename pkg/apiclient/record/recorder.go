@@ -0,0 +1,218 @@
+package record
+
+import (
+	"fmt"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+	"minik8s/pkg/apiclient"
+	client "minik8s/pkg/apiclient/interface"
+	"minik8s/pkg/logger"
+	"net/http"
+	"time"
+)
+
+const (
+	// maxQueuedEvents is the number of events waiting to be sent to ApiServer,
+	// events recorded when the queue is full are dropped
+	maxQueuedEvents = 1000
+	// maxCachedEvents is the number of events remembered for aggregation,
+	// the least recently seen one is forgotten when the cache is full
+	maxCachedEvents = 4096
+	// eventsBurst and eventsRefillInterval limit events of an object from a source,
+	// 25 events are recorded at once at most, then one every 5 minutes
+	eventsBurst          = 25
+	eventsRefillInterval = 5 * time.Minute
+)
+
+// EventRecorder records events of api objects to ApiServer, so that they can
+// be read by `kubectl get events` and `kubectl describe`. Recording does not
+// block, events are sent to ApiServer in background.
+type EventRecorder interface {
+	// Event records an event of object, eventType is core.EventTypeNormal or
+	// core.EventTypeWarning, reason is a short UpperCamelCase string, e.g.
+	// FailedScheduling, and message is a human-readable description.
+	Event(object core.IApiObject, eventType, reason, message string)
+
+	// Eventf is just like Event, but with Sprintf for the message field.
+	Eventf(object core.IApiObject, eventType, reason, messageFmt string, args ...interface{})
+}
+
+// recorder aggregates events of the same object, type, reason and message into
+// one Event with count increased, and limits events of an object by a token bucket
+type recorder struct {
+	client client.Interface
+	source core.EventSource
+	clock  func() time.Time
+	events chan *core.Event
+
+	// cache and buckets are only accessed by the goroutine sending events
+	cache   map[string]*core.Event
+	buckets map[string]*tokenBucket
+}
+
+// NewRecorder returns an EventRecorder of component running on host, host is
+// empty for components not bound to a node
+func NewRecorder(component, host string) EventRecorder {
+	cli, _ := apiclient.NewRESTClient(types.EventObjectType)
+	r := newRecorder(cli, core.EventSource{Component: component, Host: host})
+	go r.run()
+	return r
+}
+
+func newRecorder(cli client.Interface, source core.EventSource) *recorder {
+	return &recorder{
+		client:  cli,
+		source:  source,
+		clock:   time.Now,
+		events:  make(chan *core.Event, maxQueuedEvents),
+		cache:   make(map[string]*core.Event),
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+func (r *recorder) Event(object core.IApiObject, eventType, reason, message string) {
+	ref := core.GetObjectReference(object)
+	now := r.clock()
+	event := &core.Event{
+		InvolvedObject: ref,
+		Reason:         reason,
+		Message:        message,
+		Source:         r.source,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+		Type:           eventType,
+	}
+	event.Kind = string(types.EventObjectType)
+	event.Name = fmt.Sprintf("%v.%x", ref.Name, now.UnixNano())
+	event.Namespace = ref.Namespace
+
+	select {
+	case r.events <- event:
+	default:
+		logger.ApiClientLogger.Printf("[EventRecorder] queue full, drop event %v of %v %v: %v\n", reason, ref.Kind, ref.Name, message)
+	}
+}
+
+func (r *recorder) Eventf(object core.IApiObject, eventType, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *recorder) run() {
+	for event := range r.events {
+		r.record(event)
+	}
+}
+
+// record sends event to ApiServer, an event seen before is put with its count
+// increased instead of posted again
+func (r *recorder) record(event *core.Event) {
+	ref := event.InvolvedObject
+	if !r.allow(r.bucketKey(event)) {
+		logger.ApiClientLogger.Printf("[EventRecorder] too many events of %v %v, drop event %v\n", ref.Kind, ref.Name, event.Reason)
+		return
+	}
+
+	key := aggregateKey(event)
+	if cached, found := r.cache[key]; found {
+		update := *cached
+		update.Count++
+		update.LastTimestamp = event.LastTimestamp
+		code, resp, err := r.client.Put(update.UID, &update)
+		if err == nil {
+			if resp != nil {
+				update.ResourceVersion = resp.ResourceVersion
+			}
+			r.cache[key] = &update
+			return
+		}
+		if code != http.StatusNotFound && code != http.StatusConflict {
+			logger.ApiClientLogger.Printf("[EventRecorder] update event %v of %v %v failed, err: %v\n", event.Reason, ref.Kind, ref.Name, err)
+			return
+		}
+		// event is deleted or modified by others, record it again
+		delete(r.cache, key)
+	}
+
+	_, resp, err := r.client.Post(event)
+	if err != nil || resp == nil {
+		logger.ApiClientLogger.Printf("[EventRecorder] post event %v of %v %v failed, err: %v\n", event.Reason, ref.Kind, ref.Name, err)
+		return
+	}
+	event.UID = resp.UID
+	event.ResourceVersion = resp.ResourceVersion
+	r.forgetOldest()
+	r.cache[key] = event
+}
+
+// forgetOldest removes the least recently seen event when cache is full
+func (r *recorder) forgetOldest() {
+	if len(r.cache) < maxCachedEvents {
+		return
+	}
+	var oldestKey string
+	var oldest time.Time
+	for key, cached := range r.cache {
+		if oldestKey == "" || cached.LastTimestamp.Before(oldest) {
+			oldestKey, oldest = key, cached.LastTimestamp
+		}
+	}
+	delete(r.cache, oldestKey)
+}
+
+// aggregateKey returns key of events that are aggregated into one
+func aggregateKey(event *core.Event) string {
+	ref := event.InvolvedObject
+	return fmt.Sprintf("%v/%v/%v/%v/%v/%v/%v/%v/%v", event.Source.Component, event.Source.Host,
+		ref.Kind, ref.Namespace, ref.Name, ref.UID, event.Type, event.Reason, event.Message)
+}
+
+func (r *recorder) bucketKey(event *core.Event) string {
+	ref := event.InvolvedObject
+	return fmt.Sprintf("%v/%v/%v/%v/%v/%v", event.Source.Component, event.Source.Host, ref.Kind, ref.Namespace, ref.Name, ref.UID)
+}
+
+// tokenBucket holds tokens refilled one every eventsRefillInterval up to eventsBurst
+type tokenBucket struct {
+	tokens int
+	last   time.Time
+}
+
+// allow takes a token from bucket of key, returns false if there is none left
+func (r *recorder) allow(key string) bool {
+	now := r.clock()
+	bucket, found := r.buckets[key]
+	if !found {
+		r.forgetFullBuckets(now)
+		bucket = &tokenBucket{tokens: eventsBurst, last: now}
+		r.buckets[key] = bucket
+	}
+	if refilled := int(now.Sub(bucket.last) / eventsRefillInterval); refilled > 0 {
+		bucket.tokens += refilled
+		if bucket.tokens > eventsBurst {
+			bucket.tokens = eventsBurst
+		}
+		bucket.last = bucket.last.Add(time.Duration(refilled) * eventsRefillInterval)
+	}
+	if bucket.tokens == 0 {
+		return false
+	}
+	if bucket.tokens == eventsBurst {
+		bucket.last = now
+	}
+	bucket.tokens--
+	return true
+}
+
+// forgetFullBuckets removes buckets which are refilled to full when there are
+// too many of them, they are the same as new buckets
+func (r *recorder) forgetFullBuckets(now time.Time) {
+	if len(r.buckets) < maxCachedEvents {
+		return
+	}
+	for key, bucket := range r.buckets {
+		if bucket.tokens+int(now.Sub(bucket.last)/eventsRefillInterval) >= eventsBurst {
+			delete(r.buckets, key)
+		}
+	}
+}
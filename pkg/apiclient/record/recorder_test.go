@@ -0,0 +1,125 @@
+package record
+
+import (
+	"errors"
+	"fmt"
+	"minik8s/pkg/api"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/meta"
+	client "minik8s/pkg/apiclient/interface"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// fakeEventClient stores events posted and put by uid
+type fakeEventClient struct {
+	client.Interface
+	events map[string]core.Event
+	posts  int
+}
+
+func (f *fakeEventClient) Post(object core.IApiObject) (int, *api.PostResponse, error) {
+	f.posts++
+	event := *object.(*core.Event)
+	event.UID = fmt.Sprintf("event%d", f.posts)
+	f.events[event.UID] = event
+	return http.StatusOK, &api.PostResponse{UID: event.UID}, nil
+}
+
+func (f *fakeEventClient) Put(name string, object core.IApiObject) (int, *api.PutResponse, error) {
+	if _, found := f.events[name]; !found {
+		return http.StatusNotFound, nil, errors.New("StatusCode not 200")
+	}
+	f.events[name] = *object.(*core.Event)
+	return http.StatusOK, &api.PutResponse{}, nil
+}
+
+func newTestRecorder() (*recorder, *fakeEventClient, *time.Time) {
+	cli := &fakeEventClient{events: make(map[string]core.Event)}
+	r := newRecorder(cli, core.EventSource{Component: "kubelet", Host: "node1"})
+	now := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	r.clock = func() time.Time { return now }
+	return r, cli, &now
+}
+
+// flush records events queued
+func flush(r *recorder) {
+	for len(r.events) > 0 {
+		r.record(<-r.events)
+	}
+}
+
+func TestRecordAggregate(t *testing.T) {
+	r, cli, now := newTestRecorder()
+	pod := &core.Pod{ObjectMeta: meta.ObjectMeta{Name: "web", Namespace: "default", UID: "uid1"}}
+	other := &core.Pod{ObjectMeta: meta.ObjectMeta{Name: "db", Namespace: "default", UID: "uid2"}}
+
+	r.Event(pod, core.EventTypeWarning, "BackOff", "Back-off restarting failed container")
+	*now = now.Add(time.Second)
+	r.Event(pod, core.EventTypeWarning, "BackOff", "Back-off restarting failed container")
+	r.Eventf(pod, core.EventTypeNormal, "Pulled", "Container image %q already present on machine", "nginx")
+	r.Event(other, core.EventTypeWarning, "BackOff", "Back-off restarting failed container")
+	flush(r)
+
+	if len(cli.events) != 3 {
+		t.Fatalf("%d events are recorded, want 3", len(cli.events))
+	}
+	event := cli.events["event1"]
+	if event.Count != 2 || !event.LastTimestamp.Equal(*now) || !event.FirstTimestamp.Equal(now.Add(-time.Second)) {
+		t.Errorf("aggregated event = count %d, first %v, last %v", event.Count, event.FirstTimestamp, event.LastTimestamp)
+	}
+	want := core.ObjectReference{Kind: "Pod", Namespace: "default", Name: "web", UID: "uid1"}
+	if event.InvolvedObject != want || event.Source.Host != "node1" || event.Namespace != "default" {
+		t.Errorf("event is about %+v from %+v, want %+v", event.InvolvedObject, event.Source, want)
+	}
+	if message := cli.events["event2"].Message; message != `Container image "nginx" already present on machine` {
+		t.Errorf("message of event = %q", message)
+	}
+
+	// event deleted from ApiServer is posted again
+	delete(cli.events, "event1")
+	r.Event(pod, core.EventTypeWarning, "BackOff", "Back-off restarting failed container")
+	flush(r)
+	if event, found := cli.events["event4"]; !found || event.Count != 1 {
+		t.Errorf("deleted event is not posted again, events %v", cli.events)
+	}
+}
+
+func TestRecordRateLimit(t *testing.T) {
+	r, cli, now := newTestRecorder()
+	pod := &core.Pod{ObjectMeta: meta.ObjectMeta{Name: "web", UID: "uid1"}}
+	other := &core.Pod{ObjectMeta: meta.ObjectMeta{Name: "db", UID: "uid2"}}
+
+	count := func() int32 {
+		var sum int32
+		for _, event := range cli.events {
+			sum += event.Count
+		}
+		return sum
+	}
+	for i := 0; i < eventsBurst+10; i++ {
+		r.Eventf(pod, core.EventTypeWarning, "Unhealthy", "Liveness probe failed %d", i%3)
+	}
+	flush(r)
+	if count() != eventsBurst {
+		t.Errorf("%d events are recorded, want burst %d", count(), eventsBurst)
+	}
+
+	// events of other objects are not limited
+	r.Event(other, core.EventTypeWarning, "Unhealthy", "Liveness probe failed")
+	flush(r)
+	if count() != eventsBurst+1 {
+		t.Errorf("event of other object is limited")
+	}
+
+	// one token is refilled every interval
+	*now = now.Add(2*eventsRefillInterval + time.Second)
+	for i := 0; i < 5; i++ {
+		r.Event(pod, core.EventTypeWarning, "Unhealthy", "Liveness probe failed 0")
+	}
+	flush(r)
+	if count() != eventsBurst+3 {
+		t.Errorf("%d events are recorded after refilled, want %d", count(), eventsBurst+3)
+	}
+}
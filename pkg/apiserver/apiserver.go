@@ -3,6 +3,7 @@ package apiserver
 import (
 	"context"
 	"minik8s/config"
+	"minik8s/pkg/apiserver/encryption"
 	"minik8s/pkg/apiserver/etcd"
 	"minik8s/pkg/logger"
 )
@@ -28,6 +29,16 @@ func (a apiServer) Run(cancel context.CancelFunc) {
 	a.logger.Printf("[apiserver] apiserver init start\n")
 	defer a.logger.Printf("[apiserver] apiserver init finish\n")
 
+	// encryption of resources in etcd
+	transformers, err := encryption.LoadConfig(config.EncryptionProviderConfig())
+	if err != nil {
+		a.logger.Fatalf("[apiserver] load encryption configuration failed: %v\n", err)
+	}
+	for prefix := range transformers {
+		a.logger.Printf("[apiserver] values under %v are encrypted\n", prefix)
+	}
+	etcd.SetTransformers(transformers)
+
 	// etcd
 	etcd.Init()
 
@@ -0,0 +1,46 @@
+package encryption
+
+import (
+	"crypto/aes"
+	gocipher "crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// aesGCM encrypts with AES in GCM mode, the random nonce is stored before
+// the ciphertext, and etcd key is authenticated as additional data so that
+// values can not be swapped between keys
+type aesGCM struct {
+	aead gocipher.AEAD
+}
+
+func newAESGCM(secret []byte) (cipher, error) {
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		return nil, fmt.Errorf("aesgcm key must be 16, 24 or 32 bytes: %v", err)
+	}
+	aead, err := gocipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &aesGCM{aead: aead}, nil
+}
+
+func (a *aesGCM) encrypt(key string, plain []byte) ([]byte, error) {
+	nonceSize := a.aead.NonceSize()
+	result := make([]byte, nonceSize, nonceSize+len(plain)+a.aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, result); err != nil {
+		return nil, err
+	}
+	return a.aead.Seal(result, result, plain, []byte(key)), nil
+}
+
+func (a *aesGCM) decrypt(key string, data []byte) ([]byte, error) {
+	nonceSize := a.aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errors.New("data is shorter than nonce")
+	}
+	return a.aead.Open(nil, data[:nonceSize], data[nonceSize:], []byte(key))
+}
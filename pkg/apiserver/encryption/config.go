@@ -0,0 +1,153 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/ghodss/yaml"
+	"os"
+)
+
+// EncryptionConfiguration configures how values of resources are encrypted
+// in etcd, resources not listed are stored as plain text. For example
+//
+//	resources:
+//	  - resources: ["secrets"]
+//	    providers:
+//	      - aesgcm:
+//	          keys:
+//	            - name: key2
+//	              secret: <base64 encoded 16, 24 or 32 bytes>
+//	            - name: key1
+//	              secret: <base64 encoded 16, 24 or 32 bytes>
+//	      - identity: {}
+//
+// The first key of the first provider encrypts new values, all keys are
+// used to decrypt values according to the key name stored with them.
+type EncryptionConfiguration struct {
+	Resources []ResourceConfiguration `json:"resources"`
+}
+
+// ResourceConfiguration configures providers of resources, resource is the
+// name in api url, e.g. secrets for /api/secrets/
+type ResourceConfiguration struct {
+	Resources []string                `json:"resources"`
+	Providers []ProviderConfiguration `json:"providers"`
+}
+
+// ProviderConfiguration is one of the providers
+type ProviderConfiguration struct {
+	AESGCM    *KeysConfiguration     `json:"aesgcm,omitempty"`
+	Secretbox *KeysConfiguration     `json:"secretbox,omitempty"`
+	Identity  *IdentityConfiguration `json:"identity,omitempty"`
+}
+
+type KeysConfiguration struct {
+	Keys []Key `json:"keys"`
+}
+
+// Key is a named encryption key, secret is base64 encoded
+type Key struct {
+	Name   string `json:"name"`
+	Secret string `json:"secret"`
+}
+
+// IdentityConfiguration stores values as plain text
+type IdentityConfiguration struct{}
+
+// LoadConfig reads encryption configuration at path, and returns transformers
+// keyed by etcd key prefix of resources. It returns nil if the file does not exist.
+func LoadConfig(path string) (map[string]Transformer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	config := &EncryptionConfiguration{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("parse encryption configuration %s failed: %v", path, err)
+	}
+	return NewTransformers(config)
+}
+
+// NewTransformers creates transformers keyed by etcd key prefix of resources
+func NewTransformers(config *EncryptionConfiguration) (map[string]Transformer, error) {
+	transformers := make(map[string]Transformer)
+	for _, resourceConfig := range config.Resources {
+		transformer, err := newTransformer(resourceConfig.Providers)
+		if err != nil {
+			return nil, fmt.Errorf("resources %v: %v", resourceConfig.Resources, err)
+		}
+		for _, resource := range resourceConfig.Resources {
+			prefix := ResourcePrefix(resource)
+			if _, found := transformers[prefix]; found {
+				return nil, fmt.Errorf("resource %s is configured more than once", resource)
+			}
+			transformers[prefix] = transformer
+		}
+	}
+	return transformers, nil
+}
+
+// ResourcePrefix returns the etcd key prefix of resource
+func ResourcePrefix(resource string) string {
+	return "/api/" + resource + "/"
+}
+
+func newTransformer(providers []ProviderConfiguration) (Transformer, error) {
+	if len(providers) == 0 {
+		return nil, errNoProvider
+	}
+	t := &prefixTransformer{}
+	names := make(map[string]bool)
+	for i, provider := range providers {
+		var keys []Key
+		var newCipher func([]byte) (cipher, error)
+		providerName := ""
+		count := 0
+		if provider.AESGCM != nil {
+			keys, newCipher, providerName = provider.AESGCM.Keys, newAESGCM, "aesgcm"
+			count++
+		}
+		if provider.Secretbox != nil {
+			keys, newCipher, providerName = provider.Secretbox.Keys, newSecretBox, "secretbox"
+			count++
+		}
+		if provider.Identity != nil {
+			count++
+		}
+		if count != 1 {
+			return nil, errors.New("each provider must be exactly one of aesgcm, secretbox and identity")
+		}
+
+		if provider.Identity != nil {
+			t.identity = true
+			t.writeIdentity = t.writeIdentity || i == 0
+			continue
+		}
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("%s provider has no key", providerName)
+		}
+		for _, key := range keys {
+			if key.Name == "" {
+				return nil, fmt.Errorf("%s key name is required", providerName)
+			}
+			if names[providerName+"/"+key.Name] {
+				return nil, fmt.Errorf("%s key %s is duplicated", providerName, key.Name)
+			}
+			names[providerName+"/"+key.Name] = true
+			secret, err := base64.StdEncoding.DecodeString(key.Secret)
+			if err != nil {
+				return nil, fmt.Errorf("%s key %s is not base64 encoded: %v", providerName, key.Name, err)
+			}
+			c, err := newCipher(secret)
+			if err != nil {
+				return nil, fmt.Errorf("%s key %s: %v", providerName, key.Name, err)
+			}
+			t.ciphers = append(t.ciphers, prefixedCipher{prefix: makePrefix(providerName, key.Name), cipher: c})
+		}
+	}
+	return t, nil
+}
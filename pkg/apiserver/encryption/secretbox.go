@@ -0,0 +1,49 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"errors"
+	"golang.org/x/crypto/nacl/secretbox"
+	"io"
+)
+
+const (
+	secretboxKeySize   = 32
+	secretboxNonceSize = 24
+)
+
+// secretBox encrypts with XSalsa20 and Poly1305, the random nonce is stored
+// before the ciphertext
+type secretBox struct {
+	key [secretboxKeySize]byte
+}
+
+func newSecretBox(secret []byte) (cipher, error) {
+	if len(secret) != secretboxKeySize {
+		return nil, errors.New("secretbox key must be 32 bytes")
+	}
+	s := &secretBox{}
+	copy(s.key[:], secret)
+	return s, nil
+}
+
+func (s *secretBox) encrypt(key string, plain []byte) ([]byte, error) {
+	var nonce [secretboxNonceSize]byte
+	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return nil, err
+	}
+	return secretbox.Seal(nonce[:], plain, &nonce, &s.key), nil
+}
+
+func (s *secretBox) decrypt(key string, data []byte) ([]byte, error) {
+	if len(data) < secretboxNonceSize {
+		return nil, errors.New("data is shorter than nonce")
+	}
+	var nonce [secretboxNonceSize]byte
+	copy(nonce[:], data[:secretboxNonceSize])
+	plain, ok := secretbox.Open(nil, data[secretboxNonceSize:], &nonce, &s.key)
+	if !ok {
+		return nil, errors.New("secretbox open failed")
+	}
+	return plain, nil
+}
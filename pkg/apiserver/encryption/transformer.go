@@ -0,0 +1,81 @@
+package encryption
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// Transformer converts values of a resource between plain text and
+// the form stored in etcd
+type Transformer interface {
+	// TransformToStorage encrypts data with the first provider, key is the etcd key of data
+	TransformToStorage(key string, data []byte) ([]byte, error)
+	// TransformFromStorage decrypts data with the provider it was encrypted by, stale is
+	// true if it was not encrypted by the first provider and should be rewritten
+	TransformFromStorage(key string, data []byte) (plain []byte, stale bool, err error)
+}
+
+// prefixedCipher is a cipher of one key, values encrypted by it are stored with
+// prefix "minik8s:enc:<provider>:v1:<key name>:" so that the key is found on read
+type prefixedCipher struct {
+	prefix []byte
+	cipher cipher
+}
+
+type cipher interface {
+	encrypt(key string, plain []byte) ([]byte, error)
+	decrypt(key string, data []byte) ([]byte, error)
+}
+
+// prefixTransformer tries ciphers in order, the first one encrypts, values without
+// any known prefix are plain text and readable only if identity provider is configured
+type prefixTransformer struct {
+	ciphers  []prefixedCipher
+	identity bool
+	// writeIdentity is true if identity is the first provider, values are stored as plain text
+	writeIdentity bool
+}
+
+const encryptedPrefix = "minik8s:enc:"
+
+var errNoProvider = errors.New("no encryption provider configured")
+
+func makePrefix(provider string, keyName string) []byte {
+	return []byte(fmt.Sprintf("%s%s:v1:%s:", encryptedPrefix, provider, keyName))
+}
+
+func (t *prefixTransformer) TransformToStorage(key string, data []byte) ([]byte, error) {
+	if t.writeIdentity {
+		return data, nil
+	}
+	if len(t.ciphers) == 0 {
+		return nil, errNoProvider
+	}
+	first := t.ciphers[0]
+	encrypted, err := first.cipher.encrypt(key, data)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, first.prefix...), encrypted...), nil
+}
+
+func (t *prefixTransformer) TransformFromStorage(key string, data []byte) ([]byte, bool, error) {
+	for i, c := range t.ciphers {
+		if !bytes.HasPrefix(data, c.prefix) {
+			continue
+		}
+		plain, err := c.cipher.decrypt(key, data[len(c.prefix):])
+		if err != nil {
+			return nil, false, fmt.Errorf("decrypt %s failed: %v", key, err)
+		}
+		return plain, t.writeIdentity || i != 0, nil
+	}
+	if bytes.HasPrefix(data, []byte(encryptedPrefix)) {
+		return nil, false, fmt.Errorf("decrypt %s failed: no key matches prefix of value", key)
+	}
+	if !t.identity {
+		return nil, false, fmt.Errorf("%s is stored as plain text but identity provider is not configured", key)
+	}
+	return data, !t.writeIdentity, nil
+}
@@ -0,0 +1,79 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+)
+
+func TestTransformer(t *testing.T) {
+	key1 := Key{Name: "key1", Secret: base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))}
+	key2 := Key{Name: "key2", Secret: base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32))}
+	aesgcm := func(keys ...Key) ProviderConfiguration {
+		return ProviderConfiguration{AESGCM: &KeysConfiguration{Keys: keys}}
+	}
+	secretbox := func(keys ...Key) ProviderConfiguration {
+		return ProviderConfiguration{Secretbox: &KeysConfiguration{Keys: keys}}
+	}
+	identity := ProviderConfiguration{Identity: &IdentityConfiguration{}}
+
+	const key = "/api/secrets/db"
+	plain := []byte(`{"data":{"password":"MTIzNDU2"}}`)
+
+	tests := []struct {
+		name string
+		// value is written by writer and read by reader
+		writer    []ProviderConfiguration
+		reader    []ProviderConfiguration
+		wantStale bool
+		wantErr   bool
+	}{
+		{"aesgcm", []ProviderConfiguration{aesgcm(key1)}, []ProviderConfiguration{aesgcm(key1)}, false, false},
+		{"secretbox", []ProviderConfiguration{secretbox(key1)}, []ProviderConfiguration{secretbox(key1)}, false, false},
+		{"rotated key", []ProviderConfiguration{aesgcm(key1)}, []ProviderConfiguration{aesgcm(key2, key1)}, true, false},
+		{"switched provider", []ProviderConfiguration{aesgcm(key1)}, []ProviderConfiguration{secretbox(key2), aesgcm(key1)}, true, false},
+		{"removed key", []ProviderConfiguration{aesgcm(key1)}, []ProviderConfiguration{aesgcm(key2)}, false, true},
+		{"wrong secret", []ProviderConfiguration{aesgcm(key1)}, []ProviderConfiguration{aesgcm(Key{Name: "key1", Secret: key2.Secret})}, false, true},
+		{"plain text", []ProviderConfiguration{identity}, []ProviderConfiguration{aesgcm(key1), identity}, true, false},
+		{"plain text without identity", []ProviderConfiguration{identity}, []ProviderConfiguration{aesgcm(key1)}, false, true},
+		{"decrypt to plain text", []ProviderConfiguration{aesgcm(key1)}, []ProviderConfiguration{identity, aesgcm(key1)}, true, false},
+	}
+
+	for _, test := range tests {
+		writer, err := newTransformer(test.writer)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		reader, err := newTransformer(test.reader)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		stored, err := writer.TransformToStorage(key, plain)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.writer[0].Identity == nil && bytes.Contains(stored, plain) {
+			t.Errorf("%s: value is stored as plain text", test.name)
+		}
+
+		got, stale, err := reader.TransformFromStorage(key, stored)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got err %v, wantErr %v", test.name, err, test.wantErr)
+			continue
+		}
+		if test.wantErr {
+			continue
+		}
+		if !bytes.Equal(got, plain) || stale != test.wantStale {
+			t.Errorf("%s: got %q stale %v, want %q stale %v", test.name, got, stale, plain, test.wantStale)
+		}
+	}
+
+	// value encrypted by aesgcm can not be moved to another key
+	transformer, _ := newTransformer([]ProviderConfiguration{aesgcm(key1)})
+	stored, _ := transformer.TransformToStorage(key, plain)
+	if _, _, err := transformer.TransformFromStorage("/api/secrets/other", stored); err == nil {
+		t.Errorf("value is decrypted under another key")
+	}
+}
@@ -145,6 +145,12 @@ func Close() {
 }
 
 func Put(key, value string) (err error, newVersion string) {
+	value, err = toStorage(key, value)
+	if err != nil {
+		logger.ApiServerLogger.Printf("[etcd] Put failed, err:%v\n", err)
+		return err, newVersion
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
 	resp, err := etcdClient.Put(ctx, key, value)
 	cancel()
@@ -169,6 +175,12 @@ func Put(key, value string) (err error, newVersion string) {
 }
 
 func CheckVersionPut(key, value, oldVersion string) (err error, newVersion string, success bool) {
+	value, err = toStorage(key, value)
+	if err != nil {
+		logger.ApiServerLogger.Printf("[etcd] Put failed, err:%v\n", err)
+		return err, newVersion, false
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
 	resp, err := etcdClient.Put(ctx, key, value, clientv3.WithPrevKV())
 	cancel()
@@ -211,7 +223,7 @@ func Get(key string) (value string, err error) {
 	}
 
 	if len(resp.Kvs) > 0 {
-		return string(resp.Kvs[0].Value), err
+		return fromStorage(key, resp.Kvs[0].Value)
 	} else {
 		return EmptyGetResult, err
 	}
@@ -230,7 +242,8 @@ func GetWithVersion(key string) (value string, version string, err error) {
 
 	if len(resp.Kvs) > 0 {
 		version = strconv.FormatInt(resp.Kvs[0].ModRevision, 10)
-		return string(resp.Kvs[0].Value), version, err
+		value, err = fromStorage(key, resp.Kvs[0].Value)
+		return value, version, err
 	} else {
 		return EmptyGetResult, version, err
 	}
@@ -279,7 +292,11 @@ func GetAllWithPrefix(keyPrefix string) (values []string, err error) {
 		return nil, err
 	}
 	for _, ev := range resp.Kvs {
-		values = append(values, string(ev.Value))
+		value, err := fromStorage(string(ev.Key), ev.Value)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
 	}
 	return values, err
 }
@@ -328,12 +345,39 @@ func WatchAllWithPrefix(key string) (context.CancelFunc, chan *Event) {
 	return cancel, ch
 }
 
+// doWatch forwards events from rch to ch until rch is closed. If an event
+// can't be decrypted, ch is closed instead of skipping the event, so the
+// watcher doesn't miss an update silently and clients relist.
 func doWatch(rch clientv3.WatchChan, ch chan *Event) {
+	defer close(ch)
 	// continue to read rch until it's closed
 	for wresp := range rch {
 		for _, ev := range wresp.Events {
 			// logger.ApiServerLogger.Printf("[etcd] watch notified %s %q : %q\n", ev.Type, ev.Kv.Key, ev.Kv.Value)
+			if err := decryptEvent(ev); err != nil {
+				logger.ApiServerLogger.Printf("[etcd] decrypt watch event of %q failed, close watch, err:%v\n", ev.Kv.Key, err)
+				return
+			}
 			ch <- (*Event)(ev)
 		}
 	}
 }
+
+// decryptEvent decrypts values of watch event in place
+func decryptEvent(ev *clientv3.Event) error {
+	if ev.Kv != nil && len(ev.Kv.Value) != 0 {
+		value, err := fromStorage(string(ev.Kv.Key), ev.Kv.Value)
+		if err != nil {
+			return err
+		}
+		ev.Kv.Value = []byte(value)
+	}
+	if ev.PrevKv != nil && len(ev.PrevKv.Value) != 0 {
+		value, err := fromStorage(string(ev.PrevKv.Key), ev.PrevKv.Value)
+		if err != nil {
+			return err
+		}
+		ev.PrevKv.Value = []byte(value)
+	}
+	return nil
+}
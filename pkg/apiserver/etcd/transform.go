@@ -0,0 +1,103 @@
+package etcd
+
+import (
+	"context"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"minik8s/pkg/apiserver/encryption"
+	"minik8s/pkg/logger"
+	"strconv"
+	"strings"
+)
+
+// transformers encrypt values of resources, keyed by etcd key prefix of resource
+var transformers map[string]encryption.Transformer
+
+// SetTransformers sets transformers of values keyed by etcd key prefix, values of
+// keys without transformer are stored as is. It should be called before Init.
+func SetTransformers(t map[string]encryption.Transformer) {
+	transformers = t
+}
+
+func transformerOf(key string) encryption.Transformer {
+	for prefix, t := range transformers {
+		if strings.HasPrefix(key, prefix) {
+			return t
+		}
+	}
+	return nil
+}
+
+func toStorage(key, value string) (string, error) {
+	t := transformerOf(key)
+	if t == nil {
+		return value, nil
+	}
+	data, err := t.TransformToStorage(key, []byte(value))
+	return string(data), err
+}
+
+func fromStorage(key string, value []byte) (string, error) {
+	plain, _, err := fromStorageWithStale(key, value)
+	return plain, err
+}
+
+func fromStorageWithStale(key string, value []byte) (string, bool, error) {
+	t := transformerOf(key)
+	if t == nil {
+		return string(value), false, nil
+	}
+	plain, stale, err := t.TransformFromStorage(key, value)
+	if err != nil {
+		logger.ApiServerLogger.Printf("[etcd] transform from storage failed, err:%v\n", err)
+	}
+	return string(plain), stale, err
+}
+
+// GetStaleKeysWithPrefix returns keys with prefix whose values are not encrypted
+// by the current key of their resource, and should be rewritten
+func GetStaleKeysWithPrefix(keyPrefix string) (keys []string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := etcdClient.Get(ctx, keyPrefix, clientv3.WithPrefix())
+	cancel()
+	if err != nil {
+		logger.ApiServerLogger.Printf("[etcd] GetStaleKeysWithPrefix failed, err:%v\n", err)
+		return nil, err
+	}
+	for _, kv := range resp.Kvs {
+		_, stale, err := fromStorageWithStale(string(kv.Key), kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		if stale {
+			keys = append(keys, string(kv.Key))
+		}
+	}
+	return keys, nil
+}
+
+// CompareAndPut puts value only if key is not modified since version
+func CompareAndPut(key, value, version string) (err error, newVersion string, success bool) {
+	modRevision, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return err, "", false
+	}
+	value, err = toStorage(key, value)
+	if err != nil {
+		return err, "", false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := etcdClient.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", modRevision)).
+		Then(clientv3.OpPut(key, value)).
+		Commit()
+	cancel()
+	if err != nil {
+		logger.ApiServerLogger.Printf("[etcd] CompareAndPut failed, err:%v\n", err)
+		return err, "", false
+	}
+
+	newVersion = strconv.FormatInt(resp.Header.Revision, 10)
+	Rvm.setResourceVersion(newVersion)
+	return nil, newVersion, resp.Succeeded
+}
@@ -60,6 +60,12 @@ func handlePostObject(c *gin.Context, ty types.ApiObjectType) {
 		pod := newObject.(*core.Pod)
 		pod.Status = core.DefaultPosStatus()
 	}
+	if ty == types.SecretObjectType {
+		if err := prepareSecret(newObject.(*core.Secret)); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "ERR", "error": err.Error()})
+			return
+		}
+	}
 
 	// lock for version get, set and store
 	etcd.VLock.Lock()
@@ -80,6 +86,23 @@ func handlePostObject(c *gin.Context, ty types.ApiObjectType) {
 	if ty == types.FuncTemplateObjectType {
 		f := newObject.(*core.Func)
 		etcdPath += f.Spec.Name
+	} else if isKeyedByName(ty) {
+		// object referenced by name is stored by name, and name must be unique
+		name, err := objectName(newObject)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "ERR", "error": err.Error()})
+			return
+		}
+		etcdPath += name
+		has, err := etcd.Has(etcdPath)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "ERR", "error": err.Error()})
+			return
+		}
+		if has {
+			c.JSON(http.StatusConflict, gin.H{"status": "FAILED", "error": fmt.Sprintf("%v %v already exists", ty, name)})
+			return
+		}
 	} else {
 		etcdPath += objectUID
 	}
@@ -121,6 +144,20 @@ func handlePutObject(c *gin.Context, ty types.ApiObjectType) {
 		return
 	}
 
+	if ty == types.SecretObjectType {
+		if err := prepareSecret(newObject.(*core.Secret)); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "ERR", "error": err.Error()})
+			return
+		}
+	}
+
+	if ty == types.PodObjectType {
+		if err := checkMirrorPodUpdate(c.Request.URL.Path, newObject.(*core.Pod)); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "ERR", "error": err.Error()})
+			return
+		}
+	}
+
 	// get object old version
 	oldVersion := newObject.GetResourceVersion()
 	if versionHas != oldVersion {
@@ -233,7 +270,13 @@ func handleWatchObjectAndStatus(c *gin.Context, ty types.ApiObjectType, resource
 	flusher, _ := c.Writer.(http.Flusher)
 	for {
 		select {
-		case ev := <-ch:
+		case ev, ok := <-ch:
+			if !ok {
+				logger.ApiServerLogger.Printf("[apiserver][HandleWatch%v] watch closed by storage, cancel watch task\n", ty)
+				cancel()
+				c.JSON(http.StatusInternalServerError, gin.H{"status": "ERR", "error": "watch closed by storage"})
+				return
+			}
 			switch ev.Type {
 			case etcd.EventTypeDelete:
 				event, err := json.Marshal(ev)
@@ -298,7 +341,13 @@ func handleWatchObjectsAndStatus(c *gin.Context, ty types.ApiObjectType, resourc
 	flusher, _ := c.Writer.(http.Flusher)
 	for {
 		select {
-		case ev := <-ch:
+		case ev, ok := <-ch:
+			if !ok {
+				logger.ApiServerLogger.Printf("[apiserver][HandleWatch%v] watch closed by storage, cancel watch task\n", ty)
+				cancel()
+				c.JSON(http.StatusInternalServerError, gin.H{"status": "ERR", "error": "watch closed by storage"})
+				return
+			}
 			switch ev.Type {
 			case etcd.EventTypeDelete:
 				logger.ApiServerLogger.Printf("[apiserver] %v delete\n", ty)
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"minik8s/pkg/api"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+)
+
+/*--------------------- ConfigMap ---------------------*/
+
+func HandlePostConfigMap(c *gin.Context) {
+	handlePostObject(c, types.ConfigMapObjectType)
+}
+
+func HandlePutConfigMap(c *gin.Context) {
+	handlePutObject(c, types.ConfigMapObjectType)
+}
+
+func HandleDeleteConfigMap(c *gin.Context) {
+	handleDeleteObject(c, types.ConfigMapObjectType)
+}
+
+func HandleGetConfigMap(c *gin.Context) {
+	handleGetObject(c, types.ConfigMapObjectType)
+}
+
+func HandleGetConfigMaps(c *gin.Context) {
+	handleGetObjects(c, types.ConfigMapObjectType)
+}
+
+func HandleWatchConfigMap(c *gin.Context) {
+	resourceURL := api.ConfigMapsURL + c.Param("name")
+	handleWatchObjectAndStatus(c, types.ConfigMapObjectType, resourceURL)
+}
+
+func HandleWatchConfigMaps(c *gin.Context) {
+	resourceURL := api.ConfigMapsURL
+	handleWatchObjectsAndStatus(c, types.ConfigMapObjectType, resourceURL)
+}
+
+/*--------------------- Secret ---------------------*/
+
+func HandlePostSecret(c *gin.Context) {
+	handlePostObject(c, types.SecretObjectType)
+}
+
+func HandlePutSecret(c *gin.Context) {
+	handlePutObject(c, types.SecretObjectType)
+}
+
+func HandleDeleteSecret(c *gin.Context) {
+	handleDeleteObject(c, types.SecretObjectType)
+}
+
+func HandleGetSecret(c *gin.Context) {
+	handleGetObject(c, types.SecretObjectType)
+}
+
+func HandleGetSecrets(c *gin.Context) {
+	handleGetObjects(c, types.SecretObjectType)
+}
+
+func HandleWatchSecret(c *gin.Context) {
+	resourceURL := api.SecretsURL + c.Param("name")
+	handleWatchObjectAndStatus(c, types.SecretObjectType, resourceURL)
+}
+
+func HandleWatchSecrets(c *gin.Context) {
+	resourceURL := api.SecretsURL
+	handleWatchObjectsAndStatus(c, types.SecretObjectType, resourceURL)
+}
+
+// isKeyedByName returns true if objects of type are stored by name instead
+// of uid, since they are referenced by name in pod spec or by each other
+func isKeyedByName(ty types.ApiObjectType) bool {
+	switch ty {
+	case types.ConfigMapObjectType, types.SecretObjectType,
+		types.PersistentVolumeObjectType, types.PersistentVolumeClaimObjectType:
+		return true
+	}
+	return false
+}
+
+func objectName(object core.IApiObject) (string, error) {
+	name := ""
+	switch o := object.(type) {
+	case *core.ConfigMap:
+		name = o.Name
+	case *core.Secret:
+		name = o.Name
+	case *core.PersistentVolume:
+		name = o.Name
+	case *core.PersistentVolumeClaim:
+		name = o.Name
+	}
+	if name == "" {
+		return "", errors.New("name is required")
+	}
+	return name, nil
+}
+
+// prepareSecret merges string data of secret and checks its size and
+// data required by its type before stored
+func prepareSecret(secret *core.Secret) error {
+	secret.MergeStringData()
+	if secret.Size() > core.MaxSecretSize {
+		return fmt.Errorf("secret %v is larger than %v bytes", secret.Name, core.MaxSecretSize)
+	}
+	if secret.Type == core.SecretTypeDockerConfigJson {
+		data, found := secret.Data[core.DockerConfigJsonKey]
+		if !found || !json.Valid(data) {
+			return fmt.Errorf("secret %v of type %v must have JSON in key %v", secret.Name, secret.Type, core.DockerConfigJsonKey)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"minik8s/pkg/api"
+	"minik8s/pkg/api/types"
+)
+
+/*--------------------- PodDisruptionBudget ---------------------*/
+
+func HandlePostPodDisruptionBudget(c *gin.Context) {
+	handlePostObject(c, types.PodDisruptionBudgetObjectType)
+}
+
+func HandlePutPodDisruptionBudget(c *gin.Context) {
+	handlePutObject(c, types.PodDisruptionBudgetObjectType)
+}
+
+func HandleDeletePodDisruptionBudget(c *gin.Context) {
+	handleDeleteObject(c, types.PodDisruptionBudgetObjectType)
+}
+
+func HandleGetPodDisruptionBudget(c *gin.Context) {
+	handleGetObject(c, types.PodDisruptionBudgetObjectType)
+}
+
+func HandleGetPodDisruptionBudgets(c *gin.Context) {
+	handleGetObjects(c, types.PodDisruptionBudgetObjectType)
+}
+
+func HandleWatchPodDisruptionBudget(c *gin.Context) {
+	resourceURL := api.PodDisruptionBudgetsURL + c.Param("name")
+	handleWatchObjectAndStatus(c, types.PodDisruptionBudgetObjectType, resourceURL)
+}
+
+func HandleWatchPodDisruptionBudgets(c *gin.Context) {
+	resourceURL := api.PodDisruptionBudgetsURL
+	handleWatchObjectsAndStatus(c, types.PodDisruptionBudgetObjectType, resourceURL)
+}
+
+func HandleGetPodDisruptionBudgetStatus(c *gin.Context) {
+	resourceURL := api.PodDisruptionBudgetsURL + c.Param("name")
+	handleGetObjectStatus(c, types.PodDisruptionBudgetObjectType, resourceURL)
+}
+
+func HandlePutPodDisruptionBudgetStatus(c *gin.Context) {
+	etcdURL := api.PodDisruptionBudgetsURL + c.Param("name")
+	handlePutObjectStatus(c, types.PodDisruptionBudgetObjectType, etcdURL)
+}
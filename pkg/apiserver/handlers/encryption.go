@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+	"minik8s/pkg/apiserver/etcd"
+	"minik8s/pkg/logger"
+	"net/http"
+)
+
+/*--------------------- Encryption ---------------------*/
+
+// HandleReencrypt rewrites all objects of type whose values are not encrypted by the
+// current key, so that old keys can be removed from the encryption configuration
+func HandleReencrypt(c *gin.Context) {
+	ty := types.ApiObjectType(c.Param("type"))
+	prefix, ok := core.LookupApiObjectsURL(ty)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "ERR", "error": fmt.Sprintf("No ApiObjectType %v", ty)})
+		return
+	}
+
+	keys, err := etcd.GetStaleKeysWithPrefix(prefix)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "ERR", "error": err.Error()})
+		return
+	}
+
+	count := 0
+	for _, key := range keys {
+		if err := reencryptObject(ty, key); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "ERR", "error": err.Error(), "reencrypted": count})
+			return
+		}
+		count++
+	}
+	logger.ApiServerLogger.Printf("[apiserver] %v %v objects re-encrypted\n", count, ty)
+	c.JSON(http.StatusOK, gin.H{"status": "OK", "reencrypted": count})
+}
+
+// reencryptObject reads and writes back object under key, which encrypts it
+// with the current key. Resource version of object is updated like Put.
+func reencryptObject(ty types.ApiObjectType, key string) error {
+	etcd.VLock.Lock()
+	defer etcd.VLock.Unlock()
+
+	objectStr, version, err := etcd.GetWithVersion(key)
+	if err != nil {
+		return err
+	}
+	if objectStr == etcd.EmptyGetResult {
+		// deleted meanwhile
+		return nil
+	}
+
+	object := core.CreateApiObject(ty)
+	if err := object.CreateFromEtcdString(objectStr); err != nil {
+		return fmt.Errorf("parse %v failed: %v", key, err)
+	}
+	object.SetResourceVersion(etcd.Rvm.GetNextResourceVersion())
+	buf, err := object.JsonMarshal()
+	if err != nil {
+		return err
+	}
+
+	// object modified meanwhile is already written with the current key
+	err, _, _ = etcd.CompareAndPut(key, string(buf), version)
+	return err
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"minik8s/pkg/api"
+	"minik8s/pkg/api/types"
+)
+
+/*--------------------- Event ---------------------*/
+
+func HandlePostEvent(c *gin.Context) {
+	handlePostObject(c, types.EventObjectType)
+}
+
+func HandlePutEvent(c *gin.Context) {
+	handlePutObject(c, types.EventObjectType)
+}
+
+func HandleDeleteEvent(c *gin.Context) {
+	handleDeleteObject(c, types.EventObjectType)
+}
+
+func HandleGetEvent(c *gin.Context) {
+	handleGetObject(c, types.EventObjectType)
+}
+
+func HandleGetEvents(c *gin.Context) {
+	handleGetObjects(c, types.EventObjectType)
+}
+
+func HandleWatchEvent(c *gin.Context) {
+	resourceURL := api.EventsURL + c.Param("name")
+	handleWatchObjectAndStatus(c, types.EventObjectType, resourceURL)
+}
+
+func HandleWatchEvents(c *gin.Context) {
+	resourceURL := api.EventsURL
+	handleWatchObjectsAndStatus(c, types.EventObjectType, resourceURL)
+}
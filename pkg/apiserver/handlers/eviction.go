@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"minik8s/pkg/api"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/apiserver/etcd"
+	"minik8s/pkg/logger"
+	"net/http"
+	"time"
+)
+
+/*--------------------- Pod Eviction ---------------------*/
+
+// MaxDisruptedPodSize is the max size of PodDisruptionBudgetStatus.DisruptedPods. API server eviction
+// subresource handler will refuse to evict pods covered by the corresponding PDB
+// if the size of the map exceeds this value. It means a large number of
+// evictions have been approved by the API server but not noticed by the PDB controller yet.
+const MaxDisruptedPodSize = 2000
+
+// HandlePostPodEviction evicts the specified Pod. Unlike DELETE, the eviction
+// is refused with 429 Too Many Requests if it would violate the
+// PodDisruptionBudget covering the Pod
+// POST /api/pods/{name}/eviction
+func HandlePostPodEviction(c *gin.Context) {
+	podURL := api.PodsURL + c.Param("name")
+
+	podStr, err := etcd.Get(podURL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "ERR", "error": err.Error()})
+		return
+	}
+	if podStr == etcd.EmptyGetResult {
+		c.JSON(http.StatusNotFound, gin.H{"status": "ERR", "error": "No such Pod"})
+		return
+	}
+	pod := &core.Pod{}
+	err = pod.CreateFromEtcdString(podStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "ERR", "error": err.Error()})
+		return
+	}
+
+	// pods not running do not count as healthy in any budget, evicting them
+	// does not reduce availability
+	if pod.Status.Phase != core.PodRunning {
+		deletePodForEviction(c, podURL)
+		return
+	}
+
+	pdbStrs, err := etcd.GetAllWithPrefix(api.PodDisruptionBudgetsURL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "ERR", "error": err.Error()})
+		return
+	}
+	pdbList := &core.PodDisruptionBudgetList{}
+	err = pdbList.AppendItemsFromStr(pdbStrs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "ERR", "error": err.Error()})
+		return
+	}
+
+	var pdbs []*core.PodDisruptionBudget
+	for i := range pdbList.Items {
+		if pdbList.Items[i].MatchPod(pod) {
+			pdbs = append(pdbs, &pdbList.Items[i])
+		}
+	}
+	if len(pdbs) == 0 {
+		deletePodForEviction(c, podURL)
+		return
+	}
+	if len(pdbs) > 1 {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "ERR", "error": "This pod has more than one PodDisruptionBudget, which the eviction subresource does not support."})
+		return
+	}
+
+	pdb := pdbs[0]
+	err = checkAndDecrement(pod.UID, pdb)
+	if err != nil {
+		logger.ApiServerLogger.Printf("[apiserver] refuse to evict pod %v: %v\n", pod.UID, err)
+		c.JSON(http.StatusTooManyRequests, gin.H{"status": "FAILED", "error": err.Error()})
+		return
+	}
+
+	// update budget before deleting pod, so that concurrent evictions
+	// observe the decreased DisruptionsAllowed
+	code, err := updateBudgetForEviction(pdb)
+	if err != nil {
+		c.JSON(code, gin.H{"status": "FAILED", "error": err.Error()})
+		return
+	}
+
+	deletePodForEviction(c, podURL)
+}
+
+// checkAndDecrement checks if pod can be evicted under budget pdb, and
+// records the eviction in status of pdb if it can
+func checkAndDecrement(podUID string, pdb *core.PodDisruptionBudget) error {
+	if pdb.Status.DisruptionsAllowed <= 0 {
+		return errors.New(fmt.Sprintf("Cannot evict pod as it would violate the pod's disruption budget %v, needs %d healthy pods and has %d currently", pdb.Name, pdb.Status.DesiredHealthy, pdb.Status.CurrentHealthy))
+	}
+	if len(pdb.Status.DisruptedPods) > MaxDisruptedPodSize {
+		return errors.New(fmt.Sprintf("DisruptedPods map too big - too many evictions not confirmed by PDB controller"))
+	}
+
+	pdb.Status.DisruptionsAllowed--
+	if pdb.Status.DisruptedPods == nil {
+		pdb.Status.DisruptedPods = make(map[string]time.Time)
+	}
+	pdb.Status.DisruptedPods[podUID] = time.Now()
+	return nil
+}
+
+func updateBudgetForEviction(pdb *core.PodDisruptionBudget) (int, error) {
+	pdbURL := api.PodDisruptionBudgetsURL + pdb.UID
+	oldVersion := pdb.GetResourceVersion()
+
+	// lock for version get, set and store
+	etcd.VLock.Lock()
+	defer etcd.VLock.Unlock()
+
+	pdb.SetResourceVersion(etcd.Rvm.GetNextResourceVersion())
+	buf, err := pdb.JsonMarshal()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	err, _, success := etcd.CheckVersionPut(pdbURL, string(buf), oldVersion)
+	if !success {
+		return http.StatusConflict, errors.New(fmt.Sprintf("PodDisruptionBudget %v has been modified by others, please retry eviction", pdb.Name))
+	} else if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+func deletePodForEviction(c *gin.Context, podURL string) {
+	err := etcd.Delete(podURL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "ERR", "error": err.Error()})
+	} else {
+		c.JSON(http.StatusOK, gin.H{"status": "OK"})
+	}
+}
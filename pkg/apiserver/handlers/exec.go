@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"minik8s/config"
+	"minik8s/pkg/api"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+)
+
+/*--------------------- Pod Exec, Attach and Port Forward ---------------------*/
+
+// HandleGetPodExec runs a command in a container of the specified Pod. The
+// connection is upgraded to websocket and proxied to kubelet on the node
+// pod runs on. Container can be omitted if pod has only one container
+// GET /api/pods/{name}/exec?container=&command=&stdin=&stdout=&stderr=&tty=
+func HandleGetPodExec(c *gin.Context) {
+	handleContainerStream(c, api.ContainerExecPrefix)
+}
+
+// HandleGetPodAttach attaches to main process of a container in the specified
+// Pod, the connection is proxied to kubelet in the same way as exec
+// GET /api/pods/{name}/attach?container=&stdin=&stdout=&stderr=&tty=
+func HandleGetPodAttach(c *gin.Context) {
+	handleContainerStream(c, api.ContainerAttachPrefix)
+}
+
+// HandleGetPodPortForward forwards one connection to a port of the specified
+// Pod, the connection is upgraded to websocket and proxied to kubelet
+// GET /api/pods/{name}/portforward?port=
+func HandleGetPodPortForward(c *gin.Context) {
+	pod, node, found := getPodAndNode(c)
+	if !found {
+		return
+	}
+	target, err := url.Parse(config.KubeletUrl(node.Spec.Address) + api.PortForwardPrefix +
+		url.PathEscape(pod.UID) + "?" + c.Request.URL.RawQuery)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "ERR", "error": err.Error()})
+		return
+	}
+	proxyUpgrade(c, target)
+}
+
+func handleContainerStream(c *gin.Context, prefix string) {
+	pod, node, found := getPodAndNode(c)
+	if !found {
+		return
+	}
+	container, err := podContainerName(pod, c.Query(api.ExecContainerParam))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "ERR", "error": err.Error()})
+		return
+	}
+
+	query := c.Request.URL.Query()
+	query.Del(api.ExecContainerParam)
+	target, err := url.Parse(config.KubeletUrl(node.Spec.Address) + prefix +
+		url.PathEscape(pod.UID) + "/" + url.PathEscape(container) + "?" + query.Encode())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "ERR", "error": err.Error()})
+		return
+	}
+	proxyUpgrade(c, target)
+}
+
+// proxyUpgrade proxies request to target, if it is upgraded, e.g. to websocket,
+// the connection is copied in both directions until either side closes it
+func proxyUpgrade(c *gin.Context, target *url.URL) {
+	proxy := &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.URL = target
+			req.Host = target.Host
+		},
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			c.JSON(http.StatusBadGateway, gin.H{"status": "ERR", "error": err.Error()})
+		},
+	}
+	proxy.ServeHTTP(c.Writer, c.Request)
+}
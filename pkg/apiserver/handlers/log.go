@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"minik8s/config"
+	"minik8s/pkg/api"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/apiserver/etcd"
+	"net/http"
+	"net/url"
+)
+
+/*--------------------- Pod Log ---------------------*/
+
+// HandleGetPodLog reads logs of a container in the specified Pod, the request
+// is proxied to kubelet on the node pod runs on. Container can be omitted if
+// pod has only one container. Logs are streamed if follow is set
+// GET /api/pods/{name}/log?container=&follow=&tailLines=&sinceSeconds=&previous=
+func HandleGetPodLog(c *gin.Context) {
+	pod, node, found := getPodAndNode(c)
+	if !found {
+		return
+	}
+	container, err := podContainerName(pod, c.Query(api.LogContainerParam))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "ERR", "error": err.Error()})
+		return
+	}
+
+	query := c.Request.URL.Query()
+	query.Del(api.LogContainerParam)
+	logURL := config.KubeletUrl(node.Spec.Address) + api.ContainerLogsPrefix +
+		url.PathEscape(pod.UID) + "/" + url.PathEscape(container) + "?" + query.Encode()
+	proxyStream(c, logURL)
+}
+
+// getPodAndNode gets pod in path of request and node it is scheduled to,
+// it responds error if they are not found
+func getPodAndNode(c *gin.Context) (*core.Pod, *core.Node, bool) {
+	podStr, err := etcd.Get(api.PodsURL + c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "ERR", "error": err.Error()})
+		return nil, nil, false
+	}
+	if podStr == etcd.EmptyGetResult {
+		c.JSON(http.StatusNotFound, gin.H{"status": "ERR", "error": "No such Pod"})
+		return nil, nil, false
+	}
+	pod := &core.Pod{}
+	err = pod.CreateFromEtcdString(podStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "ERR", "error": err.Error()})
+		return nil, nil, false
+	}
+	if pod.Spec.NodeName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "ERR", "error": fmt.Sprintf("pod %v is not scheduled to any node yet", pod.Name)})
+		return nil, nil, false
+	}
+
+	node, err := getNodeByName(pod.Spec.NodeName)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "ERR", "error": err.Error()})
+		return nil, nil, false
+	}
+	if node == nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "ERR", "error": fmt.Sprintf("node %v of pod %v not found", pod.Spec.NodeName, pod.Name)})
+		return nil, nil, false
+	}
+	return pod, node, true
+}
+
+// podContainerName returns container requested, which defaults
+// to the only container of pod
+func podContainerName(pod *core.Pod, container string) (string, error) {
+	if container != "" {
+		return container, nil
+	}
+	if len(pod.Spec.Containers) == 1 {
+		return pod.Spec.Containers[0].Name, nil
+	}
+	var names []string
+	for _, cnt := range pod.Spec.Containers {
+		names = append(names, cnt.Name)
+	}
+	return "", fmt.Errorf("a container name must be specified for pod %v, choose one of: %v", pod.Name, names)
+}
+
+// getNodeByName returns nil without error if node is not found
+func getNodeByName(name string) (*core.Node, error) {
+	nodeStrs, err := etcd.GetAllWithPrefix(api.NodesURL)
+	if err != nil {
+		return nil, err
+	}
+	nodeList := &core.NodeList{}
+	err = nodeList.AppendItemsFromStr(nodeStrs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range nodeList.Items {
+		if nodeList.Items[i].Name == name {
+			return &nodeList.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// proxyStream sends GET request to kubelet and copies response to client,
+// flushing each read so that streamed output is not buffered
+func proxyStream(c *gin.Context, target string) {
+	req, err := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, target, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "ERR", "error": err.Error()})
+		return
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"status": "ERR", "error": err.Error()})
+		return
+	}
+	defer resp.Body.Close()
+
+	c.Header("Content-Type", resp.Header.Get("Content-Type"))
+	c.Status(resp.StatusCode)
+	buf := make([]byte, 4096)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			if _, werr := c.Writer.Write(buf[:n]); werr != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+		if err != nil {
+			// io.EOF, or kubelet or client went away
+			return
+		}
+	}
+}
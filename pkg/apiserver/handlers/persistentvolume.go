@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"minik8s/pkg/api"
+	"minik8s/pkg/api/types"
+)
+
+/*--------------------- PersistentVolume ---------------------*/
+
+func HandlePostPersistentVolume(c *gin.Context) {
+	handlePostObject(c, types.PersistentVolumeObjectType)
+}
+
+func HandlePutPersistentVolume(c *gin.Context) {
+	handlePutObject(c, types.PersistentVolumeObjectType)
+}
+
+func HandleDeletePersistentVolume(c *gin.Context) {
+	handleDeleteObject(c, types.PersistentVolumeObjectType)
+}
+
+func HandleGetPersistentVolume(c *gin.Context) {
+	handleGetObject(c, types.PersistentVolumeObjectType)
+}
+
+func HandleGetPersistentVolumes(c *gin.Context) {
+	handleGetObjects(c, types.PersistentVolumeObjectType)
+}
+
+func HandleWatchPersistentVolume(c *gin.Context) {
+	resourceURL := api.PersistentVolumesURL + c.Param("name")
+	handleWatchObjectAndStatus(c, types.PersistentVolumeObjectType, resourceURL)
+}
+
+func HandleWatchPersistentVolumes(c *gin.Context) {
+	resourceURL := api.PersistentVolumesURL
+	handleWatchObjectsAndStatus(c, types.PersistentVolumeObjectType, resourceURL)
+}
+
+func HandleGetPersistentVolumeStatus(c *gin.Context) {
+	resourceURL := api.PersistentVolumesURL + c.Param("name")
+	handleGetObjectStatus(c, types.PersistentVolumeObjectType, resourceURL)
+}
+
+func HandlePutPersistentVolumeStatus(c *gin.Context) {
+	etcdURL := api.PersistentVolumesURL + c.Param("name")
+	handlePutObjectStatus(c, types.PersistentVolumeObjectType, etcdURL)
+}
+
+/*--------------------- PersistentVolumeClaim ---------------------*/
+
+func HandlePostPersistentVolumeClaim(c *gin.Context) {
+	handlePostObject(c, types.PersistentVolumeClaimObjectType)
+}
+
+func HandlePutPersistentVolumeClaim(c *gin.Context) {
+	handlePutObject(c, types.PersistentVolumeClaimObjectType)
+}
+
+func HandleDeletePersistentVolumeClaim(c *gin.Context) {
+	handleDeleteObject(c, types.PersistentVolumeClaimObjectType)
+}
+
+func HandleGetPersistentVolumeClaim(c *gin.Context) {
+	handleGetObject(c, types.PersistentVolumeClaimObjectType)
+}
+
+func HandleGetPersistentVolumeClaims(c *gin.Context) {
+	handleGetObjects(c, types.PersistentVolumeClaimObjectType)
+}
+
+func HandleWatchPersistentVolumeClaim(c *gin.Context) {
+	resourceURL := api.PersistentVolumeClaimsURL + c.Param("name")
+	handleWatchObjectAndStatus(c, types.PersistentVolumeClaimObjectType, resourceURL)
+}
+
+func HandleWatchPersistentVolumeClaims(c *gin.Context) {
+	resourceURL := api.PersistentVolumeClaimsURL
+	handleWatchObjectsAndStatus(c, types.PersistentVolumeClaimObjectType, resourceURL)
+}
+
+func HandleGetPersistentVolumeClaimStatus(c *gin.Context) {
+	resourceURL := api.PersistentVolumeClaimsURL + c.Param("name")
+	handleGetObjectStatus(c, types.PersistentVolumeClaimObjectType, resourceURL)
+}
+
+func HandlePutPersistentVolumeClaimStatus(c *gin.Context) {
+	etcdURL := api.PersistentVolumeClaimsURL + c.Param("name")
+	handlePutObjectStatus(c, types.PersistentVolumeClaimObjectType, etcdURL)
+}
@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"minik8s/pkg/api"
+	"minik8s/pkg/api/core"
 	"minik8s/pkg/api/types"
+	"minik8s/pkg/apiserver/etcd"
+	"reflect"
 )
 
 /*--------------------- Pod ---------------------*/
@@ -49,3 +53,24 @@ func HandlePutPodStatus(c *gin.Context) {
 	etcdURL := api.PodsURL + c.Param("name")
 	handlePutObjectStatus(c, types.PodObjectType, etcdURL)
 }
+
+// checkMirrorPodUpdate rejects changes to spec of mirror pod, which is read-only
+// as it is published by kubelet for static pod, only status of it may be updated
+func checkMirrorPodUpdate(etcdURL string, pod *core.Pod) error {
+	objectJson, err := etcd.Get(etcdURL)
+	if err != nil {
+		return err
+	}
+	old := &core.Pod{}
+	if err = old.JsonUnmarshal([]byte(objectJson)); err != nil {
+		return err
+	}
+	if !old.IsMirrorPod() {
+		return nil
+	}
+	if pod.Annotations[core.ConfigMirrorAnnotationKey] != old.Annotations[core.ConfigMirrorAnnotationKey] ||
+		!reflect.DeepEqual(pod.Spec, old.Spec) {
+		return fmt.Errorf("mirror pod %s is read-only, edit manifest of static pod on node %s instead", old.Name, old.Spec.NodeName)
+	}
+	return nil
+}
@@ -59,6 +59,24 @@ func (h httpServer) BindHandlers() {
 	// Replace status of the specified Pod
 	// PUT /api/pods/{name}/status
 	h.router.PUT(api.PodStatusURL, handlers.HandlePutPodStatus)
+	/*--------------------- Pod Eviction ---------------------*/
+	// Evict the specified Pod, respecting PodDisruptionBudget
+	// POST /api/pods/{name}/eviction
+	h.router.POST(api.PodEvictionURL, handlers.HandlePostPodEviction)
+	/*--------------------- Pod Log ---------------------*/
+	// Read logs of a container in the specified Pod, proxied to kubelet
+	// GET /api/pods/{name}/log
+	h.router.GET(api.PodLogURL, handlers.HandleGetPodLog)
+	/*--------------------- Pod Exec, Attach and Port Forward ---------------------*/
+	// Run a command in a container of the specified Pod, upgraded to websocket
+	// GET /api/pods/{name}/exec
+	h.router.GET(api.PodExecURL, handlers.HandleGetPodExec)
+	// Attach to a container of the specified Pod, upgraded to websocket
+	// GET /api/pods/{name}/attach
+	h.router.GET(api.PodAttachURL, handlers.HandleGetPodAttach)
+	// Forward one connection to a port of the specified Pod, upgraded to websocket
+	// GET /api/pods/{name}/portforward
+	h.router.GET(api.PodPortForwardURL, handlers.HandleGetPodPortForward)
 
 	/*--------------------- Node ---------------------*/
 	// Create a Node
@@ -273,6 +291,170 @@ func (h httpServer) BindHandlers() {
 	// PUT /api/dns/{name}/status
 	h.router.PUT(api.DNSStatusURL, handlers.HandlePutDNSStatus)
 
+	/*--------------------- PodDisruptionBudget ---------------------*/
+	// Create a PodDisruptionBudget
+	// POST /api/pdb
+	h.router.POST(api.PodDisruptionBudgetsURL, handlers.HandlePostPodDisruptionBudget)
+	// Update/Replace the specified PodDisruptionBudget
+	// PUT /api/pdb/{name}
+	h.router.PUT(api.PodDisruptionBudgetURL, handlers.HandlePutPodDisruptionBudget)
+	// Delete a PodDisruptionBudget
+	// DELETE /api/pdb/{name}
+	h.router.DELETE(api.PodDisruptionBudgetURL, handlers.HandleDeletePodDisruptionBudget)
+	// Read the specified PodDisruptionBudget
+	// GET /api/pdb/{name}
+	h.router.GET(api.PodDisruptionBudgetURL, handlers.HandleGetPodDisruptionBudget)
+	// List or watch objects of kind PodDisruptionBudget
+	// GET /api/pdb
+	h.router.GET(api.PodDisruptionBudgetsURL, handlers.HandleGetPodDisruptionBudgets)
+	// Watch changes to an object of kind PodDisruptionBudget
+	// GET /api/watch/pdb/{name}
+	h.router.GET(api.WatchPodDisruptionBudgetURL, handlers.HandleWatchPodDisruptionBudget)
+	// Watch individual changes to a list of PodDisruptionBudget
+	// GET /api/watch/pdb
+	h.router.GET(api.WatchPodDisruptionBudgetsURL, handlers.HandleWatchPodDisruptionBudgets)
+	/*--------------------- PodDisruptionBudget Status ---------------------*/
+	// Read status of the specified PodDisruptionBudget
+	// GET /api/pdb/{name}/status
+	h.router.GET(api.PodDisruptionBudgetStatusURL, handlers.HandleGetPodDisruptionBudgetStatus)
+	// Replace status of the specified PodDisruptionBudget
+	// PUT /api/pdb/{name}/status
+	h.router.PUT(api.PodDisruptionBudgetStatusURL, handlers.HandlePutPodDisruptionBudgetStatus)
+
+	/*--------------------- ConfigMap ---------------------*/
+	// Create a ConfigMap
+	// POST /api/configmaps
+	h.router.POST(api.ConfigMapsURL, handlers.HandlePostConfigMap)
+	// Update/Replace the specified ConfigMap
+	// PUT /api/configmaps/{name}
+	h.router.PUT(api.ConfigMapURL, handlers.HandlePutConfigMap)
+	// Delete a ConfigMap
+	// DELETE /api/configmaps/{name}
+	h.router.DELETE(api.ConfigMapURL, handlers.HandleDeleteConfigMap)
+	// Read the specified ConfigMap
+	// GET /api/configmaps/{name}
+	h.router.GET(api.ConfigMapURL, handlers.HandleGetConfigMap)
+	// List or watch objects of kind ConfigMap
+	// GET /api/configmaps
+	h.router.GET(api.ConfigMapsURL, handlers.HandleGetConfigMaps)
+	// Watch changes to an object of kind ConfigMap
+	// GET /api/watch/configmaps/{name}
+	h.router.GET(api.WatchConfigMapURL, handlers.HandleWatchConfigMap)
+	// Watch individual changes to a list of ConfigMap
+	// GET /api/watch/configmaps
+	h.router.GET(api.WatchConfigMapsURL, handlers.HandleWatchConfigMaps)
+
+	/*--------------------- Secret ---------------------*/
+	// Create a Secret
+	// POST /api/secrets
+	h.router.POST(api.SecretsURL, handlers.HandlePostSecret)
+	// Update/Replace the specified Secret
+	// PUT /api/secrets/{name}
+	h.router.PUT(api.SecretURL, handlers.HandlePutSecret)
+	// Delete a Secret
+	// DELETE /api/secrets/{name}
+	h.router.DELETE(api.SecretURL, handlers.HandleDeleteSecret)
+	// Read the specified Secret
+	// GET /api/secrets/{name}
+	h.router.GET(api.SecretURL, handlers.HandleGetSecret)
+	// List or watch objects of kind Secret
+	// GET /api/secrets
+	h.router.GET(api.SecretsURL, handlers.HandleGetSecrets)
+	// Watch changes to an object of kind Secret
+	// GET /api/watch/secrets/{name}
+	h.router.GET(api.WatchSecretURL, handlers.HandleWatchSecret)
+	// Watch individual changes to a list of Secret
+	// GET /api/watch/secrets
+	h.router.GET(api.WatchSecretsURL, handlers.HandleWatchSecrets)
+
+	/*--------------------- PersistentVolume ---------------------*/
+	// Create a PersistentVolume
+	// POST /api/persistentvolumes
+	h.router.POST(api.PersistentVolumesURL, handlers.HandlePostPersistentVolume)
+	// Update/Replace the specified PersistentVolume
+	// PUT /api/persistentvolumes/{name}
+	h.router.PUT(api.PersistentVolumeURL, handlers.HandlePutPersistentVolume)
+	// Delete a PersistentVolume
+	// DELETE /api/persistentvolumes/{name}
+	h.router.DELETE(api.PersistentVolumeURL, handlers.HandleDeletePersistentVolume)
+	// Read the specified PersistentVolume
+	// GET /api/persistentvolumes/{name}
+	h.router.GET(api.PersistentVolumeURL, handlers.HandleGetPersistentVolume)
+	// List or watch objects of kind PersistentVolume
+	// GET /api/persistentvolumes
+	h.router.GET(api.PersistentVolumesURL, handlers.HandleGetPersistentVolumes)
+	// Watch changes to an object of kind PersistentVolume
+	// GET /api/watch/persistentvolumes/{name}
+	h.router.GET(api.WatchPersistentVolumeURL, handlers.HandleWatchPersistentVolume)
+	// Watch individual changes to a list of PersistentVolume
+	// GET /api/watch/persistentvolumes
+	h.router.GET(api.WatchPersistentVolumesURL, handlers.HandleWatchPersistentVolumes)
+	/*--------------------- PersistentVolume Status ---------------------*/
+	// Read status of the specified PersistentVolume
+	// GET /api/persistentvolumes/{name}/status
+	h.router.GET(api.PersistentVolumeStatusURL, handlers.HandleGetPersistentVolumeStatus)
+	// Replace status of the specified PersistentVolume
+	// PUT /api/persistentvolumes/{name}/status
+	h.router.PUT(api.PersistentVolumeStatusURL, handlers.HandlePutPersistentVolumeStatus)
+
+	/*--------------------- PersistentVolumeClaim ---------------------*/
+	// Create a PersistentVolumeClaim
+	// POST /api/persistentvolumeclaims
+	h.router.POST(api.PersistentVolumeClaimsURL, handlers.HandlePostPersistentVolumeClaim)
+	// Update/Replace the specified PersistentVolumeClaim
+	// PUT /api/persistentvolumeclaims/{name}
+	h.router.PUT(api.PersistentVolumeClaimURL, handlers.HandlePutPersistentVolumeClaim)
+	// Delete a PersistentVolumeClaim
+	// DELETE /api/persistentvolumeclaims/{name}
+	h.router.DELETE(api.PersistentVolumeClaimURL, handlers.HandleDeletePersistentVolumeClaim)
+	// Read the specified PersistentVolumeClaim
+	// GET /api/persistentvolumeclaims/{name}
+	h.router.GET(api.PersistentVolumeClaimURL, handlers.HandleGetPersistentVolumeClaim)
+	// List or watch objects of kind PersistentVolumeClaim
+	// GET /api/persistentvolumeclaims
+	h.router.GET(api.PersistentVolumeClaimsURL, handlers.HandleGetPersistentVolumeClaims)
+	// Watch changes to an object of kind PersistentVolumeClaim
+	// GET /api/watch/persistentvolumeclaims/{name}
+	h.router.GET(api.WatchPersistentVolumeClaimURL, handlers.HandleWatchPersistentVolumeClaim)
+	// Watch individual changes to a list of PersistentVolumeClaim
+	// GET /api/watch/persistentvolumeclaims
+	h.router.GET(api.WatchPersistentVolumeClaimsURL, handlers.HandleWatchPersistentVolumeClaims)
+	/*--------------------- PersistentVolumeClaim Status ---------------------*/
+	// Read status of the specified PersistentVolumeClaim
+	// GET /api/persistentvolumeclaims/{name}/status
+	h.router.GET(api.PersistentVolumeClaimStatusURL, handlers.HandleGetPersistentVolumeClaimStatus)
+	// Replace status of the specified PersistentVolumeClaim
+	// PUT /api/persistentvolumeclaims/{name}/status
+	h.router.PUT(api.PersistentVolumeClaimStatusURL, handlers.HandlePutPersistentVolumeClaimStatus)
+
+	/*--------------------- Event ---------------------*/
+	// Create an Event
+	// POST /api/events
+	h.router.POST(api.EventsURL, handlers.HandlePostEvent)
+	// Update/Replace the specified Event
+	// PUT /api/events/{uid}
+	h.router.PUT(api.EventURL, handlers.HandlePutEvent)
+	// Delete an Event
+	// DELETE /api/events/{uid}
+	h.router.DELETE(api.EventURL, handlers.HandleDeleteEvent)
+	// Read the specified Event
+	// GET /api/events/{uid}
+	h.router.GET(api.EventURL, handlers.HandleGetEvent)
+	// List or watch objects of kind Event
+	// GET /api/events
+	h.router.GET(api.EventsURL, handlers.HandleGetEvents)
+	// Watch changes to an object of kind Event
+	// GET /api/watch/events/{uid}
+	h.router.GET(api.WatchEventURL, handlers.HandleWatchEvent)
+	// Watch individual changes to a list of Event
+	// GET /api/watch/events
+	h.router.GET(api.WatchEventsURL, handlers.HandleWatchEvents)
+
+	/*--------------------- Encryption ---------------------*/
+	// Rewrite objects of type not encrypted by the current key
+	// POST /api/reencrypt/{type}
+	h.router.POST(api.ReencryptURL, handlers.HandleReencrypt)
+
 	/*--------------------- Serverless ---------------------*/
 
 	/*--------------------- Function Template ---------------------*/
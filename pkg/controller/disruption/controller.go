@@ -0,0 +1,286 @@
+package disruption
+
+import (
+	"context"
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/meta"
+	"minik8s/pkg/api/types"
+	client "minik8s/pkg/apiclient/interface"
+	"minik8s/pkg/controller/cache"
+	"minik8s/pkg/logger"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// DisruptionController keeps status of PodDisruptionBudgets up to date,
+// the eviction subresource of apiserver relies on DisruptionsAllowed in
+// status to decide whether a pod can be evicted.
+type DisruptionController interface {
+	Run(ctx context.Context)
+}
+
+func NewDisruptionController(pdbInformer cache.Informer, pdbClient client.Interface, podInformer cache.Informer, rsInformer cache.Informer) DisruptionController {
+
+	dc := &disruptionController{
+		Kind:        string(types.PodDisruptionBudgetObjectType),
+		PdbInformer: pdbInformer,
+		PdbClient:   pdbClient,
+		PodInformer: podInformer,
+		RsInformer:  rsInformer,
+		queue:       cache.NewWorkQueue(),
+	}
+
+	_ = dc.PdbInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    dc.addPdb,
+		UpdateFunc: dc.updatePdb,
+	})
+
+	_ = dc.PodInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    dc.addPod,
+		UpdateFunc: dc.updatePod,
+		DeleteFunc: dc.deletePod,
+	})
+
+	return dc
+}
+
+type disruptionController struct {
+	Kind string
+
+	PdbInformer cache.Informer
+	PdbClient   client.Interface
+	PodInformer cache.Informer
+	RsInformer  cache.Informer
+	queue       cache.WorkQueue
+}
+
+func (dc *disruptionController) Run(ctx context.Context) {
+
+	go func() {
+		logger.DisruptionControllerLogger.Printf("[DisruptionController] start\n")
+		defer logger.DisruptionControllerLogger.Printf("[DisruptionController] finish\n")
+
+		go dc.resync(ctx)
+		dc.runWorker(ctx)
+
+		// wait for controller manager stop
+		<-ctx.Done()
+	}()
+	return
+}
+
+func (dc *disruptionController) enqueuePdb(pdb *core.PodDisruptionBudget) {
+	dc.queue.Enqueue(pdb.UID)
+	logger.DisruptionControllerLogger.Printf("enqueuePdb key %s\n", pdb.UID)
+}
+
+func (dc *disruptionController) addPdb(obj interface{}) {
+	pdb := obj.(*core.PodDisruptionBudget)
+	dc.enqueuePdb(pdb)
+}
+
+func (dc *disruptionController) updatePdb(old, cur interface{}) {
+	oldPdb := old.(*core.PodDisruptionBudget)
+	curPdb := cur.(*core.PodDisruptionBudget)
+	if reflect.DeepEqual(oldPdb.Spec, curPdb.Spec) && reflect.DeepEqual(oldPdb.Status, curPdb.Status) {
+		return
+	}
+	dc.enqueuePdb(curPdb)
+}
+
+func (dc *disruptionController) addPod(obj interface{}) {
+	dc.enqueuePdbsForPod(obj.(*core.Pod))
+}
+
+func (dc *disruptionController) updatePod(old, cur interface{}) {
+	oldPod := old.(*core.Pod)
+	curPod := cur.(*core.Pod)
+	if curPod.ResourceVersion == oldPod.ResourceVersion {
+		return
+	}
+	if !reflect.DeepEqual(oldPod.Labels, curPod.Labels) {
+		dc.enqueuePdbsForPod(oldPod)
+	}
+	dc.enqueuePdbsForPod(curPod)
+}
+
+func (dc *disruptionController) deletePod(obj interface{}) {
+	dc.enqueuePdbsForPod(obj.(*core.Pod))
+}
+
+func (dc *disruptionController) enqueuePdbsForPod(pod *core.Pod) {
+	for _, item := range dc.PdbInformer.List() {
+		pdb := item.(*core.PodDisruptionBudget)
+		if pdb.MatchPod(pod) {
+			dc.enqueuePdb(pdb)
+		}
+	}
+}
+
+// resync enqueues all budgets periodically, so that expired entries in
+// DisruptedPods are cleaned up even if no pod changes
+func (dc *disruptionController) resync(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(config.DisruptionResyncPeriod):
+			for _, item := range dc.PdbInformer.List() {
+				dc.enqueuePdb(item.(*core.PodDisruptionBudget))
+			}
+		}
+	}
+}
+
+const defaultWorkerSleepInterval = time.Duration(3) * time.Second
+
+func (dc *disruptionController) runWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			logger.DisruptionControllerLogger.Printf("[worker] ctx.Done() received, worker of DisruptionController exit\n")
+			return
+		default:
+			for dc.processNextWorkItem() {
+			}
+			time.Sleep(defaultWorkerSleepInterval)
+		}
+	}
+}
+
+func (dc *disruptionController) processNextWorkItem() bool {
+	item, ok := dc.queue.Dequeue()
+	if !ok {
+		return false
+	}
+
+	key := item.(string)
+	err := dc.sync(key)
+	if err != nil {
+		logger.DisruptionControllerLogger.Printf("[sync] err: %v\n", err)
+		dc.queue.Enqueue(key)
+		return false
+	}
+	return true
+}
+
+func (dc *disruptionController) sync(key string) error {
+	item, exist := dc.PdbInformer.Get(key)
+	if !exist {
+		// budget deleted
+		return nil
+	}
+	pdb := item.(*core.PodDisruptionBudget)
+
+	var pods []*core.Pod
+	for _, podItem := range dc.PodInformer.List() {
+		pod := podItem.(*core.Pod)
+		if pdb.MatchPod(pod) {
+			pods = append(pods, pod)
+		}
+	}
+
+	newStatus := computeStatus(pdb, pods, dc.getReplicaSetReplicas, time.Now())
+	if reflect.DeepEqual(newStatus, pdb.Status) {
+		return nil
+	}
+
+	// Put is version checked, so that a concurrent eviction recorded in
+	// status by apiserver is never overwritten by a stale status
+	newPdb := *pdb
+	newPdb.Status = newStatus
+	code, _, err := dc.PdbClient.Put(newPdb.UID, &newPdb)
+	if err != nil {
+		if code == http.StatusConflict {
+			logger.DisruptionControllerLogger.Printf("[sync] pdb %v modified by others, retry later\n", pdb.Name)
+		}
+		return err
+	}
+	logger.DisruptionControllerLogger.Printf("[sync] pdb %v: currentHealthy %v, desiredHealthy %v, disruptionsAllowed %v\n",
+		pdb.Name, newStatus.CurrentHealthy, newStatus.DesiredHealthy, newStatus.DisruptionsAllowed)
+	return nil
+}
+
+func (dc *disruptionController) getReplicaSetReplicas(uid types.UID) (int32, bool) {
+	item, exist := dc.RsInformer.Get(uid)
+	if !exist {
+		return 0, false
+	}
+	return item.(*core.ReplicaSet).Spec.Replicas, true
+}
+
+// computeStatus computes status of pdb from pods selected by it.
+// getReplicas returns desired replicas of ReplicaSet with given uid, it is
+// used to count expected pods when MaxUnavailable is specified.
+func computeStatus(pdb *core.PodDisruptionBudget, pods []*core.Pod, getReplicas func(uid types.UID) (int32, bool), now time.Time) core.PodDisruptionBudgetStatus {
+	status := core.PodDisruptionBudgetStatus{}
+
+	// keep pods evicted recently but still seen, they are going away
+	existing := make(map[types.UID]bool, len(pods))
+	for _, pod := range pods {
+		existing[pod.UID] = true
+	}
+	for uid, evictedAt := range pdb.Status.DisruptedPods {
+		if !existing[uid] || now.Sub(evictedAt) > config.DisruptedPodDeletionTimeout {
+			continue
+		}
+		if status.DisruptedPods == nil {
+			status.DisruptedPods = make(map[string]types.Time)
+		}
+		status.DisruptedPods[uid] = evictedAt
+	}
+
+	for _, pod := range pods {
+		if _, disrupted := status.DisruptedPods[pod.UID]; disrupted {
+			continue
+		}
+		if pod.IsReady() {
+			status.CurrentHealthy++
+		}
+	}
+
+	if pdb.Spec.MaxUnavailable != nil {
+		status.ExpectedPods = countExpectedPods(pods, getReplicas)
+		status.DesiredHealthy = status.ExpectedPods - *pdb.Spec.MaxUnavailable
+		if status.DesiredHealthy < 0 {
+			status.DesiredHealthy = 0
+		}
+	} else {
+		status.ExpectedPods = int32(len(pods))
+		// budget without minAvailable and maxUnavailable keeps at least one pod
+		status.DesiredHealthy = 1
+		if pdb.Spec.MinAvailable != nil {
+			status.DesiredHealthy = *pdb.Spec.MinAvailable
+		}
+	}
+
+	status.DisruptionsAllowed = status.CurrentHealthy - status.DesiredHealthy
+	if status.DisruptionsAllowed < 0 {
+		status.DisruptionsAllowed = 0
+	}
+	return status
+}
+
+// countExpectedPods counts replicas of ReplicaSets owning pods, pods not
+// owned by any existing ReplicaSet are counted by themselves
+func countExpectedPods(pods []*core.Pod, getReplicas func(uid types.UID) (int32, bool)) int32 {
+	var expected int32
+	counted := make(map[types.UID]bool)
+	for _, pod := range pods {
+		hasRsOwner, owner := meta.HasOwnerKind(types.ReplicasetObjectType, pod.OwnerReferences)
+		if hasRsOwner {
+			if counted[owner.UID] {
+				continue
+			}
+			if replicas, ok := getReplicas(owner.UID); ok {
+				counted[owner.UID] = true
+				expected += replicas
+				continue
+			}
+		}
+		expected++
+	}
+	return expected
+}
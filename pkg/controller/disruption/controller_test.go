@@ -0,0 +1,113 @@
+package disruption
+
+import (
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/meta"
+	"minik8s/pkg/api/types"
+	"testing"
+	"time"
+)
+
+func newTestPod(uid string, phase core.PodPhase, rsUID string) *core.Pod {
+	pod := &core.Pod{}
+	pod.UID = uid
+	pod.Status.Phase = phase
+	if phase == core.PodRunning {
+		pod.Status.Conditions = []core.PodCondition{{Type: core.PodReady, Status: core.ConditionTrue}}
+	}
+	if rsUID != "" {
+		pod.OwnerReferences = []meta.OwnerReference{{Kind: string(types.ReplicasetObjectType), UID: rsUID}}
+	}
+	return pod
+}
+
+func TestComputeStatus(t *testing.T) {
+	now := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	one, two := int32(1), int32(2)
+	replicas := func(uid types.UID) (int32, bool) {
+		if uid == "rs1" {
+			return 4, true
+		}
+		return 0, false
+	}
+	pods := []*core.Pod{
+		newTestPod("p1", core.PodRunning, "rs1"),
+		newTestPod("p2", core.PodRunning, "rs1"),
+		newTestPod("p3", core.PodRunning, "rs1"),
+		newTestPod("p4", core.PodPending, "rs1"),
+	}
+
+	tests := []struct {
+		name              string
+		spec              core.PodDisruptionBudgetSpec
+		disrupted         map[string]time.Time
+		wantHealthy       int32
+		wantDesired       int32
+		wantExpected      int32
+		wantAllowed       int32
+		wantDisruptedPods int
+	}{
+		{
+			name:         "min available",
+			spec:         core.PodDisruptionBudgetSpec{MinAvailable: &two},
+			wantHealthy:  3,
+			wantDesired:  2,
+			wantExpected: 4,
+			wantAllowed:  1,
+		},
+		{
+			name:         "max unavailable counts replicas of owner",
+			spec:         core.PodDisruptionBudgetSpec{MaxUnavailable: &one},
+			wantHealthy:  3,
+			wantDesired:  3,
+			wantExpected: 4,
+			wantAllowed:  0,
+		},
+		{
+			name:              "disrupted pod not healthy",
+			spec:              core.PodDisruptionBudgetSpec{MinAvailable: &two},
+			disrupted:         map[string]time.Time{"p1": now.Add(-time.Second)},
+			wantHealthy:       2,
+			wantDesired:       2,
+			wantExpected:      4,
+			wantAllowed:       0,
+			wantDisruptedPods: 1,
+		},
+		{
+			name: "expired and deleted disrupted pods dropped",
+			spec: core.PodDisruptionBudgetSpec{MinAvailable: &two},
+			disrupted: map[string]time.Time{
+				"p1":   now.Add(-config.DisruptedPodDeletionTimeout - time.Second),
+				"gone": now,
+			},
+			wantHealthy:  3,
+			wantDesired:  2,
+			wantExpected: 4,
+			wantAllowed:  1,
+		},
+		{
+			name:         "default keeps one pod",
+			wantHealthy:  3,
+			wantDesired:  1,
+			wantExpected: 4,
+			wantAllowed:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdb := &core.PodDisruptionBudget{Spec: tt.spec}
+			pdb.Status.DisruptedPods = tt.disrupted
+			got := computeStatus(pdb, pods, replicas, now)
+			if got.CurrentHealthy != tt.wantHealthy || got.DesiredHealthy != tt.wantDesired ||
+				got.ExpectedPods != tt.wantExpected || got.DisruptionsAllowed != tt.wantAllowed {
+				t.Errorf("computeStatus() = %+v, want healthy %v desired %v expected %v allowed %v",
+					got, tt.wantHealthy, tt.wantDesired, tt.wantExpected, tt.wantAllowed)
+			}
+			if len(got.DisruptedPods) != tt.wantDisruptedPods {
+				t.Errorf("computeStatus() DisruptedPods = %v, want %v entries", got.DisruptedPods, tt.wantDisruptedPods)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"minik8s/pkg/api/meta"
 	"minik8s/pkg/api/types"
 	client "minik8s/pkg/apiclient/interface"
+	"minik8s/pkg/apiclient/record"
 	"minik8s/pkg/controller/cache"
 	"minik8s/pkg/logger"
 	"strings"
@@ -17,7 +18,10 @@ type DnsController interface {
 	Run(ctx context.Context)
 }
 
-func NewDnsController(podClient client.Interface, serviceClient client.Interface, DnsInformer cache.Informer, DnsClient client.Interface) DnsController {
+// Component is the source of events recorded by DnsController
+const Component = "dns-controller"
+
+func NewDnsController(podClient client.Interface, serviceClient client.Interface, DnsInformer cache.Informer, DnsClient client.Interface, recorder record.EventRecorder) DnsController {
 
 	dnsc := &dnsController{
 		Kind:          string(types.DnsObjectType),
@@ -26,6 +30,7 @@ func NewDnsController(podClient client.Interface, serviceClient client.Interface
 		DnsInformer:   DnsInformer,
 		DnsClient:     DnsClient,
 		queue:         cache.NewWorkQueue(),
+		recorder:      recorder,
 	}
 
 	_ = dnsc.DnsInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -44,6 +49,7 @@ type dnsController struct {
 	ServiceClient client.Interface
 	DnsClient     client.Interface
 	queue         cache.WorkQueue
+	recorder      record.EventRecorder
 }
 
 func (dnsc *dnsController) Run(ctx context.Context) {
@@ -159,8 +165,10 @@ func (dnsc *dnsController) processDNSCreate(dns *core.DNS) error {
 	}
 	_, pr, err := dnsc.PodClient.Post(pod)
 	if err != nil {
+		dnsc.recorder.Eventf(dns, core.EventTypeWarning, "FailedCreate", "Error creating gateway pod %v: %v", pod.Name, err)
 		return err
 	}
+	dnsc.recorder.Eventf(dns, core.EventTypeNormal, "SuccessfulCreate", "Created gateway pod: %v", pod.Name)
 	svc := &core.Service{
 		TypeMeta: meta.CreateTypeMeta(types.ServiceObjectType),
 		ObjectMeta: meta.ObjectMeta{
@@ -184,8 +192,10 @@ func (dnsc *dnsController) processDNSCreate(dns *core.DNS) error {
 	}
 	_, sr, err := dnsc.ServiceClient.Post(svc)
 	if err != nil {
+		dnsc.recorder.Eventf(dns, core.EventTypeWarning, "FailedCreate", "Error creating gateway service %v: %v", svc.Name, err)
 		return err
 	}
+	dnsc.recorder.Eventf(dns, core.EventTypeNormal, "SuccessfulCreate", "Created gateway service: %v", svc.Name)
 
 	s := 409
 	for s != 200 {
@@ -0,0 +1,74 @@
+package eventgc
+
+import (
+	"context"
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	client "minik8s/pkg/apiclient/interface"
+	"minik8s/pkg/logger"
+	"net/http"
+	"time"
+)
+
+// EventGCController deletes Events not updated for config.EventTTL, so that
+// Events of long running or deleted objects don't accumulate in etcd.
+type EventGCController interface {
+	Run(ctx context.Context)
+}
+
+func NewEventGCController(eventClient client.Interface) EventGCController {
+	return &eventGCController{
+		EventClient: eventClient,
+	}
+}
+
+type eventGCController struct {
+	EventClient client.Interface
+}
+
+func (gc *eventGCController) Run(ctx context.Context) {
+
+	go func() {
+		logger.EventGCControllerLogger.Printf("[EventGCController] start\n")
+		defer logger.EventGCControllerLogger.Printf("[EventGCController] finish\n")
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(config.EventGCPeriod):
+				gc.gc(time.Now())
+			}
+		}
+	}()
+	return
+}
+
+// gc deletes all Events expired at now
+func (gc *eventGCController) gc(now time.Time) {
+	eventList, err := gc.EventClient.GetAll()
+	if err != nil {
+		logger.EventGCControllerLogger.Printf("[EventGCController] list events failed, err: %v\n", err)
+		return
+	}
+	for _, item := range eventList.GetIApiObjectArr() {
+		event := item.(*core.Event)
+		if !isExpired(event, now) {
+			continue
+		}
+		// event may be deleted already
+		if code, _, err := gc.EventClient.Delete(event.UID); err != nil && code != http.StatusNotFound {
+			logger.EventGCControllerLogger.Printf("[EventGCController] delete event %v failed, err: %v\n", event.UID, err)
+		}
+	}
+}
+
+// isExpired returns true if event has not occurred again for config.EventTTL
+// since its LastTimestamp, or FirstTimestamp if LastTimestamp is not set
+func isExpired(event *core.Event, now time.Time) bool {
+	last := event.LastTimestamp
+	if last.IsZero() {
+		last = event.FirstTimestamp
+	}
+	return now.Sub(last) > config.EventTTL
+}
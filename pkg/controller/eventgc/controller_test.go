@@ -0,0 +1,47 @@
+package eventgc
+
+import (
+	"minik8s/pkg/api"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/meta"
+	client "minik8s/pkg/apiclient/interface"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type fakeEventClient struct {
+	client.Interface
+	events  *core.EventList
+	deleted []string
+}
+
+func (c *fakeEventClient) GetAll() (core.IApiObjectList, error) {
+	return c.events, nil
+}
+
+func (c *fakeEventClient) Delete(name string) (int, *api.DeleteResponse, error) {
+	c.deleted = append(c.deleted, name)
+	return http.StatusOK, &api.DeleteResponse{}, nil
+}
+
+func TestGC(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	newEvent := func(uid string, first, last time.Time) core.Event {
+		return core.Event{ObjectMeta: meta.ObjectMeta{UID: uid}, FirstTimestamp: first, LastTimestamp: last}
+	}
+	eventClient := &fakeEventClient{events: &core.EventList{Items: []core.Event{
+		newEvent("recent", now.Add(-time.Minute), now.Add(-time.Minute)),
+		newEvent("expired", now.Add(-2*time.Hour), now.Add(-2*time.Hour)),
+		// occurred again recently, kept although first recorded long ago
+		newEvent("aggregated", now.Add(-3*time.Hour), now.Add(-10*time.Minute)),
+		newEvent("no-last-timestamp", now.Add(-2*time.Hour), time.Time{}),
+	}}}
+	gc := &eventGCController{EventClient: eventClient}
+
+	gc.gc(now)
+	want := []string{"expired", "no-last-timestamp"}
+	if len(eventClient.deleted) != len(want) || eventClient.deleted[0] != want[0] || eventClient.deleted[1] != want[1] {
+		t.Errorf("deleted events = %v, want %v", eventClient.deleted, want)
+	}
+}
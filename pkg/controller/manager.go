@@ -6,9 +6,13 @@ import (
 	"minik8s/pkg/apiclient"
 	client "minik8s/pkg/apiclient/interface"
 	"minik8s/pkg/apiclient/listwatch"
+	"minik8s/pkg/apiclient/record"
 	"minik8s/pkg/controller/cache"
+	"minik8s/pkg/controller/disruption"
 	"minik8s/pkg/controller/dns"
-	"minik8s/pkg/controller/pod"
+	"minik8s/pkg/controller/eventgc"
+	"minik8s/pkg/controller/nodelifecycle"
+	"minik8s/pkg/controller/persistentvolume"
 	"minik8s/pkg/controller/podautoscaler"
 	"minik8s/pkg/controller/replicaset"
 	"minik8s/pkg/controller/serverless"
@@ -27,7 +31,13 @@ func NewControllerManager() Manager {
 	hpaClient, hpaInformer := NewDefaultClientSet(types.HorizontalPodAutoscalerObjectType)
 	dnsClient, dnsInformer := NewDefaultClientSet(types.DnsObjectType)
 	funcTemplateClient, funcTemplateInformer := NewDefaultClientSet(types.FuncTemplateObjectType)
+	nodeClient, nodeInformer := NewDefaultClientSet(types.NodeObjectType)
+	_, heartbeatInformer := NewDefaultClientSet(types.HeartbeatObjectType)
+	pdbClient, pdbInformer := NewDefaultClientSet(types.PodDisruptionBudgetObjectType)
+	pvClient, pvInformer := NewDefaultClientSet(types.PersistentVolumeObjectType)
+	pvcClient, pvcInformer := NewDefaultClientSet(types.PersistentVolumeClaimObjectType)
 	serviceClient, _ := apiclient.NewRESTClient(types.ServiceObjectType)
+	eventClient, _ := apiclient.NewRESTClient(types.EventObjectType)
 
 	return &manager{
 		// Client
@@ -37,18 +47,30 @@ func NewControllerManager() Manager {
 		serviceClient:      serviceClient,
 		dnsClient:          dnsClient,
 		funcTemplateClient: funcTemplateClient,
+		nodeClient:         nodeClient,
+		pdbClient:          pdbClient,
+		pvClient:           pvClient,
+		pvcClient:          pvcClient,
 		// Informer
 		podInformer:          podInformer,
 		rsInformer:           rsInformer,
 		hpaInformer:          hpaInformer,
 		dnsInformer:          dnsInformer,
 		funcTemplateInformer: funcTemplateInformer,
+		nodeInformer:         nodeInformer,
+		heartbeatInformer:    heartbeatInformer,
+		pdbInformer:          pdbInformer,
+		pvInformer:           pvInformer,
+		pvcInformer:          pvcInformer,
 		// Controller
-		replicaSetController: replicaset.NewReplicaSetController(podInformer, podClient, rsInformer, rsClient),
-		horizontalController: podautoscaler.NewHorizontalController(podInformer, podClient, hpaInformer, hpaClient, rsInformer, rsClient),
-		dnsController:        dns.NewDnsController(podClient, serviceClient, dnsInformer, dnsClient),
-		serverlessController: serverless.NewServerlessController(funcTemplateInformer, funcTemplateClient, rsClient, serviceClient, podClient),
-		podController:        pod.NewPodController(podClient, podInformer),
+		replicaSetController:       replicaset.NewReplicaSetController(podInformer, podClient, rsInformer, rsClient, record.NewRecorder(replicaset.Component, "")),
+		horizontalController:       podautoscaler.NewHorizontalController(podInformer, podClient, hpaInformer, hpaClient, rsInformer, rsClient, record.NewRecorder(podautoscaler.Component, "")),
+		dnsController:              dns.NewDnsController(podClient, serviceClient, dnsInformer, dnsClient, record.NewRecorder(dns.Component, "")),
+		serverlessController:       serverless.NewServerlessController(funcTemplateInformer, funcTemplateClient, rsClient, serviceClient, podClient, record.NewRecorder(serverless.Component, "")),
+		nodeLifecycleController:    nodelifecycle.NewNodeLifecycleController(nodeInformer, nodeClient, podInformer, podClient, heartbeatInformer),
+		disruptionController:       disruption.NewDisruptionController(pdbInformer, pdbClient, podInformer, rsInformer),
+		persistentVolumeController: persistentvolume.NewPersistentVolumeController(pvInformer, pvClient, pvcInformer, pvcClient, nodeInformer),
+		eventGCController:          eventgc.NewEventGCController(eventClient),
 	}
 }
 
@@ -60,18 +82,30 @@ type manager struct {
 	serviceClient      client.Interface
 	dnsClient          client.Interface
 	funcTemplateClient client.Interface
+	nodeClient         client.Interface
+	pdbClient          client.Interface
+	pvClient           client.Interface
+	pvcClient          client.Interface
 	// Informer
 	podInformer          cache.Informer
 	rsInformer           cache.Informer
 	hpaInformer          cache.Informer
 	dnsInformer          cache.Informer
 	funcTemplateInformer cache.Informer
+	nodeInformer         cache.Informer
+	heartbeatInformer    cache.Informer
+	pdbInformer          cache.Informer
+	pvInformer           cache.Informer
+	pvcInformer          cache.Informer
 	// Controller
-	replicaSetController replicaset.ReplicaSetController
-	horizontalController podautoscaler.HorizontalController
-	dnsController        dns.DnsController
-	serverlessController serverless.ServerlessController
-	podController        pod.PodController
+	replicaSetController       replicaset.ReplicaSetController
+	horizontalController       podautoscaler.HorizontalController
+	dnsController              dns.DnsController
+	serverlessController       serverless.ServerlessController
+	nodeLifecycleController    nodelifecycle.NodeLifecycleController
+	disruptionController       disruption.DisruptionController
+	persistentVolumeController persistentvolume.PersistentVolumeController
+	eventGCController          eventgc.EventGCController
 }
 
 func NewDefaultClientSet(objType types.ApiObjectType) (client.Interface, cache.Informer) {
@@ -95,11 +129,19 @@ func (m *manager) Run(ctx context.Context, cancel context.CancelFunc) {
 	m.hpaInformer.Run(ctx.Done())
 	m.dnsInformer.Run(ctx.Done())
 	m.funcTemplateInformer.Run(ctx.Done())
+	m.nodeInformer.Run(ctx.Done())
+	m.heartbeatInformer.Run(ctx.Done())
+	m.pdbInformer.Run(ctx.Done())
+	m.pvInformer.Run(ctx.Done())
+	m.pvcInformer.Run(ctx.Done())
 
 	// Run Controller
 	m.replicaSetController.Run(ctx)
 	m.horizontalController.Run(ctx)
 	m.dnsController.Run(ctx)
 	m.serverlessController.Run(ctx)
-	m.podController.Run(ctx)
+	m.nodeLifecycleController.Run(ctx)
+	m.disruptionController.Run(ctx)
+	m.persistentVolumeController.Run(ctx)
+	m.eventGCController.Run(ctx)
 }
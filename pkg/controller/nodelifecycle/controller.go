@@ -0,0 +1,306 @@
+package nodelifecycle
+
+import (
+	"context"
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+	client "minik8s/pkg/apiclient/interface"
+	"minik8s/pkg/controller/cache"
+	"minik8s/pkg/logger"
+	"minik8s/pkg/node"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// NodeLifecycleController monitors health of nodes through heartbeats and
+// node conditions reported by kubelet. Nodes whose heartbeat stops are marked
+// Unknown, not ready or unknown nodes are tainted with NoExecute taints, and
+// pods not tolerating the taints are evicted after a grace period.
+type NodeLifecycleController interface {
+	Run(ctx context.Context)
+}
+
+func NewNodeLifecycleController(nodeInformer cache.Informer, nodeClient client.Interface, podInformer cache.Informer,
+	podClient client.Interface, heartbeatInformer cache.Informer) NodeLifecycleController {
+
+	nc := &nodeLifecycleController{
+		NodeInformer:      nodeInformer,
+		NodeClient:        nodeClient,
+		PodInformer:       podInformer,
+		PodClient:         podClient,
+		HeartbeatInformer: heartbeatInformer,
+		nodeFirstSeen:     make(map[types.UID]time.Time),
+	}
+
+	_ = nc.NodeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: nc.deleteNode,
+	})
+
+	return nc
+}
+
+type nodeLifecycleController struct {
+	NodeInformer      cache.Informer
+	NodeClient        client.Interface
+	PodInformer       cache.Informer
+	PodClient         client.Interface
+	HeartbeatInformer cache.Informer
+
+	// nodeFirstSeen records when the controller first observes a node,
+	// used as last heartbeat time of node which has never sent heartbeat
+	nodeFirstSeen     map[types.UID]time.Time
+	nodeFirstSeenLock sync.Mutex
+}
+
+func (nc *nodeLifecycleController) Run(ctx context.Context) {
+
+	go func() {
+		logger.NodeLifecycleControllerLogger.Printf("[NodeLifecycleController] start\n")
+		defer logger.NodeLifecycleControllerLogger.Printf("[NodeLifecycleController] finish\n")
+
+		nc.runWorker(ctx)
+
+		// wait for controller manager stop
+		<-ctx.Done()
+	}()
+	return
+}
+
+func (nc *nodeLifecycleController) deleteNode(obj interface{}) {
+	no := obj.(*core.Node)
+	nc.nodeFirstSeenLock.Lock()
+	delete(nc.nodeFirstSeen, no.UID)
+	nc.nodeFirstSeenLock.Unlock()
+}
+
+const defaultWorkerSleepInterval = config.NodeMonitorPeriod
+
+func (nc *nodeLifecycleController) runWorker(ctx context.Context) {
+	// go wait.UntilWithContext(ctx, nc.worker, time.Second)
+	for {
+		select {
+		case <-ctx.Done():
+			logger.NodeLifecycleControllerLogger.Printf("[worker] ctx.Done() received, worker of NodeLifecycleController exit\n")
+			return
+		default:
+			nc.monitorNodeHealth()
+			nc.doEvictionPass()
+			time.Sleep(defaultWorkerSleepInterval)
+		}
+	}
+}
+
+// monitorNodeHealth marks conditions of nodes without heartbeat for
+// NodeMonitorGracePeriod as Unknown, and keeps NoExecute taints of node
+// consistent with its Ready condition
+func (nc *nodeLifecycleController) monitorNodeHealth() {
+	for _, item := range nc.NodeInformer.List() {
+		no := item.(*core.Node)
+		if no.Name == node.NameMaster {
+			continue
+		}
+
+		lastHeartbeat := nc.lastHeartbeatTime(no)
+		if time.Since(lastHeartbeat) > config.NodeMonitorGracePeriod {
+			nc.markNodeStatusUnknown(no, lastHeartbeat)
+		}
+
+		nc.reconcileNodeTaints(no)
+	}
+}
+
+// lastHeartbeatTime returns the latest time kubelet of node is known alive
+func (nc *nodeLifecycleController) lastHeartbeatTime(no *core.Node) time.Time {
+	nc.nodeFirstSeenLock.Lock()
+	last, ok := nc.nodeFirstSeen[no.UID]
+	if !ok {
+		last = time.Now()
+		nc.nodeFirstSeen[no.UID] = last
+	}
+	nc.nodeFirstSeenLock.Unlock()
+
+	for _, item := range nc.HeartbeatInformer.List() {
+		hb := item.(*core.Heartbeat)
+		if hb.Spec.NodeUID == no.UID && hb.Status.Timestamp.After(last) {
+			last = hb.Status.Timestamp
+		}
+	}
+	if ready := no.Status.GetCondition(core.NodeReady); ready != nil && ready.LastHeartbeatTime.After(last) {
+		last = ready.LastHeartbeatTime
+	}
+	return last
+}
+
+var nodeConditionTypes = []core.NodeConditionType{
+	core.NodeReady,
+	core.NodeMemoryPressure,
+	core.NodeDiskPressure,
+	core.NodePIDPressure,
+	core.NodeNetworkUnavailable,
+}
+
+// markNodeStatusUnknown sets all conditions of node to Unknown
+func (nc *nodeLifecycleController) markNodeStatusUnknown(no *core.Node, lastHeartbeat time.Time) {
+	now := time.Now()
+	status := no.Status
+	status.Conditions = make([]core.NodeCondition, 0, len(nodeConditionTypes))
+	changed := false
+	for _, ty := range nodeConditionTypes {
+		cond := no.Status.GetCondition(ty)
+		if cond == nil {
+			status.Conditions = append(status.Conditions, core.NodeCondition{
+				Type:               ty,
+				Status:             core.ConditionUnknown,
+				LastHeartbeatTime:  lastHeartbeat,
+				LastTransitionTime: now,
+				Reason:             "NodeStatusNeverUpdated",
+				Message:            "Kubelet never posted node status.",
+			})
+			changed = true
+			continue
+		}
+		newCond := *cond
+		if newCond.Status != core.ConditionUnknown {
+			newCond.Status = core.ConditionUnknown
+			newCond.LastTransitionTime = now
+			newCond.Reason = "NodeStatusUnknown"
+			newCond.Message = "Kubelet stopped posting node status."
+			changed = true
+		}
+		status.Conditions = append(status.Conditions, newCond)
+	}
+	if !changed {
+		return
+	}
+
+	logger.NodeLifecycleControllerLogger.Printf("[markNodeStatusUnknown] node %v heartbeat lost since %v, mark status unknown\n", no.Name, lastHeartbeat)
+	_, _, err := nc.NodeClient.PutStatus(no.UID, &status)
+	if err != nil {
+		logger.NodeLifecycleControllerLogger.Printf("[markNodeStatusUnknown] PutStatus of node %v failed, err: %v\n", no.Name, err)
+		return
+	}
+	no.Status = status
+}
+
+// reconcileNodeTaints adds not-ready or unreachable NoExecute taint
+// according to Ready condition of node, and removes the other one
+func (nc *nodeLifecycleController) reconcileNodeTaints(no *core.Node) {
+	var wantKey string
+	ready := no.Status.GetCondition(core.NodeReady)
+	if ready != nil {
+		switch ready.Status {
+		case core.ConditionFalse:
+			wantKey = core.TaintNodeNotReady
+		case core.ConditionUnknown:
+			wantKey = core.TaintNodeUnreachable
+		}
+	}
+
+	taints := make([]core.Taint, 0, len(no.Spec.Taints)+1)
+	has := false
+	for _, t := range no.Spec.Taints {
+		if t.Key == core.TaintNodeNotReady || t.Key == core.TaintNodeUnreachable {
+			if t.Key != wantKey || t.Effect != core.TaintEffectNoExecute {
+				continue
+			}
+			has = true
+		}
+		taints = append(taints, t)
+	}
+	if wantKey != "" && !has {
+		now := time.Now()
+		taints = append(taints, core.Taint{
+			Key:       wantKey,
+			Effect:    core.TaintEffectNoExecute,
+			TimeAdded: &now,
+		})
+	}
+	if reflect.DeepEqual(taints, no.Spec.Taints) || (len(taints) == 0 && len(no.Spec.Taints) == 0) {
+		return
+	}
+
+	// get the latest node, since status may have been updated by kubelet
+	nodeItem, err := nc.NodeClient.Get(no.UID)
+	if err != nil {
+		logger.NodeLifecycleControllerLogger.Printf("[reconcileNodeTaints] Get node %v failed, err: %v\n", no.Name, err)
+		return
+	}
+	latest := nodeItem.(*core.Node)
+	latest.Spec.Taints = taints
+	_, _, err = nc.NodeClient.Put(latest.UID, latest)
+	if err != nil {
+		logger.NodeLifecycleControllerLogger.Printf("[reconcileNodeTaints] Put node %v failed, err: %v\n", no.Name, err)
+		return
+	}
+	logger.NodeLifecycleControllerLogger.Printf("[reconcileNodeTaints] node %v taints updated to %v\n", no.Name, taints)
+}
+
+// doEvictionPass evicts pods on nodes with NoExecute taints,
+// once the pods have not tolerated the taints for long enough
+func (nc *nodeLifecycleController) doEvictionPass() {
+	nodes := make(map[string]*core.Node)
+	for _, item := range nc.NodeInformer.List() {
+		no := item.(*core.Node)
+		for _, t := range no.Spec.Taints {
+			if t.Effect == core.TaintEffectNoExecute {
+				nodes[no.Name] = no
+				break
+			}
+		}
+	}
+	if len(nodes) == 0 {
+		return
+	}
+
+	now := time.Now()
+	for _, item := range nc.PodInformer.List() {
+		pod := item.(*core.Pod)
+		no, ok := nodes[pod.Spec.NodeName]
+		if !ok {
+			continue
+		}
+		deadline, evict := getEvictionDeadline(pod, no.Spec.Taints)
+		if !evict || now.Before(deadline) {
+			continue
+		}
+
+		logger.NodeLifecycleControllerLogger.Printf("[doEvictionPass] evict pod %v on node %v, taints %v\n", pod.UID, no.Name, no.Spec.Taints)
+		_, _, err := nc.PodClient.Delete(pod.UID)
+		if err != nil {
+			logger.NodeLifecycleControllerLogger.Printf("[doEvictionPass] delete pod %v failed, err: %v\n", pod.UID, err)
+		}
+	}
+}
+
+// getEvictionDeadline returns the time after which pod should be evicted due to
+// NoExecute taints, evict is false if pod tolerates all the taints forever.
+// Taints not tolerated by pod are tolerated for config.PodEvictionTimeout.
+func getEvictionDeadline(pod *core.Pod, taints []core.Taint) (deadline time.Time, evict bool) {
+	for i := range taints {
+		taint := &taints[i]
+		if taint.Effect != core.TaintEffectNoExecute {
+			continue
+		}
+		added := time.Now()
+		if taint.TimeAdded != nil {
+			added = *taint.TimeAdded
+		}
+
+		tolerate := config.PodEvictionTimeout
+		if toleration := pod.Spec.FindMatchingToleration(taint); toleration != nil {
+			if toleration.TolerationSeconds == nil {
+				continue
+			}
+			tolerate = time.Duration(*toleration.TolerationSeconds) * time.Second
+		}
+
+		d := added.Add(tolerate)
+		if !evict || d.Before(deadline) {
+			deadline = d
+			evict = true
+		}
+	}
+	return deadline, evict
+}
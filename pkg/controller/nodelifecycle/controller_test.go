@@ -0,0 +1,61 @@
+package nodelifecycle
+
+import (
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	"testing"
+	"time"
+)
+
+func TestGetEvictionDeadline(t *testing.T) {
+	added := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	unreachable := core.Taint{Key: core.TaintNodeUnreachable, Effect: core.TaintEffectNoExecute, TimeAdded: &added}
+	noSchedule := core.Taint{Key: "dedicated", Value: "gpu", Effect: core.TaintEffectNoSchedule}
+	sixty := int64(60)
+
+	tests := []struct {
+		name         string
+		tolerations  []core.Toleration
+		taints       []core.Taint
+		wantDeadline time.Time
+		wantEvict    bool
+	}{
+		{
+			name:         "not tolerated",
+			taints:       []core.Taint{unreachable},
+			wantDeadline: added.Add(config.PodEvictionTimeout),
+			wantEvict:    true,
+		},
+		{
+			name:         "tolerated for seconds",
+			tolerations:  []core.Toleration{{Key: core.TaintNodeUnreachable, Operator: core.TolerationOpExists, Effect: core.TaintEffectNoExecute, TolerationSeconds: &sixty}},
+			taints:       []core.Taint{unreachable},
+			wantDeadline: added.Add(60 * time.Second),
+			wantEvict:    true,
+		},
+		{
+			name:        "tolerated forever",
+			tolerations: []core.Toleration{{Operator: core.TolerationOpExists}},
+			taints:      []core.Taint{unreachable},
+			wantEvict:   false,
+		},
+		{
+			name:      "no NoExecute taint",
+			taints:    []core.Taint{noSchedule},
+			wantEvict: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &core.Pod{}
+			pod.Spec.Tolerations = tt.tolerations
+			gotDeadline, gotEvict := getEvictionDeadline(pod, tt.taints)
+			if gotEvict != tt.wantEvict {
+				t.Errorf("getEvictionDeadline() evict = %v, want %v", gotEvict, tt.wantEvict)
+			}
+			if tt.wantEvict && !gotDeadline.Equal(tt.wantDeadline) {
+				t.Errorf("getEvictionDeadline() deadline = %v, want %v", gotDeadline, tt.wantDeadline)
+			}
+		})
+	}
+}
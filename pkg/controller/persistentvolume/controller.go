@@ -0,0 +1,478 @@
+package persistentvolume
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/meta"
+	client "minik8s/pkg/apiclient/interface"
+	"minik8s/pkg/controller/cache"
+	"minik8s/pkg/logger"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// PersistentVolumeController binds PersistentVolumeClaims to PersistentVolumes,
+// provisions local-path volumes for claims whose node is selected by scheduler,
+// and releases volumes whose claims are deleted.
+type PersistentVolumeController interface {
+	Run(ctx context.Context)
+}
+
+func NewPersistentVolumeController(pvInformer cache.Informer, pvClient client.Interface, pvcInformer cache.Informer, pvcClient client.Interface, nodeInformer cache.Informer) PersistentVolumeController {
+
+	pc := &persistentVolumeController{
+		PvInformer:   pvInformer,
+		PvClient:     pvClient,
+		PvcInformer:  pvcInformer,
+		PvcClient:    pvcClient,
+		NodeInformer: nodeInformer,
+		volumeQueue:  cache.NewWorkQueue(),
+		claimQueue:   cache.NewWorkQueue(),
+	}
+
+	_ = pc.PvInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    pc.addVolume,
+		UpdateFunc: pc.updateVolume,
+		DeleteFunc: pc.deleteVolume,
+	})
+
+	_ = pc.PvcInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    pc.addClaim,
+		UpdateFunc: pc.updateClaim,
+		DeleteFunc: pc.deleteClaim,
+	})
+
+	return pc
+}
+
+type persistentVolumeController struct {
+	PvInformer   cache.Informer
+	PvClient     client.Interface
+	PvcInformer  cache.Informer
+	PvcClient    client.Interface
+	NodeInformer cache.Informer
+
+	// queues of volume and claim uid
+	volumeQueue cache.WorkQueue
+	claimQueue  cache.WorkQueue
+}
+
+func (pc *persistentVolumeController) Run(ctx context.Context) {
+
+	go func() {
+		logger.PersistentVolumeControllerLogger.Printf("[PersistentVolumeController] start\n")
+		defer logger.PersistentVolumeControllerLogger.Printf("[PersistentVolumeController] finish\n")
+
+		go pc.resync(ctx)
+		pc.runWorker(ctx)
+
+		// wait for controller manager stop
+		<-ctx.Done()
+	}()
+	return
+}
+
+func (pc *persistentVolumeController) enqueueVolume(volume *core.PersistentVolume) {
+	pc.volumeQueue.Enqueue(volume.UID)
+	logger.PersistentVolumeControllerLogger.Printf("enqueueVolume key %s\n", volume.UID)
+}
+
+func (pc *persistentVolumeController) enqueueClaim(claim *core.PersistentVolumeClaim) {
+	pc.claimQueue.Enqueue(claim.UID)
+	logger.PersistentVolumeControllerLogger.Printf("enqueueClaim key %s\n", claim.UID)
+}
+
+func (pc *persistentVolumeController) addVolume(obj interface{}) {
+	pc.enqueueVolume(obj.(*core.PersistentVolume))
+}
+
+func (pc *persistentVolumeController) updateVolume(old, cur interface{}) {
+	oldVolume := old.(*core.PersistentVolume)
+	curVolume := cur.(*core.PersistentVolume)
+	if oldVolume.ResourceVersion == curVolume.ResourceVersion {
+		return
+	}
+	pc.enqueueVolume(curVolume)
+}
+
+// deleteVolume enqueues the claim bound to volume, so that it is marked as lost
+func (pc *persistentVolumeController) deleteVolume(obj interface{}) {
+	volume := obj.(*core.PersistentVolume)
+	for _, item := range pc.PvcInformer.List() {
+		claim := item.(*core.PersistentVolumeClaim)
+		if claim.Spec.VolumeName == volume.Name {
+			pc.enqueueClaim(claim)
+		}
+	}
+}
+
+func (pc *persistentVolumeController) addClaim(obj interface{}) {
+	pc.enqueueClaim(obj.(*core.PersistentVolumeClaim))
+}
+
+func (pc *persistentVolumeController) updateClaim(old, cur interface{}) {
+	oldClaim := old.(*core.PersistentVolumeClaim)
+	curClaim := cur.(*core.PersistentVolumeClaim)
+	if oldClaim.ResourceVersion == curClaim.ResourceVersion {
+		return
+	}
+	pc.enqueueClaim(curClaim)
+}
+
+// deleteClaim enqueues the volume bound to claim, so that it is released
+func (pc *persistentVolumeController) deleteClaim(obj interface{}) {
+	claim := obj.(*core.PersistentVolumeClaim)
+	for _, item := range pc.PvInformer.List() {
+		volume := item.(*core.PersistentVolume)
+		if volume.Spec.ClaimRef != nil && volume.Spec.ClaimRef.Name == claim.Name {
+			pc.enqueueVolume(volume)
+		}
+	}
+}
+
+// resync enqueues all volumes and claims periodically, so that claims waiting
+// for volumes are retried and released volumes are reclaimed
+func (pc *persistentVolumeController) resync(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(config.PersistentVolumeResyncPeriod):
+			for _, item := range pc.PvInformer.List() {
+				pc.enqueueVolume(item.(*core.PersistentVolume))
+			}
+			for _, item := range pc.PvcInformer.List() {
+				pc.enqueueClaim(item.(*core.PersistentVolumeClaim))
+			}
+		}
+	}
+}
+
+const defaultWorkerSleepInterval = time.Duration(3) * time.Second
+
+func (pc *persistentVolumeController) runWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			logger.PersistentVolumeControllerLogger.Printf("[worker] ctx.Done() received, worker of PersistentVolumeController exit\n")
+			return
+		default:
+			for pc.processNextWorkItem(pc.claimQueue, pc.syncClaim) {
+			}
+			for pc.processNextWorkItem(pc.volumeQueue, pc.syncVolume) {
+			}
+			time.Sleep(defaultWorkerSleepInterval)
+		}
+	}
+}
+
+func (pc *persistentVolumeController) processNextWorkItem(queue cache.WorkQueue, sync func(key string) error) bool {
+	item, ok := queue.Dequeue()
+	if !ok {
+		return false
+	}
+
+	key := item.(string)
+	err := sync(key)
+	if err != nil {
+		logger.PersistentVolumeControllerLogger.Printf("[sync] err: %v\n", err)
+		queue.Enqueue(key)
+		return false
+	}
+	return true
+}
+
+/*--------------------- Claim ---------------------*/
+
+func (pc *persistentVolumeController) syncClaim(key string) error {
+	item, exist := pc.PvcInformer.Get(key)
+	if !exist {
+		// claim deleted
+		return nil
+	}
+	claim := item.(*core.PersistentVolumeClaim)
+
+	if claim.Spec.VolumeName == "" {
+		if claim.IsBindingDelayed() && claim.Annotations[core.AnnSelectedNode] == "" {
+			// wait for the first pod using claim to be scheduled
+			return pc.updateClaimPhase(claim, core.ClaimPending)
+		}
+		volume := findBestMatch(claim, pc.listVolumes(), pc.getSelectedNode(claim))
+		if volume != nil {
+			return pc.bind(volume, claim)
+		}
+		if claim.IsBindingDelayed() {
+			return pc.provision(claim, claim.Annotations[core.AnnSelectedNode])
+		}
+		logger.PersistentVolumeControllerLogger.Printf("[syncClaim] no volume matches claim %v\n", claim.Name)
+		return pc.updateClaimPhase(claim, core.ClaimPending)
+	}
+
+	// claim is bound, or is to be bound to the volume given by user
+	volume := pc.getVolume(claim.Spec.VolumeName)
+	if volume == nil {
+		if claim.Status.Phase == core.ClaimBound || claim.Status.Phase == core.ClaimLost {
+			logger.PersistentVolumeControllerLogger.Printf("[syncClaim] volume %v of claim %v is lost\n", claim.Spec.VolumeName, claim.Name)
+			return pc.updateClaimPhase(claim, core.ClaimLost)
+		}
+		return pc.updateClaimPhase(claim, core.ClaimPending)
+	}
+	if !isReservedFor(volume, claim) {
+		if claim.Status.Phase == core.ClaimBound || claim.Status.Phase == core.ClaimLost {
+			logger.PersistentVolumeControllerLogger.Printf("[syncClaim] volume %v of claim %v is bound to other claim\n", volume.Name, claim.Name)
+			return pc.updateClaimPhase(claim, core.ClaimLost)
+		}
+		return pc.updateClaimPhase(claim, core.ClaimPending)
+	}
+	if volume.Spec.ClaimRef == nil {
+		if err := checkVolumeSatisfyClaim(volume, claim); err != nil {
+			logger.PersistentVolumeControllerLogger.Printf("[syncClaim] volume %v can not be bound to claim %v: %v\n", volume.Name, claim.Name, err)
+			return pc.updateClaimPhase(claim, core.ClaimPending)
+		}
+	}
+	return pc.bind(volume, claim)
+}
+
+// bind binds volume and claim to each other, volume is updated first
+// so that it is not bound to other claims meanwhile
+func (pc *persistentVolumeController) bind(volume *core.PersistentVolume, claim *core.PersistentVolumeClaim) error {
+	claimRef := &core.PersistentVolumeClaimReference{Name: claim.Name, UID: claim.UID}
+	if !reflect.DeepEqual(volume.Spec.ClaimRef, claimRef) || volume.Status.Phase != core.VolumeBound {
+		newVolume := *volume
+		newVolume.Spec.ClaimRef = claimRef
+		newVolume.Status = core.PersistentVolumeStatus{Phase: core.VolumeBound}
+		if err := pc.putVolume(&newVolume); err != nil {
+			return err
+		}
+	}
+
+	status := core.PersistentVolumeClaimStatus{
+		Phase:       core.ClaimBound,
+		AccessModes: volume.Spec.AccessModes,
+		Capacity:    volume.Spec.Capacity,
+	}
+	if claim.Spec.VolumeName == volume.Name && reflect.DeepEqual(claim.Status, status) {
+		return nil
+	}
+	newClaim := *claim
+	newClaim.Spec.VolumeName = volume.Name
+	newClaim.Status = status
+	if err := pc.putClaim(&newClaim); err != nil {
+		return err
+	}
+	logger.PersistentVolumeControllerLogger.Printf("[bind] claim %v bound to volume %v\n", claim.Name, volume.Name)
+	return nil
+}
+
+func (pc *persistentVolumeController) updateClaimPhase(claim *core.PersistentVolumeClaim, phase core.PersistentVolumeClaimPhase) error {
+	if claim.Status.Phase == phase {
+		return nil
+	}
+	newClaim := *claim
+	newClaim.Status.Phase = phase
+	return pc.putClaim(&newClaim)
+}
+
+/*--------------------- Volume ---------------------*/
+
+func (pc *persistentVolumeController) syncVolume(key string) error {
+	item, exist := pc.PvInformer.Get(key)
+	if !exist {
+		// volume deleted
+		return nil
+	}
+	volume := item.(*core.PersistentVolume)
+
+	if volume.Spec.ClaimRef == nil {
+		if volume.Status.Phase == core.VolumeFailed {
+			return nil
+		}
+		return pc.updateVolumePhase(volume, core.VolumeAvailable, "")
+	}
+
+	claim := pc.getClaim(volume.Spec.ClaimRef.Name)
+	if volume.Spec.ClaimRef.UID == "" {
+		// volume is reserved for claim by name, it is bound once the claim is synced
+		if claim != nil {
+			pc.enqueueClaim(claim)
+		}
+		return pc.updateVolumePhase(volume, core.VolumeAvailable, "")
+	}
+
+	if claim == nil || claim.UID != volume.Spec.ClaimRef.UID {
+		// claim is deleted, it may be recreated with the same name
+		switch volume.Status.Phase {
+		case core.VolumeReleased:
+			return pc.reclaim(volume)
+		case core.VolumeFailed:
+			return nil
+		}
+		logger.PersistentVolumeControllerLogger.Printf("[syncVolume] volume %v released by claim %v\n", volume.Name, volume.Spec.ClaimRef.Name)
+		return pc.updateVolumePhase(volume, core.VolumeReleased, "")
+	}
+
+	if claim.Spec.VolumeName != volume.Name {
+		// binding is not finished yet
+		pc.enqueueClaim(claim)
+		return nil
+	}
+	return pc.updateVolumePhase(volume, core.VolumeBound, "")
+}
+
+// reclaim handles released volume by its reclaim policy. Released volumes
+// provisioned by local-path are deleted by kubelet of the node they are on,
+// since their directories can only be removed there.
+func (pc *persistentVolumeController) reclaim(volume *core.PersistentVolume) error {
+	switch volume.Spec.PersistentVolumeReclaimPolicy {
+	case core.PersistentVolumeReclaimDelete:
+		if volume.IsProvisionedBy(core.LocalPathProvisionerName) {
+			return nil
+		}
+		return pc.updateVolumePhase(volume, core.VolumeFailed, "no deleter for volume not provisioned by "+core.LocalPathProvisionerName)
+	default:
+		// retained until the administrator removes claimRef or deletes the volume
+		return nil
+	}
+}
+
+func (pc *persistentVolumeController) updateVolumePhase(volume *core.PersistentVolume, phase core.PersistentVolumePhase, message string) error {
+	if volume.Status.Phase == phase && volume.Status.Message == message {
+		return nil
+	}
+	newVolume := *volume
+	newVolume.Status = core.PersistentVolumeStatus{Phase: phase, Message: message}
+	return pc.putVolume(&newVolume)
+}
+
+/*--------------------- Helpers ---------------------*/
+
+func (pc *persistentVolumeController) putVolume(volume *core.PersistentVolume) error {
+	code, _, err := pc.PvClient.Put(volume.Name, volume)
+	if err != nil {
+		if code == http.StatusConflict {
+			logger.PersistentVolumeControllerLogger.Printf("[putVolume] volume %v modified by others, retry later\n", volume.Name)
+		}
+		return err
+	}
+	return nil
+}
+
+func (pc *persistentVolumeController) putClaim(claim *core.PersistentVolumeClaim) error {
+	code, _, err := pc.PvcClient.Put(claim.Name, claim)
+	if err != nil {
+		if code == http.StatusConflict {
+			logger.PersistentVolumeControllerLogger.Printf("[putClaim] claim %v modified by others, retry later\n", claim.Name)
+		}
+		return err
+	}
+	return nil
+}
+
+func (pc *persistentVolumeController) listVolumes() []*core.PersistentVolume {
+	var volumes []*core.PersistentVolume
+	for _, item := range pc.PvInformer.List() {
+		volumes = append(volumes, item.(*core.PersistentVolume))
+	}
+	return volumes
+}
+
+// getVolume returns volume named name, informer is keyed by uid
+func (pc *persistentVolumeController) getVolume(name string) *core.PersistentVolume {
+	for _, item := range pc.PvInformer.List() {
+		if volume := item.(*core.PersistentVolume); volume.Name == name {
+			return volume
+		}
+	}
+	return nil
+}
+
+// getClaim returns claim named name, informer is keyed by uid
+func (pc *persistentVolumeController) getClaim(name string) *core.PersistentVolumeClaim {
+	for _, item := range pc.PvcInformer.List() {
+		if claim := item.(*core.PersistentVolumeClaim); claim.Name == name {
+			return claim
+		}
+	}
+	return nil
+}
+
+// getSelectedNode returns node selected by scheduler for claim, a node without
+// labels is returned if it is not found, nil if no node is selected
+func (pc *persistentVolumeController) getSelectedNode(claim *core.PersistentVolumeClaim) *core.Node {
+	name := claim.Annotations[core.AnnSelectedNode]
+	if name == "" {
+		return nil
+	}
+	for _, item := range pc.NodeInformer.List() {
+		if node := item.(*core.Node); node.Name == name {
+			return node
+		}
+	}
+	return &core.Node{ObjectMeta: meta.ObjectMeta{Name: name}}
+}
+
+// isReservedFor returns true if volume is not bound, or is bound or reserved for claim
+func isReservedFor(volume *core.PersistentVolume, claim *core.PersistentVolumeClaim) bool {
+	ref := volume.Spec.ClaimRef
+	if ref == nil {
+		return true
+	}
+	return ref.Name == claim.Name && (ref.UID == "" || ref.UID == claim.UID)
+}
+
+// checkVolumeSatisfyClaim checks storage class, access modes and capacity of volume
+func checkVolumeSatisfyClaim(volume *core.PersistentVolume, claim *core.PersistentVolumeClaim) error {
+	if volume.Spec.StorageClassName != claim.Spec.StorageClassName {
+		return fmt.Errorf("storage class %q does not match %q", volume.Spec.StorageClassName, claim.Spec.StorageClassName)
+	}
+	if !volume.HasAccessModes(claim.Spec.AccessModes) {
+		return errors.New("access modes are not supported")
+	}
+	requested, err := claim.StorageRequest()
+	if err != nil {
+		return fmt.Errorf("invalid storage request: %v", err)
+	}
+	capacity, err := volume.StorageCapacity()
+	if err != nil {
+		return fmt.Errorf("invalid storage capacity: %v", err)
+	}
+	if capacity < requested {
+		return fmt.Errorf("capacity %v is less than request %v", capacity, requested)
+	}
+	return nil
+}
+
+// findBestMatch finds the smallest available volume satisfying claim, volumes
+// reserved for claim are preferred. If node is not nil, only volumes accessible
+// from node are considered.
+func findBestMatch(claim *core.PersistentVolumeClaim, volumes []*core.PersistentVolume, node *core.Node) *core.PersistentVolume {
+	var best *core.PersistentVolume
+	var bestCapacity uint64
+	for _, volume := range volumes {
+		if !isReservedFor(volume, claim) {
+			continue
+		}
+		if volume.Status.Phase == core.VolumeReleased || volume.Status.Phase == core.VolumeFailed {
+			continue
+		}
+		if node != nil && !volume.MatchNode(node) {
+			continue
+		}
+		if err := checkVolumeSatisfyClaim(volume, claim); err != nil {
+			continue
+		}
+		if volume.Spec.ClaimRef != nil {
+			return volume
+		}
+		capacity, _ := volume.StorageCapacity()
+		if best == nil || capacity < bestCapacity || (capacity == bestCapacity && volume.Name < best.Name) {
+			best = volume
+			bestCapacity = capacity
+		}
+	}
+	return best
+}
@@ -0,0 +1,69 @@
+package persistentvolume
+
+import (
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/meta"
+	"minik8s/pkg/api/types"
+	"testing"
+)
+
+func newTestVolume(name string, capacity types.Quantity, class string, modes ...core.PersistentVolumeAccessMode) *core.PersistentVolume {
+	volume := &core.PersistentVolume{}
+	volume.Name = name
+	volume.Spec.Capacity = core.ResourceList{types.ResourceStorage: capacity}
+	volume.Spec.StorageClassName = class
+	volume.Spec.AccessModes = modes
+	volume.Status.Phase = core.VolumeAvailable
+	return volume
+}
+
+func TestFindBestMatch(t *testing.T) {
+	claim := &core.PersistentVolumeClaim{}
+	claim.Name = "data"
+	claim.UID = "claim-uid"
+	claim.Spec.AccessModes = []core.PersistentVolumeAccessMode{core.ReadWriteOnce}
+	claim.Spec.Resources.Requests = core.ResourceList{types.ResourceStorage: "500M"}
+
+	small := newTestVolume("small", "100M", "", core.ReadWriteOnce)
+	large := newTestVolume("large", "2000M", "", core.ReadWriteOnce, core.ReadOnlyMany)
+	medium := newTestVolume("medium", "1000M", "", core.ReadWriteOnce)
+	readOnly := newTestVolume("read-only", "1000M", "", core.ReadOnlyMany)
+	otherClass := newTestVolume("other-class", "1000M", "fast", core.ReadWriteOnce)
+	boundToOther := newTestVolume("bound", "600M", "", core.ReadWriteOnce)
+	boundToOther.Spec.ClaimRef = &core.PersistentVolumeClaimReference{Name: "other", UID: "other-uid"}
+	released := newTestVolume("released", "600M", "", core.ReadWriteOnce)
+	released.Status.Phase = core.VolumeReleased
+	reserved := newTestVolume("reserved", "2000M", "", core.ReadWriteOnce)
+	reserved.Spec.ClaimRef = &core.PersistentVolumeClaimReference{Name: "data"}
+	onNode1 := newTestVolume("on-node1", "600M", "", core.ReadWriteOnce)
+	onNode1.Spec.NodeAffinity = core.NewNodeNameAffinity("node1")
+
+	node1 := &core.Node{ObjectMeta: meta.ObjectMeta{Name: "node1"}}
+	node2 := &core.Node{ObjectMeta: meta.ObjectMeta{Name: "node2", Labels: map[string]string{core.LabelHostname: "node2"}}}
+
+	tests := []struct {
+		name    string
+		volumes []*core.PersistentVolume
+		node    *core.Node
+		want    string
+	}{
+		{"smallest fitting volume", []*core.PersistentVolume{small, large, medium}, nil, "medium"},
+		{"access modes not supported", []*core.PersistentVolume{small, readOnly}, nil, ""},
+		{"storage class not matched", []*core.PersistentVolume{otherClass}, nil, ""},
+		{"bound and released volumes skipped", []*core.PersistentVolume{boundToOther, released, large}, nil, "large"},
+		{"reserved volume preferred", []*core.PersistentVolume{medium, reserved}, nil, "reserved"},
+		{"volume on selected node", []*core.PersistentVolume{large, onNode1}, node1, "on-node1"},
+		{"volume on other node skipped", []*core.PersistentVolume{large, onNode1}, node2, "large"},
+	}
+
+	for _, test := range tests {
+		got := findBestMatch(claim, test.volumes, test.node)
+		gotName := ""
+		if got != nil {
+			gotName = got.Name
+		}
+		if gotName != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, gotName, test.want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package persistentvolume
+
+import (
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/meta"
+	"minik8s/pkg/api/types"
+	"minik8s/pkg/logger"
+	"net/http"
+	"path/filepath"
+)
+
+/*--------------------- Local Path Provisioner ---------------------*/
+
+// provision creates a local-path volume for claim on node selected by scheduler.
+// The volume is reserved for claim through claimRef and bound to it when synced,
+// its directory is created by kubelet when the first pod using it starts.
+func (pc *persistentVolumeController) provision(claim *core.PersistentVolumeClaim, nodeName string) error {
+	volume := newLocalPathVolume(claim, nodeName)
+	if pc.getVolume(volume.Name) != nil {
+		// provisioned already, wait for it to be bound
+		return nil
+	}
+
+	code, _, err := pc.PvClient.Post(volume)
+	if err != nil {
+		if code == http.StatusConflict {
+			return nil
+		}
+		return err
+	}
+	logger.PersistentVolumeControllerLogger.Printf("[provision] volume %v provisioned for claim %v on node %v\n", volume.Name, claim.Name, nodeName)
+	return nil
+}
+
+// newLocalPathVolume creates a volume for claim, named after uid of claim,
+// which is deleted with its directory after claim is deleted
+func newLocalPathVolume(claim *core.PersistentVolumeClaim, nodeName string) *core.PersistentVolume {
+	name := "pvc-" + claim.UID
+	return &core.PersistentVolume{
+		TypeMeta: meta.TypeMeta{
+			Kind:       string(types.PersistentVolumeObjectType),
+			APIVersion: "v1",
+		},
+		ObjectMeta: meta.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{core.AnnDynamicallyProvisioned: core.LocalPathProvisionerName},
+		},
+		Spec: core.PersistentVolumeSpec{
+			Capacity: core.ResourceList{
+				types.ResourceStorage: claim.Spec.Resources.Requests[types.ResourceStorage],
+			},
+			PersistentVolumeSource: core.PersistentVolumeSource{
+				Local: &core.LocalVolumeSource{Path: filepath.Join(config.LocalPathProvisionerDir, name)},
+			},
+			AccessModes:                   claim.Spec.AccessModes,
+			ClaimRef:                      &core.PersistentVolumeClaimReference{Name: claim.Name, UID: claim.UID},
+			PersistentVolumeReclaimPolicy: core.PersistentVolumeReclaimDelete,
+			StorageClassName:              core.LocalPathStorageClassName,
+			NodeAffinity:                  core.NewNodeNameAffinity(nodeName),
+		},
+		Status: core.PersistentVolumeStatus{Phase: core.VolumePending},
+	}
+}
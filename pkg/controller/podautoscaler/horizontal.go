@@ -9,6 +9,7 @@ import (
 	"minik8s/pkg/api/meta"
 	"minik8s/pkg/api/types"
 	client "minik8s/pkg/apiclient/interface"
+	"minik8s/pkg/apiclient/record"
 	"minik8s/pkg/controller/cache"
 	"minik8s/pkg/controller/podautoscaler/metrics"
 	"minik8s/pkg/logger"
@@ -22,7 +23,10 @@ type HorizontalController interface {
 	Run(ctx context.Context)
 }
 
-func NewHorizontalController(podInformer cache.Informer, podClient client.Interface, hpaInformer cache.Informer, hpaClient client.Interface, rsInformer cache.Informer, rsClient client.Interface) HorizontalController {
+// Component is the source of events recorded by HorizontalController
+const Component = "horizontal-pod-autoscaler"
+
+func NewHorizontalController(podInformer cache.Informer, podClient client.Interface, hpaInformer cache.Informer, hpaClient client.Interface, rsInformer cache.Informer, rsClient client.Interface, recorder record.EventRecorder) HorizontalController {
 
 	hc := &horizontalController{
 		podClient:     podClient,
@@ -34,6 +38,7 @@ func NewHorizontalController(podInformer cache.Informer, podClient client.Interf
 		queue:         cache.NewWorkQueue(),
 		Kind:          string(types.HorizontalPodAutoscalerObjectType),
 		metricsClient: metrics.NewResourceMetricsClient(),
+		recorder:      recorder,
 	}
 
 	_ = hc.hpaInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -57,8 +62,9 @@ type horizontalController struct {
 	rsInformer cache.Informer
 	rsClient   client.Interface
 
-	queue cache.WorkQueue
-	Kind  string
+	queue    cache.WorkQueue
+	Kind     string
+	recorder record.EventRecorder
 }
 
 func (h *horizontalController) Run(ctx context.Context) {
@@ -204,6 +210,7 @@ func (h *horizontalController) reconcileAutoscaler(ctx context.Context, key stri
 		// Control replicas num through rs
 		rs, exist := h.getScaleTargetRefRS(hpa)
 		if !exist {
+			h.recorder.Eventf(hpa, core.EventTypeWarning, "FailedGetScale", "ReplicaSet %v is not found", hpa.Spec.ScaleTargetRef.Name)
 			return errors.New(fmt.Sprintf("[reconcileAutoscaler] RS name %v not found in rsInformer", hpa.Spec.ScaleTargetRef.Name))
 		}
 
@@ -300,15 +307,18 @@ func (h *horizontalController) doScale(hpa *core.HorizontalPodAutoscaler, rs *co
 	// update hpa
 	_, _, err := h.hpaClient.Put(hpa.UID, hpa)
 	if err != nil {
+		h.recorder.Eventf(hpa, core.EventTypeWarning, "FailedRescale", "New size: %d; reason: %s; error: %v", desiredReplicas, rescaleReason, err)
 		return err
 	}
 
 	// update rs
 	_, _, err = h.rsClient.Put(rs.UID, rs)
 	if err != nil {
+		h.recorder.Eventf(hpa, core.EventTypeWarning, "FailedRescale", "New size: %d; reason: %s; error: %v", desiredReplicas, rescaleReason, err)
 		return err
 	}
 
+	h.recorder.Eventf(hpa, core.EventTypeNormal, "SuccessfulRescale", "New size: %d; reason: %s", desiredReplicas, rescaleReason)
 	return nil
 }
 
@@ -463,6 +473,7 @@ func (h *horizontalController) calculateDesiredReplicasByMertics(hpa *core.Horiz
 
 	if err != nil {
 		logger.HorizontalControllerLogger.Printf("[metricsClient] CollectAllMetrics error: %v\n", err)
+		h.recorder.Eventf(hpa, core.EventTypeWarning, "FailedGetResourceMetric", "failed to get metrics of pods: %v", err)
 		return rs.Spec.Replicas, false, "", errors.New(fmt.Sprintf("[calculateDesiredReplicasByMertics] CollectAllMetrics error: %v", err))
 	}
 
@@ -9,9 +9,11 @@ import (
 	"minik8s/pkg/api/meta"
 	"minik8s/pkg/api/types"
 	client "minik8s/pkg/apiclient/interface"
+	"minik8s/pkg/apiclient/record"
 	"minik8s/pkg/controller/cache"
 	"minik8s/pkg/logger"
 	"reflect"
+	"sort"
 	"time"
 )
 
@@ -19,7 +21,10 @@ type ReplicaSetController interface {
 	Run(ctx context.Context)
 }
 
-func NewReplicaSetController(podInformer cache.Informer, podClient client.Interface, rsInformer cache.Informer, rsClient client.Interface) ReplicaSetController {
+// Component is the source of events recorded by ReplicaSetController
+const Component = "replicaset-controller"
+
+func NewReplicaSetController(podInformer cache.Informer, podClient client.Interface, rsInformer cache.Informer, rsClient client.Interface, recorder record.EventRecorder) ReplicaSetController {
 
 	rsc := &replicaSetController{
 		Kind:        string(types.ReplicasetObjectType),
@@ -28,6 +33,7 @@ func NewReplicaSetController(podInformer cache.Informer, podClient client.Interf
 		RsInformer:  rsInformer,
 		RsClient:    rsClient,
 		queue:       cache.NewWorkQueue(),
+		recorder:    recorder,
 	}
 
 	_ = rsc.RsInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -53,6 +59,7 @@ type replicaSetController struct {
 	PodClient   client.Interface
 	RsClient    client.Interface
 	queue       cache.WorkQueue
+	recorder    record.EventRecorder
 }
 
 func (rsc *replicaSetController) Run(ctx context.Context) {
@@ -118,9 +125,10 @@ func (rsc *replicaSetController) deleteRS(obj interface{}) {
 	// delete rs owner ref in preowned pods
 	rsc.updatePreOwnedPods(rs, podsPreOwned)
 
-	// Delete pods of according rs
+	// Delete pods of according rs, deleting rs is not a voluntary disruption
+	// of its pods, so PodDisruptionBudget is not respected here
 	// TODO: check owner reference of pod in case it has other owner, meaning pod can not be delete
-	err = rsc.decreasePods(int32(len(podsOwned)), podsOwned)
+	err = rsc.deletePods(podsOwned)
 	if err != nil {
 		logger.ReplicaSetControllerLogger.Printf("[deleteRS] Delete failed when ask ApiServer to delete pods, %v\n", err)
 		return
@@ -155,6 +163,13 @@ func (rsc *replicaSetController) updatePod(old, cur interface{}) {
 		return
 	}
 
+	if curPod.IsEvicted() && !oldPod.IsEvicted() {
+		if rs := rsc.getPodOwnerReplicaSet(curPod); rs != nil {
+			logger.ReplicaSetControllerLogger.Printf("enqueue ReplicaSet %s when Pod %s is evicted\n", rs.UID, curPod.UID)
+			rsc.enqueueRS(rs)
+		}
+	}
+
 	labelChanged := !reflect.DeepEqual(curPod.Labels, oldPod.Labels)
 	if labelChanged {
 
@@ -274,7 +289,8 @@ func (rsc *replicaSetController) syncReplicaSet(ctx context.Context, key string)
 		if rs.Status.Replicas < rs.Spec.Replicas {
 			rsc.increaseReplica(rs, matchedNotOwnedPods)
 		} else {
-			rsc.decreaseReplica(rs, podsOwned)
+			// retry later if eviction is refused by PodDisruptionBudget
+			return rsc.decreaseReplica(rs, podsOwned)
 		}
 	}
 
@@ -302,6 +318,11 @@ func (rsc *replicaSetController) getPodsOwnedAndMatchedNotOwned(rs *core.Replica
 			return podsOwned, matchedNotOwnedPods, podsPreOwned, errors.New(fmt.Sprintf("[getPodsOwnedAndMatchedNotOwned] Not Pod type in PodInformer"))
 		}
 
+		// evicted pods are replaced, and mirror pods are managed by kubelet
+		if pod.IsEvicted() || pod.IsMirrorPod() {
+			continue
+		}
+
 		// check if rs is pod owner
 		if isOwner, owner := meta.CheckOwner(rsUID, pod.OwnerReferences); isOwner {
 
@@ -379,12 +400,14 @@ func (rsc *replicaSetController) increaseReplica(rs *core.ReplicaSet, matchedNot
 		_, postResponse, err := rsc.PodClient.Post(newPod)
 		if err != nil {
 			logger.ReplicaSetControllerLogger.Printf("[increaseReplica] Post failed when ask ApiServer to create new pods, %v\n", err)
+			rsc.recorder.Eventf(rs, core.EventTypeWarning, "FailedCreate", "Error creating: %v", err)
 			rsc.finishModifyReplicaAndUpdateRsStatus(rs, podReplicaNum)
 			return
 		}
 
 		// Wait for create of pod finish
 		logger.ReplicaSetControllerLogger.Printf("[increaseReplica] New Pod name %s successfully created, uid %v\n", newPod.Name, postResponse.UID)
+		rsc.recorder.Eventf(rs, core.EventTypeNormal, "SuccessfulCreate", "Created pod: %v", newPod.Name)
 		podReplicaNum++
 	}
 
@@ -399,40 +422,77 @@ func (rsc *replicaSetController) finishModifyReplicaAndUpdateRsStatus(rs *core.R
 		return
 	}
 
-	// update rs status
+	rsc.updateRsStatus(rs, replicaNum)
+}
+
+// updateRsStatus asks ApiServer to update replicas in status of rs to replicaNum
+func (rsc *replicaSetController) updateRsStatus(rs *core.ReplicaSet, replicaNum int32) {
+	rs.Status.Replicas = replicaNum
 	_, _, err := rsc.RsClient.Put(rs.UID, rs)
 	if err != nil {
-		logger.ReplicaSetControllerLogger.Printf("[finishModifyReplicaAndUpdateRsStatus] Put failed when ask ApiServer to update rs, %v\n", err)
-		return
+		logger.ReplicaSetControllerLogger.Printf("[updateRsStatus] Put failed when ask ApiServer to update rs, %v\n", err)
 	}
 }
 
-func (rsc *replicaSetController) decreaseReplica(rs *core.ReplicaSet, podsOwned []core.Pod) {
+func (rsc *replicaSetController) decreaseReplica(rs *core.ReplicaSet, podsOwned []core.Pod) error {
 	numToDecrease := rs.Status.Replicas - rs.Spec.Replicas
 
-	// ask ApiServer to delete pods
-	err := rsc.decreasePods(numToDecrease, podsOwned)
+	// ask ApiServer to evict pods
+	evicted, err := rsc.decreasePods(rs, numToDecrease, podsOwned)
 	if err != nil {
-		logger.ReplicaSetControllerLogger.Printf("[decreaseReplica] Delete failed when ask ApiServer to delete new pods, %v\n", err)
-		return
+		logger.ReplicaSetControllerLogger.Printf("[decreaseReplica] Evict failed when ask ApiServer to evict pods, %v\n", err)
+		// pods evicted before the refused one are gone, the rest are evicted on retry
+		if evicted > 0 {
+			rsc.updateRsStatus(rs, rs.Status.Replicas-evicted)
+		}
+		return err
 	}
 
 	rsc.finishModifyReplicaAndUpdateRsStatus(rs, rs.Spec.Replicas)
+	return nil
 }
 
-// decreasePods ask ApiServer to delete numToDecrease pods in podsOwned
-func (rsc *replicaSetController) decreasePods(numToDecrease int32, podsOwned []core.Pod) error {
+// decreasePods ask ApiServer to evict numToDecrease pods in podsOwned,
+// pods not running are chosen first since evicting them does not reduce
+// availability. Eviction is refused if it would violate PodDisruptionBudget,
+// then it returns the number of pods evicted before with the error.
+func (rsc *replicaSetController) decreasePods(rs *core.ReplicaSet, numToDecrease int32, podsOwned []core.Pod) (int32, error) {
 	// TODO: check owner reference of pod in case it has other owner, meaning pod can not be delete
 
-	// ask ApiServer to delete pods
+	podsToEvict := make([]core.Pod, len(podsOwned))
+	copy(podsToEvict, podsOwned)
+	sort.SliceStable(podsToEvict, func(i, j int) bool {
+		return !podsToEvict[i].IsReady() && podsToEvict[j].IsReady()
+	})
+
+	// ask ApiServer to evict pods
 	var idx int32
 	for idx = 0; idx < numToDecrease; idx++ {
-		podToDelete := podsOwned[idx]
+		podToEvict := podsToEvict[idx]
+
+		// evict pod
+		_, resp, err := rsc.PodClient.Evict(podToEvict.UID)
+		if err != nil {
+			logger.ReplicaSetControllerLogger.Printf("[decreasePods] Evict failed when ask ApiServer to evict pod %v, %v\n", podToEvict.UID, err)
+			reason := err.Error()
+			if resp != nil && resp.ErrorMsg != "" {
+				reason = resp.ErrorMsg
+			}
+			rsc.recorder.Eventf(rs, core.EventTypeWarning, "FailedDelete", "Error evicting pod %v: %v", podToEvict.Name, reason)
+			return idx, err
+		}
+		rsc.recorder.Eventf(rs, core.EventTypeNormal, "SuccessfulDelete", "Evicted pod: %v", podToEvict.Name)
+	}
+
+	return numToDecrease, nil
+}
 
-		// delete pod
+// deletePods ask ApiServer to delete all pods in pods
+func (rsc *replicaSetController) deletePods(pods []core.Pod) error {
+	for _, podToDelete := range pods {
 		_, _, err := rsc.PodClient.Delete(podToDelete.UID)
 		if err != nil {
-			logger.ReplicaSetControllerLogger.Printf("[decreasePods] Delete failed when ask ApiServer to delete pod %v, %v\n", podToDelete.UID, err)
+			logger.ReplicaSetControllerLogger.Printf("[deletePods] Delete failed when ask ApiServer to delete pod %v, %v\n", podToDelete.UID, err)
 			return err
 		}
 	}
@@ -9,6 +9,7 @@ import (
 	"minik8s/pkg/api/meta"
 	"minik8s/pkg/api/types"
 	client "minik8s/pkg/apiclient/interface"
+	"minik8s/pkg/apiclient/record"
 	"minik8s/pkg/controller/cache"
 	"minik8s/pkg/logger"
 	"minik8s/utils"
@@ -20,12 +21,16 @@ type ServerlessController interface {
 	Run(ctx context.Context)
 }
 
+// Component is the source of events recorded by ServerlessController
+const Component = "serverless-controller"
+
 func NewServerlessController(
 	FuncTemplateInformer cache.Informer,
 	FuncTemplateClient client.Interface,
 	ReplicaSetClient client.Interface,
 	ServiceClient client.Interface,
 	PodClient client.Interface,
+	recorder record.EventRecorder,
 ) ServerlessController {
 
 	sc := &serverlessController{
@@ -36,6 +41,7 @@ func NewServerlessController(
 		ServiceClient:        ServiceClient,
 		PodClient:            PodClient,
 		queue:                cache.NewWorkQueue(),
+		recorder:             recorder,
 	}
 
 	_ = sc.FuncTemplateInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -56,6 +62,7 @@ type serverlessController struct {
 	ServiceClient        client.Interface
 	PodClient            client.Interface
 	queue                cache.WorkQueue
+	recorder             record.EventRecorder
 }
 
 func (sc *serverlessController) Run(ctx context.Context) {
@@ -99,8 +106,10 @@ func (sc *serverlessController) processFuncStatusChange(oldFunc, curFunc *core.F
 		// update rs Replicas number
 		err = sc.updateRS(rs)
 		if err != nil {
+			sc.recorder.Eventf(curFunc, core.EventTypeWarning, "FailedRescale", "Scale instances from %d to %d failed: %v", oldFunc.Status.Counter, curFunc.Status.Counter, err)
 			return err
 		}
+		sc.recorder.Eventf(curFunc, core.EventTypeNormal, "SuccessfulRescale", "Scaled instances from %d to %d", oldFunc.Status.Counter, curFunc.Status.Counter)
 	}
 	return nil
 }
@@ -497,6 +506,7 @@ func (sc *serverlessController) processFuncCreate(funcTemplate *core.Func) error
 	// create service
 	svcUID, err := sc.createServiceForFunc(funcTemplate, funcTemplateOwnerRef, funcServiceLabels)
 	if err != nil {
+		sc.recorder.Eventf(funcTemplate, core.EventTypeWarning, "FailedCreate", "Error creating service: %v", err)
 		return err
 	}
 
@@ -505,8 +515,10 @@ func (sc *serverlessController) processFuncCreate(funcTemplate *core.Func) error
 	// create replica set
 	rsUID, err := sc.createReplicaSetForFunc(funcTemplate, funcReplicaSetLabels, funcTemplateOwnerRef, funcPodSpec, funcPodLabels, initReplicasNum)
 	if err != nil {
+		sc.recorder.Eventf(funcTemplate, core.EventTypeWarning, "FailedCreate", "Error creating replica set: %v", err)
 		return err
 	}
+	sc.recorder.Eventf(funcTemplate, core.EventTypeNormal, "SuccessfulCreate", "Created service and replica set of %d instances", initReplicasNum)
 
 	// modify func status
 	funcTemplate.Status.ReplicaSetUID = rsUID
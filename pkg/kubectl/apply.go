@@ -12,5 +12,6 @@ var applyCmd = &cobra.Command{
 }
 
 func init() {
+	addFilenameFlag(applyCmd)
 	rootCmd.AddCommand(applyCmd)
 }
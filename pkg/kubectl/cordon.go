@@ -0,0 +1,97 @@
+package kubectl
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+	"minik8s/pkg/apiclient"
+	"net/http"
+)
+
+var cordonCmd = &cobra.Command{
+	Use:     "cordon <node-name>",
+	Example: "cordon node1\n",
+	Short:   "mark node as unschedulable",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := setNodeUnschedulable(args[0], true)
+		if err != nil {
+			fmt.Printf("Cordon node %v failed, err: %v\n", args[0], err)
+		}
+	},
+}
+
+var uncordonCmd = &cobra.Command{
+	Use:     "uncordon <node-name>",
+	Example: "uncordon node1\n",
+	Short:   "mark node as schedulable",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := setNodeUnschedulable(args[0], false)
+		if err != nil {
+			fmt.Printf("Uncordon node %v failed, err: %v\n", args[0], err)
+		}
+	},
+}
+
+// getNode returns node whose name or uid is name
+func getNode(name string) (*core.Node, error) {
+	cli, _ := apiclient.NewRESTClient(types.NodeObjectType)
+	nodeList, err := cli.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range nodeList.GetIApiObjectArr() {
+		no := item.(*core.Node)
+		if no.Name == name || no.UID == name {
+			return no, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("node %v not found", name))
+}
+
+const maxNodeUpdateRetries = 5
+
+// setNodeUnschedulable sets spec.unschedulable of node, retry on conflict
+func setNodeUnschedulable(name string, unschedulable bool) error {
+	cli, _ := apiclient.NewRESTClient(types.NodeObjectType)
+	for i := 0; i < maxNodeUpdateRetries; i++ {
+		no, err := getNode(name)
+		if err != nil {
+			return err
+		}
+		if no.Spec.Unschedulable == unschedulable {
+			if unschedulable {
+				fmt.Printf("node %v already cordoned\n", no.Name)
+			} else {
+				fmt.Printf("node %v already uncordoned\n", no.Name)
+			}
+			return nil
+		}
+
+		no.Spec.Unschedulable = unschedulable
+		code, resp, err := cli.Put(no.UID, no)
+		if err == nil {
+			if unschedulable {
+				fmt.Printf("node %v cordoned\n", no.Name)
+			} else {
+				fmt.Printf("node %v uncordoned\n", no.Name)
+			}
+			return nil
+		}
+		if code != http.StatusConflict {
+			if resp != nil && resp.ErrorMsg != "" {
+				return errors.New(resp.ErrorMsg)
+			}
+			return err
+		}
+	}
+	return errors.New("too many conflicts when updating node")
+}
+
+func init() {
+	rootCmd.AddCommand(cordonCmd)
+	rootCmd.AddCommand(uncordonCmd)
+}
@@ -12,6 +12,6 @@ var createCmd = &cobra.Command{
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringP("filename", "f", "", "resource name")
+	addFilenameFlag(createCmd)
 	rootCmd.AddCommand(createCmd)
 }
@@ -4,13 +4,17 @@ import (
 	"fmt"
 	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
 	"minik8s/pkg/apiclient"
+	"time"
 )
 
 var describeCmd = &cobra.Command{
-	Use:   "describe <resource type>",
-	Short: "describe api objects.",
-	Args:  cobra.MinimumNArgs(1),
+	Use:     "describe <resources> | (<resource> <resource-name>)",
+	Example: "describe nodes\ndescribe node {uid}\ndescribe pod {uid}\ndescribe rs {uid}\n",
+	Short:   "describe api objects.",
+	Args:    cobra.RangeArgs(1, 2),
 	Run: func(cmd *cobra.Command, args []string) {
 		s := args[0]
 
@@ -21,13 +25,25 @@ var describeCmd = &cobra.Command{
 		}
 
 		cli, _ := apiclient.NewRESTClient(objType)
-		list, err := cli.GetAll()
-		if err != nil {
-			fmt.Printf("Get all %v failed, err: %v\n", objType, err)
-			return
+
+		var objs []core.IApiObject
+		if len(args) == 1 {
+			list, err := cli.GetAll()
+			if err != nil {
+				fmt.Printf("Get all %v failed, err: %v\n", objType, err)
+				return
+			}
+			objs = list.GetIApiObjectArr()
+		} else {
+			obj, err := cli.Get(args[1])
+			if err != nil {
+				fmt.Printf("Get %v failed, err: %v\n", objType, err)
+				return
+			}
+			objs = append(objs, obj)
 		}
 
-		for _, obj := range list.GetIApiObjectArr() {
+		for _, obj := range objs {
 			jsonData, err := obj.JsonMarshal()
 			if err != nil {
 				fmt.Printf("JsonMarshal of obj type %v failed, err: %v\n", objType, err)
@@ -41,10 +57,180 @@ var describeCmd = &cobra.Command{
 			}
 
 			fmt.Println(string(yamlData))
+
+			switch objType {
+			case types.NodeObjectType:
+				describeNodeResources(obj.(*core.Node))
+			case types.PodObjectType:
+				describePodStatus(obj.(*core.Pod))
+			}
+			if objType != types.EventObjectType {
+				describeEvents(obj.GetUID())
+			}
 		}
 	},
 }
 
+// describeNodeResources prints resources requested by pods on node versus allocatable of node
+func describeNodeResources(node *core.Node) {
+	podCli, _ := apiclient.NewRESTClient(types.PodObjectType)
+	podList, err := podCli.GetAll()
+	if err != nil {
+		fmt.Printf("Get all pods failed, err: %v\n", err)
+		return
+	}
+
+	requested := make(map[types.ResourceName]uint64)
+	for _, item := range podList.GetIApiObjectArr() {
+		pod := item.(*core.Pod)
+		// terminated pods use no resources, as the scheduler sees it
+		if pod.Spec.NodeName != node.Name || pod.IsTerminated() {
+			continue
+		}
+		for name, v := range pod.ComputeResourceRequests() {
+			requested[name] += v
+		}
+		requested[types.ResourcePods] += 1
+	}
+
+	fmt.Printf("Allocated resources:\n")
+	fmt.Printf("  %-20s\t%-15s\t%-15s\t%-15s\n", "RESOURCE", "REQUESTS", "ALLOCATABLE", "CAPACITY")
+	for _, name := range []types.ResourceName{types.ResourceCPU, types.ResourceMemory, types.ResourceEphemeralStorage, types.ResourcePods} {
+		req := formatRequested(name, requested[name])
+		allocatable, capacity := "<unknown>", "<unknown>"
+		if q, ok := node.Status.Allocatable[name]; ok {
+			allocatable = string(q)
+			if v, err := types.ParseQuantity(name, q); err == nil && v > 0 {
+				req = fmt.Sprintf("%v (%d%%)", req, requested[name]*100/v)
+			}
+		}
+		if q, ok := node.Status.Capacity[name]; ok {
+			capacity = string(q)
+		}
+		fmt.Printf("  %-20s\t%-15s\t%-15s\t%-15s\n", name, req, allocatable, capacity)
+	}
+	fmt.Println()
+}
+
+// describePodStatus prints conditions of pod and states of its containers
+func describePodStatus(pod *core.Pod) {
+	status := pod.Status
+	fmt.Printf("Status:\t\t%v\n", status.Phase)
+	if status.Reason != "" {
+		fmt.Printf("Reason:\t\t%v\n", status.Reason)
+	}
+	if status.Message != "" {
+		fmt.Printf("Message:\t%v\n", status.Message)
+	}
+	startTime := "<none>"
+	if status.StartTime != nil {
+		startTime = formatTime(*status.StartTime)
+	}
+	fmt.Printf("Start Time:\t%v\n", startTime)
+	fmt.Printf("IP:\t\t%v\n", status.PodIP)
+	fmt.Printf("QoS Class:\t%v\n", status.QOSClass)
+
+	fmt.Printf("Conditions:\n")
+	fmt.Printf("  %-16s\t%-6s\t%-25s\t%-20s\n", "TYPE", "STATUS", "LAST TRANSITION", "REASON")
+	for _, c := range status.Conditions {
+		fmt.Printf("  %-16s\t%-6s\t%-25s\t%-20s\n", c.Type, c.Status, formatTime(c.LastTransitionTime), c.Reason)
+	}
+
+	statuses := append(append([]core.ContainerStatus{}, status.InitContainerStatuses...), status.ContainerStatuses...)
+	if len(statuses) > 0 {
+		fmt.Printf("Containers:\n")
+	}
+	for _, cs := range statuses {
+		fmt.Printf("  %v:\n", cs.Name)
+		describeContainerState("State", cs.State)
+		if cs.LastTerminationState.Terminated != nil {
+			describeContainerState("Last State", cs.LastTerminationState)
+		}
+		fmt.Printf("    Ready:\t\t%v\n", cs.Ready)
+		if cs.Started != nil {
+			fmt.Printf("    Started:\t\t%v\n", *cs.Started)
+		}
+		fmt.Printf("    Restart Count:\t%v\n", cs.RestartCount)
+	}
+	fmt.Println()
+}
+
+func describeContainerState(title string, state core.ContainerState) {
+	switch {
+	case state.Running != nil:
+		fmt.Printf("    %v:\t\tRunning\n", title)
+		fmt.Printf("      Started:\t\t%v\n", formatTime(state.Running.StartedAt))
+	case state.Terminated != nil:
+		fmt.Printf("    %v:\t\tTerminated\n", title)
+		fmt.Printf("      Reason:\t\t%v\n", state.Terminated.Reason)
+		fmt.Printf("      Exit Code:\t%v\n", state.Terminated.ExitCode)
+		fmt.Printf("      Started:\t\t%v\n", formatTime(state.Terminated.StartedAt))
+		fmt.Printf("      Finished:\t\t%v\n", formatTime(state.Terminated.FinishedAt))
+	case state.Waiting != nil:
+		fmt.Printf("    %v:\t\tWaiting\n", title)
+		fmt.Printf("      Reason:\t\t%v\n", state.Waiting.Reason)
+		if state.Waiting.Message != "" {
+			fmt.Printf("      Message:\t\t%v\n", state.Waiting.Message)
+		}
+	default:
+		fmt.Printf("    %v:\t\t<unknown>\n", title)
+	}
+}
+
+// describeEvents prints events about object of uid from the oldest to the latest
+func describeEvents(uid types.UID) {
+	eventCli, _ := apiclient.NewRESTClient(types.EventObjectType)
+	eventList, err := eventCli.GetAll()
+	if err != nil {
+		fmt.Printf("Get all events failed, err: %v\n", err)
+		return
+	}
+
+	events := &core.EventList{}
+	for _, item := range eventList.GetIApiObjectArr() {
+		event := item.(*core.Event)
+		if event.InvolvedObject.UID == uid {
+			events.Items = append(events.Items, *event)
+		}
+	}
+	if len(events.Items) == 0 {
+		fmt.Printf("Events:\t<none>\n\n")
+		return
+	}
+	fmt.Printf("Events:\n")
+	fmt.Printf("  %-8s\t%-20s\t%-10s\t%-25s\t%s\n", "TYPE", "REASON", "AGE", "FROM", "MESSAGE")
+	for _, event := range events.SortedItems() {
+		age := time.Since(event.LastTimestamp).Round(time.Second).String()
+		if event.Count > 1 {
+			age = fmt.Sprintf("%v (x%d over %v)", age, event.Count, time.Since(event.FirstTimestamp).Round(time.Second))
+		}
+		from := event.Source.Component
+		if event.Source.Host != "" {
+			from += ", " + event.Source.Host
+		}
+		fmt.Printf("  %-8s\t%-20s\t%-10s\t%-25s\t%s\n", event.Type, event.Reason, age, from, event.Message)
+	}
+	fmt.Println()
+}
+
+func formatTime(t types.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return t.Format(time.RFC1123Z)
+}
+
+func formatRequested(name types.ResourceName, v uint64) string {
+	switch name {
+	case types.ResourceCPU:
+		return fmt.Sprintf("%dm", v)
+	case types.ResourcePods:
+		return fmt.Sprintf("%d", v)
+	default:
+		return fmt.Sprintf("%dM", v)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(describeCmd)
 }
@@ -15,7 +15,7 @@ func doCreate(cmd *cobra.Command, args []string) {
 		fmt.Printf("No %v type of resource, err: %v\n", s, err)
 		return
 	}
-	filename := GetFilename()
+	filename := GetFilename(cmd)
 	jsonData, err := utils.GetFormJsonData(filename)
 	if err != nil {
 		fmt.Println("File parse err:", err)
@@ -0,0 +1,191 @@
+package kubectl
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/meta"
+	"minik8s/pkg/api/types"
+	"minik8s/pkg/apiclient"
+	"net/http"
+	"time"
+)
+
+var drainCmd = &cobra.Command{
+	Use:     "drain <node-name>",
+	Example: "drain node1\ndrain node1 --force --timeout 10m\n",
+	Short:   "cordon node and evict all pods on it for maintenance",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		err := drainNode(args[0], force, timeout)
+		if err != nil {
+			fmt.Printf("Drain node %v failed, err: %v\n", args[0], err)
+		}
+	},
+}
+
+// daemonSetKind is Kind of DaemonSet in OwnerReference, pods owned by
+// DaemonSet run on every node and are not evicted when draining
+const daemonSetKind = "DaemonSet"
+
+const drainPollInterval = time.Second
+
+const evictionRetryInterval = 5 * time.Second
+
+// drainNode cordons node and evicts pods on it one by one. Pods owned by
+// DaemonSet and mirror pods of static pods are skipped, and after evicting a pod owned by ReplicaSet, it
+// waits until its replacement becomes ready before evicting the next one.
+// Pods not managed by any controller are deleted only if force is set.
+func drainNode(name string, force bool, timeout time.Duration) error {
+	err := setNodeUnschedulable(name, true)
+	if err != nil {
+		return err
+	}
+
+	no, err := getNode(name)
+	if err != nil {
+		return err
+	}
+
+	podCli, _ := apiclient.NewRESTClient(types.PodObjectType)
+	podList, err := podCli.GetAll()
+	if err != nil {
+		return err
+	}
+
+	var podsToEvict []*core.Pod
+	var unmanaged []string
+	for _, item := range podList.GetIApiObjectArr() {
+		pod := item.(*core.Pod)
+		if pod.Spec.NodeName != no.Name {
+			continue
+		}
+		// mirror pods can't be evicted, static pods keep running until
+		// their manifests are removed from the node
+		if pod.IsMirrorPod() {
+			fmt.Printf("ignoring mirror pod %v\n", pod.Name)
+			continue
+		}
+		if has, _ := meta.HasOwnerKind(daemonSetKind, pod.OwnerReferences); has {
+			fmt.Printf("ignoring DaemonSet-managed pod %v\n", pod.Name)
+			continue
+		}
+		if len(pod.OwnerReferences) == 0 {
+			unmanaged = append(unmanaged, pod.Name)
+		}
+		podsToEvict = append(podsToEvict, pod)
+	}
+
+	if len(unmanaged) > 0 && !force {
+		return errors.New(fmt.Sprintf("cannot delete pods not managed by ReplicaSet (use --force to override): %v", unmanaged))
+	}
+
+	deadline := time.Now().Add(timeout)
+	for _, pod := range podsToEvict {
+		err = evictAndWaitForReplacement(pod, no, deadline)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("node %v drained\n", no.Name)
+	return nil
+}
+
+func evictAndWaitForReplacement(pod *core.Pod, no *core.Node, deadline time.Time) error {
+	rs := getOwnerReplicaSet(pod)
+
+	// ready replicas expected on other nodes after eviction
+	want := 0
+	if rs != nil {
+		want = countReadyReplicasNotOnNode(rs, no)
+		if pod.IsReady() {
+			want++
+		}
+		if want > int(rs.Spec.Replicas) {
+			want = int(rs.Spec.Replicas)
+		}
+	}
+
+	err := evictPod(pod, deadline)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("pod %v evicted\n", pod.Name)
+
+	if rs == nil {
+		return nil
+	}
+	for countReadyReplicasNotOnNode(rs, no) < want {
+		if time.Now().After(deadline) {
+			return errors.New(fmt.Sprintf("timeout waiting for replacement of pod %v to become ready", pod.Name))
+		}
+		time.Sleep(drainPollInterval)
+	}
+	return nil
+}
+
+// evictPod evicts pod through eviction subresource, eviction refused by
+// PodDisruptionBudget is retried until deadline
+func evictPod(pod *core.Pod, deadline time.Time) error {
+	podCli, _ := apiclient.NewRESTClient(types.PodObjectType)
+	for {
+		code, resp, err := podCli.Evict(pod.UID)
+		if err == nil || code == http.StatusNotFound {
+			return nil
+		}
+		if resp != nil && resp.ErrorMsg != "" {
+			err = errors.New(resp.ErrorMsg)
+		}
+		if code != http.StatusTooManyRequests {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return errors.New(fmt.Sprintf("timeout evicting pod %v: %v", pod.Name, err))
+		}
+		fmt.Printf("error when evicting pod %v (will retry after %v): %v\n", pod.Name, evictionRetryInterval, err)
+		time.Sleep(evictionRetryInterval)
+	}
+}
+
+// getOwnerReplicaSet returns ReplicaSet owning pod, nil if not owned by ReplicaSet
+func getOwnerReplicaSet(pod *core.Pod) *core.ReplicaSet {
+	has, owner := meta.HasOwnerKind(types.ReplicasetObjectType, pod.OwnerReferences)
+	if !has {
+		return nil
+	}
+	rsCli, _ := apiclient.NewRESTClient(types.ReplicasetObjectType)
+	rsItem, err := rsCli.Get(owner.UID)
+	if err != nil {
+		return nil
+	}
+	return rsItem.(*core.ReplicaSet)
+}
+
+func countReadyReplicasNotOnNode(rs *core.ReplicaSet, no *core.Node) int {
+	podCli, _ := apiclient.NewRESTClient(types.PodObjectType)
+	podList, err := podCli.GetAll()
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, item := range podList.GetIApiObjectArr() {
+		pod := item.(*core.Pod)
+		if pod.Spec.NodeName == no.Name || !pod.IsReady() {
+			continue
+		}
+		if isOwner, _ := meta.CheckOwner(rs.UID, pod.OwnerReferences); isOwner {
+			count++
+		}
+	}
+	return count
+}
+
+func init() {
+	drainCmd.Flags().Bool("force", false, "continue even if there are pods not managed by ReplicaSet")
+	drainCmd.Flags().Duration("timeout", 5*time.Minute, "the length of time to wait before giving up")
+	rootCmd.AddCommand(drainCmd)
+}
@@ -0,0 +1,172 @@
+package kubectl
+
+import (
+	"errors"
+	"fmt"
+	"github.com/moby/term"
+	"github.com/spf13/cobra"
+	"io"
+	"minik8s/config"
+	"minik8s/pkg/api"
+	"minik8s/pkg/kubelet/streaming"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+)
+
+var execCmd = &cobra.Command{
+	Use:     "exec <pod-name> [-c <container>] [-i] [-t] -- <command> [args...]",
+	Example: "exec mypod -- date\nexec mypod -c nginx -it -- sh\n",
+	Short:   "execute a command in a container of pod",
+	Args:    cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		command := args[1:]
+		if dash := cmd.ArgsLenAtDash(); dash > 1 {
+			fmt.Println("Exec failed, err: only pod name is expected before --")
+			return
+		} else if dash == 1 {
+			command = args[dash:]
+		}
+
+		query := buildStreamQuery(cmd)
+		for _, arg := range command {
+			query.Add(api.ExecCommandParam, arg)
+		}
+		code, err := streamToPod(args[0], api.ExecSuffix, query)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Exec in pod %v failed, err: %v\n", args[0], err)
+			os.Exit(1)
+		}
+		if code != 0 {
+			fmt.Fprintf(os.Stderr, "command terminated with exit code %d\n", code)
+			os.Exit(code)
+		}
+	},
+}
+
+var attachCmd = &cobra.Command{
+	Use:     "attach <pod-name> [-c <container>] [-i] [-t]",
+	Example: "attach mypod\nattach mypod -c shell -it\n",
+	Short:   "attach to the main process of a container in pod",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		_, err := streamToPod(args[0], api.AttachSuffix, buildStreamQuery(cmd))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Attach to pod %v failed, err: %v\n", args[0], err)
+			os.Exit(1)
+		}
+	},
+}
+
+// buildStreamQuery builds query of exec and attach from flags, stdout is always
+// attached, and stderr is merged into stdout if tty is used
+func buildStreamQuery(cmd *cobra.Command) url.Values {
+	container, _ := cmd.Flags().GetString("container")
+	stdin, _ := cmd.Flags().GetBool("stdin")
+	tty, _ := cmd.Flags().GetBool("tty")
+	if tty && !stdin {
+		fmt.Fprintln(os.Stderr, "Unable to use a TTY - input is not attached, use -i with -t")
+		tty = false
+	}
+	if _, isTerminal := term.GetFdInfo(os.Stdin); tty && !isTerminal {
+		fmt.Fprintln(os.Stderr, "Unable to use a TTY - input is not a terminal or the right kind of file")
+		tty = false
+	}
+
+	query := url.Values{}
+	if container != "" {
+		query.Set(api.ExecContainerParam, container)
+	}
+	query.Set(api.ExecStdinParam, strconv.FormatBool(stdin))
+	query.Set(api.ExecStdoutParam, "true")
+	query.Set(api.ExecStderrParam, strconv.FormatBool(!tty))
+	query.Set(api.ExecTTYParam, strconv.FormatBool(tty))
+	return query
+}
+
+// streamToPod connects to exec or attach subresource of pod, and copies streams
+// between terminal and container until the remote command finishes, it returns
+// exit code of the command
+func streamToPod(name string, suffix string, query url.Values) (int, error) {
+	pod, err := getPod(name)
+	if err != nil {
+		return 0, err
+	}
+
+	conn, err := streaming.Dial(podStreamURL(pod.UID, suffix, query))
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	if query.Get(api.ExecTTYParam) == "true" {
+		inFd, _ := term.GetFdInfo(os.Stdin)
+		state, err := term.SetRawTerminal(inFd)
+		if err != nil {
+			return 0, err
+		}
+		defer term.RestoreTerminal(inFd, state)
+		go monitorTerminalSize(conn, inFd)
+	}
+	if query.Get(api.ExecStdinParam) == "true" {
+		go func() {
+			_, _ = io.Copy(conn.Writer(streaming.StdinChannel), os.Stdin)
+			// empty stdin message tells that stdin is closed
+			_ = conn.Write(streaming.StdinChannel, nil)
+		}()
+	}
+
+	for {
+		channel, data, err := conn.Read()
+		if err != nil {
+			return 0, errors.New("connection closed before remote command finished")
+		}
+		switch channel {
+		case streaming.StdoutChannel:
+			_, _ = os.Stdout.Write(data)
+		case streaming.StderrChannel:
+			_, _ = os.Stderr.Write(data)
+		case streaming.ErrorChannel:
+			status, err := streaming.ReadStatus(data)
+			if err != nil {
+				return 0, err
+			}
+			if status.Status == streaming.StatusSuccess || status.ExitCode != 0 {
+				return status.ExitCode, nil
+			}
+			return 0, errors.New(status.Message)
+		}
+	}
+}
+
+// podStreamURL is websocket url of subresource of pod, e.g. exec
+func podStreamURL(uid string, suffix string, query url.Values) string {
+	return "ws://" + config.Host() + config.Port + api.PodsURL + uid + suffix + "?" + query.Encode()
+}
+
+// monitorTerminalSize sends size of terminal once and after each change
+func monitorTerminalSize(conn *streaming.Conn, fd uintptr) {
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	defer signal.Stop(winch)
+
+	for {
+		if size, err := term.GetWinsize(fd); err == nil {
+			if conn.WriteSize(streaming.TerminalSize{Width: size.Width, Height: size.Height}) != nil {
+				return
+			}
+		}
+		<-winch
+	}
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{execCmd, attachCmd} {
+		cmd.Flags().StringP("container", "c", "", "container name, can be omitted if pod has only one container")
+		cmd.Flags().BoolP("stdin", "i", false, "pass stdin to the container")
+		cmd.Flags().BoolP("tty", "t", false, "stdin is a TTY")
+		rootCmd.AddCommand(cmd)
+	}
+}
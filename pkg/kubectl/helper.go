@@ -29,9 +29,11 @@ func GetNamespace() string {
 	return namespace
 }
 
-func GetFilename() string {
+// GetFilename returns the file given by -f flag of cmd, commands reading
+// objects from file register the flag with addFilenameFlag
+func GetFilename(cmd *cobra.Command) string {
 	filename := ""
-	name, err := rootCmd.PersistentFlags().GetString("filename")
+	name, err := cmd.Flags().GetString("filename")
 	if err != nil {
 		fmt.Println("[Kubectl] [GetFileName] [Error]", err)
 	}
@@ -41,6 +43,10 @@ func GetFilename() string {
 	return filename
 }
 
+func addFilenameFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("filename", "f", "", "resource name")
+}
+
 func Error(cmd *cobra.Command, args []string, err error) {
 	fmt.Fprintf(os.Stderr, "execute %s args:%v error:%v\n", cmd.Name(), args, err)
 	os.Exit(1)
@@ -0,0 +1,109 @@
+package kubectl
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"io"
+	"minik8s/pkg/api"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+	"minik8s/pkg/apiclient"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+var logsCmd = &cobra.Command{
+	Use:     "logs <pod-name> [-c <container>] [-f]",
+	Example: "logs mypod\nlogs mypod -c nginx -f\nlogs mypod --tail 20 --since 10m\nlogs mypod --previous\n",
+	Short:   "print logs of a container in pod",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		query, err := buildLogQuery(cmd)
+		if err != nil {
+			fmt.Printf("Invalid flags, err: %v\n", err)
+			return
+		}
+		err = printPodLogs(args[0], query)
+		if err != nil {
+			fmt.Printf("Get logs of pod %v failed, err: %v\n", args[0], err)
+		}
+	},
+}
+
+// buildLogQuery builds query of log subresource from flags
+func buildLogQuery(cmd *cobra.Command) (url.Values, error) {
+	container, _ := cmd.Flags().GetString("container")
+	follow, _ := cmd.Flags().GetBool("follow")
+	previous, _ := cmd.Flags().GetBool("previous")
+	tail, _ := cmd.Flags().GetInt64("tail")
+	since, _ := cmd.Flags().GetDuration("since")
+
+	query := url.Values{}
+	if container != "" {
+		query.Set(api.LogContainerParam, container)
+	}
+	if follow {
+		query.Set(api.LogFollowParam, "true")
+	}
+	if previous {
+		query.Set(api.LogPreviousParam, "true")
+	}
+	if tail >= 0 {
+		query.Set(api.LogTailLinesParam, strconv.FormatInt(tail, 10))
+	}
+	if since < 0 {
+		return nil, errors.New("--since must be positive")
+	}
+	if since > 0 {
+		// round up, so that logs in the last second are not missed
+		seconds := int64((since + time.Second - 1) / time.Second)
+		query.Set(api.LogSinceSecondsParam, strconv.FormatInt(seconds, 10))
+	}
+	return query, nil
+}
+
+// getPod returns pod whose name or uid is name
+func getPod(name string) (*core.Pod, error) {
+	cli, _ := apiclient.NewRESTClient(types.PodObjectType)
+	podList, err := cli.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range podList.GetIApiObjectArr() {
+		pod := item.(*core.Pod)
+		if pod.Name == name || pod.UID == name {
+			return pod, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("pod %v not found", name))
+}
+
+// printPodLogs copies logs of pod to stdout, until container stops if following
+func printPodLogs(name string, query url.Values) error {
+	pod, err := getPod(name)
+	if err != nil {
+		return err
+	}
+
+	cli, _ := apiclient.NewRESTClient(types.PodObjectType)
+	logs, err := cli.Logs(pod.UID, query)
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+
+	_, err = io.Copy(os.Stdout, logs)
+	return err
+}
+
+func init() {
+	logsCmd.Flags().StringP("container", "c", "", "print logs of this container, can be omitted if pod has only one container")
+	logsCmd.Flags().BoolP("follow", "f", false, "stream logs until container stops")
+	logsCmd.Flags().BoolP("previous", "p", false, "print logs of the previous run of container if it restarted")
+	logsCmd.Flags().Int64("tail", -1, "lines of recent logs to print, all lines if negative")
+	logsCmd.Flags().Duration("since", 0, "only print logs newer than a relative duration like 5s, 2m or 3h")
+	rootCmd.AddCommand(logsCmd)
+}
@@ -0,0 +1,248 @@
+package kubectl
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"io"
+	"minik8s/pkg/api"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+	"minik8s/pkg/apiclient"
+	"minik8s/pkg/kubelet/streaming"
+	"net"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var portForwardCmd = &cobra.Command{
+	Use:     "port-forward <pod-name> | pod/<pod-name> | svc/<service-name> [<local-port>:]<remote-port> ...",
+	Example: "port-forward mypod 8080:80\nport-forward pod/mypod 8080:80 8443:443\nport-forward svc/myservice 8080:80\nport-forward mypod :80\n",
+	Short:   "forward local ports to a pod",
+	Args:    cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		address, _ := cmd.Flags().GetString("address")
+		err := portForward(args[0], args[1:], address)
+		if err != nil {
+			fmt.Printf("Port forward to %v failed, err: %v\n", args[0], err)
+		}
+	},
+}
+
+// forwardedPort is a local port forwarded to port of pod, local port
+// is chosen randomly if it is zero
+type forwardedPort struct {
+	local  int32
+	remote int32
+}
+
+// parseForwardedPorts parses ports in form of [local:]remote
+func parseForwardedPorts(specs []string) ([]forwardedPort, error) {
+	var ports []forwardedPort
+	for _, spec := range specs {
+		localStr, remoteStr := spec, spec
+		if i := strings.Index(spec, ":"); i >= 0 {
+			localStr, remoteStr = spec[:i], spec[i+1:]
+		}
+
+		remote, err := parsePort(remoteStr)
+		if err != nil || remote == 0 {
+			return nil, errors.New(fmt.Sprintf("invalid remote port in %q", spec))
+		}
+		local := int32(0)
+		if localStr != "" {
+			if local, err = parsePort(localStr); err != nil {
+				return nil, errors.New(fmt.Sprintf("invalid local port in %q", spec))
+			}
+		}
+		ports = append(ports, forwardedPort{local: local, remote: remote})
+	}
+	return ports, nil
+}
+
+func parsePort(s string) (int32, error) {
+	port, err := strconv.ParseUint(s, 10, 16)
+	return int32(port), err
+}
+
+// getForwardTarget returns the pod to forward ports to. For a service, it
+// is a ready pod selected by service, and ports are translated from ports
+// of service to target ports on pod.
+func getForwardTarget(target string, ports []forwardedPort) (*core.Pod, error) {
+	kind, name := "pod", target
+	if i := strings.Index(target, "/"); i >= 0 {
+		kind, name = target[:i], target[i+1:]
+	}
+
+	switch kind {
+	case "pod", "pods", "po":
+		pod, err := getPod(name)
+		if err != nil {
+			return nil, err
+		}
+		if pod.Status.Phase != core.PodRunning {
+			return nil, errors.New(fmt.Sprintf("unable to forward port because pod is not running. Current status=%v", pod.Status.Phase))
+		}
+		return pod, nil
+	case "service", "services", "svc":
+		svc, err := getService(name)
+		if err != nil {
+			return nil, err
+		}
+		for i := range ports {
+			if ports[i].remote, err = serviceTargetPort(svc, ports[i].remote); err != nil {
+				return nil, err
+			}
+		}
+		return getReadyPodOfService(svc)
+	default:
+		return nil, errors.New(fmt.Sprintf("port forward to %v is not supported, use pod or svc", kind))
+	}
+}
+
+// getService returns service whose name or uid is name
+func getService(name string) (*core.Service, error) {
+	cli, _ := apiclient.NewRESTClient(types.ServiceObjectType)
+	svcList, err := cli.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range svcList.GetIApiObjectArr() {
+		svc := item.(*core.Service)
+		if svc.Name == name || svc.UID == name {
+			return svc, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("service %v not found", name))
+}
+
+func serviceTargetPort(svc *core.Service, port int32) (int32, error) {
+	for _, servicePort := range svc.Spec.Ports {
+		if servicePort.Port != port {
+			continue
+		}
+		if servicePort.TargetPort == 0 {
+			return servicePort.Port, nil
+		}
+		return servicePort.TargetPort, nil
+	}
+	return 0, errors.New(fmt.Sprintf("service %v does not have port %v", svc.Name, port))
+}
+
+func getReadyPodOfService(svc *core.Service) (*core.Pod, error) {
+	cli, _ := apiclient.NewRESTClient(types.PodObjectType)
+	podList, err := cli.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range podList.GetIApiObjectArr() {
+		pod := item.(*core.Pod)
+		if svc.SelectsPod(pod) && pod.Status.Phase == core.PodRunning && pod.IsReady() {
+			return pod, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("no ready pod selected by service %v", svc.Name))
+}
+
+// portForward listens on local ports and forwards each connection to pod
+// through a new websocket, until interrupted
+func portForward(target string, specs []string, address string) error {
+	ports, err := parseForwardedPorts(specs)
+	if err != nil {
+		return err
+	}
+	pod, err := getForwardTarget(target, ports)
+	if err != nil {
+		return err
+	}
+
+	var listeners []net.Listener
+	defer func() {
+		for _, listener := range listeners {
+			_ = listener.Close()
+		}
+	}()
+	for _, port := range ports {
+		listener, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(int(port.local))))
+		if err != nil {
+			return err
+		}
+		listeners = append(listeners, listener)
+		fmt.Printf("Forwarding from %v -> %v\n", listener.Addr(), port.remote)
+	}
+
+	var wg sync.WaitGroup
+	for i := range ports {
+		wg.Add(1)
+		go func(listener net.Listener, remote int32) {
+			defer wg.Done()
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				go forwardConnection(conn, pod.UID, remote)
+			}
+		}(listeners[i], ports[i].remote)
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	<-interrupt
+	for _, listener := range listeners {
+		_ = listener.Close()
+	}
+	wg.Wait()
+	return nil
+}
+
+// forwardConnection copies data between local connection and port of pod
+func forwardConnection(conn net.Conn, uid string, port int32) {
+	defer conn.Close()
+	fmt.Printf("Handling connection for %v\n", conn.LocalAddr())
+
+	query := url.Values{}
+	query.Set(api.PortForwardPortParam, strconv.Itoa(int(port)))
+	ws, err := streaming.Dial(podStreamURL(uid, api.PortForwardSuffix, query))
+	if err != nil {
+		fmt.Printf("error forwarding port %v: %v\n", port, err)
+		return
+	}
+	defer ws.Close()
+
+	go func() {
+		_, _ = io.Copy(ws.Writer(streaming.StdinChannel), conn)
+		// empty stdin message tells that local connection is closed
+		_ = ws.Write(streaming.StdinChannel, nil)
+	}()
+	for {
+		channel, data, err := ws.Read()
+		if err != nil {
+			return
+		}
+		switch channel {
+		case streaming.StdoutChannel:
+			if _, err = conn.Write(data); err != nil {
+				return
+			}
+		case streaming.ErrorChannel:
+			status, err := streaming.ReadStatus(data)
+			if err == nil && status.Status != streaming.StatusSuccess {
+				err = errors.New(status.Message)
+			}
+			if err != nil {
+				fmt.Printf("error forwarding port %v: %v\n", port, err)
+			}
+			return
+		}
+	}
+}
+
+func init() {
+	portForwardCmd.Flags().String("address", "localhost", "address to listen on")
+	rootCmd.AddCommand(portForwardCmd)
+}
@@ -0,0 +1,49 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/cobra"
+	"io"
+	"minik8s/config"
+	"minik8s/pkg/api"
+	"net/http"
+)
+
+var reencryptCmd = &cobra.Command{
+	Use:   "reencrypt <resource>",
+	Short: "re-encrypt all resources of a type in etcd under the current key",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		s := args[0]
+		objType, err := ParseType(s)
+		if err != nil {
+			fmt.Printf("No %v type of resource, err: %v\n", s, err)
+			return
+		}
+
+		url := config.ApiServerUrl() + "/api/reencrypt/" + string(objType)
+		res, err := http.Post(url, "application/json", nil)
+		if err != nil {
+			fmt.Println("reencrypt request sent failed, err:", err)
+			return
+		}
+		defer res.Body.Close()
+
+		buf, _ := io.ReadAll(res.Body)
+		resp := &api.ReencryptResponse{}
+		if err := json.Unmarshal(buf, resp); err != nil {
+			fmt.Println("reencrypt failed, err:", err)
+			return
+		}
+		if res.StatusCode != http.StatusOK {
+			fmt.Printf("reencrypt %v failed after %v re-encrypted, err: %v\n", objType, resp.Reencrypted, resp.ErrorMsg)
+			return
+		}
+		fmt.Printf("%v %v re-encrypted\n", resp.Reencrypted, objType)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reencryptCmd)
+}
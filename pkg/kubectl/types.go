@@ -24,6 +24,18 @@ func ParseType(ty string) (types.ApiObjectType, error) {
 		return types.JobObjectType, nil
 	case "dns":
 		return types.DnsObjectType, nil
+	case "pdb", "pdbs", "poddisruptionbudget", "poddisruptionbudgets":
+		return types.PodDisruptionBudgetObjectType, nil
+	case "configmap", "cm", "configmaps":
+		return types.ConfigMapObjectType, nil
+	case "secret", "secrets":
+		return types.SecretObjectType, nil
+	case "pv", "pvs", "persistentvolume", "persistentvolumes":
+		return types.PersistentVolumeObjectType, nil
+	case "pvc", "pvcs", "persistentvolumeclaim", "persistentvolumeclaims":
+		return types.PersistentVolumeClaimObjectType, nil
+	case "ev", "event", "events":
+		return types.EventObjectType, nil
 	default:
 		errMsg := fmt.Sprintf("No ObjectType %v", ty)
 		return types.ErrorObjectType, errors.New(errMsg)
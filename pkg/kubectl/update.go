@@ -22,7 +22,7 @@ var updateCmd = &cobra.Command{
 			return
 		}
 
-		filename := GetFilename()
+		filename := GetFilename(cmd)
 		jsonData, err := utils.GetFormJsonData(filename)
 		if err != nil {
 			fmt.Println("File parse err:", err)
@@ -39,6 +39,14 @@ var updateCmd = &cobra.Command{
 		} else {
 			if objType == types.FuncTemplateObjectType {
 				name = object.(*core.Func).Name
+			} else if objType == types.ConfigMapObjectType {
+				name = object.(*core.ConfigMap).Name
+			} else if objType == types.SecretObjectType {
+				name = object.(*core.Secret).Name
+			} else if objType == types.PersistentVolumeObjectType {
+				name = object.(*core.PersistentVolume).Name
+			} else if objType == types.PersistentVolumeClaimObjectType {
+				name = object.(*core.PersistentVolumeClaim).Name
 			} else {
 				name = object.GetUID()
 			}
@@ -58,5 +66,6 @@ var updateCmd = &cobra.Command{
 }
 
 func init() {
+	addFilenameFlag(updateCmd)
 	rootCmd.AddCommand(updateCmd)
 }
@@ -0,0 +1,229 @@
+package cm
+
+import (
+	"fmt"
+	"minik8s/config"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CgroupName is path of cgroup from root of hierarchy, e.g. {"kubepods", "burstable", "pod<uid>"}
+type CgroupName []string
+
+// ResourceConfig is cgroup settings, nil fields are left unchanged
+type ResourceConfig struct {
+	// CPUShares is relative weight of cpu time, converted to cpu.weight on cgroup v2
+	CPUShares *uint64
+	// CPUQuota is cpu time in microseconds in each CPUPeriod, -1 for unlimited
+	CPUQuota  *int64
+	CPUPeriod *uint64
+	// Memory is limit of memory in bytes, -1 for unlimited
+	Memory *int64
+}
+
+// CgroupManager creates and removes cgroups of cpu and memory controllers
+type CgroupManager interface {
+	// Create creates cgroup and its parents, and applies resources to it
+	Create(name CgroupName, resources *ResourceConfig) error
+	Update(name CgroupName, resources *ResourceConfig) error
+	// Destroy removes cgroup, processes in it must have exited
+	Destroy(name CgroupName) error
+	Exists(name CgroupName) bool
+	// Name returns cgroup as cgroup parent of container runtime
+	Name(name CgroupName) string
+	// RelativePath returns path of cgroup relative to root of hierarchy,
+	// which is also name of the cgroup in cadvisor
+	RelativePath(name CgroupName) string
+}
+
+// controllers are cgroup controllers kubelet manages
+var controllers = []string{"cpu", "memory"}
+
+type cgroupManager struct {
+	// root is mount point of cgroup filesystem, e.g. /sys/fs/cgroup
+	root   string
+	driver string
+	// unified is true for cgroup v2, whose controllers share one hierarchy
+	unified bool
+}
+
+// NewCgroupManager returns manager of cgroups under root named by driver,
+// cgroup v2 is used if root is mounted as unified hierarchy
+func NewCgroupManager(root string, driver string) (CgroupManager, error) {
+	switch driver {
+	case "":
+		driver = config.CgroupfsDriver
+	case config.CgroupfsDriver, config.SystemdDriver:
+	default:
+		return nil, fmt.Errorf("unknown cgroup driver %q", driver)
+	}
+	_, err := os.Stat(filepath.Join(root, "cgroup.controllers"))
+	return &cgroupManager{root: root, driver: driver, unified: err == nil}, nil
+}
+
+func (m *cgroupManager) Name(name CgroupName) string {
+	if m.driver == config.SystemdDriver {
+		return systemdSlice(name)
+	}
+	return "/" + strings.Join(name, "/")
+}
+
+func (m *cgroupManager) RelativePath(name CgroupName) string {
+	if m.driver != config.SystemdDriver {
+		return filepath.Join(name...)
+	}
+	// systemd nests slice a-b.slice in a.slice
+	elements := make([]string, 0, len(name))
+	for i := range name {
+		elements = append(elements, systemdSlice(name[:i+1]))
+	}
+	return filepath.Join(elements...)
+}
+
+// systemdSlice returns name of systemd slice of cgroup, dashes in
+// components are escaped as they separate levels of slices
+func systemdSlice(name CgroupName) string {
+	escaped := make([]string, 0, len(name))
+	for _, component := range name {
+		escaped = append(escaped, strings.ReplaceAll(component, "-", "_"))
+	}
+	return strings.Join(escaped, "-") + ".slice"
+}
+
+// paths returns directories of cgroup keyed by controller, which is the
+// same directory for all controllers on cgroup v2
+func (m *cgroupManager) paths(name CgroupName) map[string]string {
+	res := make(map[string]string)
+	for _, controller := range controllers {
+		if m.unified {
+			res[controller] = filepath.Join(m.root, m.RelativePath(name))
+		} else {
+			res[controller] = filepath.Join(m.root, controller, m.RelativePath(name))
+		}
+	}
+	return res
+}
+
+func (m *cgroupManager) Create(name CgroupName, resources *ResourceConfig) error {
+	for _, path := range m.paths(name) {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return err
+		}
+	}
+	if m.unified {
+		// controllers must be enabled in every ancestor to be used by cgroup
+		parent := m.root
+		for _, element := range strings.Split(m.RelativePath(name), string(os.PathSeparator)) {
+			if err := writeFile(parent, "cgroup.subtree_control", "+"+strings.Join(controllers, " +")); err != nil {
+				return err
+			}
+			parent = filepath.Join(parent, element)
+		}
+	}
+	return m.Update(name, resources)
+}
+
+func (m *cgroupManager) Update(name CgroupName, resources *ResourceConfig) error {
+	if resources == nil {
+		return nil
+	}
+	paths := m.paths(name)
+	if m.unified {
+		return setUnified(paths["cpu"], resources)
+	}
+
+	cpu, memory := paths["cpu"], paths["memory"]
+	if resources.CPUShares != nil {
+		if err := writeFile(cpu, "cpu.shares", strconv.FormatUint(*resources.CPUShares, 10)); err != nil {
+			return err
+		}
+	}
+	if resources.CPUPeriod != nil {
+		if err := writeFile(cpu, "cpu.cfs_period_us", strconv.FormatUint(*resources.CPUPeriod, 10)); err != nil {
+			return err
+		}
+	}
+	if resources.CPUQuota != nil {
+		if err := writeFile(cpu, "cpu.cfs_quota_us", strconv.FormatInt(*resources.CPUQuota, 10)); err != nil {
+			return err
+		}
+	}
+	if resources.Memory != nil {
+		if err := writeFile(memory, "memory.limit_in_bytes", strconv.FormatInt(*resources.Memory, 10)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setUnified writes resources to cgroup v2 at path
+func setUnified(path string, resources *ResourceConfig) error {
+	if resources.CPUShares != nil {
+		if err := writeFile(path, "cpu.weight", strconv.FormatUint(cpuSharesToWeight(*resources.CPUShares), 10)); err != nil {
+			return err
+		}
+	}
+	if resources.CPUQuota != nil || resources.CPUPeriod != nil {
+		quota, period := "max", strconv.FormatUint(DefaultCPUPeriod, 10)
+		if resources.CPUQuota != nil && *resources.CPUQuota > 0 {
+			quota = strconv.FormatInt(*resources.CPUQuota, 10)
+		}
+		if resources.CPUPeriod != nil {
+			period = strconv.FormatUint(*resources.CPUPeriod, 10)
+		}
+		if err := writeFile(path, "cpu.max", quota+" "+period); err != nil {
+			return err
+		}
+	}
+	if resources.Memory != nil {
+		limit := "max"
+		if *resources.Memory > 0 {
+			limit = strconv.FormatInt(*resources.Memory, 10)
+		}
+		if err := writeFile(path, "memory.max", limit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cpuSharesToWeight converts cpu.shares in [2, 262144] of cgroup v1
+// to cpu.weight in [1, 10000] of cgroup v2
+func cpuSharesToWeight(shares uint64) uint64 {
+	if shares < MinShares {
+		shares = MinShares
+	}
+	if shares > MaxShares {
+		shares = MaxShares
+	}
+	return 1 + ((shares-MinShares)*9999)/(MaxShares-MinShares)
+}
+
+func (m *cgroupManager) Destroy(name CgroupName) error {
+	for _, path := range m.paths(name) {
+		// rmdir of cgroup succeeds if it has no child cgroup, control files
+		// in it are removed by kernel
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *cgroupManager) Exists(name CgroupName) bool {
+	for _, path := range m.paths(name) {
+		if _, err := os.Stat(path); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func writeFile(dir string, file string, value string) error {
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(value), 0644); err != nil {
+		return fmt.Errorf("set %s of cgroup %s to %s: %v", file, dir, value, err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package cm
+
+import (
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+	"minik8s/pkg/kubelet/qos"
+)
+
+const (
+	// MinShares and MaxShares are range of cpu.shares
+	MinShares = 2
+	MaxShares = 262144
+	// SharesPerCPU is cpu.shares of one cpu
+	SharesPerCPU = 1024
+	// DefaultCPUPeriod is cfs period in microseconds, cpu quota is counted in it
+	DefaultCPUPeriod = 100000
+	// MinQuotaPeriod is the minimum cpu quota in microseconds allowed by kernel
+	MinQuotaPeriod = 1000
+)
+
+// MilliCPUToShares converts milli cpu to cpu.shares
+func MilliCPUToShares(milliCPU uint64) uint64 {
+	if milliCPU == 0 {
+		return MinShares
+	}
+	shares := milliCPU * SharesPerCPU / 1000
+	if shares < MinShares {
+		return MinShares
+	}
+	if shares > MaxShares {
+		return MaxShares
+	}
+	return shares
+}
+
+// MilliCPUToQuota converts milli cpu to cpu quota in period, 0 milli cpu
+// is unlimited
+func MilliCPUToQuota(milliCPU uint64, period uint64) int64 {
+	if milliCPU == 0 {
+		return 0
+	}
+	quota := int64(milliCPU * period / 1000)
+	if quota < MinQuotaPeriod {
+		quota = MinQuotaPeriod
+	}
+	return quota
+}
+
+// containerResources returns cpu requests, cpu limit and memory limit of container,
+// requests default to limits, and unrecognized or absent quantities are 0
+func containerResources(container *core.Container) (cpuRequest uint64, cpuLimit uint64, memoryLimit uint64) {
+	parse := func(list core.ResourceList, name types.ResourceName) uint64 {
+		q, found := list[name]
+		if !found {
+			return 0
+		}
+		v, err := types.ParseQuantity(name, q)
+		if err != nil {
+			return 0
+		}
+		return v
+	}
+	cpuLimit = parse(container.Resources.Limits, types.ResourceCPU)
+	memoryLimit = parse(container.Resources.Limits, types.ResourceMemory) * 1024 * 1024
+	cpuRequest = parse(container.Resources.Requests, types.ResourceCPU)
+	if cpuRequest == 0 {
+		cpuRequest = cpuLimit
+	}
+	return
+}
+
+// ResourceConfigForPod returns resources of cgroup of pod. CPU shares are from
+// cpu requests of containers, cpu quota and memory limit are from their limits,
+// and are unlimited if any container has no such limit. Init containers run one
+// by one, so pod needs the larger of their maximum and sum of app containers.
+func ResourceConfigForPod(pod *core.Pod) *ResourceConfig {
+	var cpuRequests, cpuLimits, memoryLimits uint64
+	cpuLimited, memoryLimited := true, true
+	for i := range pod.Spec.Containers {
+		cpuRequest, cpuLimit, memoryLimit := containerResources(&pod.Spec.Containers[i])
+		cpuRequests += cpuRequest
+		cpuLimits += cpuLimit
+		memoryLimits += memoryLimit
+		cpuLimited = cpuLimited && cpuLimit != 0
+		memoryLimited = memoryLimited && memoryLimit != 0
+	}
+	for i := range pod.Spec.InitContainers {
+		cpuRequest, cpuLimit, memoryLimit := containerResources(&pod.Spec.InitContainers[i])
+		cpuRequests = maxUint64(cpuRequests, cpuRequest)
+		cpuLimits = maxUint64(cpuLimits, cpuLimit)
+		memoryLimits = maxUint64(memoryLimits, memoryLimit)
+		cpuLimited = cpuLimited && cpuLimit != 0
+		memoryLimited = memoryLimited && memoryLimit != 0
+	}
+
+	shares := MilliCPUToShares(cpuRequests)
+	period := uint64(DefaultCPUPeriod)
+	quota, memory := int64(-1), int64(-1)
+	if cpuLimited {
+		quota = MilliCPUToQuota(cpuLimits, period)
+	}
+	if memoryLimited {
+		memory = int64(memoryLimits)
+	}
+	if qos.GetPodQOS(pod) == core.PodQOSBestEffort {
+		shares = MinShares
+	}
+	return &ResourceConfig{CPUShares: &shares, CPUQuota: &quota, CPUPeriod: &period, Memory: &memory}
+}
+
+func maxUint64(a, b uint64) uint64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,108 @@
+package cm
+
+import (
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+	"minik8s/pkg/kubelet/qos"
+)
+
+// Names of cgroups of pods, Guaranteed pods are directly under kubepods,
+// others are under cgroups of their qos classes
+const (
+	KubepodsCgroupName   = "kubepods"
+	BurstableCgroupName  = "burstable"
+	BestEffortCgroupName = "besteffort"
+	podCgroupNamePrefix  = "pod"
+)
+
+// PodContainerManager manages cgroups of pods, processes of all containers of a
+// pod are in its cgroup, which limits resources of the pod as a whole
+type PodContainerManager interface {
+	// Start creates cgroups of qos classes, BestEffort pods get minimum cpu shares
+	Start() error
+	// EnsureExists creates or updates cgroup of pod with resources of its containers
+	EnsureExists(pod *core.Pod) error
+	// GetPodCgroupParent returns cgroup of pod as cgroup parent of container runtime
+	GetPodCgroupParent(pod *core.Pod) string
+	// GetPodCgroupPath returns name of cgroup of pod in cadvisor
+	GetPodCgroupPath(pod *core.Pod) string
+	// Destroy removes cgroup of pod after all its containers are removed
+	Destroy(pod *core.Pod) error
+	// DestroyByUID removes cgroup of pod of uid under any qos class, it is
+	// used for pods left by kubelet before restart, whose specs are unknown
+	DestroyByUID(uid types.UID) error
+}
+
+type podContainerManager struct {
+	cgroupManager CgroupManager
+}
+
+func NewPodContainerManager(cgroupManager CgroupManager) PodContainerManager {
+	return &podContainerManager{cgroupManager: cgroupManager}
+}
+
+func (m *podContainerManager) Start() error {
+	if err := m.cgroupManager.Create(CgroupName{KubepodsCgroupName}, nil); err != nil {
+		return err
+	}
+	if err := m.cgroupManager.Create(CgroupName{KubepodsCgroupName, BurstableCgroupName}, nil); err != nil {
+		return err
+	}
+	shares := uint64(MinShares)
+	return m.cgroupManager.Create(CgroupName{KubepodsCgroupName, BestEffortCgroupName}, &ResourceConfig{CPUShares: &shares})
+}
+
+// podCgroupName returns name of cgroup of pod, which is under cgroup of its qos class
+func podCgroupName(pod *core.Pod) CgroupName {
+	name := podCgroupNamePrefix + pod.UID
+	switch qos.GetPodQOS(pod) {
+	case core.PodQOSGuaranteed:
+		return CgroupName{KubepodsCgroupName, name}
+	case core.PodQOSBurstable:
+		return CgroupName{KubepodsCgroupName, BurstableCgroupName, name}
+	default:
+		return CgroupName{KubepodsCgroupName, BestEffortCgroupName, name}
+	}
+}
+
+func (m *podContainerManager) EnsureExists(pod *core.Pod) error {
+	name := podCgroupName(pod)
+	if m.cgroupManager.Exists(name) {
+		return m.cgroupManager.Update(name, ResourceConfigForPod(pod))
+	}
+	return m.cgroupManager.Create(name, ResourceConfigForPod(pod))
+}
+
+func (m *podContainerManager) GetPodCgroupParent(pod *core.Pod) string {
+	return m.cgroupManager.Name(podCgroupName(pod))
+}
+
+func (m *podContainerManager) GetPodCgroupPath(pod *core.Pod) string {
+	return "/" + m.cgroupManager.RelativePath(podCgroupName(pod))
+}
+
+func (m *podContainerManager) Destroy(pod *core.Pod) error {
+	name := podCgroupName(pod)
+	if !m.cgroupManager.Exists(name) {
+		return nil
+	}
+	return m.cgroupManager.Destroy(name)
+}
+
+func (m *podContainerManager) DestroyByUID(uid types.UID) error {
+	name := podCgroupNamePrefix + uid
+	for _, parent := range []CgroupName{
+		{KubepodsCgroupName},
+		{KubepodsCgroupName, BurstableCgroupName},
+		{KubepodsCgroupName, BestEffortCgroupName},
+	} {
+		cgroup := append(parent, name)
+		if !m.cgroupManager.Exists(cgroup) {
+			continue
+		}
+		if err := m.cgroupManager.Destroy(cgroup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
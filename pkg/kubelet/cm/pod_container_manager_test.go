@@ -0,0 +1,96 @@
+package cm
+
+import (
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/meta"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPodContainerManager(t *testing.T) {
+	pod := &core.Pod{ObjectMeta: meta.ObjectMeta{Name: "web", UID: "a-1"}}
+	pod.Spec.Containers = []core.Container{
+		{Name: "app", Resources: core.ResourceRequirements{
+			Requests: core.ResourceList{"cpu": "250m", "memory": "128M"},
+			Limits:   core.ResourceList{"cpu": "500m", "memory": "256M"},
+		}},
+		{Name: "sidecar", Resources: core.ResourceRequirements{
+			Limits: core.ResourceList{"cpu": "500m", "memory": "256M"},
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		driver  string
+		unified bool
+		parent  string
+		files   map[string]string
+	}{
+		{
+			name:   "cgroupfs on cgroup v1",
+			driver: "cgroupfs",
+			parent: "/kubepods/burstable/poda-1",
+			files: map[string]string{
+				"cpu/kubepods/burstable/poda-1/cpu.shares":               "768",
+				"cpu/kubepods/burstable/poda-1/cpu.cfs_quota_us":         "100000",
+				"cpu/kubepods/burstable/poda-1/cpu.cfs_period_us":        "100000",
+				"memory/kubepods/burstable/poda-1/memory.limit_in_bytes": "536870912",
+				"cpu/kubepods/besteffort/cpu.shares":                     "2",
+			},
+		},
+		{
+			name:    "systemd on cgroup v2",
+			driver:  "systemd",
+			unified: true,
+			parent:  "kubepods-burstable-poda_1.slice",
+			files: map[string]string{
+				"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-poda_1.slice/cpu.weight": "30",
+				"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-poda_1.slice/cpu.max":    "100000 100000",
+				"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-poda_1.slice/memory.max": "536870912",
+				"kubepods.slice/kubepods-burstable.slice/cgroup.subtree_control":                     "+cpu +memory",
+				"cgroup.subtree_control": "+cpu +memory",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			if tt.unified {
+				if err := os.WriteFile(filepath.Join(root, "cgroup.controllers"), []byte("cpu memory"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			cgroupManager, err := NewCgroupManager(root, tt.driver)
+			if err != nil {
+				t.Fatal(err)
+			}
+			manager := NewPodContainerManager(cgroupManager)
+			if err = manager.Start(); err != nil {
+				t.Fatal(err)
+			}
+			if err = manager.EnsureExists(pod); err != nil {
+				t.Fatal(err)
+			}
+
+			if parent := manager.GetPodCgroupParent(pod); parent != tt.parent {
+				t.Errorf("GetPodCgroupParent() = %q, want %q", parent, tt.parent)
+			}
+			for file, want := range tt.files {
+				data, err := os.ReadFile(filepath.Join(root, file))
+				if err != nil {
+					t.Errorf("read %s: %v", file, err)
+				} else if string(data) != want {
+					t.Errorf("%s = %q, want %q", file, data, want)
+				}
+			}
+
+			if err = manager.Destroy(pod); err != nil {
+				t.Fatal(err)
+			}
+			if cgroupManager.Exists(podCgroupName(pod)) {
+				t.Error("cgroup of pod exists after it is destroyed")
+			}
+		})
+	}
+}
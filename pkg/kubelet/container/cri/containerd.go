@@ -0,0 +1,638 @@
+package cri
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// nameLabel is label of sandboxes and containers created by kubelet,
+// whose value is name given by kubelet
+const nameLabel = "io.minik8s.name"
+
+// containerdClient talks to CRI plugin of containerd through gRPC
+type containerdClient struct {
+	conn    *grpc.ClientConn
+	runtime runtimeapi.RuntimeServiceClient
+	image   runtimeapi.ImageServiceClient
+
+	// CRI does not start exited containers, they are created again from
+	// requests kept here to restart, keyed by container name
+	lock     sync.Mutex
+	requests map[string]*runtimeapi.CreateContainerRequest
+}
+
+// NewContainerd connects to CRI plugin of containerd on endpoint,
+// e.g. unix:///run/containerd/containerd.sock
+func NewContainerd(endpoint string) (Client, error) {
+	conn, err := grpc.Dial(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	c := &containerdClient{
+		conn:     conn,
+		runtime:  runtimeapi.NewRuntimeServiceClient(conn),
+		image:    runtimeapi.NewImageServiceClient(conn),
+		requests: make(map[string]*runtimeapi.CreateContainerRequest),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err = c.runtime.Version(ctx, &runtimeapi.VersionRequest{}); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("connect to containerd on %s: %v", endpoint, err)
+	}
+	return c, nil
+}
+
+func (c *containerdClient) Close() {
+	_ = c.conn.Close()
+}
+
+/*---------------------------- Sandbox ----------------------------*/
+
+func (c *containerdClient) RunPodSandbox(ctx context.Context, sandbox SandboxConfig) (string, error) {
+	resp, err := c.runtime.RunPodSandbox(ctx, &runtimeapi.RunPodSandboxRequest{Config: buildSandboxConfig(sandbox)})
+	if err != nil {
+		return "", err
+	}
+	return resp.PodSandboxId, nil
+}
+
+func (c *containerdClient) StopPodSandbox(ctx context.Context, name string) error {
+	id, err := c.sandboxID(ctx, name)
+	if err != nil {
+		return err
+	}
+	_, err = c.runtime.StopPodSandbox(ctx, &runtimeapi.StopPodSandboxRequest{PodSandboxId: id})
+	return err
+}
+
+// RemovePodSandbox removes sandbox with its containers, and logs of them
+func (c *containerdClient) RemovePodSandbox(ctx context.Context, name string) error {
+	id, err := c.sandboxID(ctx, name)
+	if err != nil {
+		return err
+	}
+	resp, err := c.runtime.PodSandboxStatus(ctx, &runtimeapi.PodSandboxStatusRequest{PodSandboxId: id})
+	if err != nil {
+		return err
+	}
+	if _, err = c.runtime.RemovePodSandbox(ctx, &runtimeapi.RemovePodSandboxRequest{PodSandboxId: id}); err != nil {
+		return err
+	}
+	if metadata := resp.Status.GetMetadata(); metadata != nil {
+		return os.RemoveAll(sandboxLogDirectory(metadata.Namespace, metadata.Name, metadata.Uid))
+	}
+	return nil
+}
+
+func (c *containerdClient) PodSandboxStatus(ctx context.Context, name string) (SandboxStatus, error) {
+	id, err := c.sandboxID(ctx, name)
+	if err != nil {
+		return SandboxStatus{}, err
+	}
+	resp, err := c.runtime.PodSandboxStatus(ctx, &runtimeapi.PodSandboxStatusRequest{PodSandboxId: id})
+	if err != nil {
+		return SandboxStatus{}, err
+	}
+	return SandboxStatus{
+		ID:    id,
+		Ready: resp.Status.State == runtimeapi.PodSandboxState_SANDBOX_READY,
+		IP:    resp.Status.GetNetwork().GetIp(),
+	}, nil
+}
+
+func (c *containerdClient) ListPodSandboxes(ctx context.Context) ([]SandboxInfo, error) {
+	resp, err := c.runtime.ListPodSandbox(ctx, &runtimeapi.ListPodSandboxRequest{})
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[string]*runtimeapi.PodSandbox)
+	for _, item := range resp.Items {
+		name, found := item.Labels[nameLabel]
+		if !found {
+			continue
+		}
+		if other, found := latest[name]; !found || item.CreatedAt > other.CreatedAt {
+			latest[name] = item
+		}
+	}
+	var sandboxes []SandboxInfo
+	for name, item := range latest {
+		sandboxes = append(sandboxes, SandboxInfo{
+			Name:   name,
+			PodUID: item.GetMetadata().GetUid(),
+			Ready:  item.State == runtimeapi.PodSandboxState_SANDBOX_READY,
+		})
+	}
+	return sandboxes, nil
+}
+
+// sandboxID returns id of the latest sandbox named name
+func (c *containerdClient) sandboxID(ctx context.Context, name string) (string, error) {
+	resp, err := c.runtime.ListPodSandbox(ctx, &runtimeapi.ListPodSandboxRequest{
+		Filter: &runtimeapi.PodSandboxFilter{LabelSelector: map[string]string{nameLabel: name}},
+	})
+	if err != nil {
+		return "", err
+	}
+	var latest *runtimeapi.PodSandbox
+	for _, item := range resp.Items {
+		if latest == nil || item.CreatedAt > latest.CreatedAt {
+			latest = item
+		}
+	}
+	if latest == nil {
+		return "", fmt.Errorf("sandbox %s: %w", name, ErrNotFound)
+	}
+	return latest.Id, nil
+}
+
+// sandboxLogDirectory is the directory logs of containers in sandbox are written in
+func sandboxLogDirectory(namespace, name, uid string) string {
+	return filepath.Join(config.PodLogsDir, strings.Join([]string{namespace, name, uid}, "_"))
+}
+
+func buildSandboxConfig(sandbox SandboxConfig) *runtimeapi.PodSandboxConfig {
+	namespace := sandbox.PodNamespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	return &runtimeapi.PodSandboxConfig{
+		Metadata: &runtimeapi.PodSandboxMetadata{
+			Name:      sandbox.PodName,
+			Uid:       sandbox.PodUID,
+			Namespace: namespace,
+		},
+		Hostname:     sandbox.PodName,
+		LogDirectory: sandboxLogDirectory(namespace, sandbox.PodName, sandbox.PodUID),
+		DnsConfig:    &runtimeapi.DNSConfig{Servers: sandbox.DNSServers},
+		Labels:       map[string]string{nameLabel: sandbox.Name, PodUIDLabel: sandbox.PodUID},
+		Linux: &runtimeapi.LinuxPodSandboxConfig{
+			CgroupParent: sandbox.CgroupParent,
+			// security context of sandbox has no oom_score_adj, it is passed in resources
+			Resources: &runtimeapi.LinuxContainerResources{OomScoreAdj: int64(sandbox.OOMScoreAdj)},
+		},
+	}
+}
+
+// sandboxConfigOf returns config sandbox is created with from verbose info of
+// its status, or builds one from its metadata if info is absent
+func sandboxConfigOf(sandbox string, status *runtimeapi.PodSandboxStatusResponse) (*runtimeapi.PodSandboxConfig, error) {
+	info := struct {
+		Config *runtimeapi.PodSandboxConfig `json:"config"`
+	}{}
+	if err := json.Unmarshal([]byte(status.Info["info"]), &info); err == nil && info.Config != nil {
+		return info.Config, nil
+	}
+
+	metadata := status.Status.GetMetadata()
+	if metadata == nil {
+		return nil, fmt.Errorf("sandbox %s has no metadata", sandbox)
+	}
+	return buildSandboxConfig(SandboxConfig{
+		Name:         sandbox,
+		PodName:      metadata.Name,
+		PodNamespace: metadata.Namespace,
+		PodUID:       metadata.Uid,
+	}), nil
+}
+
+/*---------------------------- Container ----------------------------*/
+
+// ContainerCreate creates container in sandbox, containerd creates cgroup of
+// container under cgroup parent in sandbox config of the request
+func (c *containerdClient) ContainerCreate(ctx context.Context, sandbox string, cnt core.Container, resources Resources, mounts []Mount) (string, error) {
+	sandboxID, err := c.sandboxID(ctx, sandbox)
+	if err != nil {
+		return "", err
+	}
+	status, err := c.runtime.PodSandboxStatus(ctx, &runtimeapi.PodSandboxStatusRequest{PodSandboxId: sandboxID, Verbose: true})
+	if err != nil {
+		return "", err
+	}
+	sandboxConfig, err := sandboxConfigOf(sandbox, status)
+	if err != nil {
+		return "", err
+	}
+
+	containerConfig := buildContainerdContainerConfig(cnt, resources, mounts)
+	request := &runtimeapi.CreateContainerRequest{
+		PodSandboxId:  sandboxID,
+		Config:        containerConfig,
+		SandboxConfig: sandboxConfig,
+	}
+	resp, err := c.runtime.CreateContainer(ctx, request)
+	if err != nil {
+		return "", err
+	}
+
+	c.lock.Lock()
+	c.requests[cnt.Name] = request
+	c.lock.Unlock()
+	return resp.ContainerId, nil
+}
+
+func (c *containerdClient) ContainerRemove(ctx context.Context, name string) error {
+	id, err := c.containerID(ctx, name)
+	if err != nil {
+		return err
+	}
+	if _, err = c.runtime.RemoveContainer(ctx, &runtimeapi.RemoveContainerRequest{ContainerId: id}); err != nil {
+		return err
+	}
+	c.lock.Lock()
+	delete(c.requests, name)
+	c.lock.Unlock()
+	return nil
+}
+
+// ContainerStart starts created container. Exited container is removed and
+// created again from the same request, its logs are appended to the same file
+func (c *containerdClient) ContainerStart(ctx context.Context, name string) error {
+	id, err := c.containerID(ctx, name)
+	if err != nil {
+		return err
+	}
+	status, err := c.runtime.ContainerStatus(ctx, &runtimeapi.ContainerStatusRequest{ContainerId: id, Verbose: true})
+	if err != nil {
+		return err
+	}
+
+	if status.Status.State == runtimeapi.ContainerState_CONTAINER_EXITED {
+		request, err := c.restartRequest(ctx, name, status)
+		if err != nil {
+			return err
+		}
+		if _, err = c.runtime.RemoveContainer(ctx, &runtimeapi.RemoveContainerRequest{ContainerId: id}); err != nil {
+			return err
+		}
+		request.Config.Metadata.Attempt++
+		resp, err := c.runtime.CreateContainer(ctx, request)
+		if err != nil {
+			return err
+		}
+		id = resp.ContainerId
+	}
+	_, err = c.runtime.StartContainer(ctx, &runtimeapi.StartContainerRequest{ContainerId: id})
+	return err
+}
+
+// restartRequest returns request exited container is created with. Containers
+// created before kubelet restarts are created again from configs in verbose info
+// of their statuses, which are kept for them later
+func (c *containerdClient) restartRequest(ctx context.Context, name string, status *runtimeapi.ContainerStatusResponse) (*runtimeapi.CreateContainerRequest, error) {
+	c.lock.Lock()
+	request, found := c.requests[name]
+	c.lock.Unlock()
+	if found {
+		return request, nil
+	}
+
+	info := struct {
+		SandboxID string                      `json:"sandboxID"`
+		Config    *runtimeapi.ContainerConfig `json:"config"`
+	}{}
+	if err := json.Unmarshal([]byte(status.Info["info"]), &info); err != nil || info.Config == nil {
+		return nil, fmt.Errorf("container %s can not be restarted, its config is unknown", name)
+	}
+	sandboxStatus, err := c.runtime.PodSandboxStatus(ctx, &runtimeapi.PodSandboxStatusRequest{PodSandboxId: info.SandboxID, Verbose: true})
+	if err != nil {
+		return nil, err
+	}
+	sandboxConfig, err := sandboxConfigOf(sandboxStatus.Status.GetLabels()[nameLabel], sandboxStatus)
+	if err != nil {
+		return nil, err
+	}
+	request = &runtimeapi.CreateContainerRequest{
+		PodSandboxId:  info.SandboxID,
+		Config:        info.Config,
+		SandboxConfig: sandboxConfig,
+	}
+	c.lock.Lock()
+	c.requests[name] = request
+	c.lock.Unlock()
+	return request, nil
+}
+
+// ContainerStop stops container with stop signal of its image, as CRI
+// does not take signal from kubelet
+func (c *containerdClient) ContainerStop(ctx context.Context, name string, signal string, timeout time.Duration) error {
+	id, err := c.containerID(ctx, name)
+	if err != nil {
+		return err
+	}
+	_, err = c.runtime.StopContainer(ctx, &runtimeapi.StopContainerRequest{ContainerId: id, Timeout: int64(timeout.Seconds())})
+	return err
+}
+
+func (c *containerdClient) ContainerStatus(ctx context.Context, name string) (ContainerStatus, error) {
+	id, err := c.containerID(ctx, name)
+	if err != nil {
+		return ContainerStatus{}, err
+	}
+	resp, err := c.runtime.ContainerStatus(ctx, &runtimeapi.ContainerStatusRequest{ContainerId: id})
+	if err != nil {
+		return ContainerStatus{}, err
+	}
+	status := ContainerStatus{
+		ID:       id,
+		Running:  resp.Status.State == runtimeapi.ContainerState_CONTAINER_RUNNING,
+		ExitCode: int(resp.Status.ExitCode),
+		Reason:   resp.Status.Reason,
+	}
+	if resp.Status.StartedAt > 0 {
+		status.StartedAt = time.Unix(0, resp.Status.StartedAt)
+	}
+	if resp.Status.FinishedAt > 0 {
+		status.FinishedAt = time.Unix(0, resp.Status.FinishedAt)
+	}
+	return status, nil
+}
+
+// ListContainers returns the latest container of each name, pod of which is
+// found by metadata of its sandbox
+func (c *containerdClient) ListContainers(ctx context.Context) ([]ContainerInfo, error) {
+	sandboxes, err := c.runtime.ListPodSandbox(ctx, &runtimeapi.ListPodSandboxRequest{})
+	if err != nil {
+		return nil, err
+	}
+	sandboxByID := make(map[string]*runtimeapi.PodSandbox)
+	for _, item := range sandboxes.Items {
+		sandboxByID[item.Id] = item
+	}
+
+	resp, err := c.runtime.ListContainers(ctx, &runtimeapi.ListContainersRequest{})
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[string]*runtimeapi.Container)
+	for _, item := range resp.Containers {
+		name, found := item.Labels[nameLabel]
+		if !found {
+			continue
+		}
+		if other, found := latest[name]; !found || item.CreatedAt > other.CreatedAt {
+			latest[name] = item
+		}
+	}
+	var containers []ContainerInfo
+	for name, item := range latest {
+		sandbox, found := sandboxByID[item.PodSandboxId]
+		if !found {
+			continue
+		}
+		containers = append(containers, ContainerInfo{
+			Name:    name,
+			Sandbox: sandbox.Labels[nameLabel],
+			PodUID:  sandbox.GetMetadata().GetUid(),
+			Running: item.State == runtimeapi.ContainerState_CONTAINER_RUNNING,
+		})
+	}
+	return containers, nil
+}
+
+// containerID returns id of the latest container named name
+func (c *containerdClient) containerID(ctx context.Context, name string) (string, error) {
+	resp, err := c.runtime.ListContainers(ctx, &runtimeapi.ListContainersRequest{
+		Filter: &runtimeapi.ContainerFilter{LabelSelector: map[string]string{nameLabel: name}},
+	})
+	if err != nil {
+		return "", err
+	}
+	var latest *runtimeapi.Container
+	for _, item := range resp.Containers {
+		if latest == nil || item.CreatedAt > latest.CreatedAt {
+			latest = item
+		}
+	}
+	if latest == nil {
+		return "", fmt.Errorf("container %s: %w", name, ErrNotFound)
+	}
+	return latest.Id, nil
+}
+
+var containerdMountPropagations = map[core.MountPropagationMode]runtimeapi.MountPropagation{
+	core.MountPropagationNone:            runtimeapi.MountPropagation_PROPAGATION_PRIVATE,
+	core.MountPropagationHostToContainer: runtimeapi.MountPropagation_PROPAGATION_HOST_TO_CONTAINER,
+	core.MountPropagationBidirectional:   runtimeapi.MountPropagation_PROPAGATION_BIDIRECTIONAL,
+}
+
+func buildContainerdContainerConfig(cnt core.Container, resources Resources, mounts []Mount) *runtimeapi.ContainerConfig {
+	containerConfig := &runtimeapi.ContainerConfig{
+		Metadata:   &runtimeapi.ContainerMetadata{Name: cnt.Name},
+		Image:      &runtimeapi.ImageSpec{Image: cnt.Image},
+		Command:    cnt.Command,
+		Args:       cnt.Args,
+		WorkingDir: cnt.WorkingDir,
+		Labels:     map[string]string{nameLabel: cnt.Name},
+		LogPath:    cnt.Name + ".log",
+		Stdin:      cnt.Stdin,
+		StdinOnce:  cnt.StdinOnce,
+		Tty:        cnt.TTY,
+		Linux: &runtimeapi.LinuxContainerConfig{Resources: &runtimeapi.LinuxContainerResources{
+			CpuShares:          resources.CPUShares,
+			CpuQuota:           resources.CPUQuota,
+			CpuPeriod:          resources.CPUPeriod,
+			MemoryLimitInBytes: resources.MemoryLimit,
+			OomScoreAdj:        int64(resources.OOMScoreAdj),
+		}},
+	}
+	for _, ev := range cnt.Env {
+		containerConfig.Envs = append(containerConfig.Envs, &runtimeapi.KeyValue{Key: ev.Name, Value: ev.Value})
+	}
+	for _, m := range mounts {
+		containerConfig.Mounts = append(containerConfig.Mounts, &runtimeapi.Mount{
+			ContainerPath: m.ContainerPath,
+			HostPath:      m.HostPath,
+			Readonly:      m.ReadOnly,
+			Propagation:   containerdMountPropagations[m.Propagation],
+		})
+	}
+	return containerConfig
+}
+
+/*---------------------------- Exec, Attach and Logs ----------------------------*/
+
+func (c *containerdClient) ContainerExec(ctx context.Context, name string, cmd []string) (int, []byte, error) {
+	id, err := c.containerID(ctx, name)
+	if err != nil {
+		return 0, nil, err
+	}
+	request := &runtimeapi.ExecSyncRequest{ContainerId: id, Cmd: cmd}
+	if deadline, ok := ctx.Deadline(); ok {
+		// timeout of CRI is in seconds, and zero means no timeout
+		request.Timeout = int64(time.Until(deadline).Seconds()) + 1
+	}
+	resp, err := c.runtime.ExecSync(ctx, request)
+	if err != nil {
+		return 0, nil, err
+	}
+	return int(resp.ExitCode), append(resp.Stdout, resp.Stderr...), nil
+}
+
+func (c *containerdClient) ContainerExecStream(ctx context.Context, name string, cmd []string, streams StreamOptions) (int, error) {
+	id, err := c.containerID(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.runtime.Exec(ctx, &runtimeapi.ExecRequest{
+		ContainerId: id,
+		Cmd:         cmd,
+		Tty:         streams.TTY,
+		Stdin:       streams.Stdin != nil,
+		Stdout:      streams.Stdout != nil,
+		Stderr:      streams.Stderr != nil,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return streamRemoteCommand(ctx, resp.Url, streams)
+}
+
+func (c *containerdClient) ContainerAttach(ctx context.Context, name string, streams StreamOptions) error {
+	id, err := c.containerID(ctx, name)
+	if err != nil {
+		return err
+	}
+	resp, err := c.runtime.Attach(ctx, &runtimeapi.AttachRequest{
+		ContainerId: id,
+		Tty:         streams.TTY,
+		Stdin:       streams.Stdin != nil,
+		Stdout:      streams.Stdout != nil,
+		Stderr:      streams.Stderr != nil,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = streamRemoteCommand(ctx, resp.Url, streams)
+	return err
+}
+
+// ContainerLogs reads log file of container written by containerd
+func (c *containerdClient) ContainerLogs(ctx context.Context, name string, opts LogOptions, stdout, stderr io.Writer) error {
+	id, err := c.containerID(ctx, name)
+	if err != nil {
+		return err
+	}
+	resp, err := c.runtime.ContainerStatus(ctx, &runtimeapi.ContainerStatusRequest{ContainerId: id})
+	if err != nil {
+		return err
+	}
+	running := func() bool {
+		status, err := c.ContainerStatus(ctx, name)
+		return err == nil && status.Running
+	}
+	return readCRILog(ctx, resp.Status.LogPath, opts, stdout, stderr, running)
+}
+
+// PortForward dials port in network namespace of sandbox, whose pid is
+// in verbose info of sandbox status
+func (c *containerdClient) PortForward(ctx context.Context, sandbox string, port int32, in io.Reader, out io.Writer) error {
+	id, err := c.sandboxID(ctx, sandbox)
+	if err != nil {
+		return err
+	}
+	resp, err := c.runtime.PodSandboxStatus(ctx, &runtimeapi.PodSandboxStatusRequest{PodSandboxId: id, Verbose: true})
+	if err != nil {
+		return err
+	}
+	if resp.Status.State != runtimeapi.PodSandboxState_SANDBOX_READY {
+		return fmt.Errorf("sandbox %s is not ready", sandbox)
+	}
+	info := struct {
+		Pid int `json:"pid"`
+	}{}
+	if err = json.Unmarshal([]byte(resp.Info["info"]), &info); err != nil || info.Pid == 0 {
+		return fmt.Errorf("pid of sandbox %s is unknown", sandbox)
+	}
+	return forwardInNetns(ctx, info.Pid, port, in, out)
+}
+
+/*---------------------------- Image ----------------------------*/
+
+func (c *containerdClient) ImageStatus(ctx context.Context, image string) (*Image, error) {
+	resp, err := c.image.ImageStatus(ctx, &runtimeapi.ImageStatusRequest{Image: &runtimeapi.ImageSpec{Image: image}})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Image == nil {
+		return nil, nil
+	}
+	res := convertImage(resp.Image)
+	return &res, nil
+}
+
+// PullImage pulls image through CRI, which reports no progress
+func (c *containerdClient) PullImage(ctx context.Context, image string, auth *AuthConfig, _ func(string)) (string, error) {
+	request := &runtimeapi.PullImageRequest{Image: &runtimeapi.ImageSpec{Image: image}}
+	if auth != nil {
+		request.Auth = &runtimeapi.AuthConfig{
+			Username:      auth.Username,
+			Password:      auth.Password,
+			Auth:          auth.Auth,
+			ServerAddress: auth.ServerAddress,
+			IdentityToken: auth.IdentityToken,
+		}
+	}
+	resp, err := c.image.PullImage(ctx, request)
+	if err != nil {
+		return "", err
+	}
+	return resp.ImageRef, nil
+}
+
+func (c *containerdClient) ListImages(ctx context.Context) ([]Image, error) {
+	resp, err := c.image.ListImages(ctx, &runtimeapi.ListImagesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Image, 0, len(resp.Images))
+	for _, image := range resp.Images {
+		res = append(res, convertImage(image))
+	}
+	return res, nil
+}
+
+func (c *containerdClient) RemoveImage(ctx context.Context, id string) error {
+	_, err := c.image.RemoveImage(ctx, &runtimeapi.RemoveImageRequest{Image: &runtimeapi.ImageSpec{Image: id}})
+	return err
+}
+
+// ImageFsInfo returns usage of filesystem of the first image filesystem
+// reported by containerd, i.e. that of its snapshotter
+func (c *containerdClient) ImageFsInfo(ctx context.Context) (FsInfo, error) {
+	resp, err := c.image.ImageFsInfo(ctx, &runtimeapi.ImageFsInfoRequest{})
+	if err != nil {
+		return FsInfo{}, err
+	}
+	for _, fs := range resp.ImageFilesystems {
+		if fs.GetFsId().GetMountpoint() != "" {
+			return statFs(fs.FsId.Mountpoint)
+		}
+	}
+	return FsInfo{}, fmt.Errorf("containerd reports no image filesystem")
+}
+
+// convertImage converts CRI image, the sandbox image is pinned by containerd
+func convertImage(image *runtimeapi.Image) Image {
+	return Image{
+		ID:       image.Id,
+		RepoTags: image.RepoTags,
+		Size:     int64(image.Size_),
+		Pinned:   image.Pinned,
+	}
+}
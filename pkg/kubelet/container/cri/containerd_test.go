@@ -0,0 +1,22 @@
+package cri
+
+import "testing"
+
+func TestBuildSandboxConfig(t *testing.T) {
+	config := buildSandboxConfig(SandboxConfig{
+		Name:         "uid1-pause",
+		PodName:      "web",
+		PodUID:       "uid1",
+		CgroupParent: "/kubepods/poduid1",
+		OOMScoreAdj:  -998,
+	})
+	if config.Labels[nameLabel] != "uid1-pause" || config.Labels[PodUIDLabel] != "uid1" {
+		t.Errorf("labels of sandbox = %v, want name and pod uid", config.Labels)
+	}
+	if config.Metadata.Namespace != "default" {
+		t.Errorf("namespace of sandbox = %q, want default", config.Metadata.Namespace)
+	}
+	if config.Linux.CgroupParent != "/kubepods/poduid1" || config.Linux.Resources.GetOomScoreAdj() != -998 {
+		t.Errorf("linux config of sandbox = %+v, want cgroup parent and oom_score_adj set", config.Linux)
+	}
+}
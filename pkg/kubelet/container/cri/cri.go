@@ -2,20 +2,230 @@ package cri
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"minik8s/config"
 	"minik8s/pkg/api/core"
+	"minik8s/pkg/kubelet/streaming"
+	"syscall"
+	"time"
 )
 
+// Client is the container runtime kubelet runs pods with. Containers of a pod
+// run in its sandbox, which holds network namespace shared by them. Sandboxes
+// and containers are referred to by names given by kubelet when they are
+// created, ids of runtime are kept inside implementations.
 type Client interface {
-	//ContainerEnsure(ctx context.Context, cnt core.Container, group *sync.WaitGroup)
-	//ContainerCleanEnsure(name string) error
-	// VolumeCreate VolumeRemove FIXME they should be separated to csi, this is ugly
-	//VolumeCreate(name string) error
-	//VolumeRemove(name string) error
-	ContainerCreate(ctx context.Context, cnt core.Container) (string, error)
+	// RunPodSandbox creates and starts sandbox of pod, and returns its id
+	RunPodSandbox(ctx context.Context, sandbox SandboxConfig) (string, error)
+	// StopPodSandbox stops sandbox, containers in it should be stopped before
+	StopPodSandbox(ctx context.Context, name string) error
+	RemovePodSandbox(ctx context.Context, name string) error
+	PodSandboxStatus(ctx context.Context, name string) (SandboxStatus, error)
+	// ListPodSandboxes returns sandboxes created by kubelet, including stopped ones
+	ListPodSandboxes(ctx context.Context) ([]SandboxInfo, error)
+
+	// ContainerCreate creates container in sandbox limited by resources, with
+	// host paths in mounts bind mounted, and returns its id
+	ContainerCreate(ctx context.Context, sandbox string, cnt core.Container, resources Resources, mounts []Mount) (string, error)
 	ContainerRemove(ctx context.Context, name string) error
+	// ContainerStart starts container, or restarts it if it has exited
 	ContainerStart(ctx context.Context, name string) error
-	ContainerStatus(ctx context.Context, id string) (bool, int, error)
-	ContainerIP(ctx context.Context, id string) (string, error)
-	ContainerId(ctx context.Context, id string) string
+	// ContainerStop sends signal to container, and kills it if it is
+	// still running after timeout, the default signal is used if empty
+	ContainerStop(ctx context.Context, name string, signal string, timeout time.Duration) error
+	ContainerStatus(ctx context.Context, name string) (ContainerStatus, error)
+	// ListContainers returns containers created by kubelet in sandboxes, including
+	// exited ones, sandboxes themselves are not included
+	ListContainers(ctx context.Context) ([]ContainerInfo, error)
+	// ContainerExec runs cmd in container and returns its exit code and
+	// combined output, it is canceled when ctx is done
+	ContainerExec(ctx context.Context, name string, cmd []string) (int, []byte, error)
+	// ContainerExecStream runs cmd in container with streams attached, and returns
+	// exit code of cmd after its output ends, it is canceled when ctx is done
+	ContainerExecStream(ctx context.Context, name string, cmd []string, streams StreamOptions) (int, error)
+	// ContainerAttach attaches streams to main process of container, and returns
+	// after its output ends, i.e. the process exits, or ctx is done
+	ContainerAttach(ctx context.Context, name string, streams StreamOptions) error
+	// ContainerLogs writes stdout and stderr logs of container selected by opts
+	// to stdout and stderr, if opts.Follow is set, it returns after container
+	// stops or ctx is done
+	ContainerLogs(ctx context.Context, name string, opts LogOptions, stdout, stderr io.Writer) error
+	// PortForward connects in and out to port in network namespace of sandbox, and
+	// copies data in both directions until port closes connection or ctx is done
+	PortForward(ctx context.Context, sandbox string, port int32, in io.Reader, out io.Writer) error
+
+	// ImageStatus returns image of the name, or nil if it is not present
+	ImageStatus(ctx context.Context, image string) (*Image, error)
+	// PullImage pulls image with auth, which is nil for anonymous pull, and returns
+	// id of the image. Progress of pull is reported to progress if runtime supports
+	PullImage(ctx context.Context, image string, auth *AuthConfig, progress func(string)) (string, error)
+	ListImages(ctx context.Context) ([]Image, error)
+	RemoveImage(ctx context.Context, id string) error
+	// ImageFsInfo returns usage of filesystem images are stored in
+	ImageFsInfo(ctx context.Context) (FsInfo, error)
 	Close()
 }
+
+// PodUIDLabel is label of sandboxes and containers created by kubelet, whose value
+// is uid of their pod, so that they are found again after kubelet restarts
+const PodUIDLabel = "io.minik8s.pod.uid"
+
+// ErrNotFound is returned if sandbox or container of the name does not exist
+var ErrNotFound = errors.New("not found in container runtime")
+
+// New connects to container runtime, endpoint can be empty to use the
+// default one, and docker is used if runtime is empty
+func New(runtime string, endpoint string) (Client, error) {
+	switch runtime {
+	case "", config.DockerRuntime:
+		return NewDocker()
+	case config.ContainerdRuntime:
+		if endpoint == "" {
+			endpoint = config.DefaultContainerdEndpoint
+		}
+		return NewContainerd(endpoint)
+	default:
+		return nil, fmt.Errorf("unknown container runtime %q", runtime)
+	}
+}
+
+// SandboxConfig is config of pod sandbox
+type SandboxConfig struct {
+	// Name identifies sandbox in runtime
+	Name string
+	// PodName, PodNamespace and PodUID are metadata of pod
+	PodName      string
+	PodNamespace string
+	PodUID       string
+	// DNSServers are nameservers of pod
+	DNSServers []string
+	// CgroupParent is cgroup of pod created by kubelet, which sandbox and
+	// containers in it are created under, e.g. /kubepods/burstable/pod<uid>
+	CgroupParent string
+	// OOMScoreAdj is oom_score_adj of processes of sandbox
+	OOMScoreAdj int
+}
+
+// Resources are cgroup settings of container computed by kubelet, zero
+// values are unlimited
+type Resources struct {
+	// CPUShares is relative weight of cpu time of container
+	CPUShares int64
+	// CPUQuota is cpu time in microseconds container can use in each CPUPeriod
+	CPUQuota  int64
+	CPUPeriod int64
+	// MemoryLimit is limit of memory in bytes
+	MemoryLimit int64
+	// OOMScoreAdj is oom_score_adj of processes of container
+	OOMScoreAdj int
+}
+
+// SandboxStatus is status of pod sandbox
+type SandboxStatus struct {
+	ID    string
+	Ready bool
+	// IP is ip of pod, shared by all containers in sandbox
+	IP string
+}
+
+// SandboxInfo is a sandbox listed from runtime
+type SandboxInfo struct {
+	Name   string
+	PodUID string
+	Ready  bool
+}
+
+// ContainerInfo is a container listed from runtime
+type ContainerInfo struct {
+	Name string
+	// Sandbox is name of sandbox container is in
+	Sandbox string
+	PodUID  string
+	Running bool
+}
+
+// ContainerStatus is status of container, ExitCode is valid only if
+// container is not running
+type ContainerStatus struct {
+	ID       string
+	Running  bool
+	ExitCode int
+	// StartedAt is time container last started, FinishedAt is time it last
+	// exited, both are zero if unknown
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// Reason is why container exited given by runtime, e.g. OOMKilled
+	Reason string
+}
+
+// Mount is a host path mounted into container
+type Mount struct {
+	HostPath      string
+	ContainerPath string
+	ReadOnly      bool
+	Propagation   core.MountPropagationMode
+}
+
+// LogOptions selects logs of container
+type LogOptions struct {
+	// Follow streams new logs until container stops
+	Follow bool
+	// TailLines is the number of lines from the end of logs, all lines if negative
+	TailLines int64
+	// Since and Until limit time range of logs, unlimited if zero
+	Since time.Time
+	Until time.Time
+	// Timestamps prefixes each line with its timestamp
+	Timestamps bool
+}
+
+// StreamOptions are streams of exec and attach, nil streams are not attached
+type StreamOptions struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// TTY allocates a terminal, whose output is all written to Stdout
+	TTY bool
+	// Resize receives size of terminal if TTY is set
+	Resize <-chan streaming.TerminalSize
+}
+
+// Image is an image in container runtime
+type Image struct {
+	ID       string
+	RepoTags []string
+	// Size is the size of image in bytes
+	Size int64
+	// Pinned images are never removed by image garbage collection
+	Pinned bool
+}
+
+// AuthConfig is credential to pull image from registry
+type AuthConfig struct {
+	Username string
+	Password string
+	// Auth is base64 encoded "username:password", used if Username is empty
+	Auth          string
+	ServerAddress string
+	IdentityToken string
+}
+
+// FsInfo is usage of a filesystem in bytes
+type FsInfo struct {
+	Capacity  uint64
+	Available uint64
+}
+
+// statFs returns usage of filesystem path is on
+func statFs(path string) (FsInfo, error) {
+	stat := syscall.Statfs_t{}
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return FsInfo{}, err
+	}
+	return FsInfo{
+		Capacity:  stat.Blocks * uint64(stat.Bsize),
+		Available: stat.Bavail * uint64(stat.Bsize),
+	}, nil
+}
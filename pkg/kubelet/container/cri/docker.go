@@ -5,14 +5,19 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"minik8s/config"
 	"minik8s/pkg/api/core"
-	"minik8s/pkg/api/types"
+	"minik8s/pkg/kubelet/constants"
+	"minik8s/pkg/kubelet/streaming"
+	"strconv"
+	"strings"
+	"time"
 
 	dt "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 )
 
 func NewDocker() (Client, error) {
@@ -33,103 +38,426 @@ type dockerClient struct {
 }
 
 func (c *dockerClient) ContainerStart(ctx context.Context, name string) error {
-	return c.Client.ContainerStart(ctx, c.ContainerId(ctx, name), dt.ContainerStartOptions{})
+	id, err := c.containerID(ctx, name)
+	if err != nil {
+		return err
+	}
+	return c.Client.ContainerStart(ctx, id, dt.ContainerStartOptions{})
 }
 
-func (c *dockerClient) ContainerStatus(ctx context.Context, id string) (bool, int, error) {
-	resp, err := c.Client.ContainerInspect(ctx, id)
+func (c *dockerClient) ContainerStop(ctx context.Context, name string, signal string, timeout time.Duration) error {
+	id, err := c.containerID(ctx, name)
 	if err != nil {
-		return false, 0, err
+		return err
 	}
-	return resp.State.Running, resp.State.ExitCode, nil
+	seconds := int(timeout.Seconds())
+	return c.Client.ContainerStop(ctx, id, container.StopOptions{
+		Signal:  signal,
+		Timeout: &seconds,
+	})
 }
-func (c *dockerClient) ContainerIP(ctx context.Context, id string) (string, error) {
-	resp, err := c.Client.ContainerInspect(ctx, id)
+
+func (c *dockerClient) ContainerExec(ctx context.Context, name string, cmd []string) (int, []byte, error) {
+	id, err := c.containerID(ctx, name)
 	if err != nil {
-		return "", err
+		return 0, nil, err
+	}
+	exec, err := c.Client.ContainerExecCreate(ctx, id, dt.ExecConfig{
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          cmd,
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	resp, err := c.Client.ContainerExecAttach(ctx, exec.ID, dt.ExecStartCheck{})
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Close()
+
+	// output is multiplexed stdout and stderr, read until exec exits
+	done := make(chan error, 1)
+	var output []byte
+	go func() {
+		var err error
+		output, err = io.ReadAll(resp.Reader)
+		done <- err
+	}()
+	select {
+	case <-ctx.Done():
+		return 0, nil, ctx.Err()
+	case err = <-done:
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
+	inspect, err := c.Client.ContainerExecInspect(ctx, exec.ID)
+	if err != nil {
+		return 0, nil, err
 	}
-	return resp.NetworkSettings.IPAddress, nil
+	return inspect.ExitCode, output, nil
 }
 
-func soundClose(cli *client.Client) {
-	err := cli.Close()
+func (c *dockerClient) ContainerExecStream(ctx context.Context, name string, cmd []string, streams StreamOptions) (int, error) {
+	id, err := c.containerID(ctx, name)
 	if err != nil {
-		log.Println(err.Error())
+		return 0, err
+	}
+	exec, err := c.Client.ContainerExecCreate(ctx, id, dt.ExecConfig{
+		Tty:          streams.TTY,
+		AttachStdin:  streams.Stdin != nil,
+		AttachStdout: streams.Stdout != nil,
+		AttachStderr: streams.Stderr != nil,
+		Cmd:          cmd,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.Client.ContainerExecAttach(ctx, exec.ID, dt.ExecStartCheck{Tty: streams.TTY})
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Close()
+
+	if streams.TTY {
+		go handleResize(ctx, streams.Resize, func(size dt.ResizeOptions) error {
+			return c.Client.ContainerExecResize(ctx, exec.ID, size)
+		})
+	}
+	if err = pipeStreams(ctx, resp, streams); err != nil {
+		return 0, err
+	}
+
+	inspect, err := c.Client.ContainerExecInspect(ctx, exec.ID)
+	if err != nil {
+		return 0, err
 	}
+	return inspect.ExitCode, nil
 }
 
-func (c *dockerClient) ContainerCreate(ctx context.Context, cnt core.Container) (string, error) {
-	if len(cnt.Master) == 0 {
-		return c.containerMasterCreate(ctx, cnt)
-	} else {
-		return c.containerSlaverCreate(ctx, cnt)
+func (c *dockerClient) ContainerAttach(ctx context.Context, name string, streams StreamOptions) error {
+	id, err := c.containerID(ctx, name)
+	if err != nil {
+		return err
 	}
+	resp, err := c.Client.ContainerAttach(ctx, id, dt.ContainerAttachOptions{
+		Stream: true,
+		Stdin:  streams.Stdin != nil,
+		Stdout: streams.Stdout != nil,
+		Stderr: streams.Stderr != nil,
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	if streams.TTY {
+		go handleResize(ctx, streams.Resize, func(size dt.ResizeOptions) error {
+			return c.Client.ContainerResize(ctx, id, size)
+		})
+	}
+	return pipeStreams(ctx, resp, streams)
 }
 
-func (c *dockerClient) ContainerRemove(ctx context.Context, name string) error {
-	return c.Client.ContainerRemove(ctx, c.ContainerId(ctx, name), dt.ContainerRemoveOptions{
-		RemoveVolumes: false,
-		RemoveLinks:   false,
-		Force:         true,
+// pipeStreams copies stdin to hijacked connection and its output to stdout
+// and stderr, until output ends or ctx is done
+func pipeStreams(ctx context.Context, resp dt.HijackedResponse, streams StreamOptions) error {
+	if streams.Stdin != nil {
+		go func() {
+			_, _ = io.Copy(resp.Conn, streams.Stdin)
+			_ = resp.CloseWrite()
+		}()
+	}
+
+	stdout, stderr := streams.Stdout, streams.Stderr
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+	done := make(chan error, 1)
+	go func() {
+		var err error
+		// output of tty is raw, otherwise multiplexed stdout and stderr
+		if streams.TTY {
+			_, err = io.Copy(stdout, resp.Reader)
+		} else {
+			_, err = stdcopy.StdCopy(stdout, stderr, resp.Reader)
+		}
+		done <- err
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		return err
+	}
+}
+
+// handleResize resizes terminal until ctx is done
+func handleResize(ctx context.Context, resize <-chan streaming.TerminalSize, resizeFunc func(dt.ResizeOptions) error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case size := <-resize:
+			if size.Width == 0 || size.Height == 0 {
+				continue
+			}
+			if err := resizeFunc(dt.ResizeOptions{Width: uint(size.Width), Height: uint(size.Height)}); err != nil {
+				log.Printf("resize terminal to %vx%v failed: %v\n", size.Width, size.Height, err)
+			}
+		}
+	}
+}
+
+func (c *dockerClient) ContainerLogs(ctx context.Context, name string, opts LogOptions, stdout, stderr io.Writer) error {
+	id, err := c.containerID(ctx, name)
+	if err != nil {
+		return err
+	}
+	inspect, err := c.Client.ContainerInspect(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	tail := "all"
+	if opts.TailLines >= 0 {
+		tail = strconv.FormatInt(opts.TailLines, 10)
+	}
+	reader, err := c.Client.ContainerLogs(ctx, id, dt.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Since:      formatLogTime(opts.Since),
+		Until:      formatLogTime(opts.Until),
+		Timestamps: opts.Timestamps,
+		Follow:     opts.Follow,
+		Tail:       tail,
 	})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// logs of container with tty are raw, otherwise multiplexed stdout and stderr
+	if inspect.Config != nil && inspect.Config.Tty {
+		_, err = io.Copy(stdout, reader)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, reader)
+	}
+	return err
+}
+
+// formatLogTime formats t as unix timestamp accepted by docker, empty if zero
+func formatLogTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%d.%09d", t.Unix(), t.Nanosecond())
+}
 
+func (c *dockerClient) ContainerStatus(ctx context.Context, name string) (ContainerStatus, error) {
+	id, err := c.containerID(ctx, name)
+	if err != nil {
+		return ContainerStatus{}, err
+	}
+	resp, err := c.Client.ContainerInspect(ctx, id)
+	if err != nil {
+		return ContainerStatus{}, err
+	}
+	status := ContainerStatus{
+		ID:         id,
+		Running:    resp.State.Running,
+		ExitCode:   resp.State.ExitCode,
+		StartedAt:  parseDockerTime(resp.State.StartedAt),
+		FinishedAt: parseDockerTime(resp.State.FinishedAt),
+	}
+	if resp.State.OOMKilled {
+		status.Reason = "OOMKilled"
+	}
+	return status, nil
 }
 
-func (c *dockerClient) containerMasterCreate(ctx context.Context, cnt core.Container) (string, error) {
-	if cnt.Master != "" {
-		return "", fmt.Errorf("HasMaster")
+// parseDockerTime parses time in container state of docker, which
+// is 0001-01-01T00:00:00Z if container is not started or exited
+func parseDockerTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil || t.Year() <= 1 {
+		return time.Time{}
+	}
+	return t
+}
+
+func soundClose(cli *client.Client) {
+	err := cli.Close()
+	if err != nil {
+		log.Println(err.Error())
 	}
-	if err := c.handleImagePull(ctx, cnt); err != nil {
+}
+
+// RunPodSandbox runs pause container of pod, whose namespaces are joined by
+// containers of pod
+func (c *dockerClient) RunPodSandbox(ctx context.Context, sandbox SandboxConfig) (string, error) {
+	if err := c.ensurePauseImage(ctx); err != nil {
 		return "", err
 	}
-	resp, err := c.Client.ContainerCreate(ctx, buildMasterContainerConfig(cnt), buildMasterHostConfig(cnt), nil, nil, cnt.Name)
+	resp, err := c.Client.ContainerCreate(ctx, buildSandboxContainerConfig(constants.InitialPauseContainer, sandbox), buildSandboxHostConfig(sandbox), nil, nil, sandbox.Name)
 	if err != nil {
 		return "", err
 	}
+	if err = c.Client.ContainerStart(ctx, resp.ID, dt.ContainerStartOptions{}); err != nil {
+		return "", err
+	}
 	return resp.ID, nil
 }
 
-func (c *dockerClient) containerSlaverCreate(ctx context.Context, cnt core.Container) (string, error) {
-	if cnt.Master == "" {
-		return "", fmt.Errorf("NoMaster")
+func (c *dockerClient) StopPodSandbox(ctx context.Context, name string) error {
+	return c.ContainerStop(ctx, name, "", 0)
+}
+
+func (c *dockerClient) RemovePodSandbox(ctx context.Context, name string) error {
+	return c.ContainerRemove(ctx, name)
+}
+
+func (c *dockerClient) PodSandboxStatus(ctx context.Context, name string) (SandboxStatus, error) {
+	id, err := c.containerID(ctx, name)
+	if err != nil {
+		return SandboxStatus{}, err
+	}
+	resp, err := c.Client.ContainerInspect(ctx, id)
+	if err != nil {
+		return SandboxStatus{}, err
 	}
-	if err := c.handleImagePull(ctx, cnt); err != nil {
+	return SandboxStatus{ID: id, Ready: resp.State.Running, IP: resp.NetworkSettings.IPAddress}, nil
+}
+
+// ContainerCreate creates container sharing network namespace of pause
+// container of sandbox, under the same cgroup parent
+func (c *dockerClient) ContainerCreate(ctx context.Context, sandbox string, cnt core.Container, resources Resources, mounts []Mount) (string, error) {
+	sandboxID, err := c.containerID(ctx, sandbox)
+	if err != nil {
 		return "", err
 	}
-	resp, err := c.Client.ContainerCreate(ctx, buildSlaverContainerConfig(cnt.Master, cnt), buildSlaverHostConfig(cnt.Master, cnt), nil, nil, cnt.Name)
+	inspect, err := c.Client.ContainerInspect(ctx, sandboxID)
+	if err != nil {
+		return "", err
+	}
+	labels := map[string]string{
+		PodUIDLabel:  inspect.Config.Labels[PodUIDLabel],
+		sandboxLabel: sandbox,
+	}
+	resp, err := c.Client.ContainerCreate(ctx, buildContainerConfig(cnt, labels), buildContainerHostConfig(inspect, resources, mounts), nil, nil, cnt.Name)
 	if err != nil {
 		return "", err
 	}
 	return resp.ID, nil
 }
 
-func (c *dockerClient) ContainerId(ctx context.Context, name string) string {
+func (c *dockerClient) ContainerRemove(ctx context.Context, name string) error {
+	id, err := c.containerID(ctx, name)
+	if err != nil {
+		return err
+	}
+	return c.Client.ContainerRemove(ctx, id, dt.ContainerRemoveOptions{
+		RemoveVolumes: false,
+		RemoveLinks:   false,
+		Force:         true,
+	})
+}
+
+// sandboxLabel is label of containers in sandboxes, whose value is name of
+// sandbox, pause containers of sandboxes are those without it
+const sandboxLabel = "io.minik8s.sandbox"
+
+// listPodContainers returns docker containers of pods created by kubelet, names
+// of which are without the leading slash
+func (c *dockerClient) listPodContainers(ctx context.Context) ([]dt.Container, error) {
+	list, err := c.Client.ContainerList(ctx, dt.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", PodUIDLabel)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if len(list[i].Names) > 0 {
+			list[i].Names[0] = strings.TrimPrefix(list[i].Names[0], "/")
+		}
+	}
+	return list, nil
+}
+
+func (c *dockerClient) ListPodSandboxes(ctx context.Context) ([]SandboxInfo, error) {
+	list, err := c.listPodContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var sandboxes []SandboxInfo
+	for _, item := range list {
+		if _, found := item.Labels[sandboxLabel]; found || len(item.Names) == 0 {
+			continue
+		}
+		sandboxes = append(sandboxes, SandboxInfo{
+			Name:   item.Names[0],
+			PodUID: item.Labels[PodUIDLabel],
+			Ready:  item.State == "running",
+		})
+	}
+	return sandboxes, nil
+}
+
+func (c *dockerClient) ListContainers(ctx context.Context) ([]ContainerInfo, error) {
+	list, err := c.listPodContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var containers []ContainerInfo
+	for _, item := range list {
+		sandbox, found := item.Labels[sandboxLabel]
+		if !found || len(item.Names) == 0 {
+			continue
+		}
+		containers = append(containers, ContainerInfo{
+			Name:    item.Names[0],
+			Sandbox: sandbox,
+			PodUID:  item.Labels[PodUIDLabel],
+			Running: item.State == "running",
+		})
+	}
+	return containers, nil
+}
+
+// containerID returns id of container named name
+func (c *dockerClient) containerID(ctx context.Context, name string) (string, error) {
 	list, err := c.Client.ContainerList(ctx, dt.ContainerListOptions{All: true})
 	if err != nil {
-		return ""
+		return "", err
 	}
 
 	for _, c := range list {
 		for _, n := range c.Names {
 			if n == "/"+name {
-				return c.ID
+				return c.ID, nil
 			}
 		}
 	}
-	return ""
+	return "", fmt.Errorf("container %s: %w", name, ErrNotFound)
 }
 
-func buildMasterContainerConfig(cnt core.Container) *container.Config {
+func buildSandboxContainerConfig(pause core.Container, sandbox SandboxConfig) *container.Config {
 	return &container.Config{
-		Tty:        cnt.TTY,
-		StdinOnce:  cnt.StdinOnce,
-		Cmd:        append(cnt.Command, cnt.Args...),
-		Image:      cnt.Image,
-		WorkingDir: cnt.WorkingDir,
+		Image:  pause.Image,
+		Labels: map[string]string{PodUIDLabel: sandbox.PodUID},
 	}
 }
 
-func buildSlaverContainerConfig(master string, cnt core.Container) *container.Config {
+func buildContainerConfig(cnt core.Container, labels map[string]string) *container.Config {
 	return &container.Config{
 		Hostname:        "",
 		Domainname:      "",
@@ -139,7 +467,7 @@ func buildSlaverContainerConfig(master string, cnt core.Container) *container.Co
 		AttachStderr:    false,
 		ExposedPorts:    nil,
 		Tty:             cnt.TTY,
-		OpenStdin:       false,
+		OpenStdin:       cnt.Stdin,
 		StdinOnce:       cnt.StdinOnce,
 		Env:             buildEnv(cnt),
 		Cmd:             append(cnt.Command, cnt.Args...),
@@ -152,7 +480,7 @@ func buildSlaverContainerConfig(master string, cnt core.Container) *container.Co
 		NetworkDisabled: false,
 		MacAddress:      "",
 		OnBuild:         nil,
-		Labels:          nil,
+		Labels:          labels,
 		StopSignal:      "",
 		StopTimeout:     nil,
 		Shell:           nil,
@@ -167,39 +495,27 @@ func buildEnv(cnt core.Container) []string {
 	return ret
 }
 
-func buildMasterHostConfig(cnt core.Container) *container.HostConfig {
+func buildSandboxHostConfig(sandbox SandboxConfig) *container.HostConfig {
 	return &container.HostConfig{
-		Mounts: buildMount(cnt),
-		DNS:    []string{config.Host()},
+		DNS:         sandbox.DNSServers,
+		OomScoreAdj: sandbox.OOMScoreAdj,
+		Resources:   container.Resources{CgroupParent: sandbox.CgroupParent},
 	}
 }
 
-func buildSlaverHostConfig(master string, cnt core.Container) *container.HostConfig {
-	res := container.Resources{}
-
-	for k, r := range cnt.Resources.Limits {
-		switch k {
-		case types.ResourceCPU:
-			q, err := types.ParseQuantity(k, r)
-			if err != nil {
-				log.Fatalf("cpu quota not recognized %v", r)
-			}
-			res.NanoCPUs = int64(1_000_000_000 * q / 1024)
-			break
-		case types.ResourceMemory:
-			q, err := types.ParseQuantity(k, r)
-			if err != nil {
-				log.Fatalf("memory quota not recognized %v", r)
-			}
-			res.Memory = int64(q * 1024 * 1204)
-			break
-		}
+func buildContainerHostConfig(sandbox dt.ContainerJSON, resources Resources, mounts []Mount) *container.HostConfig {
+	res := container.Resources{
+		CgroupParent: sandbox.HostConfig.CgroupParent,
+		CPUShares:    resources.CPUShares,
+		CPUQuota:     resources.CPUQuota,
+		CPUPeriod:    resources.CPUPeriod,
+		Memory:       resources.MemoryLimit,
 	}
 	return &container.HostConfig{
 		Binds:           nil,
 		ContainerIDFile: "",
 		LogConfig:       container.LogConfig{},
-		NetworkMode:     container.NetworkMode("container:" + master),
+		NetworkMode:     container.NetworkMode("container:" + sandbox.ID),
 		PortBindings:    nil,
 		RestartPolicy:   container.RestartPolicy{},
 		AutoRemove:      false,
@@ -214,9 +530,9 @@ func buildSlaverHostConfig(master string, cnt core.Container) *container.HostCon
 		ExtraHosts:      nil,
 		GroupAdd:        nil,
 		IpcMode:         "",
-		Cgroup:          container.CgroupSpec("container:" + master),
+		Cgroup:          container.CgroupSpec("container:" + sandbox.ID),
 		Links:           nil,
-		OomScoreAdj:     0,
+		OomScoreAdj:     resources.OOMScoreAdj,
 		PidMode:         "",
 		Privileged:      false,
 		PublishAllPorts: false,
@@ -232,53 +548,32 @@ func buildSlaverHostConfig(master string, cnt core.Container) *container.HostCon
 		ConsoleSize:     [2]uint{},
 		Isolation:       "",
 		Resources:       res,
-		Mounts:          buildMount(cnt),
+		Mounts:          buildMounts(mounts),
 		MaskedPaths:     nil,
 		ReadonlyPaths:   nil,
 		Init:            nil,
 	}
 }
 
-func buildMount(cnt core.Container) []mount.Mount {
+var mountPropagations = map[core.MountPropagationMode]mount.Propagation{
+	core.MountPropagationNone:            mount.PropagationRPrivate,
+	core.MountPropagationHostToContainer: mount.PropagationRSlave,
+	core.MountPropagationBidirectional:   mount.PropagationRShared,
+}
+
+func buildMounts(mounts []Mount) []mount.Mount {
 	mnt := make([]mount.Mount, 0)
-	for _, m := range cnt.VolumeMounts {
+	for _, m := range mounts {
 		mnt = append(mnt, mount.Mount{
-			Type:   mount.TypeVolume,
-			Source: m.Name,
-			Target: m.MountPath,
+			Type:     mount.TypeBind,
+			Source:   m.HostPath,
+			Target:   m.ContainerPath,
+			ReadOnly: m.ReadOnly,
+			BindOptions: &mount.BindOptions{
+				Propagation: mountPropagations[m.Propagation],
+			},
 		})
 	}
 
 	return mnt
 }
-
-func (c *dockerClient) handleImagePull(ctx context.Context, cnt core.Container) error {
-	switch cnt.ImagePullPolicy {
-	default:
-		fallthrough
-	case core.PullIfNotPresent:
-		//FIXME: check if present
-		images, err := c.Client.ImageList(ctx, dt.ImageListOptions{})
-		if err != nil {
-			return err
-		}
-		for _, i := range images {
-			for _, t := range i.RepoTags {
-				if t == cnt.Image {
-					return nil
-				}
-
-			}
-		}
-		fallthrough
-	case core.PullAlways:
-		out, err := c.Client.ImagePull(ctx, cnt.Image, dt.ImagePullOptions{})
-		if err != nil {
-			return err
-		}
-		_, err = io.Copy(io.Discard, out)
-		return err
-	case core.PullNever:
-		return nil
-	}
-}
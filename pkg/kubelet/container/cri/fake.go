@@ -0,0 +1,461 @@
+package cri
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"minik8s/pkg/api/core"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is an in-memory container runtime for tests, started containers keep
+// running until they are stopped, or exited by Exit
+type Fake struct {
+	lock       sync.Mutex
+	nextID     int
+	sandboxes  map[string]*FakeSandbox
+	containers map[string]*FakeContainer
+
+	// ExecFunc runs commands of ContainerExec and ContainerExecStream,
+	// they exit with 0 and no output if it is nil
+	ExecFunc func(name string, cmd []string) (int, []byte, error)
+	// sandboxErrors and createErrors fail RunPodSandbox and ContainerCreate,
+	// keyed by name of sandbox and container
+	sandboxErrors map[string]error
+	createErrors  map[string]error
+
+	images     map[string]*Image
+	pullErrors map[string]error
+	pulls      []FakePull
+	// Capacity is capacity of image filesystem, whose usage is sum of
+	// sizes of images
+	Capacity uint64
+}
+
+// FakePull is a pull of image in Fake
+type FakePull struct {
+	Image string
+	Auth  *AuthConfig
+}
+
+// FakeSandbox is a sandbox in Fake
+type FakeSandbox struct {
+	ID     string
+	Config SandboxConfig
+	IP     string
+	Ready  bool
+}
+
+// FakeContainer is a container in Fake
+type FakeContainer struct {
+	ID        string
+	Sandbox   string
+	Spec      core.Container
+	Resources Resources
+	Mounts    []Mount
+	Running   bool
+	ExitCode  int
+	// StartCount is the number of times container is started
+	StartCount int
+	// StopSignal is signal of the last stop
+	StopSignal string
+	// StartedAt and FinishedAt are time of the last start and exit
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+func NewFake() *Fake {
+	return &Fake{
+		sandboxes:     make(map[string]*FakeSandbox),
+		containers:    make(map[string]*FakeContainer),
+		sandboxErrors: make(map[string]error),
+		createErrors:  make(map[string]error),
+		images:        make(map[string]*Image),
+		pullErrors:    make(map[string]error),
+		Capacity:      1 << 40,
+	}
+}
+
+func (f *Fake) newID() string {
+	f.nextID++
+	return fmt.Sprintf("fake-%d", f.nextID)
+}
+
+// Sandbox returns copy of sandbox named name
+func (f *Fake) Sandbox(name string) (FakeSandbox, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	sandbox, found := f.sandboxes[name]
+	if !found {
+		return FakeSandbox{}, false
+	}
+	return *sandbox, true
+}
+
+// Container returns copy of container named name
+func (f *Fake) Container(name string) (FakeContainer, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	container, found := f.containers[name]
+	if !found {
+		return FakeContainer{}, false
+	}
+	return *container, true
+}
+
+// ContainerNames returns sorted names of containers in all sandboxes
+func (f *Fake) ContainerNames() []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	names := make([]string, 0, len(f.containers))
+	for name := range f.containers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Exit makes running container exit with code
+func (f *Fake) Exit(name string, code int) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	container, found := f.containers[name]
+	if !found {
+		return fmt.Errorf("container %s: %w", name, ErrNotFound)
+	}
+	if !container.Running {
+		return fmt.Errorf("container %s is not running", name)
+	}
+	container.Running = false
+	container.ExitCode = code
+	container.FinishedAt = time.Now()
+	return nil
+}
+
+func (f *Fake) RunPodSandbox(_ context.Context, sandbox SandboxConfig) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if _, found := f.sandboxes[sandbox.Name]; found {
+		return "", fmt.Errorf("sandbox %s already exists", sandbox.Name)
+	}
+	if err := f.sandboxErrors[sandbox.Name]; err != nil {
+		return "", err
+	}
+	id := f.newID()
+	f.sandboxes[sandbox.Name] = &FakeSandbox{
+		ID:     id,
+		Config: sandbox,
+		IP:     fmt.Sprintf("10.0.0.%d", f.nextID),
+		Ready:  true,
+	}
+	return id, nil
+}
+
+func (f *Fake) StopPodSandbox(_ context.Context, name string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	sandbox, found := f.sandboxes[name]
+	if !found {
+		return fmt.Errorf("sandbox %s: %w", name, ErrNotFound)
+	}
+	sandbox.Ready = false
+	return nil
+}
+
+// RemovePodSandbox removes sandbox with containers in it
+func (f *Fake) RemovePodSandbox(_ context.Context, name string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if _, found := f.sandboxes[name]; !found {
+		return fmt.Errorf("sandbox %s: %w", name, ErrNotFound)
+	}
+	delete(f.sandboxes, name)
+	for containerName, container := range f.containers {
+		if container.Sandbox == name {
+			delete(f.containers, containerName)
+		}
+	}
+	return nil
+}
+
+func (f *Fake) PodSandboxStatus(_ context.Context, name string) (SandboxStatus, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	sandbox, found := f.sandboxes[name]
+	if !found {
+		return SandboxStatus{}, fmt.Errorf("sandbox %s: %w", name, ErrNotFound)
+	}
+	return SandboxStatus{ID: sandbox.ID, Ready: sandbox.Ready, IP: sandbox.IP}, nil
+}
+
+func (f *Fake) ListPodSandboxes(context.Context) ([]SandboxInfo, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	var sandboxes []SandboxInfo
+	for name, sandbox := range f.sandboxes {
+		sandboxes = append(sandboxes, SandboxInfo{Name: name, PodUID: sandbox.Config.PodUID, Ready: sandbox.Ready})
+	}
+	return sandboxes, nil
+}
+
+func (f *Fake) ContainerCreate(_ context.Context, sandbox string, cnt core.Container, resources Resources, mounts []Mount) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if _, found := f.sandboxes[sandbox]; !found {
+		return "", fmt.Errorf("sandbox %s: %w", sandbox, ErrNotFound)
+	}
+	if _, found := f.containers[cnt.Name]; found {
+		return "", fmt.Errorf("container %s already exists", cnt.Name)
+	}
+	if err := f.createErrors[cnt.Name]; err != nil {
+		return "", err
+	}
+	id := f.newID()
+	f.containers[cnt.Name] = &FakeContainer{ID: id, Sandbox: sandbox, Spec: cnt, Resources: resources, Mounts: mounts}
+	return id, nil
+}
+
+func (f *Fake) ContainerRemove(_ context.Context, name string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if _, found := f.containers[name]; !found {
+		return fmt.Errorf("container %s: %w", name, ErrNotFound)
+	}
+	delete(f.containers, name)
+	return nil
+}
+
+func (f *Fake) ContainerStart(_ context.Context, name string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	container, found := f.containers[name]
+	if !found {
+		return fmt.Errorf("container %s: %w", name, ErrNotFound)
+	}
+	container.Running = true
+	container.ExitCode = 0
+	container.StartCount++
+	container.StartedAt = time.Now()
+	return nil
+}
+
+// ContainerStop stops container at once, as if it exits on signal
+func (f *Fake) ContainerStop(_ context.Context, name string, signal string, _ time.Duration) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	container, found := f.containers[name]
+	if !found {
+		return fmt.Errorf("container %s: %w", name, ErrNotFound)
+	}
+	if container.Running {
+		container.Running = false
+		container.ExitCode = 137
+		container.FinishedAt = time.Now()
+	}
+	container.StopSignal = signal
+	return nil
+}
+
+func (f *Fake) ContainerStatus(_ context.Context, name string) (ContainerStatus, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	container, found := f.containers[name]
+	if !found {
+		return ContainerStatus{}, fmt.Errorf("container %s: %w", name, ErrNotFound)
+	}
+	return ContainerStatus{
+		ID:         container.ID,
+		Running:    container.Running,
+		ExitCode:   container.ExitCode,
+		StartedAt:  container.StartedAt,
+		FinishedAt: container.FinishedAt,
+	}, nil
+}
+
+func (f *Fake) ListContainers(context.Context) ([]ContainerInfo, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	var containers []ContainerInfo
+	for name, container := range f.containers {
+		containers = append(containers, ContainerInfo{
+			Name:    name,
+			Sandbox: container.Sandbox,
+			PodUID:  f.sandboxes[container.Sandbox].Config.PodUID,
+			Running: container.Running,
+		})
+	}
+	return containers, nil
+}
+
+func (f *Fake) ContainerExec(_ context.Context, name string, cmd []string) (int, []byte, error) {
+	if container, found := f.Container(name); !found || !container.Running {
+		return 0, nil, fmt.Errorf("container %s is not running", name)
+	}
+	if f.ExecFunc == nil {
+		return 0, nil, nil
+	}
+	return f.ExecFunc(name, cmd)
+}
+
+func (f *Fake) ContainerExecStream(ctx context.Context, name string, cmd []string, streams StreamOptions) (int, error) {
+	code, output, err := f.ContainerExec(ctx, name, cmd)
+	if err != nil {
+		return 0, err
+	}
+	if streams.Stdout != nil {
+		_, _ = streams.Stdout.Write(output)
+	}
+	return code, nil
+}
+
+// ContainerAttach returns after container stops, the main process of
+// fake container has no output
+func (f *Fake) ContainerAttach(ctx context.Context, name string, _ StreamOptions) error {
+	for {
+		container, found := f.Container(name)
+		if !found {
+			return fmt.Errorf("container %s: %w", name, ErrNotFound)
+		}
+		if !container.Running {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// ContainerLogs writes nothing, fake containers have no output
+func (f *Fake) ContainerLogs(_ context.Context, name string, _ LogOptions, _, _ io.Writer) error {
+	if _, found := f.Container(name); !found {
+		return fmt.Errorf("container %s: %w", name, ErrNotFound)
+	}
+	return nil
+}
+
+func (f *Fake) PortForward(context.Context, string, int32, io.Reader, io.Writer) error {
+	return errors.New("port forward is not supported by fake runtime")
+}
+
+// AddImage adds image named name to Fake as if it is pulled
+func (f *Fake) AddImage(name string, size int64) string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.addImage(name, size)
+}
+
+func (f *Fake) addImage(name string, size int64) string {
+	if image, found := f.images[name]; found {
+		return image.ID
+	}
+	id := f.newID()
+	f.images[name] = &Image{ID: id, RepoTags: []string{name}, Size: size}
+	return id
+}
+
+// SetSandboxError makes creation of sandbox fail with err, or succeed if err is nil
+func (f *Fake) SetSandboxError(name string, err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.sandboxErrors[name] = err
+}
+
+// SetCreateError makes creation of container fail with err, or succeed if err is nil
+func (f *Fake) SetCreateError(name string, err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.createErrors[name] = err
+}
+
+// SetPullError makes pulls of image fail with err, or succeed if err is nil
+func (f *Fake) SetPullError(image string, err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.pullErrors[image] = err
+}
+
+// Pulls returns images pulled and their auth, in order of pulls
+func (f *Fake) Pulls() []FakePull {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return append([]FakePull(nil), f.pulls...)
+}
+
+// Images returns sorted names of images
+func (f *Fake) Images() []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	names := make([]string, 0, len(f.images))
+	for name := range f.images {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (f *Fake) ImageStatus(_ context.Context, image string) (*Image, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if res, found := f.images[image]; found {
+		copied := *res
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+// PullImage fails with error set by SetPullError if any, and adds image of size 0
+func (f *Fake) PullImage(_ context.Context, image string, auth *AuthConfig, progress func(string)) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.pulls = append(f.pulls, FakePull{Image: image, Auth: auth})
+	if err := f.pullErrors[image]; err != nil {
+		return "", err
+	}
+	if progress != nil {
+		progress("1/1 layers")
+	}
+	return f.addImage(image, 0), nil
+}
+
+func (f *Fake) ListImages(context.Context) ([]Image, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	res := make([]Image, 0, len(f.images))
+	for _, image := range f.images {
+		res = append(res, *image)
+	}
+	return res, nil
+}
+
+func (f *Fake) RemoveImage(_ context.Context, id string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for name, image := range f.images {
+		if image.ID == id {
+			delete(f.images, name)
+			return nil
+		}
+	}
+	return fmt.Errorf("image %s: %w", id, ErrNotFound)
+}
+
+func (f *Fake) ImageFsInfo(context.Context) (FsInfo, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	var used uint64
+	for _, image := range f.images {
+		used += uint64(image.Size)
+	}
+	if used > f.Capacity {
+		used = f.Capacity
+	}
+	return FsInfo{Capacity: f.Capacity, Available: f.Capacity - used}, nil
+}
+
+func (f *Fake) Close() {
+}
@@ -0,0 +1,211 @@
+package cri
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"minik8s/pkg/kubelet/constants"
+
+	dt "github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-units"
+)
+
+func (c *dockerClient) ImageStatus(ctx context.Context, image string) (*Image, error) {
+	inspect, _, err := c.Client.ImageInspectWithRaw(ctx, image)
+	if client.IsErrNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Image{
+		ID:       inspect.ID,
+		RepoTags: inspect.RepoTags,
+		Size:     inspect.Size,
+		Pinned:   isPauseImage(inspect.RepoTags),
+	}, nil
+}
+
+// PullImage pulls image and reports progress summed over its layers
+func (c *dockerClient) PullImage(ctx context.Context, image string, auth *AuthConfig, progress func(string)) (string, error) {
+	options := dt.ImagePullOptions{}
+	if auth != nil {
+		encoded, err := encodeAuth(auth)
+		if err != nil {
+			return "", err
+		}
+		options.RegistryAuth = encoded
+	}
+	out, err := c.Client.ImagePull(ctx, image, options)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	decoder := json.NewDecoder(out)
+	layers := pullProgress{}
+	for {
+		message := pullMessage{}
+		if err = decoder.Decode(&message); err == io.EOF {
+			break
+		} else if err != nil {
+			return "", err
+		}
+		// errors of pull are reported in output with status 200
+		if message.Error != "" {
+			return "", errors.New(message.Error)
+		}
+		if layers.update(message) && progress != nil {
+			progress(layers.String())
+		}
+	}
+
+	status, err := c.ImageStatus(ctx, image)
+	if err != nil {
+		return "", err
+	}
+	if status == nil {
+		return "", fmt.Errorf("image %s is not present after pull", image)
+	}
+	return status.ID, nil
+}
+
+func (c *dockerClient) ListImages(ctx context.Context) ([]Image, error) {
+	list, err := c.Client.ImageList(ctx, dt.ImageListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Image, 0, len(list))
+	for _, image := range list {
+		res = append(res, Image{
+			ID:       image.ID,
+			RepoTags: image.RepoTags,
+			Size:     image.Size,
+			Pinned:   isPauseImage(image.RepoTags),
+		})
+	}
+	return res, nil
+}
+
+// RemoveImage removes image with all its tags, it fails if image is used
+// by any container
+func (c *dockerClient) RemoveImage(ctx context.Context, id string) error {
+	_, err := c.Client.ImageRemove(ctx, id, dt.ImageRemoveOptions{PruneChildren: true})
+	if client.IsErrNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// ImageFsInfo returns usage of filesystem of docker root directory, where
+// layers of images are stored
+func (c *dockerClient) ImageFsInfo(ctx context.Context) (FsInfo, error) {
+	info, err := c.Client.Info(ctx)
+	if err != nil {
+		return FsInfo{}, err
+	}
+	return statFs(info.DockerRootDir)
+}
+
+// ensurePauseImage pulls image of pause container if it is absent
+func (c *dockerClient) ensurePauseImage(ctx context.Context) error {
+	image := constants.InitialPauseContainer.Image
+	status, err := c.ImageStatus(ctx, image)
+	if err != nil || status != nil {
+		return err
+	}
+	_, err = c.PullImage(ctx, image, nil, nil)
+	return err
+}
+
+// isPauseImage returns whether tags are of pause image, which is kept
+// from image garbage collection
+func isPauseImage(tags []string) bool {
+	for _, tag := range tags {
+		if tag == constants.InitialPauseContainer.Image {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeAuth encodes auth as X-Registry-Auth header of docker
+func encodeAuth(auth *AuthConfig) (string, error) {
+	data, err := json.Marshal(dt.AuthConfig{
+		Username:      auth.Username,
+		Password:      auth.Password,
+		Auth:          auth.Auth,
+		ServerAddress: auth.ServerAddress,
+		IdentityToken: auth.IdentityToken,
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(data), nil
+}
+
+// pullMessage is a line of output of docker image pull
+type pullMessage struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Progress struct {
+		Current int64 `json:"current"`
+		Total   int64 `json:"total"`
+	} `json:"progressDetail"`
+	Error string `json:"error"`
+}
+
+type layerProgress struct {
+	current int64
+	total   int64
+	done    bool
+}
+
+// pullProgress is progress of layers of image being pulled, keyed by layer id
+type pullProgress map[string]*layerProgress
+
+// update updates progress with message, and returns whether it changes
+func (p pullProgress) update(message pullMessage) bool {
+	layer := p[message.ID]
+	switch message.Status {
+	case "Pulling fs layer", "Waiting":
+		if layer == nil {
+			p[message.ID] = &layerProgress{}
+			return true
+		}
+	case "Downloading":
+		if layer != nil {
+			layer.current, layer.total = message.Progress.Current, message.Progress.Total
+			return true
+		}
+	case "Download complete", "Pull complete", "Already exists":
+		if layer == nil {
+			layer = &layerProgress{}
+			p[message.ID] = layer
+		}
+		if !layer.done {
+			layer.current = layer.total
+			layer.done = true
+			return true
+		}
+	}
+	return false
+}
+
+// String returns progress as e.g. "2/3 layers, 12.5MB/30MB downloaded"
+func (p pullProgress) String() string {
+	done := 0
+	var current, total int64
+	for _, layer := range p {
+		if layer.done {
+			done++
+		}
+		current += layer.current
+		total += layer.total
+	}
+	return fmt.Sprintf("%d/%d layers, %s/%s downloaded", done, len(p), units.HumanSize(float64(current)), units.HumanSize(float64(total)))
+}
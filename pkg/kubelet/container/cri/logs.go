@@ -0,0 +1,187 @@
+package cri
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// logPollInterval is the interval to check new logs when following log file
+const logPollInterval = 200 * time.Millisecond
+
+// criLogEntry is a line in log file of CRI runtimes, in form of
+// "2016-10-06T00:17:09.669794202Z stdout F content", long lines are
+// split into partial entries tagged P and the last one tagged F
+type criLogEntry struct {
+	timestamp time.Time
+	stderr    bool
+	partial   bool
+	content   []byte
+}
+
+func parseCRILogLine(line []byte) (criLogEntry, error) {
+	entry := criLogEntry{}
+	fields := bytes.SplitN(line, []byte{' '}, 4)
+	if len(fields) < 3 {
+		return entry, errors.New("invalid log line: " + string(line))
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, string(fields[0]))
+	if err != nil {
+		return entry, err
+	}
+	entry.timestamp = timestamp
+	switch string(fields[1]) {
+	case "stdout":
+	case "stderr":
+		entry.stderr = true
+	default:
+		return entry, errors.New("invalid stream in log line: " + string(line))
+	}
+	// tag may carry more fields after ':', the first one is P or F
+	entry.partial = bytes.HasPrefix(fields[2], []byte("P"))
+	if len(fields) == 4 {
+		entry.content = append([]byte{}, fields[3]...)
+	}
+	return entry, nil
+}
+
+// selected tells whether entry is in time range of opts
+func (e criLogEntry) selected(opts LogOptions) bool {
+	if !opts.Since.IsZero() && e.timestamp.Before(opts.Since) {
+		return false
+	}
+	return opts.Until.IsZero() || e.timestamp.Before(opts.Until)
+}
+
+// tailCRILogEntries returns entries of the last n lines
+func tailCRILogEntries(entries []criLogEntry, n int64) []criLogEntry {
+	lines := int64(0)
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].partial {
+			continue
+		}
+		lines++
+		if lines > n {
+			return entries[i+1:]
+		}
+	}
+	return entries
+}
+
+// criLogWriter writes entries to stdout and stderr, the timestamp
+// prefixes each line if opts.Timestamps is set
+type criLogWriter struct {
+	stdout     io.Writer
+	stderr     io.Writer
+	timestamps bool
+	// inLine of stdout and stderr tells that last entry was partial
+	inLine [2]bool
+}
+
+func (w *criLogWriter) write(entry criLogEntry) error {
+	out, stream := w.stdout, 0
+	if entry.stderr {
+		out, stream = w.stderr, 1
+	}
+	var buf bytes.Buffer
+	if w.timestamps && !w.inLine[stream] {
+		buf.WriteString(entry.timestamp.Format(time.RFC3339Nano) + " ")
+	}
+	buf.Write(entry.content)
+	if !entry.partial {
+		buf.WriteByte('\n')
+	}
+	w.inLine[stream] = entry.partial
+	_, err := out.Write(buf.Bytes())
+	return err
+}
+
+// readCRILog writes logs in log file of CRI runtimes selected by opts, if opts.Follow
+// is set, it keeps reading new logs until running returns false or ctx is done
+func readCRILog(ctx context.Context, path string, opts LogOptions, stdout, stderr io.Writer, running func() bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	var pending []byte
+	// next returns next entry, io.EOF if there is no complete line yet
+	next := func() (criLogEntry, error) {
+		for {
+			line, err := reader.ReadBytes('\n')
+			pending = append(pending, line...)
+			if err != nil {
+				return criLogEntry{}, err
+			}
+			entry, err := parseCRILogLine(bytes.TrimSuffix(pending, []byte{'\n'}))
+			pending = pending[:0]
+			if err == nil {
+				return entry, nil
+			}
+		}
+	}
+
+	writer := &criLogWriter{stdout: stdout, stderr: stderr, timestamps: opts.Timestamps}
+	var entries []criLogEntry
+	tail := opts.TailLines >= 0
+	for {
+		entry, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if !entry.selected(opts) {
+			continue
+		}
+		if tail {
+			entries = append(entries, entry)
+		} else if err = writer.write(entry); err != nil {
+			return err
+		}
+	}
+	if tail {
+		for _, entry := range tailCRILogEntries(entries, opts.TailLines) {
+			if err := writer.write(entry); err != nil {
+				return err
+			}
+		}
+	}
+	if !opts.Follow {
+		return nil
+	}
+
+	// logs written before container stops are read once more after it stops
+	stopped := false
+	for {
+		entry, err := next()
+		if err == nil {
+			if entry.selected(opts) {
+				if err = writer.write(entry); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+		if stopped {
+			return nil
+		}
+		stopped = !running()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(logPollInterval):
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package cri
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadCRILog(t *testing.T) {
+	log := "2023-05-01T00:00:01Z stdout F first\n" +
+		"2023-05-01T00:00:02Z stderr F oops\n" +
+		"2023-05-01T00:00:03Z stdout P sec\n" +
+		"2023-05-01T00:00:03Z stdout F ond\n" +
+		"malformed line\n" +
+		"2023-05-01T00:00:04Z stdout F third\n" +
+		"2023-05-01T00:00:05Z stdout F incomplete"
+	path := filepath.Join(t.TempDir(), "0.log")
+	if err := os.WriteFile(path, []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+	at := func(second int) time.Time {
+		return time.Date(2023, 5, 1, 0, 0, second, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name   string
+		opts   LogOptions
+		stdout string
+		stderr string
+	}{
+		{
+			name:   "all logs",
+			opts:   LogOptions{TailLines: -1},
+			stdout: "first\nsecond\nthird\n",
+			stderr: "oops\n",
+		},
+		{
+			name:   "tail counts complete lines",
+			opts:   LogOptions{TailLines: 2},
+			stdout: "second\nthird\n",
+		},
+		{
+			name:   "time range",
+			opts:   LogOptions{TailLines: -1, Since: at(2), Until: at(4)},
+			stdout: "second\n",
+			stderr: "oops\n",
+		},
+		{
+			name:   "timestamps prefix lines",
+			opts:   LogOptions{TailLines: -1, Since: at(3), Timestamps: true},
+			stdout: "2023-05-01T00:00:03Z second\n2023-05-01T00:00:04Z third\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := readCRILog(context.Background(), path, tt.opts, &stdout, &stderr, func() bool { return false })
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stdout.String() != tt.stdout || stderr.String() != tt.stderr {
+				t.Errorf("readCRILog() = %q, %q, want %q, %q", stdout.String(), stderr.String(), tt.stdout, tt.stderr)
+			}
+		})
+	}
+}
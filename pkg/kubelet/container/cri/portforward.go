@@ -0,0 +1,104 @@
+package cri
+
+import (
+	"context"
+	"fmt"
+	"github.com/vishvananda/netns"
+	"io"
+	"net"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// portForwardDialTimeout is timeout of connecting to port in container
+const portForwardDialTimeout = 5 * time.Second
+
+func (c *dockerClient) PortForward(ctx context.Context, sandbox string, port int32, in io.Reader, out io.Writer) error {
+	id, err := c.containerID(ctx, sandbox)
+	if err != nil {
+		return err
+	}
+	inspect, err := c.Client.ContainerInspect(ctx, id)
+	if err != nil {
+		return err
+	}
+	if inspect.State == nil || !inspect.State.Running {
+		return fmt.Errorf("sandbox %s is not running", sandbox)
+	}
+	return forwardInNetns(ctx, inspect.State.Pid, port, in, out)
+}
+
+// forwardInNetns copies data between in, out and port in network namespace of process pid
+func forwardInNetns(ctx context.Context, pid int, port int32, in io.Reader, out io.Writer) error {
+	conn, err := dialInNetns(pid, port)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	go func() {
+		_, _ = io.Copy(conn, in)
+		// tell port that no more data will be sent
+		_ = conn.CloseWrite()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(out, conn)
+		done <- err
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err = <-done:
+		return err
+	}
+}
+
+// dialInNetns connects to port on localhost in network namespace of process pid,
+// so that ports listening on loopback of container can be reached as well. The
+// socket stays in that namespace after the thread switches back.
+func dialInNetns(pid int, port int32) (*net.TCPConn, error) {
+	type result struct {
+		conn *net.TCPConn
+		err  error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		// thread is unlocked only after it is back in its own namespace,
+		// otherwise it is terminated when this goroutine exits
+		runtime.LockOSThread()
+		conn, restored, err := dialOnLockedThread(pid, port)
+		if restored {
+			runtime.UnlockOSThread()
+		}
+		ch <- result{conn: conn, err: err}
+	}()
+	r := <-ch
+	return r.conn, r.err
+}
+
+func dialOnLockedThread(pid int, port int32) (*net.TCPConn, bool, error) {
+	origin, err := netns.Get()
+	if err != nil {
+		return nil, true, err
+	}
+	defer origin.Close()
+	target, err := netns.GetFromPid(pid)
+	if err != nil {
+		return nil, true, err
+	}
+	defer target.Close()
+
+	if err = netns.Set(target); err != nil {
+		return nil, true, err
+	}
+	conn, dialErr := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(int(port))), portForwardDialTimeout)
+	restored := netns.Set(origin) == nil
+
+	if dialErr != nil {
+		return nil, restored, fmt.Errorf("unable to connect to port %d in pod: %v", port, dialErr)
+	}
+	return conn.(*net.TCPConn), restored, nil
+}
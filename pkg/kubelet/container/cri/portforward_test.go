@@ -0,0 +1,39 @@
+package cri
+
+import (
+	"io"
+	"net"
+	"os"
+	"testing"
+)
+
+func TestDialInNetns(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = io.Copy(conn, conn)
+	}()
+
+	// network namespace of this process has the listener
+	port := int32(listener.Addr().(*net.TCPAddr).Port)
+	conn, err := dialInNetns(os.Getpid(), port)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	_, _ = conn.Write([]byte("ping"))
+	_ = conn.CloseWrite()
+	got, _ := io.ReadAll(conn)
+	if string(got) != "ping" {
+		t.Errorf("got %q, want %q", got, "ping")
+	}
+}
@@ -0,0 +1,129 @@
+package cri
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"minik8s/pkg/kubelet/streaming"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/websocket"
+)
+
+// remoteCommandProtocol is websocket protocol of streaming server of CRI runtimes,
+// whose channels are the same as ones kubelet serves
+const remoteCommandProtocol = "v4.channel.k8s.io"
+
+// remoteCommandStatus is status sent by streaming server of CRI runtimes on
+// error channel, exit code of failed command is one of its causes
+type remoteCommandStatus struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Reason  string `json:"reason"`
+	Details *struct {
+		Causes []struct {
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"causes"`
+	} `json:"details"`
+}
+
+// streamRemoteCommand connects to url of exec or attach returned by CRI runtime,
+// copies streams until command finishes, and returns its exit code
+func streamRemoteCommand(ctx context.Context, url string, streams StreamOptions) (int, error) {
+	wsConfig, err := websocket.NewConfig(strings.Replace(url, "http", "ws", 1), "http://localhost/")
+	if err != nil {
+		return 0, err
+	}
+	wsConfig.Protocol = []string{remoteCommandProtocol}
+	ws, err := websocket.DialConfig(wsConfig)
+	if err != nil {
+		return 0, err
+	}
+	conn := streaming.NewConn(ws)
+	defer conn.Close()
+
+	if streams.Stdin != nil {
+		go func() {
+			_, _ = io.Copy(conn.Writer(streaming.StdinChannel), streams.Stdin)
+		}()
+	}
+	if streams.TTY {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case size := <-streams.Resize:
+					if conn.WriteSize(size) != nil {
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	type result struct {
+		code int
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		code, err := copyRemoteOutput(conn, streams)
+		done <- result{code: code, err: err}
+	}()
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case r := <-done:
+		return r.code, r.err
+	}
+}
+
+// copyRemoteOutput copies stdout and stderr until status of command is received
+func copyRemoteOutput(conn *streaming.Conn, streams StreamOptions) (int, error) {
+	for {
+		channel, data, err := conn.Read()
+		if err == io.EOF {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		switch channel {
+		case streaming.StdoutChannel:
+			if streams.Stdout != nil {
+				_, _ = streams.Stdout.Write(data)
+			}
+		case streaming.StderrChannel:
+			if streams.Stderr != nil {
+				_, _ = streams.Stderr.Write(data)
+			}
+		case streaming.ErrorChannel:
+			return parseRemoteCommandStatus(data)
+		}
+	}
+}
+
+func parseRemoteCommandStatus(data []byte) (int, error) {
+	status := remoteCommandStatus{}
+	if err := json.Unmarshal(data, &status); err != nil {
+		return 0, errors.New("invalid status of remote command: " + string(data))
+	}
+	if status.Status == streaming.StatusSuccess {
+		return 0, nil
+	}
+	if status.Reason == "NonZeroExitCode" && status.Details != nil {
+		for _, cause := range status.Details.Causes {
+			if cause.Reason != "ExitCode" {
+				continue
+			}
+			if code, err := strconv.Atoi(cause.Message); err == nil {
+				return code, nil
+			}
+		}
+	}
+	return 0, errors.New(status.Message)
+}
@@ -0,0 +1,38 @@
+package cri
+
+import (
+	"context"
+	"log"
+	"minik8s/config"
+	"time"
+)
+
+// GracefulStop stops container in the order of: run preStop hook, send stop signal,
+// wait for the rest of grace period, and kill the container if it is still running.
+// Grace period counts down before preStop hook runs, but container always has at
+// least config.MinimumGracePeriod to stop after the signal.
+func GracefulStop(ctx context.Context, c Client, name string, preStop func(ctx context.Context) error, signal string, gracePeriod time.Duration) error {
+	deadline := time.Now().Add(gracePeriod)
+
+	if preStop != nil {
+		hookCtx, cancel := context.WithDeadline(ctx, deadline)
+		if err := preStop(hookCtx); err != nil {
+			log.Printf("[CRI] preStop hook of container %s failed: %v\n", name, err)
+		}
+		cancel()
+	}
+
+	timeout := time.Until(deadline)
+	if timeout < config.MinimumGracePeriod {
+		timeout = config.MinimumGracePeriod
+	}
+	return c.ContainerStop(ctx, name, signal, timeout)
+}
+
+// StopAndRemove stops container gracefully and then removes it
+func StopAndRemove(ctx context.Context, c Client, name string, preStop func(ctx context.Context) error, signal string, gracePeriod time.Duration) error {
+	if err := GracefulStop(ctx, c, name, preStop, signal, gracePeriod); err != nil {
+		log.Printf("[CRI] stop container %s failed: %v\n", name, err)
+	}
+	return c.ContainerRemove(ctx, name)
+}
@@ -0,0 +1,93 @@
+package kubelet
+
+import (
+	"context"
+	"log"
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/kubelet/container/cri"
+	"minik8s/pkg/logger"
+	"sync"
+	"time"
+)
+
+/*---------------------------- Container Lifecycle ----------------------------*/
+
+func terminationGracePeriod(pod *core.Pod) time.Duration {
+	if pod.Spec.TerminationGracePeriodSeconds == nil {
+		return config.DefaultTerminationGracePeriod
+	}
+	return time.Duration(*pod.Spec.TerminationGracePeriodSeconds) * time.Second
+}
+
+func stopSignal(container core.Container) string {
+	if container.Lifecycle == nil || container.Lifecycle.StopSignal == nil {
+		return ""
+	}
+	return string(*container.Lifecycle.StopSignal)
+}
+
+func (k *kubelet) podIP(ctx context.Context, pod *core.Pod) string {
+	sandbox, err := k.criClient.PodSandboxStatus(ctx, makePodSandboxName(pod))
+	if err != nil {
+		log.Println("[ERROR]: failed to get ip of pod", pod.Name, err.Error())
+	}
+	return sandbox.IP
+}
+
+// preStopHook returns preStop hook of container to be run by cri, nil if not set
+func (k *kubelet) preStopHook(pod *core.Pod, container core.Container) func(ctx context.Context) error {
+	if container.Lifecycle == nil || container.Lifecycle.PreStop == nil {
+		return nil
+	}
+	return func(ctx context.Context) error {
+		logger.KubeletLogger.Printf("Run preStop hook of container %s in pod %s\n", container.Name, pod.Name)
+		return k.handlerRunner.Run(ctx, makePodContainerName(pod, container), k.podIP(ctx, pod), container.Lifecycle.PreStop)
+	}
+}
+
+// stopContainer stops container gracefully without removing it
+func (k *kubelet) stopContainer(ctx context.Context, pod *core.Pod, container core.Container) {
+	name := makePodContainerName(pod, container)
+	err := cri.GracefulStop(ctx, k.criClient, name, k.preStopHook(pod, container), stopSignal(container), terminationGracePeriod(pod))
+	if err != nil {
+		log.Println("[ERROR]: failed to stop container", name, err.Error())
+	}
+}
+
+// removeContainers stops containers gracefully in parallel and removes them
+func (k *kubelet) removeContainers(ctx context.Context, pod *core.Pod, containers []core.Container) {
+	wg := sync.WaitGroup{}
+	for _, container := range containers {
+		wg.Add(1)
+		go func(container core.Container) {
+			defer wg.Done()
+			name := makePodContainerName(pod, container)
+			err := cri.StopAndRemove(ctx, k.criClient, name, k.preStopHook(pod, container), stopSignal(container), terminationGracePeriod(pod))
+			if err != nil {
+				log.Println("[ERROR]: failed to remove container", name, err.Error())
+			}
+		}(container)
+	}
+	wg.Wait()
+}
+
+// runPostStartHook runs postStart hook of container in background after it is
+// started, the container is stopped if hook fails and then restarted by policy
+func (k *kubelet) runPostStartHook(ctx context.Context, pod *core.Pod, container core.Container) {
+	if container.Lifecycle == nil || container.Lifecycle.PostStart == nil {
+		return
+	}
+	go func() {
+		hookCtx, cancel := context.WithTimeout(ctx, config.PostStartHookTimeout)
+		defer cancel()
+
+		logger.KubeletLogger.Printf("Run postStart hook of container %s in pod %s\n", container.Name, pod.Name)
+		err := k.handlerRunner.Run(hookCtx, makePodContainerName(pod, container), k.podIP(hookCtx, pod), container.Lifecycle.PostStart)
+		if err != nil {
+			logger.KubeletLogger.Printf("PostStart hook of container %s in pod %s failed: %v, stop it\n", container.Name, pod.Name, err)
+			k.eventf(pod, core.EventTypeWarning, FailedPostStartHook, "PostStart hook of container %s failed: %v", container.Name, err)
+			k.stopContainer(ctx, pod, container)
+		}
+	}()
+}
@@ -0,0 +1,154 @@
+package kubelet
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/logger"
+	"time"
+)
+
+/*---------------------------- Container Restart ----------------------------*/
+
+// CrashLoopBackOff is the waiting reason of a failed container
+// which is waiting for back-off delay before it is restarted
+const CrashLoopBackOff = "CrashLoopBackOff"
+
+// containerRestartState is what kubelet remembers about restarts of a container
+type containerRestartState struct {
+	restartCount int32
+	lastState    core.ContainerState
+
+	// back-off delay of current and next crash, and time to restart current crash
+	delay       time.Duration
+	backoff     time.Duration
+	nextRestart time.Time
+	// container is terminated and waiting to be restarted
+	waiting   bool
+	startedAt time.Time
+
+	// containers are restarted in place and keep one log in runtime, logs
+	// are split by time current and previous run started, zero for first run
+	runStartedAt     time.Time
+	lastRunStartedAt time.Time
+}
+
+// shouldRestart returns true if container exited with exitCode
+// should be restarted under restart policy of pod
+func shouldRestart(policy core.RestartPolicy, exitCode int32) bool {
+	switch policy {
+	case core.RestartPolicyNever:
+		return false
+	case core.RestartPolicyOnFailure:
+		return exitCode != 0
+	default:
+		return true
+	}
+}
+
+// observeTermination schedules restart of a newly terminated container,
+// the first crash is restarted at once, and the delay doubles after that
+func (s *containerRestartState) observeTermination(now time.Time) {
+	if s.waiting {
+		return
+	}
+	if !s.startedAt.IsZero() && now.Sub(s.startedAt) >= config.CrashLoopBackOffResetPeriod {
+		s.backoff = 0
+	}
+
+	s.waiting = true
+	s.delay = s.backoff
+	s.nextRestart = now.Add(s.delay)
+	switch {
+	case s.backoff == 0:
+		s.backoff = config.CrashLoopBackOffInitial
+	case s.backoff*2 > config.CrashLoopBackOffMax:
+		s.backoff = config.CrashLoopBackOffMax
+	default:
+		s.backoff *= 2
+	}
+}
+
+// getRestartState should be called with k.lock held
+func (k *kubelet) getRestartState(name string) *containerRestartState {
+	state, found := k.restartStates[name]
+	if !found {
+		state = &containerRestartState{}
+		k.restartStates[name] = state
+	}
+	return state
+}
+
+// removeRestartState should be called with k.lock held
+func (k *kubelet) removeRestartState(name string) {
+	delete(k.restartStates, name)
+}
+
+// syncContainerRestarts restarts terminated containers of pod in place according
+// to its restart policy, and fills restart count, last state and waiting reason
+// of container statuses
+func (k *kubelet) syncContainerRestarts(ctx context.Context, pod *core.Pod, statuses []core.ContainerStatus) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	// pod is deleted meanwhile
+	if _, found := k.podManager.GetPodByUID(pod.UID); !found {
+		return
+	}
+
+	now := time.Now()
+	for idx, container := range pod.Spec.Containers {
+		status := &statuses[idx]
+		name := makePodContainerName(pod, container)
+		state := k.getRestartState(name)
+
+		if status.State.Running != nil && state.startedAt.IsZero() {
+			state.startedAt = now
+		}
+
+		terminated := status.State.Terminated
+		if terminated != nil && shouldRestart(pod.Spec.RestartPolicy, terminated.ExitCode) {
+			crashed := !state.waiting
+			state.observeTermination(now)
+			if now.Before(state.nextRestart) {
+				if crashed {
+					k.eventf(pod, core.EventTypeWarning, BackOffStartContainer, "Back-off restarting failed container %s", container.Name)
+				}
+				status.State = core.ContainerState{
+					Waiting: &core.ContainerStateWaiting{
+						Reason:  CrashLoopBackOff,
+						Message: fmt.Sprintf("back-off %v restarting failed container %s", state.delay, container.Name),
+					},
+				}
+			} else if k.restartContainer(ctx, pod, container) {
+				state.waiting = false
+				state.restartCount++
+				state.lastState = core.ContainerState{Terminated: terminated}
+				state.startedAt = now
+				state.lastRunStartedAt, state.runStartedAt = state.runStartedAt, now
+				started := false
+				status.State = core.ContainerState{Running: &core.ContainerStateRunning{StartedAt: now}}
+				status.Ready = false
+				status.Started = &started
+			}
+		}
+
+		status.RestartCount = state.restartCount
+		status.LastTerminationState = state.lastState
+	}
+}
+
+func (k *kubelet) restartContainer(ctx context.Context, pod *core.Pod, container core.Container) bool {
+	name := makePodContainerName(pod, container)
+	logger.KubeletLogger.Printf("Restart container %s of pod %s\n", container.Name, pod.Name)
+	if err := k.criClient.ContainerStart(ctx, name); err != nil {
+		log.Println("[ERROR]: failed to restart container", name, err.Error())
+		return false
+	}
+	k.eventf(pod, core.EventTypeNormal, StartedContainer, "Started container %s", container.Name)
+	k.probeManager.ContainerRestarted(pod.UID, container.Name)
+	k.runPostStartHook(ctx, pod, container)
+	return true
+}
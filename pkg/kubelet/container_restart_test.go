@@ -0,0 +1,62 @@
+package kubelet
+
+import (
+	"minik8s/config"
+	"testing"
+	"time"
+)
+
+func TestObserveTermination(t *testing.T) {
+	now := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		backoff   time.Duration
+		startedAt time.Time
+		delay     time.Duration
+		next      time.Duration
+	}{
+		{
+			name:  "first crash is restarted at once",
+			delay: 0,
+			next:  config.CrashLoopBackOffInitial,
+		},
+		{
+			name:      "delay doubles",
+			backoff:   20 * time.Second,
+			startedAt: now.Add(-time.Minute),
+			delay:     20 * time.Second,
+			next:      40 * time.Second,
+		},
+		{
+			name:      "delay is capped",
+			backoff:   160 * time.Second,
+			startedAt: now.Add(-time.Minute),
+			delay:     160 * time.Second,
+			next:      config.CrashLoopBackOffMax,
+		},
+		{
+			name:      "back-off is reset after running long enough",
+			backoff:   config.CrashLoopBackOffMax,
+			startedAt: now.Add(-config.CrashLoopBackOffResetPeriod),
+			delay:     0,
+			next:      config.CrashLoopBackOffInitial,
+		},
+	}
+
+	for _, test := range tests {
+		state := &containerRestartState{backoff: test.backoff, startedAt: test.startedAt}
+		state.observeTermination(now)
+		if !state.waiting || state.delay != test.delay || !state.nextRestart.Equal(now.Add(test.delay)) {
+			t.Errorf("%s: got delay %v, expected %v", test.name, state.delay, test.delay)
+		}
+		if state.backoff != test.next {
+			t.Errorf("%s: got next back-off %v, expected %v", test.name, state.backoff, test.next)
+		}
+
+		// termination already observed is not scheduled again
+		state.observeTermination(now.Add(time.Second))
+		if state.backoff != test.next {
+			t.Errorf("%s: back-off changed after observing the same termination", test.name)
+		}
+	}
+}
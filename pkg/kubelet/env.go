@@ -0,0 +1,259 @@
+package kubelet
+
+import (
+	"context"
+	"fmt"
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/kubelet/volume"
+	"minik8s/pkg/logger"
+	"regexp"
+	"sort"
+	"time"
+)
+
+/*---------------------------- ConfigMaps and Secrets ----------------------------*/
+
+// CreateContainerConfigError is the waiting reason of containers whose
+// environment variables can not be resolved
+const CreateContainerConfigError = "CreateContainerConfigError"
+
+// getConfigMap returns nil without error if ConfigMap is not found
+func (k *kubelet) getConfigMap(name string) (*core.ConfigMap, error) {
+	r, err := k.configMapClient.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	configMap := r.(*core.ConfigMap)
+	if configMap.Name == "" {
+		return nil, nil
+	}
+	return configMap, nil
+}
+
+// getSecret returns nil without error if Secret is not found
+func (k *kubelet) getSecret(name string) (*core.Secret, error) {
+	r, err := k.secretClient.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	secret := r.(*core.Secret)
+	if secret.Name == "" {
+		return nil, nil
+	}
+	return secret, nil
+}
+
+// resolveContainerConfigs resolves environment variables of all containers of pod
+// before any container is created, it retries until all ConfigMaps and Secrets
+// referenced are available, and returns false if pod is deleted meanwhile
+func (k *kubelet) resolveContainerConfigs(pod *core.Pod) bool {
+	containers := append(append([]core.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	lastMessage := ""
+	for {
+		k.lock.RLock()
+		_, found := k.podManager.GetPodByUID(pod.UID)
+		k.lock.RUnlock()
+		if !found {
+			return false
+		}
+
+		var err error
+		for _, container := range containers {
+			if _, err = makeEnvironmentVariables(container, k.getConfigMap, k.getSecret); err != nil {
+				err = fmt.Errorf("container %s: %v", container.Name, err)
+				break
+			}
+		}
+		if err == nil {
+			return true
+		}
+
+		logger.KubeletLogger.Printf("Resolve environment variables of pod %s failed: %v\n", pod.Name, err)
+		if err.Error() != lastMessage {
+			lastMessage = err.Error()
+			k.reportContainersWaiting(pod, CreateContainerConfigError, lastMessage)
+		}
+		time.Sleep(config.ContainerConfigRetryInterval)
+	}
+}
+
+// syncConfigVolumes refreshes volumes projected from ConfigMaps and Secrets
+// periodically, so that mounted files follow changes of source objects
+func (k *kubelet) syncConfigVolumes(ctx context.Context) {
+	ticker := time.NewTicker(config.ConfigVolumeSyncPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, pod := range k.podManager.GetPods() {
+			if hasConfigVolumes(pod) {
+				k.refreshVolumes(pod)
+			}
+		}
+	}
+}
+
+// refreshVolumes refreshes volumes of pod with read lock held,
+// so that they are not refreshed after torn down
+func (k *kubelet) refreshVolumes(pod *core.Pod) {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	if _, found := k.podVolumes[pod.UID]; !found {
+		return
+	}
+	if err := k.volumeManager.RefreshPod(pod); err != nil {
+		logger.KubeletLogger.Printf("Refresh volumes of pod %s failed: %v\n", pod.Name, err)
+	}
+}
+
+func hasConfigVolumes(pod *core.Pod) bool {
+	for _, v := range pod.Spec.Volumes {
+		if v.ConfigMap != nil || v.Secret != nil {
+			return true
+		}
+	}
+	return false
+}
+
+var envVarNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// makeEnvironmentVariables resolves environment variables of container into
+// literal values. Variables from envFrom come first in order of sources, then
+// variables of env, a later variable overrides the earlier one of the same name.
+func makeEnvironmentVariables(container core.Container, getConfigMap volume.ConfigMapGetter, getSecret volume.SecretGetter) ([]core.EnvVar, error) {
+	var result []core.EnvVar
+	index := make(map[string]int)
+	set := func(name, value string) {
+		if i, found := index[name]; found {
+			result[i].Value = value
+			return
+		}
+		index[name] = len(result)
+		result = append(result, core.EnvVar{Name: name, Value: value})
+	}
+
+	configMaps := make(map[string]*core.ConfigMap)
+	configMap := func(name string) (*core.ConfigMap, error) {
+		if c, found := configMaps[name]; found {
+			return c, nil
+		}
+		c, err := getConfigMap(name)
+		if err != nil {
+			return nil, err
+		}
+		configMaps[name] = c
+		return c, nil
+	}
+	secrets := make(map[string]*core.Secret)
+	secret := func(name string) (*core.Secret, error) {
+		if s, found := secrets[name]; found {
+			return s, nil
+		}
+		s, err := getSecret(name)
+		if err != nil {
+			return nil, err
+		}
+		secrets[name] = s
+		return s, nil
+	}
+
+	for _, from := range container.EnvFrom {
+		data := make(map[string]string)
+		switch {
+		case from.ConfigMapRef != nil:
+			c, err := configMap(from.ConfigMapRef.Name)
+			if err != nil {
+				return nil, err
+			}
+			if c == nil {
+				if isOptional(from.ConfigMapRef.Optional) {
+					continue
+				}
+				return nil, fmt.Errorf("configmap %s is not found", from.ConfigMapRef.Name)
+			}
+			for key, value := range c.Data {
+				data[key] = value
+			}
+		case from.SecretRef != nil:
+			s, err := secret(from.SecretRef.Name)
+			if err != nil {
+				return nil, err
+			}
+			if s == nil {
+				if isOptional(from.SecretRef.Optional) {
+					continue
+				}
+				return nil, fmt.Errorf("secret %s is not found", from.SecretRef.Name)
+			}
+			for key, value := range s.Data {
+				data[key] = string(value)
+			}
+		}
+
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			// keys which are not valid variable names are skipped
+			if name := from.Prefix + key; envVarNameRegexp.MatchString(name) {
+				set(name, data[key])
+			}
+		}
+	}
+
+	for _, env := range container.Env {
+		if env.ValueFrom == nil {
+			set(env.Name, env.Value)
+			continue
+		}
+		switch {
+		case env.ValueFrom.ConfigMapKeyRef != nil:
+			ref := env.ValueFrom.ConfigMapKeyRef
+			c, err := configMap(ref.Name)
+			if err != nil {
+				return nil, err
+			}
+			value, found := "", false
+			if c != nil {
+				value, found = c.Data[ref.Key]
+			}
+			if !found {
+				if isOptional(ref.Optional) {
+					continue
+				}
+				return nil, fmt.Errorf("key %s of configmap %s is not found", ref.Key, ref.Name)
+			}
+			set(env.Name, value)
+		case env.ValueFrom.SecretKeyRef != nil:
+			ref := env.ValueFrom.SecretKeyRef
+			s, err := secret(ref.Name)
+			if err != nil {
+				return nil, err
+			}
+			var value []byte
+			found := false
+			if s != nil {
+				value, found = s.Data[ref.Key]
+			}
+			if !found {
+				if isOptional(ref.Optional) {
+					continue
+				}
+				return nil, fmt.Errorf("key %s of secret %s is not found", ref.Key, ref.Name)
+			}
+			set(env.Name, string(value))
+		}
+	}
+	return result, nil
+}
+
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
+}
@@ -0,0 +1,101 @@
+package kubelet
+
+import (
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/meta"
+	"reflect"
+	"testing"
+)
+
+func TestMakeEnvironmentVariables(t *testing.T) {
+	configMaps := map[string]*core.ConfigMap{
+		"app": {
+			ObjectMeta: meta.ObjectMeta{Name: "app"},
+			Data:       map[string]string{"MODE": "prod", "LEVEL": "info", "app.conf": "skipped"},
+		},
+	}
+	secrets := map[string]*core.Secret{
+		"db": {
+			ObjectMeta: meta.ObjectMeta{Name: "db"},
+			Data:       map[string][]byte{"password": []byte("123456")},
+		},
+	}
+	getConfigMap := func(name string) (*core.ConfigMap, error) { return configMaps[name], nil }
+	getSecret := func(name string) (*core.Secret, error) { return secrets[name], nil }
+	optional := true
+
+	tests := []struct {
+		name      string
+		container core.Container
+		want      []core.EnvVar
+		wantErr   bool
+	}{
+		{
+			name: "env overrides envFrom",
+			container: core.Container{
+				EnvFrom: []core.EnvFromSource{
+					{Prefix: "APP_", ConfigMapRef: &core.ConfigMapEnvSource{LocalObjectReference: core.LocalObjectReference{Name: "app"}}},
+				},
+				Env: []core.EnvVar{
+					{Name: "APP_MODE", Value: "dev"},
+					{Name: "DB_PASSWORD", ValueFrom: &core.EnvVarSource{
+						SecretKeyRef: &core.SecretKeySelector{LocalObjectReference: core.LocalObjectReference{Name: "db"}, Key: "password"},
+					}},
+				},
+			},
+			want: []core.EnvVar{
+				{Name: "APP_LEVEL", Value: "info"},
+				{Name: "APP_MODE", Value: "dev"},
+				{Name: "DB_PASSWORD", Value: "123456"},
+			},
+		},
+		{
+			name: "optional references are skipped",
+			container: core.Container{
+				EnvFrom: []core.EnvFromSource{
+					{SecretRef: &core.SecretEnvSource{LocalObjectReference: core.LocalObjectReference{Name: "missing"}, Optional: &optional}},
+				},
+				Env: []core.EnvVar{
+					{Name: "LEVEL", ValueFrom: &core.EnvVarSource{
+						ConfigMapKeyRef: &core.ConfigMapKeySelector{LocalObjectReference: core.LocalObjectReference{Name: "app"}, Key: "LEVEL"},
+					}},
+					{Name: "MISSING", ValueFrom: &core.EnvVarSource{
+						ConfigMapKeyRef: &core.ConfigMapKeySelector{LocalObjectReference: core.LocalObjectReference{Name: "app"}, Key: "missing", Optional: &optional},
+					}},
+				},
+			},
+			want: []core.EnvVar{{Name: "LEVEL", Value: "info"}},
+		},
+		{
+			name: "missing configmap",
+			container: core.Container{
+				EnvFrom: []core.EnvFromSource{
+					{ConfigMapRef: &core.ConfigMapEnvSource{LocalObjectReference: core.LocalObjectReference{Name: "missing"}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing secret key",
+			container: core.Container{
+				Env: []core.EnvVar{
+					{Name: "TOKEN", ValueFrom: &core.EnvVarSource{
+						SecretKeyRef: &core.SecretKeySelector{LocalObjectReference: core.LocalObjectReference{Name: "db"}, Key: "token"},
+					}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := makeEnvironmentVariables(test.container, getConfigMap, getSecret)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got err %v, wantErr %v", test.name, err, test.wantErr)
+			continue
+		}
+		if !test.wantErr && !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
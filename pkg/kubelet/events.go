@@ -0,0 +1,42 @@
+package kubelet
+
+import (
+	"minik8s/pkg/api/core"
+)
+
+/*---------------------------- Events ----------------------------*/
+
+// Component is the source of events recorded by kubelet
+const Component = "kubelet"
+
+// Reasons of events recorded by kubelet
+const (
+	CreatedContainer        = "Created"
+	StartedContainer        = "Started"
+	FailedToCreateContainer = "Failed"
+	KillingContainer        = "Killing"
+	BackOffStartContainer   = "BackOff"
+	PullingImage            = "Pulling"
+	PulledImage             = "Pulled"
+	FailedToPullImage       = "Failed"
+	BackOffPullImage        = "BackOff"
+	FailedMountVolume       = "FailedMount"
+	FailedPostStartHook     = "FailedPostStartHook"
+	FailedCreatePodSandBox  = "FailedCreatePodSandBox"
+	ContainerUnhealthy      = "Unhealthy"
+)
+
+// eventf records event of pod, events of static pods are about their mirror
+// pods, and dropped if there is none yet
+func (k *kubelet) eventf(pod *core.Pod, eventType, reason, messageFmt string, args ...interface{}) {
+	if pod.IsStaticPod() {
+		uid, found := k.podStatusUID(pod)
+		if !found {
+			return
+		}
+		mirror := *pod
+		mirror.UID = uid
+		pod = &mirror
+	}
+	k.recorder.Eventf(pod, eventType, reason, messageFmt, args...)
+}
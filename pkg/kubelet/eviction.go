@@ -0,0 +1,122 @@
+package kubelet
+
+import (
+	"context"
+	"fmt"
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/kubelet/eviction"
+	"minik8s/pkg/logger"
+	"time"
+)
+
+/*---------------------------- Eviction ----------------------------*/
+
+// parseEvictionThresholds returns eviction thresholds of node config,
+// default hard thresholds are used if they are not set
+func parseEvictionThresholds(node *core.Node) ([]eviction.Threshold, error) {
+	hard := node.Spec.EvictionHard
+	if hard == nil {
+		hard = config.DefaultEvictionHard
+	}
+	return eviction.ParseThresholds(hard, node.Spec.EvictionSoft, node.Spec.EvictionSoftGracePeriod)
+}
+
+// synchronizeEviction periodically checks eviction thresholds, and evicts pods
+// one at a time while node is short of memory or disk
+func (k *kubelet) synchronizeEviction(ctx context.Context) {
+	ticker := time.NewTicker(config.EvictionMonitoringPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pod, err := k.evictionManager.Synchronize(ctx)
+		if err != nil {
+			logger.KubeletLogger.Printf("Eviction manager synchronize failed: %v\n", err)
+			continue
+		}
+		if pod != nil {
+			logger.KubeletLogger.Printf("Pod %s is evicted\n", pod.Name)
+		}
+	}
+}
+
+// activePods returns pods that may be evicted from this node. Finished pods
+// are kept in podManager but use no resources, and static pods are not
+// evicted since kubelet would start them again from their manifests
+func (k *kubelet) activePods() []*core.Pod {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	pods := make([]*core.Pod, 0)
+	for _, p := range k.podManager.GetPods() {
+		if p.Spec.NodeName == k.node.Name && !isPodFinished(p) && !p.IsStaticPod() {
+			pods = append(pods, p)
+		}
+	}
+	return pods
+}
+
+// evictPod kills pod within gracePeriod and reports it Failed with reason Evicted,
+// evicted pod is not started again until it is deleted
+func (k *kubelet) evictPod(pod *core.Pod, gracePeriod time.Duration, message string) error {
+	k.lock.Lock()
+	old, found := k.podManager.GetPodByUID(pod.UID)
+	if !found {
+		k.lock.Unlock()
+		return fmt.Errorf("pod %s is not found", pod.Name)
+	}
+	logger.KubeletLogger.Printf("Evict pod %s: %s\n", pod.Name, message)
+	k.eventf(old, core.EventTypeWarning, core.PodReasonEvicted, "%s", message)
+	k.forgetContainers(old, old.Spec.InitContainers)
+	k.forgetContainers(old, old.Spec.Containers)
+	delete(k.podVolumes, old.UID)
+	k.podManager.DeletePod(old)
+	k.evictedPods[old.UID] = true
+	k.lock.Unlock()
+
+	killed := *old
+	seconds := int64(gracePeriod / time.Second)
+	killed.Spec.TerminationGracePeriodSeconds = &seconds
+	k.killPod(context.Background(), &killed)
+
+	uid, found := k.podStatusUID(pod)
+	if !found {
+		return nil
+	}
+	return k.updatePodStatus(uid, func(p *core.Pod) {
+		p.Status.Phase = core.PodFailed
+		p.Status.Reason = core.PodReasonEvicted
+		p.Status.Message = message
+		for i := range p.Status.ContainerStatuses {
+			status := &p.Status.ContainerStatuses[i]
+			if status.State.Terminated == nil {
+				terminated := &core.ContainerStateTerminated{
+					ExitCode:    137,
+					Reason:      core.PodReasonEvicted,
+					FinishedAt:  time.Now(),
+					ContainerID: status.ContainerID,
+				}
+				if status.State.Running != nil {
+					terminated.StartedAt = status.State.Running.StartedAt
+				}
+				status.State = core.ContainerState{Terminated: terminated}
+			}
+			status.Ready = false
+		}
+		setPodReady(&p.Status, false)
+	})
+}
+
+// reclaimImages removes all unused images to relieve disk pressure
+func (k *kubelet) reclaimImages(ctx context.Context) error {
+	freed, err := k.imageGCManager.DeleteUnusedImages(ctx, k.usedImages())
+	if err != nil {
+		return err
+	}
+	logger.KubeletLogger.Printf("Removed unused images of %d bytes under disk pressure\n", freed)
+	return nil
+}
@@ -0,0 +1,161 @@
+package eviction
+
+import (
+	"context"
+	"minik8s/pkg/api/core"
+	"sync"
+	"time"
+)
+
+// Manager evicts pods when resources of node run low
+type Manager interface {
+	// Synchronize observes signals and evicts at most one pod if any threshold is
+	// met, disk is reclaimed before evicting pods for disk pressure. It returns
+	// the evicted pod, nil if no pod is evicted.
+	Synchronize(ctx context.Context) (*core.Pod, error)
+	// IsUnderMemoryPressure returns true if node is under memory pressure
+	IsUnderMemoryPressure() bool
+	// IsUnderDiskPressure returns true if node is under disk pressure
+	IsUnderDiskPressure() bool
+}
+
+type manager struct {
+	config     Config
+	stats      StatsProvider
+	activePods ActivePodsFunc
+	killPod    KillPodFunc
+	reclaim    ReclaimFunc
+	clock      func() time.Time
+
+	lock sync.RWMutex
+	// time thresholds are first met since they were not
+	thresholdsFirstObservedAt map[Threshold]time.Time
+	// time node conditions are last observed
+	nodeConditionsLastObservedAt map[core.NodeConditionType]time.Time
+	// node conditions reported currently
+	nodeConditions map[core.NodeConditionType]bool
+}
+
+// NewManager returns eviction manager, reclaim is called under disk pressure
+// before pods are evicted
+func NewManager(config Config, stats StatsProvider, activePods ActivePodsFunc, killPod KillPodFunc, reclaim ReclaimFunc) Manager {
+	return &manager{
+		config:                       config,
+		stats:                        stats,
+		activePods:                   activePods,
+		killPod:                      killPod,
+		reclaim:                      reclaim,
+		clock:                        time.Now,
+		thresholdsFirstObservedAt:    make(map[Threshold]time.Time),
+		nodeConditionsLastObservedAt: make(map[core.NodeConditionType]time.Time),
+		nodeConditions:               make(map[core.NodeConditionType]bool),
+	}
+}
+
+func (m *manager) IsUnderMemoryPressure() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.nodeConditions[core.NodeMemoryPressure]
+}
+
+func (m *manager) IsUnderDiskPressure() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.nodeConditions[core.NodeDiskPressure]
+}
+
+func (m *manager) Synchronize(ctx context.Context) (*core.Pod, error) {
+	observations, err := m.stats.Observe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	threshold, found := m.updateThresholds(observations)
+	if !found {
+		return nil, nil
+	}
+
+	if threshold.Signal != SignalMemoryAvailable && m.reclaim != nil {
+		if err = m.reclaim(ctx); err != nil {
+			return nil, err
+		}
+		if observations, err = m.stats.Observe(ctx); err != nil {
+			return nil, err
+		}
+		if len(thresholdsMet([]Threshold{threshold}, observations)) == 0 {
+			return nil, nil
+		}
+	}
+
+	// terminated pods use no memory and are never evicted
+	pods := make([]*core.Pod, 0)
+	for _, pod := range m.activePods() {
+		if !pod.IsTerminated() {
+			pods = append(pods, pod)
+		}
+	}
+	if len(pods) == 0 {
+		return nil, nil
+	}
+	rankForEviction(pods, threshold.Signal, m.stats)
+
+	// pod evicted by hard threshold is killed at once
+	var gracePeriod time.Duration
+	if !threshold.isHard() {
+		gracePeriod = m.config.MaxPodGracePeriod
+		if seconds := pods[0].Spec.TerminationGracePeriodSeconds; seconds != nil && time.Duration(*seconds)*time.Second < gracePeriod {
+			gracePeriod = time.Duration(*seconds) * time.Second
+		}
+	}
+	pod := pods[0]
+	if err = m.killPod(pod, gracePeriod, evictionMessage(threshold, observations[threshold.Signal])); err != nil {
+		return nil, err
+	}
+	return pod, nil
+}
+
+// updateThresholds records thresholds met and node conditions, it returns the
+// threshold to evict pods for, whose grace period has passed since it is met
+func (m *manager) updateThresholds(observations map[Signal]Observation) (Threshold, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	now := m.clock()
+
+	met := thresholdsMet(m.config.Thresholds, observations)
+	firstObservedAt := make(map[Threshold]time.Time)
+	for _, threshold := range met {
+		firstObservedAt[threshold] = now
+		if at, found := m.thresholdsFirstObservedAt[threshold]; found {
+			firstObservedAt[threshold] = at
+		}
+		m.nodeConditionsLastObservedAt[signalToNodeCondition[threshold.Signal]] = now
+	}
+	m.thresholdsFirstObservedAt = firstObservedAt
+
+	// node reports pressure until thresholds are not met for transition period
+	for condition, at := range m.nodeConditionsLastObservedAt {
+		m.nodeConditions[condition] = now.Sub(at) < m.config.PressureTransitionPeriod || at.Equal(now)
+	}
+
+	var evict *Threshold
+	for i, threshold := range met {
+		if now.Sub(firstObservedAt[threshold]) < threshold.GracePeriod {
+			continue
+		}
+		if evict == nil || evictsBefore(threshold, *evict) {
+			evict = &met[i]
+		}
+	}
+	if evict == nil {
+		return Threshold{}, false
+	}
+	return *evict, true
+}
+
+// evictsBefore returns true if pods are evicted for threshold a before b,
+// hard thresholds come first, and memory is reclaimed before disk
+func evictsBefore(a, b Threshold) bool {
+	if a.isHard() != b.isHard() {
+		return a.isHard()
+	}
+	return a.Signal == SignalMemoryAvailable && b.Signal != SignalMemoryAvailable
+}
@@ -0,0 +1,171 @@
+package eviction
+
+import (
+	"context"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/meta"
+	"minik8s/pkg/api/types"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseThresholds(t *testing.T) {
+	tests := []struct {
+		name         string
+		hard         map[string]string
+		soft         map[string]string
+		gracePeriods map[string]string
+		want         []Threshold
+		wantErr      bool
+	}{
+		{
+			name:         "hard before soft",
+			hard:         map[string]string{"nodefs.available": "10%", "memory.available": "100M"},
+			soft:         map[string]string{"memory.available": "300"},
+			gracePeriods: map[string]string{"memory.available": "1m30s"},
+			want: []Threshold{
+				{Signal: SignalMemoryAvailable, Value: ThresholdValue{Quantity: 100 << 20}},
+				{Signal: SignalNodeFsAvailable, Value: ThresholdValue{Percentage: 0.1}},
+				{Signal: SignalMemoryAvailable, Value: ThresholdValue{Quantity: 300 << 20}, GracePeriod: 90 * time.Second},
+			},
+		},
+		{name: "unknown signal", hard: map[string]string{"pid.available": "10%"}, wantErr: true},
+		{name: "invalid percentage", hard: map[string]string{"imagefs.available": "120%"}, wantErr: true},
+		{name: "invalid quantity", hard: map[string]string{"memory.available": "1Gi"}, wantErr: true},
+		{name: "soft without grace period", soft: map[string]string{"memory.available": "100M"}, wantErr: true},
+		{name: "grace period without soft", gracePeriods: map[string]string{"memory.available": "1m"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseThresholds(tt.hard, tt.soft, tt.gracePeriods)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseThresholds() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseThresholds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeStats struct {
+	observations map[Signal]Observation
+	memory       map[string]uint64
+}
+
+func (s *fakeStats) Observe(ctx context.Context) (map[Signal]Observation, error) {
+	return s.observations, nil
+}
+
+func (s *fakeStats) PodMemoryUsage(pod *core.Pod) (uint64, error) {
+	return s.memory[pod.Name], nil
+}
+
+func newPod(name string, priority int32, requests, limits core.ResourceList) *core.Pod {
+	return &core.Pod{
+		ObjectMeta: meta.ObjectMeta{Name: name, UID: name},
+		Spec: core.PodSpec{
+			Priority: &priority,
+			Containers: []core.Container{{
+				Name:      "app",
+				Resources: core.ResourceRequirements{Requests: requests, Limits: limits},
+			}},
+		},
+	}
+}
+
+func TestSynchronize(t *testing.T) {
+	memory := core.ResourceList{types.ResourceMemory: "100M"}
+	guaranteed := core.ResourceList{types.ResourceCPU: "100m", types.ResourceMemory: "100M"}
+	completed := newPod("completed", 0, nil, nil)
+	completed.Status.Phase = core.PodSucceeded
+	pods := []*core.Pod{
+		completed,
+		newPod("guaranteed", 0, guaranteed, guaranteed),
+		newPod("burstable-high", 10, memory, nil),
+		newPod("burstable-small", 0, memory, nil),
+		newPod("burstable-large", 0, memory, nil),
+	}
+	stats := &fakeStats{
+		observations: map[Signal]Observation{
+			SignalMemoryAvailable:  {Available: 1 << 30, Capacity: 8 << 30},
+			SignalImageFsAvailable: {Available: 50 << 30, Capacity: 100 << 30},
+		},
+		memory: map[string]uint64{"burstable-small": 150 << 20, "burstable-large": 500 << 20},
+	}
+	thresholds, err := ParseThresholds(
+		map[string]string{"memory.available": "500M", "imagefs.available": "15%"},
+		map[string]string{"memory.available": "2048M"},
+		map[string]string{"memory.available": "1m"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var killed []string
+	var gracePeriods []time.Duration
+	reclaimed := 0
+	m := NewManager(Config{Thresholds: thresholds, PressureTransitionPeriod: 5 * time.Minute, MaxPodGracePeriod: 30 * time.Second}, stats,
+		func() []*core.Pod {
+			return append([]*core.Pod{}, pods...)
+		},
+		func(pod *core.Pod, gracePeriod time.Duration, message string) error {
+			killed = append(killed, pod.Name)
+			gracePeriods = append(gracePeriods, gracePeriod)
+			for i := range pods {
+				if pods[i] == pod {
+					pods = append(pods[:i], pods[i+1:]...)
+					break
+				}
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			reclaimed++
+			stats.observations[SignalImageFsAvailable] = Observation{Available: 50 << 30, Capacity: 100 << 30}
+			return nil
+		},
+	).(*manager)
+	now := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	m.clock = func() time.Time { return now }
+	ctx := context.Background()
+
+	// soft threshold is met, but not for its grace period
+	if pod, _ := m.Synchronize(ctx); pod != nil || !m.IsUnderMemoryPressure() || m.IsUnderDiskPressure() {
+		t.Fatalf("Synchronize() evicts %v, memory pressure %v, disk pressure %v", pod, m.IsUnderMemoryPressure(), m.IsUnderDiskPressure())
+	}
+	now = now.Add(time.Minute)
+	if _, err = m.Synchronize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// hard threshold is met, lower priority and larger usage beyond request go
+	// first, completed best-effort pod is never evicted
+	stats.observations[SignalMemoryAvailable] = Observation{Available: 100 << 20, Capacity: 8 << 30}
+	for i := 0; i < 3; i++ {
+		now = now.Add(10 * time.Second)
+		if _, err = m.Synchronize(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []string{"burstable-large", "burstable-small", "burstable-high", "guaranteed"}; !reflect.DeepEqual(killed, want) {
+		t.Errorf("evicted pods %v, want %v", killed, want)
+	}
+	if want := []time.Duration{30 * time.Second, 0, 0, 0}; !reflect.DeepEqual(gracePeriods, want) {
+		t.Errorf("grace periods %v, want %v", gracePeriods, want)
+	}
+
+	// pressure is reported for transition period after thresholds are not met,
+	// disk is reclaimed before eviction
+	stats.observations[SignalMemoryAvailable] = Observation{Available: 4 << 30, Capacity: 8 << 30}
+	stats.observations[SignalImageFsAvailable] = Observation{Available: 5 << 30, Capacity: 100 << 30}
+	now = now.Add(time.Minute)
+	if pod, _ := m.Synchronize(ctx); pod != nil || reclaimed != 1 || !m.IsUnderMemoryPressure() || !m.IsUnderDiskPressure() {
+		t.Errorf("Synchronize() evicts %v, reclaimed %d times, memory pressure %v, disk pressure %v", pod, reclaimed, m.IsUnderMemoryPressure(), m.IsUnderDiskPressure())
+	}
+	now = now.Add(4*time.Minute + 30*time.Second)
+	if _, err = m.Synchronize(ctx); err != nil || m.IsUnderMemoryPressure() || !m.IsUnderDiskPressure() {
+		t.Errorf("memory pressure %v, disk pressure %v", m.IsUnderMemoryPressure(), m.IsUnderDiskPressure())
+	}
+}
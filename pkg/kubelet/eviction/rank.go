@@ -0,0 +1,50 @@
+package eviction
+
+import (
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/kubelet/qos"
+	"sort"
+)
+
+// qosOrder is order of qos classes in which pods are evicted
+var qosOrder = map[core.PodQOSClass]int{
+	core.PodQOSBestEffort: 0,
+	core.PodQOSBurstable:  1,
+	core.PodQOSGuaranteed: 2,
+}
+
+// rankForEviction sorts pods in order they are evicted for signal: by qos class
+// from BestEffort to Guaranteed, then by priority from low to high. Under memory
+// pressure, pods using more memory beyond their requests are evicted first.
+func rankForEviction(pods []*core.Pod, signal Signal, stats StatsProvider) {
+	exceeds := make(map[*core.Pod]int64)
+	if signal == SignalMemoryAvailable {
+		for _, pod := range pods {
+			usage, err := stats.PodMemoryUsage(pod)
+			if err != nil {
+				continue
+			}
+			exceeds[pod] = int64(usage) - int64(podMemoryRequest(pod))
+		}
+	}
+
+	sort.SliceStable(pods, func(i, j int) bool {
+		a, b := pods[i], pods[j]
+		if qa, qb := qosOrder[qos.GetPodQOS(a)], qosOrder[qos.GetPodQOS(b)]; qa != qb {
+			return qa < qb
+		}
+		if pa, pb := a.Spec.GetPriority(), b.Spec.GetPriority(); pa != pb {
+			return pa < pb
+		}
+		return exceeds[a] > exceeds[b]
+	})
+}
+
+// podMemoryRequest returns memory requested by app containers of pod in bytes
+func podMemoryRequest(pod *core.Pod) uint64 {
+	var request uint64
+	for i := range pod.Spec.Containers {
+		request += qos.MemoryRequest(&pod.Spec.Containers[i])
+	}
+	return request
+}
@@ -0,0 +1,82 @@
+package eviction
+
+import (
+	"context"
+	"fmt"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/cadvisor"
+	"minik8s/pkg/kubelet/cm"
+	"minik8s/pkg/kubelet/container/cri"
+
+	info "github.com/google/cadvisor/info/v1"
+)
+
+// statsProvider observes memory and nodefs from cadvisor, and imagefs from container runtime
+type statsProvider struct {
+	cadvisorClient      cadvisor.Interface
+	runtime             cri.Client
+	podContainerManager cm.PodContainerManager
+}
+
+func NewStatsProvider(cadvisorClient cadvisor.Interface, runtime cri.Client, podContainerManager cm.PodContainerManager) StatsProvider {
+	return &statsProvider{
+		cadvisorClient:      cadvisorClient,
+		runtime:             runtime,
+		podContainerManager: podContainerManager,
+	}
+}
+
+// latestStats returns the latest stats of cgroup named name in cadvisor
+func (p *statsProvider) latestStats(name string) (*info.ContainerStats, error) {
+	containerInfo, err := p.cadvisorClient.ContainerInfo(name, &info.ContainerInfoRequest{NumStats: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(containerInfo.Stats) == 0 {
+		return nil, fmt.Errorf("no stats of %s in cadvisor", name)
+	}
+	return containerInfo.Stats[len(containerInfo.Stats)-1], nil
+}
+
+func (p *statsProvider) Observe(ctx context.Context) (map[Signal]Observation, error) {
+	machineInfo, err := p.cadvisorClient.MachineInfo()
+	if err != nil {
+		return nil, err
+	}
+	stats, err := p.latestStats("/")
+	if err != nil {
+		return nil, err
+	}
+
+	observations := make(map[Signal]Observation)
+	var available uint64
+	if stats.Memory.WorkingSet < machineInfo.MemoryCapacity {
+		available = machineInfo.MemoryCapacity - stats.Memory.WorkingSet
+	}
+	observations[SignalMemoryAvailable] = Observation{Available: available, Capacity: machineInfo.MemoryCapacity}
+
+	// cadvisor does not tell which filesystem is root,
+	// use the largest one as node filesystem
+	var nodeFs *info.FsStats
+	for i := range stats.Filesystem {
+		if nodeFs == nil || stats.Filesystem[i].Limit > nodeFs.Limit {
+			nodeFs = &stats.Filesystem[i]
+		}
+	}
+	if nodeFs != nil {
+		observations[SignalNodeFsAvailable] = Observation{Available: nodeFs.Available, Capacity: nodeFs.Limit}
+	}
+
+	if imageFs, err := p.runtime.ImageFsInfo(ctx); err == nil {
+		observations[SignalImageFsAvailable] = Observation{Available: imageFs.Available, Capacity: imageFs.Capacity}
+	}
+	return observations, nil
+}
+
+func (p *statsProvider) PodMemoryUsage(pod *core.Pod) (uint64, error) {
+	stats, err := p.latestStats(p.podContainerManager.GetPodCgroupPath(pod))
+	if err != nil {
+		return 0, err
+	}
+	return stats.Memory.WorkingSet, nil
+}
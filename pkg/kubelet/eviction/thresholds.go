@@ -0,0 +1,125 @@
+package eviction
+
+import (
+	"fmt"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	units "github.com/docker/go-units"
+)
+
+// signalToNodeCondition is node condition reported when threshold of signal is met
+var signalToNodeCondition = map[Signal]core.NodeConditionType{
+	SignalMemoryAvailable:  core.NodeMemoryPressure,
+	SignalNodeFsAvailable:  core.NodeDiskPressure,
+	SignalImageFsAvailable: core.NodeDiskPressure,
+}
+
+// ParseThresholds parses hard and soft thresholds keyed by signal, e.g.
+// "memory.available": "100M" or "nodefs.available": "10%", quantities are in
+// unit of types.ParseQuantity. Each soft threshold must have a grace period,
+// e.g. "memory.available": "1m30s".
+func ParseThresholds(hard, soft, gracePeriods map[string]string) ([]Threshold, error) {
+	thresholds := make([]Threshold, 0, len(hard)+len(soft))
+	for signal, value := range hard {
+		threshold, err := parseThreshold(signal, value)
+		if err != nil {
+			return nil, err
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	for signal, value := range soft {
+		threshold, err := parseThreshold(signal, value)
+		if err != nil {
+			return nil, err
+		}
+		gracePeriod, found := gracePeriods[signal]
+		if !found {
+			return nil, fmt.Errorf("soft eviction threshold %s has no grace period", signal)
+		}
+		if threshold.GracePeriod, err = time.ParseDuration(gracePeriod); err != nil {
+			return nil, fmt.Errorf("invalid grace period of soft eviction threshold %s: %v", signal, err)
+		}
+		if threshold.GracePeriod <= 0 {
+			return nil, fmt.Errorf("grace period of soft eviction threshold %s must be positive", signal)
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	for signal := range gracePeriods {
+		if _, found := soft[signal]; !found {
+			return nil, fmt.Errorf("grace period of %s has no soft eviction threshold", signal)
+		}
+	}
+
+	// hard thresholds first, then by signal
+	sort.Slice(thresholds, func(i, j int) bool {
+		a, b := thresholds[i], thresholds[j]
+		if (a.GracePeriod == 0) != (b.GracePeriod == 0) {
+			return a.GracePeriod == 0
+		}
+		return a.Signal < b.Signal
+	})
+	return thresholds, nil
+}
+
+func parseThreshold(signal string, value string) (Threshold, error) {
+	threshold := Threshold{Signal: Signal(signal)}
+	if _, found := signalToNodeCondition[threshold.Signal]; !found {
+		return threshold, fmt.Errorf("unknown eviction signal %s", signal)
+	}
+	if strings.HasSuffix(value, "%") {
+		percentage, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil || percentage <= 0 || percentage > 100 {
+			return threshold, fmt.Errorf("invalid percentage %s of eviction threshold %s", value, signal)
+		}
+		threshold.Value.Percentage = percentage / 100
+		return threshold, nil
+	}
+	quantity, err := types.ParseQuantity(types.ResourceMemory, types.Quantity(value))
+	if err != nil || quantity == 0 {
+		return threshold, fmt.Errorf("invalid quantity %s of eviction threshold %s", value, signal)
+	}
+	threshold.Value.Quantity = quantity * 1024 * 1024
+	return threshold, nil
+}
+
+// quantity returns threshold value in bytes for resource of capacity
+func (t Threshold) quantity(capacity uint64) uint64 {
+	if t.Value.Quantity != 0 {
+		return t.Value.Quantity
+	}
+	return uint64(float64(capacity) * t.Value.Percentage)
+}
+
+func (t Threshold) isHard() bool {
+	return t.GracePeriod == 0
+}
+
+// thresholdsMet returns thresholds whose signals are observed less than them
+func thresholdsMet(thresholds []Threshold, observations map[Signal]Observation) []Threshold {
+	met := make([]Threshold, 0)
+	for _, threshold := range thresholds {
+		observed, found := observations[threshold.Signal]
+		if !found {
+			continue
+		}
+		if observed.Available < threshold.quantity(observed.Capacity) {
+			met = append(met, threshold)
+		}
+	}
+	return met
+}
+
+// evictionMessage explains why pod is evicted for threshold
+func evictionMessage(threshold Threshold, observed Observation) string {
+	resource := "memory"
+	if threshold.Signal != SignalMemoryAvailable {
+		resource = "ephemeral-storage"
+	}
+	return fmt.Sprintf("The node was low on resource: %s. Threshold quantity: %s, available: %s.",
+		resource, units.BytesSize(float64(threshold.quantity(observed.Capacity))), units.BytesSize(float64(observed.Available)))
+}
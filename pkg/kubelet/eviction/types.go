@@ -0,0 +1,70 @@
+package eviction
+
+import (
+	"context"
+	"minik8s/pkg/api/core"
+	"time"
+)
+
+// Signal is a resource of node whose availability is monitored for eviction
+type Signal string
+
+const (
+	// SignalMemoryAvailable is memory of node not used by any process
+	SignalMemoryAvailable Signal = "memory.available"
+	// SignalNodeFsAvailable is free space of filesystem of kubelet and volumes
+	SignalNodeFsAvailable Signal = "nodefs.available"
+	// SignalImageFsAvailable is free space of filesystem of container runtime
+	SignalImageFsAvailable Signal = "imagefs.available"
+)
+
+// ThresholdValue is either an absolute quantity or a percentage of capacity
+type ThresholdValue struct {
+	// Quantity is in bytes
+	Quantity uint64
+	// Percentage is fraction of capacity in [0, 1], used if Quantity is zero
+	Percentage float64
+}
+
+// Threshold is met if available resource of signal is less than value. A hard
+// threshold evicts pods once it is met, a soft one evicts pods after it has
+// been met for its grace period
+type Threshold struct {
+	Signal      Signal
+	Value       ThresholdValue
+	GracePeriod time.Duration
+}
+
+// Observation is what is observed of a signal, in bytes
+type Observation struct {
+	Available uint64
+	Capacity  uint64
+}
+
+// StatsProvider observes signals of node and resource usage of pods
+type StatsProvider interface {
+	// Observe returns observations of signals, signals not observable are omitted
+	Observe(ctx context.Context) (map[Signal]Observation, error)
+	// PodMemoryUsage returns working set of memory of pod in bytes
+	PodMemoryUsage(pod *core.Pod) (uint64, error)
+}
+
+// ActivePodsFunc returns pods running on node, which may be evicted
+type ActivePodsFunc func() []*core.Pod
+
+// KillPodFunc stops pod within gracePeriod and reports it Evicted with message
+type KillPodFunc func(pod *core.Pod, gracePeriod time.Duration, message string) error
+
+// ReclaimFunc frees resources of node without evicting pods, e.g. removes unused images
+type ReclaimFunc func(ctx context.Context) error
+
+// Config is configuration of eviction manager
+type Config struct {
+	Thresholds []Threshold
+	// PressureTransitionPeriod is how long node reports pressure
+	// after thresholds are no longer met
+	PressureTransitionPeriod time.Duration
+	// MaxPodGracePeriod is the maximum grace period of pods evicted by soft thresholds,
+	// pods evicted by hard thresholds are killed with MinimumGracePeriod
+	MaxPodGracePeriod time.Duration
+}
@@ -0,0 +1,155 @@
+package kubelet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+	"log"
+	"minik8s/pkg/api"
+	"minik8s/pkg/kubelet/container/cri"
+	"minik8s/pkg/kubelet/streaming"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+/*---------------------------- Exec and Attach ----------------------------*/
+
+// streamQuery is query of exec and attach
+type streamQuery struct {
+	command []string
+	stdin   bool
+	stdout  bool
+	stderr  bool
+	tty     bool
+}
+
+func parseStreamQuery(values url.Values) (streamQuery, error) {
+	query := streamQuery{command: values[api.ExecCommandParam]}
+	params := []struct {
+		name  string
+		value *bool
+	}{
+		{api.ExecStdinParam, &query.stdin},
+		{api.ExecStdoutParam, &query.stdout},
+		{api.ExecStderrParam, &query.stderr},
+		{api.ExecTTYParam, &query.tty},
+	}
+	for _, param := range params {
+		v := values.Get(param.name)
+		if v == "" {
+			continue
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return query, fmt.Errorf("invalid %s %q", param.name, v)
+		}
+		*param.value = b
+	}
+
+	if !query.stdin && !query.stdout && !query.stderr {
+		return query, errors.New("you must specify at least one of stdin, stdout, stderr")
+	}
+	if query.tty && query.stderr {
+		return query, errors.New("stderr is merged into stdout if tty is set, it can not be requested")
+	}
+	return query, nil
+}
+
+// handleExec runs command in container, with streams multiplexed over websocket
+// GET /exec/{pod}/{container}?command=&stdin=&stdout=&stderr=&tty=
+func (k *kubelet) handleExec(c *gin.Context) {
+	query, err := parseStreamQuery(c.Request.URL.Query())
+	if err == nil && len(query.command) == 0 {
+		err = errors.New("command is required")
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "ERR", "error": err.Error()})
+		return
+	}
+	pod, container, found := k.getRequestedContainer(c)
+	if !found {
+		return
+	}
+
+	name := makePodContainerName(pod, container)
+	k.serveStreams(c, query, func(ctx context.Context, streams cri.StreamOptions) streaming.Status {
+		code, err := k.criClient.ContainerExecStream(ctx, name, query.command, streams)
+		if err != nil {
+			return streaming.Status{Status: streaming.StatusFailure, Message: err.Error()}
+		}
+		if code != 0 {
+			return streaming.Status{
+				Status:   streaming.StatusFailure,
+				Message:  fmt.Sprintf("command terminated with exit code %d", code),
+				ExitCode: code,
+			}
+		}
+		return streaming.Status{Status: streaming.StatusSuccess}
+	})
+}
+
+// handleAttach attaches to main process of container, with streams multiplexed
+// over websocket. Stdin can be attached only if container sets stdin, and the
+// output is tty only if container sets tty.
+// GET /attach/{pod}/{container}?stdin=&stdout=&stderr=&tty=
+func (k *kubelet) handleAttach(c *gin.Context) {
+	query, err := parseStreamQuery(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "ERR", "error": err.Error()})
+		return
+	}
+	pod, container, found := k.getRequestedContainer(c)
+	if !found {
+		return
+	}
+	if query.stdin && !container.Stdin {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "ERR", "error": fmt.Sprintf("stdin is not enabled for container %s", container.Name)})
+		return
+	}
+	if query.tty && !container.TTY {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "ERR", "error": fmt.Sprintf("tty is not enabled for container %s", container.Name)})
+		return
+	}
+	query.tty = container.TTY
+
+	name := makePodContainerName(pod, container)
+	k.serveStreams(c, query, func(ctx context.Context, streams cri.StreamOptions) streaming.Status {
+		if err := k.criClient.ContainerAttach(ctx, name, streams); err != nil {
+			return streaming.Status{Status: streaming.StatusFailure, Message: err.Error()}
+		}
+		return streaming.Status{Status: streaming.StatusSuccess}
+	})
+}
+
+// serveStreams upgrades request to websocket and runs remote command with
+// streams in query, status of the command is sent before connection is closed
+func (k *kubelet) serveStreams(c *gin.Context, query streamQuery, run func(ctx context.Context, streams cri.StreamOptions) streaming.Status) {
+	server := websocket.Server{Handler: func(ws *websocket.Conn) {
+		conn := streaming.NewConn(ws)
+		defer conn.Close()
+
+		ctx, serverStreams := conn.ServeStreams(context.Background(), query.stdin, query.stdout, query.stderr)
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		streams := cri.StreamOptions{
+			Stdout: serverStreams.Stdout,
+			Stderr: serverStreams.Stderr,
+			TTY:    query.tty,
+			Resize: serverStreams.Resize,
+		}
+		if serverStreams.Stdin != nil {
+			streams.Stdin = serverStreams.Stdin
+			defer serverStreams.Stdin.Close()
+		}
+
+		status := run(ctx, streams)
+		if err := conn.WriteStatus(status); err != nil {
+			log.Printf("[Kubelet] Write status of remote command error: %v\n", err)
+		}
+	}}
+	server.ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,154 @@
+package kubelet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/kubelet/images"
+	"minik8s/pkg/logger"
+	"time"
+)
+
+/*---------------------------- Images ----------------------------*/
+
+// Waiting reasons of containers whose image is not pulled
+const (
+	ErrImagePull      = "ErrImagePull"
+	ImagePullBackOff  = "ImagePullBackOff"
+	ErrImageNeverPull = "ErrImageNeverPull"
+)
+
+// imagePullBackOff is back-off of retrying failed pulls of an image
+type imagePullBackOff struct {
+	initial time.Duration
+	max     time.Duration
+}
+
+// next returns delay after a failure, given delay after the last one
+func (b imagePullBackOff) next(delay time.Duration) time.Duration {
+	switch {
+	case delay == 0:
+		return b.initial
+	case delay*2 > b.max:
+		return b.max
+	default:
+		return delay * 2
+	}
+}
+
+// pullImages pulls images of containers of pod before they are created, failed
+// pulls are reported to report and retried with back-off until they succeed,
+// it returns false if pod is deleted meanwhile
+func (k *kubelet) pullImages(ctx context.Context, pod *core.Pod, containers []core.Container, report func(reason string, message string)) bool {
+	for _, container := range containers {
+		var delay time.Duration
+		for {
+			k.lock.RLock()
+			_, found := k.podManager.GetPodByUID(pod.UID)
+			k.lock.RUnlock()
+			if !found {
+				return false
+			}
+
+			err := k.pullImage(ctx, pod, container, report)
+			if err == nil {
+				break
+			}
+			logger.KubeletLogger.Printf("Pull image %s of pod %s failed: %v\n", container.Image, pod.Name, err)
+			reason := ErrImagePull
+			if errors.Is(err, images.ErrImageNeverPull) {
+				reason = ErrImageNeverPull
+			}
+			report(reason, err.Error())
+			k.eventf(pod, core.EventTypeWarning, FailedToPullImage, "Failed to pull image %q: %v", container.Image, err)
+
+			// error is reported for at most half of back-off delay
+			delay = k.imageBackOff.next(delay)
+			errorPeriod := config.ImagePullErrorPeriod
+			if errorPeriod > delay/2 {
+				errorPeriod = delay / 2
+			}
+			time.Sleep(errorPeriod)
+			if reason == ErrImagePull {
+				report(ImagePullBackOff, fmt.Sprintf("Back-off pulling image %q", container.Image))
+				k.eventf(pod, core.EventTypeNormal, BackOffPullImage, "Back-off pulling image %q", container.Image)
+			}
+			time.Sleep(delay - errorPeriod)
+		}
+	}
+	return true
+}
+
+// pullImage pulls image of container with credentials of pod, its progress
+// is reported as ContainerCreating at most once in ImagePullProgressInterval
+func (k *kubelet) pullImage(ctx context.Context, pod *core.Pod, container core.Container, report func(reason string, message string)) error {
+	keyring, err := k.makeKeyring(pod)
+	if err != nil {
+		return err
+	}
+	var lastReport, pullStarted time.Time
+	progress := func(message string) {
+		if pullStarted.IsZero() {
+			pullStarted = time.Now()
+			k.eventf(pod, core.EventTypeNormal, PullingImage, "Pulling image %q", container.Image)
+		}
+		if time.Since(lastReport) < config.ImagePullProgressInterval {
+			return
+		}
+		lastReport = time.Now()
+		report(ContainerCreating, fmt.Sprintf("Pulling image %q: %s", container.Image, message))
+	}
+	_, err = images.EnsureImageExists(ctx, k.criClient, container, keyring, progress)
+	if err == nil && !pullStarted.IsZero() {
+		k.eventf(pod, core.EventTypeNormal, PulledImage, "Successfully pulled image %q in %v", container.Image, time.Since(pullStarted).Round(time.Millisecond))
+	}
+	return err
+}
+
+// makeKeyring reads credentials from image pull secrets of pod, secrets not
+// found are skipped as images may be pulled without them
+func (k *kubelet) makeKeyring(pod *core.Pod) (*images.Keyring, error) {
+	secrets := make([]*core.Secret, 0, len(pod.Spec.ImagePullSecrets))
+	for _, ref := range pod.Spec.ImagePullSecrets {
+		secret, err := k.getSecret(ref.Name)
+		if err != nil || secret == nil {
+			logger.KubeletLogger.Printf("Image pull secret %s of pod %s is not found: %v\n", ref.Name, pod.Name, err)
+			continue
+		}
+		secrets = append(secrets, secret)
+	}
+	return images.NewKeyring(secrets)
+}
+
+// garbageCollectImages removes unused images periodically when image
+// filesystem fills up, images of pods on this node are kept
+func (k *kubelet) garbageCollectImages(ctx context.Context) {
+	ticker := time.NewTicker(config.ImageGCPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := k.imageGCManager.GarbageCollect(ctx, k.usedImages()); err != nil {
+			logger.KubeletLogger.Printf("Image garbage collection failed: %v\n", err)
+		}
+	}
+}
+
+// usedImages returns images of pods known to kubelet
+func (k *kubelet) usedImages() []string {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	var used []string
+	for _, p := range k.podManager.GetPods() {
+		for _, container := range append(append([]core.Container{}, p.Spec.InitContainers...), p.Spec.Containers...) {
+			used = append(used, container.Image)
+		}
+	}
+	return used
+}
@@ -0,0 +1,128 @@
+package images
+
+import (
+	"encoding/json"
+	"fmt"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/kubelet/container/cri"
+	"sort"
+	"strings"
+)
+
+// defaultRegistry is registry of images whose name has no registry host
+const defaultRegistry = "docker.io"
+
+// dockerConfigJSON is content of ~/.docker/config.json kept in secrets
+// of type kubernetes.io/dockerconfigjson
+type dockerConfigJSON struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+type dockerConfigEntry struct {
+	Username      string `json:"username,omitempty"`
+	Password      string `json:"password,omitempty"`
+	Auth          string `json:"auth,omitempty"`
+	IdentityToken string `json:"identitytoken,omitempty"`
+}
+
+// Keyring is credentials of registries from image pull secrets of a pod
+type Keyring struct {
+	// entries are sorted by their keys, longer keys first, so that the
+	// most specific credential of an image is tried first
+	entries []keyringEntry
+}
+
+type keyringEntry struct {
+	// key is registry host with optional path of repositories, e.g. registry.io/team
+	key  string
+	auth cri.AuthConfig
+}
+
+// NewKeyring reads credentials from secrets in order, secrets not of type
+// kubernetes.io/dockerconfigjson are rejected
+func NewKeyring(secrets []*core.Secret) (*Keyring, error) {
+	keyring := &Keyring{}
+	for _, secret := range secrets {
+		if secret.Type != core.SecretTypeDockerConfigJson {
+			return nil, fmt.Errorf("secret %s is of type %s, not %s", secret.Name, secret.Type, core.SecretTypeDockerConfigJson)
+		}
+		cfg := dockerConfigJSON{}
+		if err := json.Unmarshal(secret.Data[core.DockerConfigJsonKey], &cfg); err != nil {
+			return nil, fmt.Errorf("parse %s of secret %s: %v", core.DockerConfigJsonKey, secret.Name, err)
+		}
+		for server, entry := range cfg.Auths {
+			keyring.entries = append(keyring.entries, keyringEntry{
+				key: normalizeRegistry(server),
+				auth: cri.AuthConfig{
+					Username:      entry.Username,
+					Password:      entry.Password,
+					Auth:          entry.Auth,
+					ServerAddress: server,
+					IdentityToken: entry.IdentityToken,
+				},
+			})
+		}
+	}
+	// stable sort keeps credentials of earlier secrets first for the same key
+	sort.SliceStable(keyring.entries, func(i, j int) bool {
+		return len(keyring.entries[i].key) > len(keyring.entries[j].key)
+	})
+	return keyring, nil
+}
+
+// Lookup returns credentials matching registry and repository of image,
+// in the order they should be tried
+func (k *Keyring) Lookup(image string) []cri.AuthConfig {
+	if k == nil {
+		return nil
+	}
+	repository := parseRepository(image)
+	var res []cri.AuthConfig
+	for _, entry := range k.entries {
+		if repository == entry.key || strings.HasPrefix(repository, entry.key+"/") {
+			res = append(res, entry.auth)
+		}
+	}
+	return res
+}
+
+// normalizeRegistry normalizes server address in docker config, e.g.
+// https://index.docker.io/v1/ to docker.io
+func normalizeRegistry(server string) string {
+	server = strings.TrimPrefix(server, "https://")
+	server = strings.TrimPrefix(server, "http://")
+	server = strings.TrimSuffix(server, "/")
+	for _, suffix := range []string{"/v1", "/v2"} {
+		server = strings.TrimSuffix(server, suffix)
+	}
+	host, path, _ := strings.Cut(server, "/")
+	switch host {
+	case "index.docker.io", "registry-1.docker.io":
+		host = defaultRegistry
+	}
+	if path == "" {
+		return host
+	}
+	return host + "/" + path
+}
+
+// parseRepository returns registry host and repository path of image without
+// tag or digest, e.g. nginx:1.25 is docker.io/library/nginx
+func parseRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	// a colon after the last slash separates tag, others are of host port
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+
+	host, path, found := strings.Cut(image, "/")
+	if !found || !(strings.ContainsAny(host, ".:") || host == "localhost") {
+		host, path = defaultRegistry, image
+	}
+	if host == defaultRegistry && !strings.Contains(path, "/") {
+		path = "library/" + path
+	}
+	return normalizeRegistry(host) + "/" + path
+}
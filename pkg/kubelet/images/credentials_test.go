@@ -0,0 +1,55 @@
+package images
+
+import (
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/meta"
+	"reflect"
+	"testing"
+)
+
+func TestKeyringLookup(t *testing.T) {
+	newSecret := func(name string, config string) *core.Secret {
+		return &core.Secret{
+			ObjectMeta: meta.ObjectMeta{Name: name},
+			Type:       core.SecretTypeDockerConfigJson,
+			Data:       map[string][]byte{core.DockerConfigJsonKey: []byte(config)},
+		}
+	}
+	keyring, err := NewKeyring([]*core.Secret{
+		newSecret("hub", `{"auths": {"https://index.docker.io/v1/": {"auth": "aHViOnB3"}}}`),
+		newSecret("private", `{"auths": {
+			"registry.io:5000": {"username": "all", "password": "pw"},
+			"registry.io:5000/team": {"username": "team", "password": "pw"}
+		}}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		image string
+		users []string
+	}{
+		{image: "nginx", users: []string{""}},
+		{image: "docker.io/library/nginx:1.25", users: []string{""}},
+		{image: "registry.io:5000/app:v1", users: []string{"all"}},
+		{image: "registry.io:5000/team/app@sha256:abc", users: []string{"team", "all"}},
+		{image: "registry.io:5000/teams/app", users: []string{"all"}},
+		{image: "quay.io/app", users: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			var users []string
+			for _, auth := range keyring.Lookup(tt.image) {
+				users = append(users, auth.Username)
+			}
+			if !reflect.DeepEqual(users, tt.users) {
+				t.Errorf("Lookup(%q) = credentials of %q, want %q", tt.image, users, tt.users)
+			}
+		})
+	}
+
+	if _, err = NewKeyring([]*core.Secret{{ObjectMeta: meta.ObjectMeta{Name: "opaque"}, Type: core.SecretTypeOpaque}}); err == nil {
+		t.Error("NewKeyring() accepts secret of type Opaque")
+	}
+}
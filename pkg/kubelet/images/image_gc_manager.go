@@ -0,0 +1,182 @@
+package images
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"minik8s/pkg/kubelet/container/cri"
+	"sort"
+	"sync"
+	"time"
+)
+
+// GCPolicy is policy of image garbage collection
+type GCPolicy struct {
+	// HighThresholdPercent is the percent of disk usage of image filesystem
+	// at or above which images are garbage collected
+	HighThresholdPercent int
+	// LowThresholdPercent is the percent of disk usage garbage collection
+	// frees images down to
+	LowThresholdPercent int
+	// MinAge is the minimum age of an unused image before it is removed
+	MinAge time.Duration
+}
+
+// ImageGCManager removes unused images when image filesystem fills up
+type ImageGCManager interface {
+	// GarbageCollect removes unused images least recently used first, if disk
+	// usage is at or above high threshold, until it is below low threshold.
+	// usedImages are images used by pods on the node, which are never removed.
+	GarbageCollect(ctx context.Context, usedImages []string) error
+	// DeleteUnusedImages removes all images not in use regardless of disk usage,
+	// to reclaim disk under disk pressure, and returns bytes freed
+	DeleteUnusedImages(ctx context.Context, usedImages []string) (int64, error)
+}
+
+// imageRecord is what manager knows about an image
+type imageRecord struct {
+	firstDetected time.Time
+	lastUsed      time.Time
+	size          int64
+	pinned        bool
+}
+
+type imageGCManager struct {
+	runtime cri.Client
+	policy  GCPolicy
+	clock   func() time.Time
+
+	lock sync.Mutex
+	// records of images in runtime, keyed by image id
+	images map[string]*imageRecord
+}
+
+func NewImageGCManager(runtime cri.Client, policy GCPolicy) (ImageGCManager, error) {
+	if policy.HighThresholdPercent < 0 || policy.HighThresholdPercent > 100 {
+		return nil, fmt.Errorf("invalid high threshold percent %d", policy.HighThresholdPercent)
+	}
+	if policy.LowThresholdPercent < 0 || policy.LowThresholdPercent > policy.HighThresholdPercent {
+		return nil, fmt.Errorf("invalid low threshold percent %d, it must be between 0 and high threshold", policy.LowThresholdPercent)
+	}
+	return &imageGCManager{
+		runtime: runtime,
+		policy:  policy,
+		clock:   time.Now,
+		images:  make(map[string]*imageRecord),
+	}, nil
+}
+
+// detectImages updates records of images in runtime, and returns ids of images in use
+func (m *imageGCManager) detectImages(ctx context.Context, usedImages []string) (map[string]bool, error) {
+	now := m.clock()
+	list, err := m.runtime.ListImages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	inUse := make(map[string]bool)
+	for _, image := range usedImages {
+		status, err := m.runtime.ImageStatus(ctx, image)
+		if err != nil {
+			return nil, err
+		}
+		if status != nil {
+			inUse[status.ID] = true
+		}
+	}
+
+	present := make(map[string]bool)
+	for _, image := range list {
+		present[image.ID] = true
+		record, found := m.images[image.ID]
+		if !found {
+			record = &imageRecord{firstDetected: now}
+			m.images[image.ID] = record
+		}
+		if inUse[image.ID] {
+			record.lastUsed = now
+		}
+		record.size = image.Size
+		record.pinned = image.Pinned
+	}
+	for id := range m.images {
+		if !present[id] {
+			delete(m.images, id)
+		}
+	}
+	return inUse, nil
+}
+
+func (m *imageGCManager) GarbageCollect(ctx context.Context, usedImages []string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	inUse, err := m.detectImages(ctx, usedImages)
+	if err != nil {
+		return err
+	}
+	fs, err := m.runtime.ImageFsInfo(ctx)
+	if err != nil {
+		return err
+	}
+	if fs.Capacity == 0 {
+		return fmt.Errorf("image filesystem has zero capacity")
+	}
+	usage := fs.Capacity - fs.Available
+	if usage*100 < uint64(m.policy.HighThresholdPercent)*fs.Capacity {
+		return nil
+	}
+
+	toFree := int64(usage) - int64(fs.Capacity*uint64(m.policy.LowThresholdPercent)/100)
+	freed := m.freeSpace(ctx, toFree, inUse)
+	if freed < toFree {
+		return fmt.Errorf("image garbage collection freed %d bytes, but %d bytes should be freed", freed, toFree)
+	}
+	return nil
+}
+
+func (m *imageGCManager) DeleteUnusedImages(ctx context.Context, usedImages []string) (int64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	inUse, err := m.detectImages(ctx, usedImages)
+	if err != nil {
+		return 0, err
+	}
+	return m.freeSpace(ctx, math.MaxInt64, inUse), nil
+}
+
+// freeSpace removes images not in use least recently used first until
+// toFree bytes are freed, and returns bytes freed
+func (m *imageGCManager) freeSpace(ctx context.Context, toFree int64, inUse map[string]bool) int64 {
+	now := m.clock()
+	candidates := make([]string, 0, len(m.images))
+	for id, record := range m.images {
+		if inUse[id] || record.pinned || now.Sub(record.firstDetected) < m.policy.MinAge {
+			continue
+		}
+		candidates = append(candidates, id)
+	}
+	// images never used are ordered by time they are detected
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := m.images[candidates[i]], m.images[candidates[j]]
+		if !a.lastUsed.Equal(b.lastUsed) {
+			return a.lastUsed.Before(b.lastUsed)
+		}
+		return a.firstDetected.Before(b.firstDetected)
+	})
+
+	var freed int64
+	for _, id := range candidates {
+		if freed >= toFree {
+			break
+		}
+		if err := m.runtime.RemoveImage(ctx, id); err != nil {
+			// image may be used by containers not known to kubelet
+			continue
+		}
+		freed += m.images[id].size
+		delete(m.images, id)
+	}
+	return freed
+}
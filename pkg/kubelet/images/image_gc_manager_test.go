@@ -0,0 +1,79 @@
+package images
+
+import (
+	"context"
+	"minik8s/pkg/kubelet/container/cri"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGarbageCollect(t *testing.T) {
+	runtime := cri.NewFake()
+	manager, err := NewImageGCManager(runtime, GCPolicy{HighThresholdPercent: 85, LowThresholdPercent: 80, MinAge: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	manager.(*imageGCManager).clock = func() time.Time { return now }
+	ctx := context.Background()
+
+	// a is never used, c is used before b
+	runtime.Capacity = 10000
+	for _, name := range []string{"a", "b", "c"} {
+		runtime.AddImage(name, 300)
+	}
+	if err = manager.GarbageCollect(ctx, []string{"b", "c"}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+	if err = manager.GarbageCollect(ctx, []string{"b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		capacity   uint64
+		usedImages []string
+		images     []string
+		wantErr    bool
+	}{
+		{
+			name:     "below high threshold",
+			capacity: 1200,
+			images:   []string{"a", "b", "c", "d"},
+		},
+		{
+			name:     "least recently used first",
+			capacity: 1000,
+			images:   []string{"b", "c", "d"},
+		},
+		{
+			name:       "images in use are kept",
+			capacity:   700,
+			usedImages: []string{"c"},
+			images:     []string{"c", "d"},
+		},
+		{
+			name:       "young images are kept",
+			capacity:   400,
+			usedImages: []string{"c"},
+			images:     []string{"c", "d"},
+			wantErr:    true,
+		},
+	}
+	// d is newer than min age
+	runtime.AddImage("d", 100)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtime.Capacity = tt.capacity
+			err := manager.GarbageCollect(ctx, tt.usedImages)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GarbageCollect() error = %v, want error %v", err, tt.wantErr)
+			}
+			if images := runtime.Images(); !reflect.DeepEqual(images, tt.images) {
+				t.Errorf("images = %v, want %v", images, tt.images)
+			}
+		})
+	}
+}
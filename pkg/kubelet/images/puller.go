@@ -0,0 +1,46 @@
+package images
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/kubelet/container/cri"
+)
+
+// ErrImageNeverPull is returned if image of container with pull policy
+// Never is not present
+var ErrImageNeverPull = errors.New("image is not present with pull policy of Never")
+
+// EnsureImageExists pulls image of container under its pull policy, which
+// is IfNotPresent if empty, and returns id of the image. Credentials of
+// keyring matching the image are tried in order, and the image is pulled
+// anonymously if none matches. Progress of pull is reported to progress.
+func EnsureImageExists(ctx context.Context, runtime cri.Client, container core.Container, keyring *Keyring, progress func(string)) (string, error) {
+	image := container.Image
+	if container.ImagePullPolicy != core.PullAlways {
+		status, err := runtime.ImageStatus(ctx, image)
+		if err != nil {
+			return "", err
+		}
+		if status != nil {
+			return status.ID, nil
+		}
+		if container.ImagePullPolicy == core.PullNever {
+			return "", fmt.Errorf("container %s image %s: %w", container.Name, image, ErrImageNeverPull)
+		}
+	}
+
+	auths := keyring.Lookup(image)
+	if len(auths) == 0 {
+		return runtime.PullImage(ctx, image, nil, progress)
+	}
+	var err error
+	for i := range auths {
+		var id string
+		if id, err = runtime.PullImage(ctx, image, &auths[i], progress); err == nil {
+			return id, nil
+		}
+	}
+	return "", err
+}
@@ -0,0 +1,199 @@
+package kubelet
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/kubelet/container/cri"
+	"minik8s/pkg/kubelet/qos"
+	"minik8s/pkg/logger"
+	"time"
+)
+
+/*---------------------------- Init Containers ----------------------------*/
+
+// PodInitializing is the waiting reason of init containers not run yet
+const PodInitializing = "PodInitializing"
+
+// initContainerPollInterval is the interval to check if init container exits
+const initContainerPollInterval = time.Second
+
+// runInitContainers runs init containers of pod one by one in sandbox of pod,
+// each of them must complete successfully before the next one starts.
+// Failed init container is restarted with back-off unless restart policy is Never,
+// in which case pod fails. It returns true if all init containers succeed.
+func (k *kubelet) runInitContainers(ctx context.Context, pod *core.Pod) bool {
+	if len(pod.Spec.InitContainers) == 0 {
+		return true
+	}
+
+	statuses := make([]core.ContainerStatus, len(pod.Spec.InitContainers))
+	for idx, container := range pod.Spec.InitContainers {
+		statuses[idx] = core.ContainerStatus{
+			Name:    container.Name,
+			State:   core.ContainerState{Waiting: &core.ContainerStateWaiting{Reason: PodInitializing}},
+			Image:   container.Image,
+			ImageID: container.Image,
+		}
+	}
+
+	for idx, container := range pod.Spec.InitContainers {
+		status := &statuses[idx]
+		state := &containerRestartState{}
+		created := false
+
+		for {
+			found, err := k.startInitContainer(ctx, pod, container, created)
+			if !found {
+				return false
+			}
+			if err != nil {
+				// failed init container is created or started again later
+				log.Println("[ERROR]: failed to start init container", container.Name, err.Error())
+				k.eventf(pod, core.EventTypeWarning, FailedToCreateContainer, "Error: %v", err)
+				status.State = core.ContainerState{Waiting: &core.ContainerStateWaiting{Reason: CreateContainerError, Message: err.Error()}}
+				k.updateInitContainerStatuses(pod, statuses, core.PodPending)
+				if !k.waitPod(ctx, pod, k.startRetryInterval) {
+					return false
+				}
+				continue
+			}
+			created = true
+			name := makePodContainerName(pod, container)
+			startedAt := time.Now()
+			if containerStatus, err := k.criClient.ContainerStatus(ctx, name); err == nil {
+				status.ContainerID = containerStatus.ID
+				if !containerStatus.StartedAt.IsZero() {
+					startedAt = containerStatus.StartedAt
+				}
+			}
+			status.State = core.ContainerState{Running: &core.ContainerStateRunning{StartedAt: startedAt}}
+			k.updateInitContainerStatuses(pod, statuses, core.PodPending)
+
+			exited, ok := k.waitInitContainer(ctx, pod, name)
+			if !ok {
+				return false
+			}
+			exitCode := exited.ExitCode
+			status.State = containerState(exited)
+
+			if exitCode == 0 {
+				status.Ready = true
+				k.updateInitContainerStatuses(pod, statuses, core.PodPending)
+				break
+			}
+
+			logger.KubeletLogger.Printf("Init container %s of pod %s exited with %d\n", container.Name, pod.Name, exitCode)
+			if pod.Spec.RestartPolicy == core.RestartPolicyNever {
+				k.updateInitContainerStatuses(pod, statuses, core.PodFailed)
+				return false
+			}
+
+			// init containers are restarted on failure no matter the policy is Always or OnFailure
+			now := time.Now()
+			state.observeTermination(now)
+			status.RestartCount++
+			status.LastTerminationState = status.State
+			if now.Before(state.nextRestart) {
+				status.State = core.ContainerState{
+					Waiting: &core.ContainerStateWaiting{
+						Reason:  CrashLoopBackOff,
+						Message: fmt.Sprintf("back-off %v restarting failed container %s", state.delay, container.Name),
+					},
+				}
+				k.updateInitContainerStatuses(pod, statuses, core.PodPending)
+				k.eventf(pod, core.EventTypeWarning, BackOffStartContainer, "Back-off restarting failed container %s", container.Name)
+				if !k.waitPod(ctx, pod, state.nextRestart.Sub(now)) {
+					return false
+				}
+			}
+			state.waiting = false
+			state.startedAt = time.Now()
+		}
+	}
+
+	pod.Status.InitContainerStatuses = statuses
+	return true
+}
+
+// startInitContainer creates init container at the first time, and starts
+// it again for restarts. It returns false if pod is deleted meanwhile
+func (k *kubelet) startInitContainer(ctx context.Context, pod *core.Pod, container core.Container, created bool) (bool, error) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	if _, found := k.podManager.GetPodByUID(pod.UID); !found {
+		return false, nil
+	}
+	if !created {
+		_, err := k.createContainer(ctx, pod, container)
+		return true, err
+	}
+
+	name := makePodContainerName(pod, container)
+	if err := k.criClient.ContainerStart(ctx, name); err != nil {
+		return true, fmt.Errorf("start container %s: %v", container.Name, err)
+	}
+	k.eventf(pod, core.EventTypeNormal, StartedContainer, "Started container %s", container.Name)
+	return true, nil
+}
+
+// waitInitContainer waits until init container exits and returns its status,
+// it returns false if pod is deleted meanwhile
+func (k *kubelet) waitInitContainer(ctx context.Context, pod *core.Pod, name string) (cri.ContainerStatus, bool) {
+	for {
+		k.lock.RLock()
+		_, found := k.podManager.GetPodByUID(pod.UID)
+		k.lock.RUnlock()
+		if !found {
+			return cri.ContainerStatus{}, false
+		}
+
+		status, err := k.criClient.ContainerStatus(ctx, name)
+		if err != nil {
+			logger.KubeletLogger.Printf("Inspect init container of pod %s error: %v\n", pod.Name, err)
+			return cri.ContainerStatus{}, false
+		}
+		if !status.Running {
+			return status, true
+		}
+		time.Sleep(initContainerPollInterval)
+	}
+}
+
+// updateInitContainerStatuses reports init container statuses and phase of pod
+func (k *kubelet) updateInitContainerStatuses(pod *core.Pod, statuses []core.ContainerStatus, phase core.PodPhase) {
+	uid, found := k.podStatusUID(pod)
+	if !found {
+		return
+	}
+	initialized := true
+	for _, status := range statuses {
+		initialized = initialized && status.State.Terminated != nil && status.State.Terminated.ExitCode == 0
+	}
+	err := k.updatePodStatus(uid, func(p *core.Pod) {
+		status := &p.Status
+		status.Phase = phase
+		status.QOSClass = qos.GetPodQOS(pod)
+		status.InitContainerStatuses = append([]core.ContainerStatus{}, statuses...)
+		if status.StartTime == nil {
+			status.StartTime = pod.Status.StartTime
+		}
+		setPodConditions(status, initialized, false)
+		// app containers wait for init containers
+		if len(status.ContainerStatuses) == 0 {
+			for _, container := range pod.Spec.Containers {
+				status.ContainerStatuses = append(status.ContainerStatuses, core.ContainerStatus{
+					Name:    container.Name,
+					State:   core.ContainerState{Waiting: &core.ContainerStateWaiting{Reason: PodInitializing}},
+					Image:   container.Image,
+					ImageID: container.Image,
+				})
+			}
+		}
+	})
+	if err != nil {
+		logger.KubeletLogger.Printf("Update init container statuses of pod %s error: %v\n", pod.Name, err)
+	}
+}
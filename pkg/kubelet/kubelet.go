@@ -3,6 +3,7 @@ package kubelet
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"minik8s/config"
 	"minik8s/pkg/api/core"
@@ -11,10 +12,18 @@ import (
 	"minik8s/pkg/apiclient"
 	client "minik8s/pkg/apiclient/interface"
 	"minik8s/pkg/apiclient/listwatch"
+	"minik8s/pkg/apiclient/record"
 	"minik8s/pkg/cadvisor"
+	"minik8s/pkg/kubelet/cm"
 	"minik8s/pkg/kubelet/constants"
 	"minik8s/pkg/kubelet/container/cri"
+	"minik8s/pkg/kubelet/eviction"
+	"minik8s/pkg/kubelet/images"
+	"minik8s/pkg/kubelet/lifecycle"
 	"minik8s/pkg/kubelet/pod"
+	"minik8s/pkg/kubelet/prober"
+	"minik8s/pkg/kubelet/qos"
+	"minik8s/pkg/kubelet/volume"
 	"minik8s/pkg/logger"
 	"reflect"
 	"sync"
@@ -24,6 +33,8 @@ import (
 type Kubelet interface {
 	Run()
 	Close()
+	// StartStaticPods runs static pods, it needs no ApiServer and may be called before Run
+	StartStaticPods(ctx context.Context)
 }
 
 func New(node *core.Node) (Kubelet, error) {
@@ -33,20 +44,111 @@ func New(node *core.Node) (Kubelet, error) {
 		return nil, err
 	}
 
-	criClient, err := cri.NewDocker()
+	nodeClient, err := apiclient.NewRESTClient(types.NodeObjectType)
 	if err != nil {
 		return nil, err
 	}
 
-	return &kubelet{
-		name:             "Kubelet", // FIXME: change to node name + Kubelet
-		podClient:        podClient,
-		podListerWatcher: listwatch.NewListWatchFromClient(podClient),
-		podManager:       pod.NewPodManager(),
-		criClient:        criClient,
-		cadvisorClient:   cadvisor.NewClient(config.CadvisorUrl(config.CadvisorHost)),
-		node:             node,
-	}, nil
+	configMapClient, err := apiclient.NewRESTClient(types.ConfigMapObjectType)
+	if err != nil {
+		return nil, err
+	}
+
+	secretClient, err := apiclient.NewRESTClient(types.SecretObjectType)
+	if err != nil {
+		return nil, err
+	}
+
+	pvcClient, err := apiclient.NewRESTClient(types.PersistentVolumeClaimObjectType)
+	if err != nil {
+		return nil, err
+	}
+
+	pvClient, err := apiclient.NewRESTClient(types.PersistentVolumeObjectType)
+	if err != nil {
+		return nil, err
+	}
+
+	criClient, err := cri.New(node.Spec.ContainerRuntime, node.Spec.ContainerRuntimeEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	cgroupManager, err := cm.NewCgroupManager(config.CgroupRoot, node.Spec.CgroupDriver)
+	if err != nil {
+		return nil, err
+	}
+	podContainerManager := cm.NewPodContainerManager(cgroupManager)
+	if err = podContainerManager.Start(); err != nil {
+		return nil, err
+	}
+
+	imageGCManager, err := images.NewImageGCManager(criClient, images.GCPolicy{
+		HighThresholdPercent: config.ImageGCHighThresholdPercent,
+		LowThresholdPercent:  config.ImageGCLowThresholdPercent,
+		MinAge:               config.ImageMinimumGCAge,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	thresholds, err := parseEvictionThresholds(node)
+	if err != nil {
+		return nil, err
+	}
+
+	runtimePods, err := listRuntimePods(context.Background(), criClient)
+	if err != nil {
+		return nil, err
+	}
+
+	staticPodPath := node.Spec.StaticPodPath
+	if staticPodPath == "" {
+		staticPodPath = config.StaticPodPath
+	}
+
+	k := &kubelet{
+		name:                "Kubelet", // FIXME: change to node name + Kubelet
+		podClient:           podClient,
+		nodeClient:          nodeClient,
+		configMapClient:     configMapClient,
+		secretClient:        secretClient,
+		pvcClient:           pvcClient,
+		pvClient:            pvClient,
+		podListerWatcher:    listwatch.NewListWatchFromClient(podClient),
+		podManager:          pod.NewPodManager(),
+		criClient:           criClient,
+		podContainerManager: podContainerManager,
+		imageGCManager:      imageGCManager,
+		imageBackOff:        imagePullBackOff{initial: config.ImagePullBackOffInitial, max: config.ImagePullBackOffMax},
+		startRetryInterval:  config.PodStartRetryInterval,
+		cadvisorClient:      cadvisor.NewClient(config.CadvisorUrl(config.CadvisorHost)),
+		node:                node,
+		restartStates:       make(map[string]*containerRestartState),
+		podVolumes:          make(map[types.UID]map[string]string),
+		evictedPods:         make(map[types.UID]bool),
+		staticPodPath:       staticPodPath,
+		staticPods:          make(map[types.UID]*core.Pod),
+		mirrorPods:          make(map[types.UID]types.UID),
+		runtimePods:         runtimePods,
+		recorder:            record.NewRecorder(Component, node.Name),
+	}
+	k.volumeManager = volume.NewManager(config.KubeletRootDir,
+		volume.NewEmptyDirPlugin(),
+		volume.NewHostPathPlugin(),
+		volume.NewConfigMapPlugin(k.getConfigMap),
+		volume.NewSecretPlugin(k.getSecret),
+		volume.NewPersistentVolumeClaimPlugin(node, k.getPersistentVolumeClaim, k.getPersistentVolume),
+	)
+	k.probeManager = prober.NewManager(criClient, k.handleProbeFailure)
+	k.handlerRunner = lifecycle.NewHandlerRunner(criClient)
+	k.evictionManager = eviction.NewManager(eviction.Config{
+		Thresholds:               thresholds,
+		PressureTransitionPeriod: config.EvictionPressureTransitionPeriod,
+		MaxPodGracePeriod:        config.EvictionMaxPodGracePeriod,
+	}, eviction.NewStatsProvider(k.cadvisorClient, criClient, podContainerManager), k.activePods, k.evictPod, k.reclaimImages)
+
+	return k, nil
 }
 
 func (k *kubelet) Close() {
@@ -56,11 +158,58 @@ type kubelet struct {
 	name             string
 	node             *core.Node
 	podClient        client.Interface
+	nodeClient       client.Interface
+	configMapClient  client.Interface
+	secretClient     client.Interface
+	pvcClient        client.Interface
+	pvClient         client.Interface
 	podListerWatcher listwatch.ListerWatcher
 	podManager       pod.Manager
 	criClient        cri.Client
+	probeManager     prober.Manager
+	handlerRunner    lifecycle.HandlerRunner
 	lock             sync.RWMutex
 	cadvisorClient   cadvisor.Interface
+	recorder         record.EventRecorder
+
+	// cgroups of pods limiting resources of their containers
+	podContainerManager cm.PodContainerManager
+
+	// unused images are removed when image filesystem fills up, failed
+	// pulls of images are retried with back-off
+	imageGCManager images.ImageGCManager
+	imageBackOff   imagePullBackOff
+
+	// containers failed to be created are retried after startRetryInterval
+	startRetryInterval time.Duration
+
+	// pods are evicted when node is short of memory or disk, evicted
+	// pods are not started again, keyed by pod uid
+	evictionManager eviction.Manager
+	evictedPods     map[types.UID]bool
+
+	// static pods run from manifests in staticPodPath keyed by uid, and uid
+	// of their mirror pods in ApiServer status is reported to
+	staticPodPath string
+	staticPods    map[types.UID]*core.Pod
+	mirrorLock    sync.Mutex
+	mirrorPods    map[types.UID]types.UID
+
+	// sandboxes and containers found in runtime when kubelet starts, keyed
+	// by pod uid, those of pods not adopted are removed after pods are listed
+	runtimePods map[types.UID]*runtimePod
+
+	// restart states of containers, keyed by container name in runtime
+	restartStates map[string]*containerRestartState
+
+	// host paths of volumes set up for pods, keyed by pod uid and volume name
+	volumeManager volume.Manager
+	podVolumes    map[types.UID]map[string]string
+
+	// node status reported by kubelet
+	nodeCapacity         core.ResourceList
+	nodeAllocatable      core.ResourceList
+	lastNodeStatusReport time.Time
 }
 
 func (k *kubelet) Run() {
@@ -75,6 +224,25 @@ func (k *kubelet) Run() {
 	// start cadvisor on current node
 	k.startCadvisorClient()
 
+	// report node capacity, allocatable and conditions
+	k.initNodeCapacity()
+	go k.syncNodeStatus(ctx)
+
+	// refresh files projected from ConfigMaps and Secrets
+	go k.syncConfigVolumes(ctx)
+
+	// delete released local-path volumes on this node
+	go k.reclaimVolumes(ctx)
+
+	// remove unused images when disk fills up
+	go k.garbageCollectImages(ctx)
+
+	// evict pods when node is short of memory or disk
+	go k.synchronizeEviction(ctx)
+
+	// serve container logs for ApiServer
+	go k.serve()
+
 	k.listPods(ctx)
 
 	// start watch pods
@@ -105,12 +273,21 @@ func (k *kubelet) listPods(ctx context.Context) {
 		log.Fatalln(err)
 	}
 
+	// mirror pods are not run, as their static pods are run from manifests
+	pods := make([]*core.Pod, 0)
 	for _, p := range podList.GetIApiObjectArr() {
-		k.podManager.UpdatePod(p.(*core.Pod))
+		if !p.(*core.Pod).IsMirrorPod() {
+			pods = append(pods, p.(*core.Pod))
+		}
 	}
-	for _, p := range podList.GetIApiObjectArr() {
-		go k.startWatchContainers(ctx, *p.(*core.Pod))
+
+	// pods left in runtime are adopted, others are created
+	for _, p := range pods {
+		if p.Spec.NodeName == k.node.Name {
+			k.handlePodModify(p)
+		}
 	}
+	k.removeOrphanPods(ctx)
 }
 
 func (k *kubelet) watchPods(ctx context.Context) {
@@ -150,8 +327,8 @@ loop:
 
 			p := event.Object.(*core.Pod)
 
-			// filter pod not belong to current node
-			if p.Spec.NodeName == k.node.Name {
+			// filter pod not belong to current node, and mirror pods
+			if p.Spec.NodeName == k.node.Name && !p.IsMirrorPod() {
 
 				log.Printf("[handleWatchPods] event %v\n", event)
 				log.Printf("[handleWatchPods] event object %v\n", event.Object)
@@ -190,7 +367,9 @@ func (k *kubelet) handlePodModify(pod *core.Pod) {
 	defer k.lock.Unlock()
 	old, found := k.podManager.GetPodByUID(pod.UID)
 	if !found {
-		k.createPod(pod)
+		if !k.evictedPods[pod.UID] && !pod.IsEvicted() {
+			k.createPod(pod)
+		}
 		return
 	}
 
@@ -204,9 +383,24 @@ func (k *kubelet) handlePodModify(pod *core.Pod) {
 	down := containersNew(pod.Spec.Containers, old.Spec.Containers)
 
 	ctx := context.Background()
-	k.removeContainers(ctx, pod, down)
-	k.createContainers(ctx, pod, up)
-	go k.startWatchContainers(ctx, *pod)
+	k.forgetContainers(pod, down)
+	go k.removeContainers(ctx, pod, down)
+	go k.startContainers(ctx, pod, up)
+}
+
+// startContainers pulls images of containers added to running pod, then starts them
+func (k *kubelet) startContainers(ctx context.Context, pod *core.Pod, containers []core.Container) {
+	report := func(reason string, message string) {
+		logger.KubeletLogger.Printf("Containers of pod %s are waiting: %s: %s\n", pod.Name, reason, message)
+	}
+	if !k.pullImages(ctx, pod, containers, report) {
+		return
+	}
+
+	if !k.runContainers(ctx, pod, containers) {
+		return
+	}
+	k.startWatchContainers(ctx, *pod)
 }
 
 func (k *kubelet) handlePodDelete(pod *core.Pod) {
@@ -215,31 +409,141 @@ func (k *kubelet) handlePodDelete(pod *core.Pod) {
 
 	logger.KubeletLogger.Printf("Pod %v delete on current node %v, start handle pod delete\n", pod.UID, k.node.Name)
 
+	// evicted pod is already killed
+	if k.evictedPods[pod.UID] {
+		delete(k.evictedPods, pod.UID)
+		return
+	}
+
 	old, find := k.podManager.GetPodByUID(pod.UID)
 	if !find {
 		log.Println("unconsistent delete")
 		return
 	}
-	ctx := context.Background()
-	k.removeContainers(ctx, old, old.Spec.Containers)
-	k.removeMasterContainer(ctx, pod)
+	k.forgetContainers(old, old.Spec.InitContainers)
+	k.forgetContainers(old, old.Spec.Containers)
+	delete(k.podVolumes, old.UID)
 
 	// delete pod in podManager
 	k.podManager.DeletePod(old)
+
+	// stopping containers may take as long as grace period
+	go k.killPod(context.Background(), old)
+}
+
+// killPod stops app containers gracefully, then removes init containers and sandbox
+func (k *kubelet) killPod(ctx context.Context, pod *core.Pod) {
+	k.removeContainers(ctx, pod, pod.Spec.Containers)
+	k.removeContainers(ctx, pod, pod.Spec.InitContainers)
+	k.removePodSandbox(ctx, pod)
+	k.tearDownVolumes(pod)
 }
 
 /*----------------------------  ----------------------------*/
 
 func (k *kubelet) createPod(pod *core.Pod) {
 
+	ctx := context.Background()
+	if k.adoptPod(ctx, pod) {
+		return
+	}
+	// pod finished before kubelet restarts is not run again
+	if isPodFinished(pod) {
+		k.podManager.AddPod(pod)
+		return
+	}
+
 	logger.KubeletLogger.Printf("New Pod %v bind to current node %v, start handle pod create on current machine\n", pod.UID, k.node.Name)
 
-	ctx := context.Background()
-	k.createMasterContainer(ctx, pod)
-	k.createContainers(ctx, pod, pod.Spec.Containers)
-	go k.startWatchContainers(ctx, *pod)
+	pod.Status.QOSClass = qos.GetPodQOS(pod)
+	setPodStartTime(&pod.Status)
 	// add pod to podManager
 	k.podManager.AddPod(pod)
+	// init containers may take long to complete, start pod in background
+	go k.startPod(ctx, pod)
+}
+
+// startPod pulls images of pod and runs init containers to completion,
+// then starts app containers
+func (k *kubelet) startPod(ctx context.Context, pod *core.Pod) {
+	if !k.setUpVolumes(pod) {
+		return
+	}
+	if !k.resolveContainerConfigs(pod) {
+		return
+	}
+	if !k.runPodSandbox(ctx, pod) {
+		return
+	}
+	report := func(reason string, message string) {
+		k.reportContainersWaiting(pod, reason, message)
+	}
+	containers := append(append([]core.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	if !k.pullImages(ctx, pod, containers, report) {
+		return
+	}
+	if !k.runInitContainers(ctx, pod) {
+		return
+	}
+
+	if !k.runContainers(ctx, pod, pod.Spec.Containers) {
+		return
+	}
+	k.startWatchContainers(ctx, *pod)
+}
+
+// runContainers creates containers of pod, those failed to be created are
+// retried until they run. It returns false if pod is deleted meanwhile
+func (k *kubelet) runContainers(ctx context.Context, pod *core.Pod, containers []core.Container) bool {
+	for {
+		k.lock.Lock()
+		if _, found := k.podManager.GetPodByUID(pod.UID); !found {
+			k.lock.Unlock()
+			return false
+		}
+		failed, err := k.createContainers(ctx, pod, containers)
+		k.lock.Unlock()
+		if len(failed) == 0 {
+			return true
+		}
+
+		k.reportContainersWaiting(pod, CreateContainerError, err.Error())
+		if !k.waitPod(ctx, pod, k.startRetryInterval) {
+			return false
+		}
+		containers = failed
+	}
+}
+
+// podWaitPollInterval is the interval to check if pod waiting for retry is deleted
+const podWaitPollInterval = time.Second
+
+// waitPod waits for d before pod is retried, it returns false as soon as
+// kubelet stops or pod is deleted meanwhile
+func (k *kubelet) waitPod(ctx context.Context, pod *core.Pod, d time.Duration) bool {
+	found := func() bool {
+		k.lock.RLock()
+		defer k.lock.RUnlock()
+		_, found := k.podManager.GetPodByUID(pod.UID)
+		return found
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	ticker := time.NewTicker(podWaitPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-timer.C:
+			return found()
+		case <-ticker.C:
+			if !found() {
+				return false
+			}
+		}
+	}
 }
 
 func containersNew(old []core.Container, new []core.Container) []core.Container {
@@ -264,67 +568,162 @@ func makePodContainerName(pod *core.Pod, container core.Container) string {
 	return pod.UID + "-" + container.Name
 }
 
-func (k *kubelet) createMasterContainer(ctx context.Context, pod *core.Pod) {
-	container := constants.InitialPauseContainer
-	container.Name = makePodContainerName(pod, container)
-	_, err := k.criClient.ContainerCreate(ctx, container)
-	if err != nil {
-		log.Fatalf("create failed %v", err)
+// makePodSandboxName is name of sandbox of pod, which is name of its pause
+// container for docker
+func makePodSandboxName(pod *core.Pod) string {
+	return makePodContainerName(pod, constants.InitialPauseContainer)
+}
+
+// runPodSandbox creates sandbox of pod unless it is adopted, failures are retried
+// until it is created. It returns false if pod is deleted meanwhile
+func (k *kubelet) runPodSandbox(ctx context.Context, pod *core.Pod) bool {
+	if _, err := k.criClient.PodSandboxStatus(ctx, makePodSandboxName(pod)); err == nil {
+		return true
+	}
+	for {
+		k.lock.Lock()
+		if _, found := k.podManager.GetPodByUID(pod.UID); !found {
+			k.lock.Unlock()
+			return false
+		}
+		err := k.createPodSandbox(ctx, pod)
+		k.lock.Unlock()
+		if err == nil {
+			return true
+		}
+
+		log.Println("[ERROR]: failed to create sandbox of pod", pod.Name, err.Error())
+		k.eventf(pod, core.EventTypeWarning, FailedCreatePodSandBox, "Failed to create pod sandbox: %v", err)
+		k.reportContainersWaiting(pod, ContainerCreating, err.Error())
+		if !k.waitPod(ctx, pod, k.startRetryInterval) {
+			return false
+		}
 	}
+}
 
-	if err := k.criClient.ContainerStart(ctx, container.Name); err != nil {
-		log.Fatalf("run failed")
+// createPodSandbox creates cgroup of pod and runs its sandbox in the cgroup,
+// it should be called with k.lock held
+func (k *kubelet) createPodSandbox(ctx context.Context, pod *core.Pod) error {
+	if err := k.podContainerManager.EnsureExists(pod); err != nil {
+		log.Println("[ERROR]: failed to create cgroup of pod", pod.Name, err.Error())
 	}
+	name := makePodSandboxName(pod)
+	_, err := k.criClient.RunPodSandbox(ctx, cri.SandboxConfig{
+		Name:         name,
+		PodName:      pod.Name,
+		PodNamespace: pod.Namespace,
+		PodUID:       pod.UID,
+		DNSServers:   []string{config.Host()},
+		CgroupParent: k.podContainerManager.GetPodCgroupParent(pod),
+		OOMScoreAdj:  qos.PodInfraOOMAdj,
+	})
+	if err != nil {
+		// sandbox partly created is removed, so that it is created again on retry
+		_ = k.criClient.RemovePodSandbox(ctx, name)
+		return fmt.Errorf("run pod sandbox: %v", err)
+	}
+	return nil
 }
 
-func (k *kubelet) createContainers(ctx context.Context, pod *core.Pod, containers []core.Container) {
+// createContainers creates and starts containers in sandbox of pod, it returns
+// containers failed to be created and the last error. It should be called
+// with k.lock held
+func (k *kubelet) createContainers(ctx context.Context, pod *core.Pod, containers []core.Container) ([]core.Container, error) {
+	created := make([]core.Container, 0, len(containers))
+	failed := make([]core.Container, 0)
+	var lastErr error
 	for _, container := range containers {
-		name := container.Name
-		container.Name = makePodContainerName(pod, container)
-		container.Master = k.criClient.ContainerId(ctx, makePodContainerName(pod, constants.InitialPauseContainer))
-		if container.Master == "" {
-			log.Fatalf("MissingMaster")
-		}
-		id, err := k.criClient.ContainerCreate(ctx, container)
+		id, err := k.createContainer(ctx, pod, container)
 		if err != nil {
-			log.Fatalf("create failed %v", err)
+			log.Println("[ERROR]: failed to create container", container.Name, err.Error())
+			k.eventf(pod, core.EventTypeWarning, FailedToCreateContainer, "Error: %v", err)
+			failed = append(failed, container)
+			lastErr = err
+			continue
 		}
+		created = append(created, container)
 		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, core.ContainerStatus{
-			Name: name,
-			State: core.ContainerState{
-				Waiting:    nil,
-				Running:    &core.ContainerStateRunning{},
-				Terminated: nil,
-			},
+			Name:        container.Name,
+			State:       core.ContainerState{Running: &core.ContainerStateRunning{StartedAt: time.Now()}},
 			Image:       container.Image,
 			ImageID:     container.Image,
 			ContainerID: id,
 		})
-		if err := k.criClient.ContainerStart(ctx, container.Name); err != nil {
-			log.Fatalf("run failed")
-		}
 	}
+	k.addProbes(ctx, pod, created)
+	return failed, lastErr
 }
 
-func (k *kubelet) removeContainers(ctx context.Context, pod *core.Pod, containers []core.Container) {
-	for _, container := range containers {
-		container.Name = makePodContainerName(pod, container)
-		container.Master = makePodContainerName(pod, constants.InitialPauseContainer)
-		err := k.criClient.ContainerRemove(ctx, container.Name)
-		if err != nil {
+// createContainer creates and starts container in sandbox of pod,
+// it should be called with k.lock held
+func (k *kubelet) createContainer(ctx context.Context, pod *core.Pod, spec core.Container) (string, error) {
+	mounts, err := k.makeMounts(pod, spec)
+	if err != nil {
+		return "", err
+	}
+	env, err := makeEnvironmentVariables(spec, k.getConfigMap, k.getSecret)
+	if err != nil {
+		return "", err
+	}
+	container := spec
+	container.Name = makePodContainerName(pod, container)
+	container.Env = env
+	id, err := k.criClient.ContainerCreate(ctx, makePodSandboxName(pod), container, k.makeContainerResources(pod, &spec), mounts)
+	if err != nil {
+		return "", fmt.Errorf("create container %s: %v", spec.Name, err)
+	}
+	k.eventf(pod, core.EventTypeNormal, CreatedContainer, "Created container %s", spec.Name)
+	if err := k.criClient.ContainerStart(ctx, container.Name); err != nil {
+		// container failed to start is removed, so that it is created again on retry
+		if err := k.criClient.ContainerRemove(ctx, container.Name); err != nil {
 			log.Println("[ERROR]: failed to remove container", container.Name, err.Error())
 		}
+		return "", fmt.Errorf("start container %s: %v", spec.Name, err)
 	}
+	k.eventf(pod, core.EventTypeNormal, StartedContainer, "Started container %s", spec.Name)
+	k.runPostStartHook(ctx, pod, spec)
+	return id, nil
 }
 
-func (k *kubelet) removeMasterContainer(ctx context.Context, pod *core.Pod) {
-	err := k.criClient.ContainerRemove(
-		ctx,
-		makePodContainerName(pod, constants.InitialPauseContainer),
-	)
-
+// addProbes starts probe workers for containers of pod
+func (k *kubelet) addProbes(ctx context.Context, pod *core.Pod, containers []core.Container) {
+	sandbox, err := k.criClient.PodSandboxStatus(ctx, makePodSandboxName(pod))
 	if err != nil {
-		log.Println("[ERROR]: failed to remove pod master container ", pod.Name, err.Error())
+		log.Println("[ERROR]: failed to get ip of pod", pod.Name, err.Error())
+		return
+	}
+	for _, container := range containers {
+		k.probeManager.AddContainer(pod, container, makePodContainerName(pod, container), sandbox.IP)
+	}
+}
+
+// handleProbeFailure stops container whose liveness or startup probe fails,
+// it is then restarted or not according to restart policy of pod
+func (k *kubelet) handleProbeFailure(pod *core.Pod, container core.Container, probeType prober.ProbeType, message string) {
+	logger.KubeletLogger.Printf("Container %s of pod %s failed %s probe: %s, stop it\n", container.Name, pod.Name, probeType, message)
+	k.eventf(pod, core.EventTypeWarning, ContainerUnhealthy, "%s probe of container %s failed: %s", probeType, container.Name, message)
+	k.eventf(pod, core.EventTypeNormal, KillingContainer, "Container %s failed %s probe, will be restarted", container.Name, probeType)
+	k.stopContainer(context.Background(), pod, container)
+}
+
+// forgetContainers stops probing containers and drops their restart states
+func (k *kubelet) forgetContainers(pod *core.Pod, containers []core.Container) {
+	for _, container := range containers {
+		k.probeManager.RemoveContainer(pod.UID, container.Name)
+		k.removeRestartState(makePodContainerName(pod, container))
+	}
+}
+
+func (k *kubelet) removePodSandbox(ctx context.Context, pod *core.Pod) {
+	name := makePodSandboxName(pod)
+	if err := k.criClient.StopPodSandbox(ctx, name); err != nil {
+		log.Println("[ERROR]: failed to stop pod sandbox ", pod.Name, err.Error())
+	}
+	if err := k.criClient.RemovePodSandbox(ctx, name); err != nil {
+		log.Println("[ERROR]: failed to remove pod sandbox ", pod.Name, err.Error())
+	}
+	if err := k.podContainerManager.Destroy(pod); err != nil {
+		log.Println("[ERROR]: failed to remove cgroup of pod ", pod.Name, err.Error())
 	}
 }
 
@@ -340,46 +739,25 @@ func (k *kubelet) startWatchContainers(ctx context.Context, pod core.Pod) {
 }
 
 func (k *kubelet) inspectContainer(ctx context.Context, pod core.Pod) error {
-	ip, err := k.criClient.ContainerIP(ctx, k.criClient.ContainerId(ctx, pod.UID+"-"+"pause"))
-
+	// container being restarted by probe failure is not inspected,
+	// pod deleted or evicted is no longer inspected
+	k.lock.RLock()
+	if _, found := k.podManager.GetPodByUID(pod.UID); !found {
+		k.lock.RUnlock()
+		return fmt.Errorf("pod %s is removed", pod.Name)
+	}
+	ncs, ip, err := k.containerStatuses(ctx, pod)
+	k.lock.RUnlock()
 	if err != nil {
 		return err
 	}
-	ncs := make([]core.ContainerStatus, 0)
-	for _, container := range pod.Spec.Containers {
-		ncs = append(ncs, core.ContainerStatus{
-			Name: container.Name,
-			State: core.ContainerState{
-				Waiting:    nil,
-				Running:    &core.ContainerStateRunning{},
-				Terminated: nil,
-			},
-			Image:       container.Image,
-			ImageID:     container.Image,
-			ContainerID: k.criClient.ContainerId(ctx, pod.UID+"-"+container.Name),
-		})
-	}
-	for idx, c := range ncs {
-		r, e, err := k.criClient.ContainerStatus(ctx, c.ContainerID)
-		if err != nil {
-			return err
-		}
-		if !r || err != nil {
-			ncs[idx].State = core.ContainerState{
-				Terminated: &core.ContainerStateTerminated{
-					ExitCode:    int32(e),
-					Signal:      0,
-					Reason:      "",
-					Message:     "",
-					ContainerID: c.ContainerID,
-				},
-			}
-		}
-	}
+	k.syncContainerRestarts(ctx, &pod, ncs)
+
+	// pod is finished only if all containers are terminated and not restarted
 	var ns core.PodPhase
 	t := true
 	for _, c := range ncs {
-		if c.State.Running != nil {
+		if c.State.Terminated == nil {
 			t = false
 		}
 	}
@@ -399,19 +777,81 @@ func (k *kubelet) inspectContainer(ctx context.Context, pod core.Pod) error {
 		ns = core.PodRunning
 	}
 
+	// phase of finished pod is kept in podManager, it is no longer active
+	if ns == core.PodSucceeded || ns == core.PodFailed {
+		k.lock.Lock()
+		if p, found := k.podManager.GetPodByUID(pod.UID); found {
+			p.Status.Phase = ns
+		}
+		k.lock.Unlock()
+	}
+
+	ready := ns == core.PodRunning
+	for _, c := range ncs {
+		ready = ready && c.Ready
+	}
+
 	if ns != pod.Status.Phase || ip != pod.Status.PodIP || !reflect.DeepEqual(ncs, pod.Status.ContainerStatuses) {
+		// status of static pod is reported once its mirror pod is created
+		uid, found := k.podStatusUID(&pod)
+		if !found {
+			return nil
+		}
 		pod.Status.Phase = ns
 		pod.Status.PodIP = ip
 		pod.Status.ContainerStatuses = ncs
-		r, err := k.podClient.Get(pod.UID)
+		err = k.updatePodStatus(uid, func(p *core.Pod) {
+			// spec of mirror pod is read-only
+			if !pod.IsStaticPod() && !reflect.DeepEqual(p.Spec, pod.Spec) {
+				return
+			}
+			status := pod.Status
+			status.Conditions = p.Status.Conditions
+			setPodConditions(&status, true, ready)
+			setPodStartTime(&status)
+			p.Status = status
+		})
+		// status is reported again on next inspection
 		if err != nil {
-			return err
-		}
-		rr := r.(*core.Pod)
-		if reflect.DeepEqual(rr.Spec, pod.Spec) && !reflect.DeepEqual(rr.Status, pod.Status) {
-			rr.Status = pod.Status
-			_, _, err = k.podClient.Put(pod.UID, rr)
+			logger.KubeletLogger.Printf("Update status of pod %s error: %v\n", pod.Name, err)
 		}
 	}
 	return nil
 }
+
+// containerStatuses inspects containers of pod in runtime, and
+// returns their statuses and ip of pod
+func (k *kubelet) containerStatuses(ctx context.Context, pod core.Pod) ([]core.ContainerStatus, string, error) {
+	sandbox, err := k.criClient.PodSandboxStatus(ctx, makePodSandboxName(&pod))
+	if err != nil {
+		return nil, "", err
+	}
+	ncs := make([]core.ContainerStatus, 0)
+	for _, container := range pod.Spec.Containers {
+		status, err := k.criClient.ContainerStatus(ctx, makePodContainerName(&pod, container))
+		if err != nil {
+			return nil, "", err
+		}
+		ncs = append(ncs, core.ContainerStatus{
+			Name:        container.Name,
+			State:       containerState(status),
+			Image:       container.Image,
+			ImageID:     container.Image,
+			ContainerID: status.ID,
+		})
+	}
+	for idx, container := range pod.Spec.Containers {
+		running := ncs[idx].State.Running != nil
+		started := running
+		if result, found := k.probeManager.GetResult(pod.UID, container.Name, prober.Startup); found {
+			started = running && result == prober.Success
+		}
+		ready := started
+		if result, found := k.probeManager.GetResult(pod.UID, container.Name, prober.Readiness); found {
+			ready = started && result == prober.Success
+		}
+		ncs[idx].Started = &started
+		ncs[idx].Ready = ready
+	}
+	return ncs, sandbox.IP, nil
+}
@@ -0,0 +1,592 @@
+package kubelet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"minik8s/config"
+	"minik8s/pkg/api"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/meta"
+	"minik8s/pkg/api/types"
+	client "minik8s/pkg/apiclient/interface"
+	"minik8s/pkg/apiclient/record"
+	"minik8s/pkg/kubelet/cm"
+	"minik8s/pkg/kubelet/container/cri"
+	"minik8s/pkg/kubelet/lifecycle"
+	"minik8s/pkg/kubelet/pod"
+	"minik8s/pkg/kubelet/prober"
+	"minik8s/pkg/kubelet/volume"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakePodClient keeps pods whose status is reported by kubelet, the
+// next conflicts puts fail as if pods are modified by others
+type fakePodClient struct {
+	client.Interface
+	lock      sync.Mutex
+	pods      map[string][]byte
+	conflicts int
+}
+
+func (c *fakePodClient) Get(name string) (core.IApiObject, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	data, found := c.pods[name]
+	if !found {
+		return nil, errors.New("pod " + name + " not found")
+	}
+	p := &core.Pod{}
+	err := json.Unmarshal(data, p)
+	return p, err
+}
+
+func (c *fakePodClient) Put(name string, object core.IApiObject) (int, *api.PutResponse, error) {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return 0, nil, err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.conflicts > 0 {
+		c.conflicts--
+		return http.StatusConflict, nil, errors.New("StatusCode not 200")
+	}
+	c.pods[name] = data
+	return http.StatusOK, &api.PutResponse{}, nil
+}
+
+func (c *fakePodClient) getPod(t *testing.T, uid string) *core.Pod {
+	object, err := c.Get(uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return object.(*core.Pod)
+}
+
+// fakeSecretClient keeps secrets read by kubelet
+type fakeSecretClient struct {
+	client.Interface
+	secrets map[string]*core.Secret
+}
+
+func (c *fakeSecretClient) Get(name string) (core.IApiObject, error) {
+	secret, found := c.secrets[name]
+	if !found {
+		return nil, errors.New("secret " + name + " not found")
+	}
+	return secret, nil
+}
+
+func newTestKubelet(t *testing.T) (*kubelet, *cri.Fake, *fakePodClient) {
+	runtime := cri.NewFake()
+	podClient := &fakePodClient{pods: make(map[string][]byte)}
+	cgroupManager, err := cm.NewCgroupManager(t.TempDir(), config.CgroupfsDriver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := &kubelet{
+		name:                "Kubelet",
+		node:                &core.Node{ObjectMeta: meta.ObjectMeta{Name: "node1"}},
+		podClient:           podClient,
+		secretClient:        &fakeSecretClient{secrets: make(map[string]*core.Secret)},
+		podManager:          pod.NewPodManager(),
+		criClient:           runtime,
+		podContainerManager: cm.NewPodContainerManager(cgroupManager),
+		restartStates:       make(map[string]*containerRestartState),
+		podVolumes:          make(map[types.UID]map[string]string),
+		evictedPods:         make(map[types.UID]bool),
+		volumeManager:       volume.NewManager(t.TempDir()),
+		imageBackOff:        imagePullBackOff{initial: 200 * time.Millisecond, max: 200 * time.Millisecond},
+		startRetryInterval:  100 * time.Millisecond,
+		recorder:            record.NewFakeRecorder(100),
+	}
+	k.probeManager = prober.NewManager(runtime, k.handleProbeFailure)
+	k.handlerRunner = lifecycle.NewHandlerRunner(runtime)
+	return k, runtime, podClient
+}
+
+func newTestPod(policy core.RestartPolicy, initContainers []string, containers ...string) *core.Pod {
+	p := &core.Pod{ObjectMeta: meta.ObjectMeta{Name: "web", UID: "uid1"}}
+	p.Spec.NodeName = "node1"
+	p.Spec.RestartPolicy = policy
+	for _, name := range initContainers {
+		p.Spec.InitContainers = append(p.Spec.InitContainers, core.Container{Name: name, Image: "busybox"})
+	}
+	for _, name := range containers {
+		p.Spec.Containers = append(p.Spec.Containers, core.Container{Name: name, Image: "nginx"})
+	}
+	return p
+}
+
+// recorded returns true if event is recorded by kubelet, events before it are dropped
+func recorded(k *kubelet, event string) bool {
+	events := k.recorder.(*record.FakeRecorder).Events
+	for {
+		select {
+		case e := <-events:
+			if e == event {
+				return true
+			}
+		default:
+			return false
+		}
+	}
+}
+
+// waitFor waits until condition is true, and fails test if it times out
+func waitFor(t *testing.T, what string, condition func() bool) {
+	deadline := time.Now().Add(10 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func running(runtime *cri.Fake, name string) func() bool {
+	return func() bool {
+		container, found := runtime.Container(name)
+		return found && container.Running
+	}
+}
+
+func TestPodLifecycle(t *testing.T) {
+	k, runtime, podClient := newTestKubelet(t)
+	p := newTestPod(core.RestartPolicyNever, []string{"init"}, "app", "sidecar")
+	_, _, _ = podClient.Put(p.UID, p)
+
+	k.handlePodModify(p)
+	waitFor(t, "sandbox of pod to be created", func() bool {
+		_, found := runtime.Sandbox(makePodSandboxName(p))
+		return found
+	})
+	sandbox, _ := runtime.Sandbox(makePodSandboxName(p))
+	if sandbox.Config.PodName != p.Name || sandbox.Config.PodUID != p.UID {
+		t.Errorf("sandbox config = %+v, want metadata of pod %s", sandbox.Config, p.Name)
+	}
+
+	// app containers start after init container succeeds
+	waitFor(t, "init container to run", running(runtime, "uid1-init"))
+	if _, found := runtime.Container("uid1-app"); found {
+		t.Error("app container is created before init container completes")
+	}
+	if err := runtime.Exit("uid1-init", 0); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "app container to run", running(runtime, "uid1-app"))
+	waitFor(t, "sidecar container to run", running(runtime, "uid1-sidecar"))
+	for _, name := range []string{"uid1-init", "uid1-app", "uid1-sidecar"} {
+		if container, _ := runtime.Container(name); container.Sandbox != makePodSandboxName(p) {
+			t.Errorf("container %s is in sandbox %q, want %q", name, container.Sandbox, makePodSandboxName(p))
+		}
+	}
+
+	waitFor(t, "pod to be running", func() bool {
+		return podClient.getPod(t, p.UID).Status.Phase == core.PodRunning
+	})
+	status := podClient.getPod(t, p.UID).Status
+	if status.PodIP != sandbox.IP {
+		t.Errorf("pod ip = %q, want %q", status.PodIP, sandbox.IP)
+	}
+	for _, cs := range status.ContainerStatuses {
+		container, _ := runtime.Container("uid1-" + cs.Name)
+		if cs.ContainerID != container.ID || cs.State.Running == nil || cs.State.Running.StartedAt.IsZero() {
+			t.Errorf("status of container %s = %+v, want running with id %s", cs.Name, cs, container.ID)
+		}
+	}
+	if status.StartTime == nil {
+		t.Error("start time of pod is not set")
+	}
+	for _, conditionType := range []core.PodConditionType{core.PodScheduled, core.PodInitialized, core.ContainersReady, core.PodReady} {
+		if c := status.GetCondition(conditionType); c == nil || c.Status != core.ConditionTrue {
+			t.Errorf("condition %s of pod = %+v, want true", conditionType, c)
+		}
+	}
+
+	// pod fails if any container fails and is not restarted
+	_ = runtime.Exit("uid1-app", 0)
+	_ = runtime.Exit("uid1-sidecar", 1)
+	waitFor(t, "pod to fail", func() bool {
+		return podClient.getPod(t, p.UID).Status.Phase == core.PodFailed
+	})
+	status = podClient.getPod(t, p.UID).Status
+	if c := status.GetCondition(core.PodReady); c == nil || c.Status != core.ConditionFalse {
+		t.Errorf("ready condition of failed pod = %+v, want false", c)
+	}
+	for _, cs := range status.ContainerStatuses {
+		if terminated := cs.State.Terminated; terminated == nil || terminated.FinishedAt.IsZero() {
+			t.Errorf("state of container %s = %+v, want terminated with finish time", cs.Name, cs.State)
+		}
+	}
+
+	k.handlePodDelete(p)
+	waitFor(t, "sandbox to be removed", func() bool {
+		_, found := runtime.Sandbox(makePodSandboxName(p))
+		return !found && len(runtime.ContainerNames()) == 0
+	})
+}
+
+func TestRestartContainer(t *testing.T) {
+	k, runtime, podClient := newTestKubelet(t)
+	p := newTestPod(core.RestartPolicyAlways, nil, "app")
+	_, _, _ = podClient.Put(p.UID, p)
+
+	k.handlePodModify(p)
+	waitFor(t, "app container to run", running(runtime, "uid1-app"))
+
+	// the first crash is restarted at once
+	if err := runtime.Exit("uid1-app", 1); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "app container to restart", func() bool {
+		container, _ := runtime.Container("uid1-app")
+		return container.Running && container.StartCount == 2
+	})
+	waitFor(t, "restart count to be reported", func() bool {
+		statuses := podClient.getPod(t, p.UID).Status.ContainerStatuses
+		return len(statuses) == 1 && statuses[0].RestartCount == 1
+	})
+	if !recorded(k, "Normal Started Started container app") {
+		t.Error("restart of container is not recorded")
+	}
+
+	k.handlePodDelete(p)
+	waitFor(t, "sandbox to be removed", func() bool {
+		_, found := runtime.Sandbox(makePodSandboxName(p))
+		return !found
+	})
+}
+
+func TestCreateContainerError(t *testing.T) {
+	k, runtime, podClient := newTestKubelet(t)
+	p := newTestPod(core.RestartPolicyAlways, nil, "app", "sidecar")
+	_, _, _ = podClient.Put(p.UID, p)
+	runtime.SetCreateError("uid1-app", errors.New("invalid mount"))
+
+	// kubelet keeps running other containers, and reports the failure
+	k.handlePodModify(p)
+	waitFor(t, "sidecar container to run", running(runtime, "uid1-sidecar"))
+	waitFor(t, "failure to be recorded", func() bool {
+		return recorded(k, "Warning Failed Error: create container app: invalid mount")
+	})
+	waitFor(t, "CreateContainerError to be reported", func() bool {
+		statuses := podClient.getPod(t, p.UID).Status.ContainerStatuses
+		return len(statuses) == 2 && statuses[0].State.Waiting != nil && statuses[0].State.Waiting.Reason == CreateContainerError
+	})
+
+	// creation is retried until it succeeds
+	runtime.SetCreateError("uid1-app", nil)
+	waitFor(t, "app container to run", running(runtime, "uid1-app"))
+	waitFor(t, "pod to be running", func() bool {
+		return podClient.getPod(t, p.UID).Status.Phase == core.PodRunning
+	})
+
+	k.handlePodDelete(p)
+	waitFor(t, "sandbox to be removed", func() bool {
+		_, found := runtime.Sandbox(makePodSandboxName(p))
+		return !found && len(runtime.ContainerNames()) == 0
+	})
+}
+
+func TestInitContainerCreateError(t *testing.T) {
+	k, runtime, podClient := newTestKubelet(t)
+	p := newTestPod(core.RestartPolicyAlways, []string{"init"}, "app")
+	_, _, _ = podClient.Put(p.UID, p)
+	runtime.SetCreateError("uid1-init", errors.New("invalid mount"))
+
+	// pod stays pending with the failure reported, and is retried
+	k.handlePodModify(p)
+	waitFor(t, "CreateContainerError to be reported", func() bool {
+		statuses := podClient.getPod(t, p.UID).Status.InitContainerStatuses
+		return len(statuses) == 1 && statuses[0].State.Waiting != nil && statuses[0].State.Waiting.Reason == CreateContainerError
+	})
+	if !recorded(k, "Warning Failed Error: create container init: invalid mount") {
+		t.Error("failure of init container is not recorded")
+	}
+
+	runtime.SetCreateError("uid1-init", nil)
+	waitFor(t, "init container to run", running(runtime, "uid1-init"))
+	_ = runtime.Exit("uid1-init", 0)
+	waitFor(t, "app container to run", running(runtime, "uid1-app"))
+
+	k.handlePodDelete(p)
+	waitFor(t, "sandbox to be removed", func() bool {
+		_, found := runtime.Sandbox(makePodSandboxName(p))
+		return !found && len(runtime.ContainerNames()) == 0
+	})
+}
+
+func TestUpdatePodStatusConflict(t *testing.T) {
+	k, _, podClient := newTestKubelet(t)
+	p := newTestPod(core.RestartPolicyAlways, []string{"init"}, "app")
+	_, _, _ = podClient.Put(p.UID, p)
+
+	statuses := []core.ContainerStatus{{Name: "init", State: core.ContainerState{Running: &core.ContainerStateRunning{}}}}
+	podClient.conflicts = 2
+	k.updateInitContainerStatuses(p, statuses, core.PodPending)
+	if got := podClient.getPod(t, p.UID).Status.InitContainerStatuses; len(got) != 1 || got[0].State.Running == nil {
+		t.Errorf("init container statuses = %+v after conflicts, want running", got)
+	}
+
+	podClient.conflicts = maxPodStatusUpdateRetries
+	if err := k.updatePodStatus(p.UID, func(p *core.Pod) { p.Status.Message = "updated" }); err == nil {
+		t.Error("too many conflicts are not reported")
+	}
+}
+
+func TestUnsupportedVolume(t *testing.T) {
+	k, runtime, podClient := newTestKubelet(t)
+	p := newTestPod(core.RestartPolicyAlways, nil, "app")
+	p.Spec.Volumes = []core.Volume{{Name: "data", VolumeSource: core.VolumeSource{EmptyDir: &core.EmptyDirVolumeSource{}}}}
+	_, _, _ = podClient.Put(p.UID, p)
+
+	// no plugin is registered in test kubelet, pod fails without retrying
+	k.handlePodModify(p)
+	waitFor(t, "pod to fail", func() bool {
+		status := podClient.getPod(t, p.UID).Status
+		return status.Phase == core.PodFailed && status.Reason == UnsupportedVolume
+	})
+	if _, found := runtime.Sandbox(makePodSandboxName(p)); found {
+		t.Error("sandbox is created for pod with unsupported volume")
+	}
+	k.handlePodDelete(p)
+}
+
+func TestRunPodSandboxError(t *testing.T) {
+	k, runtime, podClient := newTestKubelet(t)
+	p := newTestPod(core.RestartPolicyAlways, nil, "app")
+	_, _, _ = podClient.Put(p.UID, p)
+	runtime.SetSandboxError(makePodSandboxName(p), errors.New("network setup failed"))
+
+	k.handlePodModify(p)
+	waitFor(t, "failure to be recorded", func() bool {
+		return recorded(k, "Warning FailedCreatePodSandBox Failed to create pod sandbox: run pod sandbox: network setup failed")
+	})
+	if _, found := runtime.Container("uid1-app"); found {
+		t.Error("container is created without sandbox")
+	}
+
+	// sandbox is created again until it succeeds
+	runtime.SetSandboxError(makePodSandboxName(p), nil)
+	waitFor(t, "app container to run", running(runtime, "uid1-app"))
+
+	k.handlePodDelete(p)
+	waitFor(t, "sandbox to be removed", func() bool {
+		_, found := runtime.Sandbox(makePodSandboxName(p))
+		return !found
+	})
+}
+
+func TestImagePullBackOff(t *testing.T) {
+	k, runtime, podClient := newTestKubelet(t)
+	p := newTestPod(core.RestartPolicyAlways, nil, "app")
+	_, _, _ = podClient.Put(p.UID, p)
+	runtime.SetPullError("nginx", errors.New("manifest unknown"))
+
+	k.handlePodModify(p)
+	waitingReason := func(reason string) func() bool {
+		return func() bool {
+			statuses := podClient.getPod(t, p.UID).Status.ContainerStatuses
+			return len(statuses) == 1 && statuses[0].State.Waiting != nil && statuses[0].State.Waiting.Reason == reason
+		}
+	}
+	waitFor(t, "ErrImagePull to be reported", waitingReason(ErrImagePull))
+	waitFor(t, "ImagePullBackOff to be reported", waitingReason(ImagePullBackOff))
+	if _, found := runtime.Container("uid1-app"); found {
+		t.Error("container is created before its image is pulled")
+	}
+
+	// pull is retried until it succeeds
+	runtime.SetPullError("nginx", nil)
+	waitFor(t, "app container to run", running(runtime, "uid1-app"))
+
+	k.handlePodDelete(p)
+	waitFor(t, "sandbox to be removed", func() bool {
+		_, found := runtime.Sandbox(makePodSandboxName(p))
+		return !found
+	})
+}
+
+func TestImagePullSecrets(t *testing.T) {
+	k, runtime, podClient := newTestKubelet(t)
+	k.secretClient.(*fakeSecretClient).secrets["regcred"] = &core.Secret{
+		ObjectMeta: meta.ObjectMeta{Name: "regcred"},
+		Type:       core.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			core.DockerConfigJsonKey: []byte(`{"auths": {"registry.io": {"username": "user", "password": "pw"}}}`),
+		},
+	}
+	p := newTestPod(core.RestartPolicyAlways, nil, "app")
+	p.Spec.Containers[0].Image = "registry.io/app:v1"
+	p.Spec.Containers = append(p.Spec.Containers, core.Container{Name: "cached", Image: "busybox", ImagePullPolicy: core.PullIfNotPresent})
+	p.Spec.ImagePullSecrets = []core.LocalObjectReference{{Name: "regcred"}, {Name: "missing"}}
+	_, _, _ = podClient.Put(p.UID, p)
+	runtime.AddImage("busybox", 0)
+
+	k.handlePodModify(p)
+	waitFor(t, "app container to run", running(runtime, "uid1-app"))
+	waitFor(t, "cached container to run", running(runtime, "uid1-cached"))
+	pulls := runtime.Pulls()
+	if len(pulls) != 1 || pulls[0].Image != "registry.io/app:v1" || pulls[0].Auth == nil || pulls[0].Auth.Username != "user" {
+		t.Errorf("pulls = %+v, want registry.io/app:v1 pulled with credentials of regcred", pulls)
+	}
+
+	k.handlePodDelete(p)
+	waitFor(t, "sandbox to be removed", func() bool {
+		_, found := runtime.Sandbox(makePodSandboxName(p))
+		return !found
+	})
+}
+
+func TestPodResources(t *testing.T) {
+	k, runtime, podClient := newTestKubelet(t)
+	k.nodeCapacity = core.ResourceList{"memory": "4096M"}
+	p := newTestPod(core.RestartPolicyAlways, nil, "app", "sidecar")
+	p.Spec.Containers[0].Resources.Limits = core.ResourceList{"cpu": "500m", "memory": "1024M"}
+	p.Spec.Containers[1].Resources.Requests = core.ResourceList{"cpu": "250m", "memory": "2048M"}
+	_, _, _ = podClient.Put(p.UID, p)
+
+	k.handlePodModify(p)
+	waitFor(t, "app container to run", running(runtime, "uid1-app"))
+	waitFor(t, "sidecar container to run", running(runtime, "uid1-sidecar"))
+	sandbox, _ := runtime.Sandbox(makePodSandboxName(p))
+	if sandbox.Config.CgroupParent != "/kubepods/burstable/poduid1" {
+		t.Errorf("cgroup parent of sandbox = %q, want cgroup of burstable pod", sandbox.Config.CgroupParent)
+	}
+	app, _ := runtime.Container("uid1-app")
+	want := cri.Resources{CPUShares: 512, CPUQuota: 50000, CPUPeriod: 100000, MemoryLimit: 1 << 30, OOMScoreAdj: 750}
+	if app.Resources != want {
+		t.Errorf("resources of app container = %+v, want %+v", app.Resources, want)
+	}
+	sidecar, _ := runtime.Container("uid1-sidecar")
+	want = cri.Resources{CPUShares: 256, CPUPeriod: 100000, OOMScoreAdj: 500}
+	if sidecar.Resources != want {
+		t.Errorf("resources of sidecar container = %+v, want %+v", sidecar.Resources, want)
+	}
+	waitFor(t, "qos class to be reported", func() bool {
+		return podClient.getPod(t, p.UID).Status.QOSClass == core.PodQOSBurstable
+	})
+
+	k.handlePodDelete(p)
+	waitFor(t, "sandbox to be removed", func() bool {
+		_, found := runtime.Sandbox(makePodSandboxName(p))
+		return !found
+	})
+}
+
+func TestEvictPod(t *testing.T) {
+	k, runtime, podClient := newTestKubelet(t)
+	p := newTestPod(core.RestartPolicyAlways, nil, "app")
+	_, _, _ = podClient.Put(p.UID, p)
+
+	k.handlePodModify(p)
+	waitFor(t, "pod to be running", func() bool {
+		return podClient.getPod(t, p.UID).Status.Phase == core.PodRunning
+	})
+
+	if err := k.evictPod(p, 0, "The node was low on resource: memory."); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := runtime.Sandbox(makePodSandboxName(p)); found || len(runtime.ContainerNames()) != 0 {
+		t.Error("sandbox or containers of evicted pod are not removed")
+	}
+	evicted := podClient.getPod(t, p.UID)
+	if !evicted.IsEvicted() || evicted.Status.Message != "The node was low on resource: memory." {
+		t.Errorf("status of evicted pod = %+v, want Failed with reason Evicted", evicted.Status)
+	}
+	if cs := evicted.Status.ContainerStatuses; len(cs) != 1 || cs[0].State.Terminated == nil {
+		t.Errorf("container statuses of evicted pod = %+v, want terminated", cs)
+	}
+	if !recorded(k, "Warning Evicted The node was low on resource: memory.") {
+		t.Error("eviction of pod is not recorded")
+	}
+
+	// evicted pod is not started again
+	k.handlePodModify(evicted)
+	if _, found := runtime.Sandbox(makePodSandboxName(p)); found {
+		t.Error("evicted pod is started again")
+	}
+	k.handlePodDelete(evicted)
+	if k.evictedPods[p.UID] {
+		t.Error("deleted pod is still recorded as evicted")
+	}
+}
+
+func TestActivePods(t *testing.T) {
+	k, _, _ := newTestKubelet(t)
+	running := newTestPod(core.RestartPolicyAlways, nil, "app")
+	finished := newTestPod(core.RestartPolicyNever, nil, "app")
+	finished.Name, finished.UID = "job", "uid2"
+	finished.Status.Phase = core.PodSucceeded
+	static := newTestPod(core.RestartPolicyAlways, nil, "app")
+	static.Name, static.UID = "static-web", "uid3"
+	static.Annotations = map[string]string{core.ConfigSourceAnnotationKey: core.ConfigSourceFile}
+	for _, p := range []*core.Pod{running, finished, static} {
+		k.podManager.AddPod(p)
+	}
+
+	pods := k.activePods()
+	if len(pods) != 1 || pods[0].UID != running.UID {
+		t.Errorf("activePods() = %v, want only pod %v", pods, running.Name)
+	}
+}
+
+func TestAdoptPods(t *testing.T) {
+	k, runtime, podClient := newTestKubelet(t)
+	ctx := context.Background()
+	leave := func(uid string, containers ...string) {
+		sandbox := uid + "-pause"
+		if _, err := runtime.RunPodSandbox(ctx, cri.SandboxConfig{Name: sandbox, PodUID: uid}); err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range containers {
+			if _, err := runtime.ContainerCreate(ctx, sandbox, core.Container{Name: uid + "-" + name}, cri.Resources{}, nil); err != nil {
+				t.Fatal(err)
+			}
+			_ = runtime.ContainerStart(ctx, uid+"-"+name)
+		}
+	}
+	// pod uid1 has sidecar added and old removed while kubelet is down, pod uid2 is deleted
+	leave("uid1", "app", "old")
+	leave("uid2", "app")
+	app, _ := runtime.Container("uid1-app")
+
+	p := newTestPod(core.RestartPolicyAlways, nil, "app", "sidecar")
+	p.Status.ContainerStatuses = []core.ContainerStatus{{Name: "app", RestartCount: 2}}
+	_, _, _ = podClient.Put(p.UID, p)
+	runtimePods, err := listRuntimePods(ctx, runtime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.runtimePods = runtimePods
+
+	k.handlePodModify(p)
+	k.removeOrphanPods(ctx)
+	waitFor(t, "sidecar container to run", running(runtime, "uid1-sidecar"))
+	if names := runtime.ContainerNames(); !reflect.DeepEqual(names, []string{"uid1-app", "uid1-sidecar"}) {
+		t.Errorf("containers in runtime = %v, want [uid1-app uid1-sidecar]", names)
+	}
+	if _, found := runtime.Sandbox("uid2-pause"); found {
+		t.Error("sandbox of orphan pod is not removed")
+	}
+	if adopted, _ := runtime.Container("uid1-app"); adopted.ID != app.ID || adopted.StartCount != 1 {
+		t.Errorf("app container is restarted, start count %d", adopted.StartCount)
+	}
+	waitFor(t, "statuses of adopted pod to be reported", func() bool {
+		status := podClient.getPod(t, p.UID).Status
+		return status.Phase == core.PodRunning && len(status.ContainerStatuses) == 2 && status.ContainerStatuses[0].ContainerID == app.ID &&
+			status.ContainerStatuses[0].RestartCount == 2
+	})
+
+	k.handlePodDelete(p)
+	waitFor(t, "sandbox to be removed", func() bool {
+		_, found := runtime.Sandbox(makePodSandboxName(p))
+		return !found
+	})
+}
@@ -0,0 +1,91 @@
+package lifecycle
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/kubelet/container/cri"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// HandlerRunner runs postStart and preStop hooks of containers
+type HandlerRunner interface {
+	// Run runs handler against container, runtimeName is the name of container
+	// in runtime, and podIP is used if http handler does not set host
+	Run(ctx context.Context, runtimeName string, podIP string, handler *core.LifecycleHandler) error
+}
+
+type handlerRunner struct {
+	criClient  cri.Client
+	httpClient *http.Client
+}
+
+func NewHandlerRunner(criClient cri.Client) HandlerRunner {
+	return &handlerRunner{
+		criClient: criClient,
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+				DisableKeepAlives: true,
+			},
+		},
+	}
+}
+
+func (r *handlerRunner) Run(ctx context.Context, runtimeName string, podIP string, handler *core.LifecycleHandler) error {
+	switch {
+	case handler.Exec != nil:
+		code, output, err := r.criClient.ContainerExec(ctx, runtimeName, handler.Exec.Command)
+		if err != nil {
+			return err
+		}
+		if code != 0 {
+			return fmt.Errorf("exec %v exited with %d: %s", handler.Exec.Command, code, output)
+		}
+		return nil
+	case handler.HTTPGet != nil:
+		return r.runHTTPGet(ctx, handler.HTTPGet, podIP)
+	default:
+		return fmt.Errorf("invalid handler: %v", handler)
+	}
+}
+
+func (r *handlerRunner) runHTTPGet(ctx context.Context, action *core.HTTPGetAction, podIP string) error {
+	host := action.Host
+	if host == "" {
+		host = podIP
+	}
+	scheme := action.Scheme
+	if scheme == "" {
+		scheme = core.URISchemeHTTP
+	}
+	u := url.URL{
+		Scheme: strings.ToLower(string(scheme)),
+		Host:   net.JoinHostPort(host, strconv.Itoa(int(action.Port))),
+		Path:   action.Path,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	for _, header := range action.HTTPHeaders {
+		req.Header.Add(header.Name, header.Value)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("GET %s returned status %d", u.String(), resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package kubelet
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"log"
+	"minik8s/pkg/api"
+	"minik8s/pkg/kubelet/container/cri"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+/*---------------------------- Container Logs ----------------------------*/
+
+// logQuery is query of container logs
+type logQuery struct {
+	follow bool
+	// tailLines is negative for all lines
+	tailLines int64
+	// sinceSeconds is zero for logs since container started
+	sinceSeconds int64
+	previous     bool
+}
+
+func parseLogQuery(values url.Values) (logQuery, error) {
+	query := logQuery{tailLines: -1}
+	var err error
+	if v := values.Get(api.LogFollowParam); v != "" {
+		if query.follow, err = strconv.ParseBool(v); err != nil {
+			return query, fmt.Errorf("invalid %s %q", api.LogFollowParam, v)
+		}
+	}
+	if v := values.Get(api.LogPreviousParam); v != "" {
+		if query.previous, err = strconv.ParseBool(v); err != nil {
+			return query, fmt.Errorf("invalid %s %q", api.LogPreviousParam, v)
+		}
+	}
+	if v := values.Get(api.LogTailLinesParam); v != "" {
+		if query.tailLines, err = strconv.ParseInt(v, 10, 64); err != nil || query.tailLines < 0 {
+			return query, fmt.Errorf("invalid %s %q", api.LogTailLinesParam, v)
+		}
+	}
+	if v := values.Get(api.LogSinceSecondsParam); v != "" {
+		if query.sinceSeconds, err = strconv.ParseInt(v, 10, 64); err != nil || query.sinceSeconds <= 0 {
+			return query, fmt.Errorf("invalid %s %q", api.LogSinceSecondsParam, v)
+		}
+	}
+	return query, nil
+}
+
+// logOptions selects logs of current or previous run of container, previous
+// run is the one before last restart, whose logs can not be followed
+func (s *containerRestartState) logOptions(query logQuery, now time.Time) (cri.LogOptions, error) {
+	opts := cri.LogOptions{
+		Follow:    query.follow,
+		TailLines: query.tailLines,
+		Since:     s.runStartedAt,
+	}
+	if query.previous {
+		if s.restartCount == 0 {
+			return opts, errors.New("previous terminated container not found")
+		}
+		opts.Follow = false
+		opts.Since = s.lastRunStartedAt
+		opts.Until = s.runStartedAt
+	}
+	if query.sinceSeconds > 0 {
+		if since := now.Add(-time.Duration(query.sinceSeconds) * time.Second); since.After(opts.Since) {
+			opts.Since = since
+		}
+	}
+	return opts, nil
+}
+
+// handleContainerLogs writes logs of container in pod as plain text
+// GET /containerLogs/{pod}/{container}?follow=&tailLines=&sinceSeconds=&previous=
+func (k *kubelet) handleContainerLogs(c *gin.Context) {
+	query, err := parseLogQuery(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "ERR", "error": err.Error()})
+		return
+	}
+
+	pod, container, found := k.getRequestedContainer(c)
+	if !found {
+		return
+	}
+
+	name := makePodContainerName(pod, container)
+	k.lock.RLock()
+	state := containerRestartState{}
+	if s, found := k.restartStates[name]; found {
+		state = *s
+	}
+	k.lock.RUnlock()
+
+	opts, err := state.logOptions(query, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "ERR", "error": fmt.Sprintf("%v in pod %s: %s", err, pod.Name, container.Name)})
+		return
+	}
+
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Status(http.StatusOK)
+	w := &flushWriter{w: c.Writer}
+	err = k.criClient.ContainerLogs(c.Request.Context(), name, opts, w, w)
+	if err != nil {
+		if !c.Writer.Written() {
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "ERR", "error": err.Error()})
+			return
+		}
+		log.Printf("[Kubelet] Write logs of container %s error: %v\n", name, err)
+	}
+}
+
+// flushWriter flushes each write, so that followed logs reach client at once
+type flushWriter struct {
+	w gin.ResponseWriter
+}
+
+func (fw *flushWriter) Write(p []byte) (int, error) {
+	n, err := fw.w.Write(p)
+	fw.w.Flush()
+	return n, err
+}
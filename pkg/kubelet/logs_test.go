@@ -0,0 +1,86 @@
+package kubelet
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestContainerLogOptions(t *testing.T) {
+	now := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	firstRestart := now.Add(-time.Hour)
+	secondRestart := now.Add(-time.Minute)
+	restarted := containerRestartState{restartCount: 2, lastRunStartedAt: firstRestart, runStartedAt: secondRestart}
+
+	tests := []struct {
+		name    string
+		state   containerRestartState
+		query   string
+		follow  bool
+		tail    int64
+		since   time.Time
+		until   time.Time
+		wantErr bool
+	}{
+		{
+			name: "all logs of first run",
+			tail: -1,
+		},
+		{
+			name:   "follow current run",
+			state:  restarted,
+			query:  "follow=true&tailLines=10",
+			follow: true,
+			tail:   10,
+			since:  secondRestart,
+		},
+		{
+			name:  "previous run is not followed",
+			state: restarted,
+			query: "previous=true&follow=true",
+			tail:  -1,
+			since: firstRestart,
+			until: secondRestart,
+		},
+		{
+			name:  "since seconds later than run started",
+			state: restarted,
+			query: "sinceSeconds=30",
+			tail:  -1,
+			since: now.Add(-30 * time.Second),
+		},
+		{
+			name:  "since seconds earlier than run started",
+			state: restarted,
+			query: "sinceSeconds=3600",
+			tail:  -1,
+			since: secondRestart,
+		},
+		{
+			name:    "no previous run",
+			query:   "previous=true",
+			wantErr: true,
+		},
+		{
+			name:    "invalid tail lines",
+			query:   "tailLines=-1",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		values, _ := url.ParseQuery(test.query)
+		query, err := parseLogQuery(values)
+		if err == nil {
+			o, e := test.state.logOptions(query, now)
+			if e != nil {
+				err = e
+			} else if o.Follow != test.follow || o.TailLines != test.tail || !o.Since.Equal(test.since) || !o.Until.Equal(test.until) {
+				t.Errorf("%s: got %+v", test.name, o)
+			}
+		}
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v, expected error %v", test.name, err, test.wantErr)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package kubelet
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+/*---------------------------- Node Status ----------------------------*/
+
+// initNodeCapacity gets capacity of node from cadvisor machine info,
+// and allocatable which is capacity minus resources reserved for system
+func (k *kubelet) initNodeCapacity() {
+	machineInfo, err := k.cadvisorClient.MachineInfo()
+	if err != nil {
+		log.Printf("[Kubelet] Get machine info from cadvisor error: %v\n", err)
+		return
+	}
+
+	// cadvisor does not tell which filesystem is root,
+	// use the largest one as node filesystem
+	var storageCapacity uint64 = 0
+	for _, fs := range machineInfo.Filesystems {
+		if fs.Capacity > storageCapacity {
+			storageCapacity = fs.Capacity
+		}
+	}
+
+	// memory and storage are in the unit of types.ParseQuantity, i.e. M
+	capacity := core.ResourceList{
+		types.ResourceCPU:              types.Quantity(strconv.Itoa(machineInfo.NumCores)),
+		types.ResourceMemory:           types.Quantity(fmt.Sprintf("%dM", machineInfo.MemoryCapacity/(1024*1024))),
+		types.ResourceEphemeralStorage: types.Quantity(fmt.Sprintf("%dM", storageCapacity/(1024*1024))),
+		types.ResourcePods:             types.Quantity(strconv.Itoa(config.MaxPods)),
+	}
+	reserved := core.ResourceList{
+		types.ResourceCPU:              config.SystemReservedCPU,
+		types.ResourceMemory:           config.SystemReservedMemory,
+		types.ResourceEphemeralStorage: config.SystemReservedEphemeralStorage,
+	}
+
+	k.nodeCapacity = capacity
+	k.nodeAllocatable = computeAllocatable(capacity, reserved)
+	log.Printf("[Kubelet] Node capacity %v, allocatable %v\n", k.nodeCapacity, k.nodeAllocatable)
+}
+
+// syncNodeStatus periodically updates node status to ApiServer
+func (k *kubelet) syncNodeStatus(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("[Kubelet] ctx.Done() received, syncNodeStatus exit\n")
+			return
+		default:
+			k.updateNodeStatus()
+			time.Sleep(config.NodeStatusUpdateInterval)
+		}
+	}
+}
+
+// updateNodeStatus reports capacity and conditions of node. Status is
+// put only if it changes or it has not been reported for NodeStatusReportInterval,
+// liveness of node is reported by heartbeat.
+func (k *kubelet) updateNodeStatus() {
+	statusItem, err := k.nodeClient.GetStatus(k.node.UID)
+	if err != nil {
+		log.Printf("[Kubelet] Get node status error: %v\n", err)
+		return
+	}
+	status := statusItem.(*core.NodeStatus)
+	now := time.Now()
+
+	changed := false
+	if k.nodeCapacity != nil && !reflect.DeepEqual(status.Capacity, k.nodeCapacity) {
+		status.Capacity = k.nodeCapacity
+		status.Allocatable = k.nodeAllocatable
+		changed = true
+	}
+	for _, setter := range k.nodeConditionSetters() {
+		ty, s, reason, message := setter()
+		if setNodeCondition(status, ty, s, reason, message, now) {
+			changed = true
+		}
+	}
+
+	if !changed && now.Sub(k.lastNodeStatusReport) < config.NodeStatusReportInterval {
+		return
+	}
+
+	_, _, err = k.nodeClient.PutStatus(k.node.UID, status)
+	if err != nil {
+		log.Printf("[Kubelet] Put node status error: %v\n", err)
+		return
+	}
+	k.node.Status = *status
+	k.lastNodeStatusReport = now
+}
+
+type nodeConditionSetter func() (ty core.NodeConditionType, status core.ConditionStatus, reason, message string)
+
+// nodeConditionSetters returns functions computing each node condition
+func (k *kubelet) nodeConditionSetters() []nodeConditionSetter {
+	return []nodeConditionSetter{
+		func() (core.NodeConditionType, core.ConditionStatus, string, string) {
+			return core.NodeReady, core.ConditionTrue, "KubeletReady", "kubelet is posting ready status"
+		},
+		func() (core.NodeConditionType, core.ConditionStatus, string, string) {
+			if k.evictionManager.IsUnderMemoryPressure() {
+				return core.NodeMemoryPressure, core.ConditionTrue, "KubeletHasInsufficientMemory", "kubelet has insufficient memory available"
+			}
+			return core.NodeMemoryPressure, core.ConditionFalse, "KubeletHasSufficientMemory", "kubelet has sufficient memory available"
+		},
+		func() (core.NodeConditionType, core.ConditionStatus, string, string) {
+			if k.evictionManager.IsUnderDiskPressure() {
+				return core.NodeDiskPressure, core.ConditionTrue, "KubeletHasDiskPressure", "kubelet has disk pressure"
+			}
+			return core.NodeDiskPressure, core.ConditionFalse, "KubeletHasNoDiskPressure", "kubelet has no disk pressure"
+		},
+		func() (core.NodeConditionType, core.ConditionStatus, string, string) {
+			return core.NodePIDPressure, core.ConditionFalse, "KubeletHasSufficientPID", "kubelet has sufficient PID available"
+		},
+		func() (core.NodeConditionType, core.ConditionStatus, string, string) {
+			return core.NodeNetworkUnavailable, core.ConditionFalse, "NetworkReady", "node network is configured"
+		},
+	}
+}
+
+// setNodeCondition updates condition of type ty in node status, LastTransitionTime
+// is updated only if status of condition changes. Returns true if status, reason
+// or message of condition changes.
+func setNodeCondition(nodeStatus *core.NodeStatus, ty core.NodeConditionType, status core.ConditionStatus, reason, message string, now time.Time) bool {
+	cond := nodeStatus.GetCondition(ty)
+	if cond == nil {
+		nodeStatus.Conditions = append(nodeStatus.Conditions, core.NodeCondition{
+			Type:               ty,
+			Status:             status,
+			LastHeartbeatTime:  now,
+			LastTransitionTime: now,
+			Reason:             reason,
+			Message:            message,
+		})
+		return true
+	}
+
+	changed := cond.Status != status || cond.Reason != reason || cond.Message != message
+	if cond.Status != status {
+		cond.LastTransitionTime = now
+	}
+	cond.Status = status
+	cond.Reason = reason
+	cond.Message = message
+	cond.LastHeartbeatTime = now
+	return changed
+}
+
+// computeAllocatable returns capacity minus reserved, resources
+// with reserved more than capacity are set to zero
+func computeAllocatable(capacity, reserved core.ResourceList) core.ResourceList {
+	allocatable := core.ResourceList{}
+	for name, q := range capacity {
+		r, ok := reserved[name]
+		if !ok {
+			allocatable[name] = q
+			continue
+		}
+		c, err := types.ParseQuantity(name, q)
+		if err != nil {
+			allocatable[name] = q
+			continue
+		}
+		rv, err := types.ParseQuantity(name, r)
+		if err != nil {
+			allocatable[name] = q
+			continue
+		}
+		var left uint64 = 0
+		if c > rv {
+			left = c - rv
+		}
+		switch name {
+		case types.ResourceCPU:
+			allocatable[name] = types.Quantity(fmt.Sprintf("%dm", left))
+		case types.ResourcePods:
+			allocatable[name] = types.Quantity(strconv.FormatUint(left, 10))
+		default:
+			allocatable[name] = types.Quantity(fmt.Sprintf("%dM", left))
+		}
+	}
+	return allocatable
+}
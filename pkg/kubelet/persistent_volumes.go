@@ -0,0 +1,95 @@
+package kubelet
+
+import (
+	"context"
+	"fmt"
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/logger"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+/*---------------------------- Persistent Volumes ----------------------------*/
+
+// getPersistentVolumeClaim returns nil without error if claim is not found
+func (k *kubelet) getPersistentVolumeClaim(name string) (*core.PersistentVolumeClaim, error) {
+	r, err := k.pvcClient.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	claim := r.(*core.PersistentVolumeClaim)
+	if claim.Name == "" {
+		return nil, nil
+	}
+	return claim, nil
+}
+
+// getPersistentVolume returns nil without error if volume is not found
+func (k *kubelet) getPersistentVolume(name string) (*core.PersistentVolume, error) {
+	r, err := k.pvClient.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	volume := r.(*core.PersistentVolume)
+	if volume.Name == "" {
+		return nil, nil
+	}
+	return volume, nil
+}
+
+// reclaimVolumes deletes local-path volumes on this node periodically after
+// they are released by their claims, the directories are removed before the
+// volumes are deleted from apiserver
+func (k *kubelet) reclaimVolumes(ctx context.Context) {
+	ticker := time.NewTicker(config.VolumeReclaimPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		list, err := k.pvClient.GetAll()
+		if err != nil {
+			logger.KubeletLogger.Printf("List persistent volumes failed: %v\n", err)
+			continue
+		}
+		for _, item := range list.GetIApiObjectArr() {
+			pv := item.(*core.PersistentVolume)
+			if !k.shouldDeleteVolume(pv) {
+				continue
+			}
+			if err := deleteLocalPathVolume(pv); err != nil {
+				logger.KubeletLogger.Printf("Delete directory of persistent volume %s failed: %v\n", pv.Name, err)
+				continue
+			}
+			if _, _, err := k.pvClient.Delete(pv.Name); err != nil {
+				logger.KubeletLogger.Printf("Delete persistent volume %s failed: %v\n", pv.Name, err)
+				continue
+			}
+			logger.KubeletLogger.Printf("Persistent volume %s released by claim is deleted\n", pv.Name)
+		}
+	}
+}
+
+func (k *kubelet) shouldDeleteVolume(pv *core.PersistentVolume) bool {
+	return pv.Status.Phase == core.VolumeReleased &&
+		pv.Spec.PersistentVolumeReclaimPolicy == core.PersistentVolumeReclaimDelete &&
+		pv.IsProvisionedBy(core.LocalPathProvisionerName) &&
+		pv.MatchNode(k.node)
+}
+
+// deleteLocalPathVolume removes directory of volume, which must be
+// under the directory of local-path provisioner
+func deleteLocalPathVolume(pv *core.PersistentVolume) error {
+	path := filepath.Clean(pv.Path())
+	rel, err := filepath.Rel(config.LocalPathProvisionerDir, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("path %s is not under %s", path, config.LocalPathProvisionerDir)
+	}
+	return os.RemoveAll(path)
+}
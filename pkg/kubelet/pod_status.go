@@ -0,0 +1,127 @@
+package kubelet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+	"minik8s/pkg/kubelet/container/cri"
+	"net/http"
+	"time"
+)
+
+/*---------------------------- Pod Status ----------------------------*/
+
+// Reasons of pod conditions which are false
+const (
+	ContainersNotInitialized = "ContainersNotInitialized"
+	ContainersNotReady       = "ContainersNotReady"
+	// PodCompleted is reason of Ready condition of pod whose containers all succeeded
+	PodCompleted = "PodCompleted"
+)
+
+// maxPodStatusUpdateRetries is the number of times status of pod is put on conflict
+const maxPodStatusUpdateRetries = 5
+
+// updatePodStatus gets pod of uid from ApiServer, changes its status by mutate
+// and puts it back if the status is changed. Put fails if pod is modified by
+// others meanwhile, e.g. bound by scheduler, then it is retried on the latest version
+func (k *kubelet) updatePodStatus(uid types.UID, mutate func(pod *core.Pod)) error {
+	for i := 0; i < maxPodStatusUpdateRetries; i++ {
+		r, err := k.podClient.Get(uid)
+		if err != nil {
+			return err
+		}
+		rr := r.(*core.Pod)
+		old, err := json.Marshal(rr.Status)
+		if err != nil {
+			return err
+		}
+		mutate(rr)
+		if now, err := json.Marshal(rr.Status); err == nil && bytes.Equal(old, now) {
+			return nil
+		}
+		code, _, err := k.podClient.Put(uid, rr)
+		if err == nil || code != http.StatusConflict {
+			return err
+		}
+	}
+	return errors.New("too many conflicts when updating pod status")
+}
+
+// setPodConditions sets conditions of pod reported by kubelet, pod on node is scheduled,
+// it is initialized after all init containers succeed, and ready once all its
+// containers are ready
+func setPodConditions(status *core.PodStatus, initialized bool, ready bool) {
+	status.SetCondition(newPodCondition(core.PodScheduled, true, ""))
+	status.SetCondition(newPodCondition(core.PodInitialized, initialized, ContainersNotInitialized))
+	setPodReady(status, ready)
+}
+
+// setPodReady sets ContainersReady and Ready conditions of pod status
+func setPodReady(status *core.PodStatus, ready bool) {
+	reason := ContainersNotReady
+	if status.Phase == core.PodSucceeded {
+		reason = PodCompleted
+	}
+	status.SetCondition(newPodCondition(core.ContainersReady, ready, reason))
+	status.SetCondition(newPodCondition(core.PodReady, ready, reason))
+}
+
+// newPodCondition returns condition of type, reason is only set if it is false
+func newPodCondition(conditionType core.PodConditionType, status bool, reason string) core.PodCondition {
+	if status {
+		return core.PodCondition{Type: conditionType, Status: core.ConditionTrue}
+	}
+	return core.PodCondition{Type: conditionType, Status: core.ConditionFalse, Reason: reason}
+}
+
+// setPodStartTime sets start time of pod to the time kubelet accepts it
+func setPodStartTime(status *core.PodStatus) {
+	if status.StartTime == nil {
+		now := time.Now()
+		status.StartTime = &now
+	}
+}
+
+func terminatedReason(exitCode int) string {
+	if exitCode == 0 {
+		return "Completed"
+	}
+	return "Error"
+}
+
+// containerState converts status of container in runtime to state of container
+func containerState(status cri.ContainerStatus) core.ContainerState {
+	if status.Running {
+		return core.ContainerState{Running: &core.ContainerStateRunning{StartedAt: status.StartedAt}}
+	}
+	reason := status.Reason
+	if reason == "" {
+		reason = terminatedReason(status.ExitCode)
+	}
+	return core.ContainerState{
+		Terminated: &core.ContainerStateTerminated{
+			ExitCode:    int32(status.ExitCode),
+			Reason:      reason,
+			StartedAt:   status.StartedAt,
+			FinishedAt:  status.FinishedAt,
+			ContainerID: status.ID,
+		},
+	}
+}
+
+// podStatusEqual compares statuses by their json, as times in status read from
+// ApiServer are not deeply equal to those of runtime even if they are the same
+func podStatusEqual(a, b core.PodStatus) bool {
+	aj, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bj, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(aj, bj)
+}
@@ -0,0 +1,39 @@
+package kubelet
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"minik8s/pkg/api"
+	"minik8s/pkg/kubelet/container/cri"
+	"minik8s/pkg/kubelet/streaming"
+	"net/http"
+	"strconv"
+)
+
+/*---------------------------- Port Forward ----------------------------*/
+
+// handlePortForward forwards one connection to port of pod, which is dialed in
+// network namespace of sandbox shared by all containers of pod. Data
+// from client is sent on stdin channel of websocket, and data from pod on stdout
+// GET /portForward/{pod}?port=
+func (k *kubelet) handlePortForward(c *gin.Context) {
+	port, err := strconv.ParseInt(c.Query(api.PortForwardPortParam), 10, 32)
+	if err != nil || port <= 0 || port > 65535 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "ERR", "error": fmt.Sprintf("invalid port %q", c.Query(api.PortForwardPortParam))})
+		return
+	}
+	pod, found := k.getRequestedPod(c)
+	if !found {
+		return
+	}
+
+	name := makePodSandboxName(pod)
+	query := streamQuery{stdin: true, stdout: true}
+	k.serveStreams(c, query, func(ctx context.Context, streams cri.StreamOptions) streaming.Status {
+		if err := k.criClient.PortForward(ctx, name, int32(port), streams.Stdin, streams.Stdout); err != nil {
+			return streaming.Status{Status: streaming.StatusFailure, Message: err.Error()}
+		}
+		return streaming.Status{Status: streaming.StatusSuccess}
+	})
+}
@@ -0,0 +1,127 @@
+package prober
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/kubelet/container/cri"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProbeType is the type of container probe
+type ProbeType string
+
+const (
+	Liveness  ProbeType = "Liveness"
+	Readiness ProbeType = "Readiness"
+	Startup   ProbeType = "Startup"
+)
+
+// Result is the result of a probe
+type Result string
+
+const (
+	Unknown Result = "Unknown"
+	Success Result = "Success"
+	Failure Result = "Failure"
+)
+
+// prober runs a single probe against a container
+type prober struct {
+	criClient cri.Client
+}
+
+// probe executes the probe handler once, containerName is the runtime
+// name of the container and podIP is used if handler does not set host
+func (pb *prober) probe(probe *core.Probe, containerName string, podIP string) (Result, string) {
+	timeout := time.Duration(probe.TimeoutSeconds) * time.Second
+
+	switch {
+	case probe.Exec != nil:
+		return pb.runExec(probe.Exec, containerName, timeout)
+	case probe.HTTPGet != nil:
+		return runHTTPGet(probe.HTTPGet, podIP, timeout)
+	case probe.TCPSocket != nil:
+		return runTCPSocket(probe.TCPSocket, podIP, timeout)
+	default:
+		return Unknown, "missing probe handler"
+	}
+}
+
+func (pb *prober) runExec(action *core.ExecAction, containerName string, timeout time.Duration) (Result, string) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	code, output, err := pb.criClient.ContainerExec(ctx, containerName, action.Command)
+	if err != nil {
+		return Failure, fmt.Sprintf("exec %v failed: %v", action.Command, err)
+	}
+	if code != 0 {
+		return Failure, fmt.Sprintf("exec %v exited with %d: %s", action.Command, code, output)
+	}
+	return Success, ""
+}
+
+func runHTTPGet(action *core.HTTPGetAction, podIP string, timeout time.Duration) (Result, string) {
+	host := action.Host
+	if host == "" {
+		host = podIP
+	}
+	scheme := action.Scheme
+	if scheme == "" {
+		scheme = core.URISchemeHTTP
+	}
+	u := url.URL{
+		Scheme: strings.ToLower(string(scheme)),
+		Host:   net.JoinHostPort(host, strconv.Itoa(int(action.Port))),
+		Path:   action.Path,
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return Failure, err.Error()
+	}
+	for _, header := range action.HTTPHeaders {
+		req.Header.Add(header.Name, header.Value)
+	}
+
+	// certificates are not verified, the same as kubernetes does
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			DisableKeepAlives: true,
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Failure, fmt.Sprintf("GET %s failed: %v", u.String(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusBadRequest {
+		return Success, ""
+	}
+	return Failure, fmt.Sprintf("GET %s returned status %d", u.String(), resp.StatusCode)
+}
+
+func runTCPSocket(action *core.TCPSocketAction, podIP string, timeout time.Duration) (Result, string) {
+	host := action.Host
+	if host == "" {
+		host = podIP
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(int(action.Port)))
+
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return Failure, fmt.Sprintf("dial %s failed: %v", address, err)
+	}
+	_ = conn.Close()
+	return Success, ""
+}
@@ -0,0 +1,180 @@
+package prober
+
+import (
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+	"minik8s/pkg/kubelet/container/cri"
+	"sync"
+)
+
+// Manager manages probe workers of containers running on the node,
+// kubelet reads probe results from it when it computes pod status
+type Manager interface {
+	// AddContainer starts probe workers for each probe of the container,
+	// runtimeName is the name of container in runtime
+	AddContainer(pod *core.Pod, container core.Container, runtimeName string, podIP string)
+	// RemoveContainer stops probe workers of the container
+	RemoveContainer(podUID types.UID, containerName string)
+	// RemovePod stops probe workers of all containers in the pod
+	RemovePod(podUID types.UID)
+	// GetResult returns the latest result of probe, and false
+	// if container has no such probe
+	GetResult(podUID types.UID, containerName string, probeType ProbeType) (Result, bool)
+	// ContainerRestarted resets results of all probes of the container,
+	// workers wait initial delay again before probing the new container
+	ContainerRestarted(podUID types.UID, containerName string)
+}
+
+// FailureHandler is called when liveness or startup probe of a
+// container fails, it is expected to kill the container
+type FailureHandler func(pod *core.Pod, container core.Container, probeType ProbeType, message string)
+
+type probeKey struct {
+	podUID        types.UID
+	containerName string
+	probeType     ProbeType
+}
+
+type containerKey struct {
+	podUID        types.UID
+	containerName string
+}
+
+type manager struct {
+	prober    *prober
+	onFailure FailureHandler
+
+	lock     sync.RWMutex
+	workers  map[probeKey]*worker
+	results  map[probeKey]Result
+	restarts map[containerKey]int
+}
+
+func NewManager(criClient cri.Client, onFailure FailureHandler) Manager {
+	return &manager{
+		prober:    &prober{criClient: criClient},
+		onFailure: onFailure,
+		workers:   make(map[probeKey]*worker),
+		results:   make(map[probeKey]Result),
+		restarts:  make(map[containerKey]int),
+	}
+}
+
+func (m *manager) AddContainer(pod *core.Pod, container core.Container, runtimeName string, podIP string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	probes := map[ProbeType]*core.Probe{
+		Liveness:  container.LivenessProbe,
+		Readiness: container.ReadinessProbe,
+		Startup:   container.StartupProbe,
+	}
+	for probeType, probe := range probes {
+		if probe == nil {
+			continue
+		}
+		key := probeKey{podUID: pod.UID, containerName: container.Name, probeType: probeType}
+		if _, found := m.workers[key]; found {
+			continue
+		}
+		w := newWorker(m, probeType, pod, container, runtimeName, podIP)
+		m.workers[key] = w
+		m.results[key] = initialResult(probeType)
+		go w.run()
+	}
+}
+
+func (m *manager) RemoveContainer(podUID types.UID, containerName string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for key, w := range m.workers {
+		if key.podUID == podUID && key.containerName == containerName {
+			w.stop()
+		}
+	}
+}
+
+func (m *manager) RemovePod(podUID types.UID) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for key, w := range m.workers {
+		if key.podUID == podUID {
+			w.stop()
+		}
+	}
+}
+
+func (m *manager) GetResult(podUID types.UID, containerName string, probeType ProbeType) (Result, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	key := probeKey{podUID: podUID, containerName: containerName, probeType: probeType}
+	if _, found := m.workers[key]; !found {
+		return Unknown, false
+	}
+	return m.results[key], true
+}
+
+// removeWorker is called by worker when it exits
+func (m *manager) removeWorker(w *worker) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.workers[w.key] != w {
+		return
+	}
+	delete(m.workers, w.key)
+	delete(m.results, w.key)
+
+	// forget restarts after all workers of container exit
+	ck := containerKey{podUID: w.key.podUID, containerName: w.key.containerName}
+	for key := range m.workers {
+		if key.podUID == ck.podUID && key.containerName == ck.containerName {
+			return
+		}
+	}
+	delete(m.restarts, ck)
+}
+
+func (m *manager) getResult(key probeKey) Result {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.results[key]
+}
+
+func (m *manager) setResult(key probeKey, result Result) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, found := m.workers[key]; found {
+		m.results[key] = result
+	}
+}
+
+func (m *manager) getRestartCount(key probeKey) int {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.restarts[containerKey{podUID: key.podUID, containerName: key.containerName}]
+}
+
+func (m *manager) ContainerRestarted(podUID types.UID, containerName string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	ck := containerKey{podUID: podUID, containerName: containerName}
+	m.restarts[ck]++
+	for key := range m.workers {
+		if key.podUID == ck.podUID && key.containerName == ck.containerName {
+			m.results[key] = initialResult(key.probeType)
+		}
+	}
+}
+
+// containerFailed hands the failed container to kubelet, results of its
+// probes are kept until kubelet restarts it
+func (m *manager) containerFailed(w *worker, message string) {
+	if m.onFailure != nil {
+		m.onFailure(w.pod, w.container, w.probeType, message)
+	}
+}
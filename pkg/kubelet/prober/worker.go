@@ -0,0 +1,180 @@
+package prober
+
+import (
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/logger"
+	"time"
+)
+
+// worker periodically probes a container with one kind of probe,
+// and reports result to manager after it is seen enough times in a row
+type worker struct {
+	stopCh chan struct{}
+
+	manager   *manager
+	key       probeKey
+	probeType ProbeType
+	spec      core.Probe
+
+	// the pod and container to probe
+	pod         *core.Pod
+	container   core.Container
+	runtimeName string
+	podIP       string
+
+	// last probe result and how many times it is seen in a row
+	lastResult Result
+	resultRun  int
+
+	// container restarts seen by this worker
+	restartCount int
+}
+
+func newWorker(m *manager, probeType ProbeType, pod *core.Pod, container core.Container, runtimeName, podIP string) *worker {
+	w := &worker{
+		stopCh:      make(chan struct{}, 1),
+		manager:     m,
+		key:         probeKey{podUID: pod.UID, containerName: container.Name, probeType: probeType},
+		probeType:   probeType,
+		pod:         pod,
+		container:   container,
+		runtimeName: runtimeName,
+		podIP:       podIP,
+		lastResult:  Unknown,
+	}
+
+	switch probeType {
+	case Liveness:
+		w.spec = *container.LivenessProbe
+	case Readiness:
+		w.spec = *container.ReadinessProbe
+	case Startup:
+		w.spec = *container.StartupProbe
+	}
+	setProbeDefaults(&w.spec)
+
+	return w
+}
+
+func setProbeDefaults(probe *core.Probe) {
+	if probe.TimeoutSeconds <= 0 {
+		probe.TimeoutSeconds = config.ProbeDefaultTimeoutSeconds
+	}
+	if probe.PeriodSeconds <= 0 {
+		probe.PeriodSeconds = config.ProbeDefaultPeriodSeconds
+	}
+	if probe.SuccessThreshold <= 0 {
+		probe.SuccessThreshold = config.ProbeDefaultSuccessThreshold
+	}
+	if probe.FailureThreshold <= 0 {
+		probe.FailureThreshold = config.ProbeDefaultFailureThreshold
+	}
+}
+
+// initialResult is the result before the first probe is reported, a container
+// is alive but neither ready nor started until proved by probe
+func initialResult(probeType ProbeType) Result {
+	if probeType == Liveness {
+		return Success
+	}
+	return Failure
+}
+
+func (w *worker) stop() {
+	select {
+	case w.stopCh <- struct{}{}:
+	default:
+	}
+}
+
+func (w *worker) run() {
+	defer w.manager.removeWorker(w)
+
+	if !w.wait(time.Duration(w.spec.InitialDelaySeconds) * time.Second) {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(w.spec.PeriodSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for w.doProbe() {
+		select {
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// wait sleeps for d, returns false if worker is stopped meanwhile
+func (w *worker) wait(d time.Duration) bool {
+	select {
+	case <-w.stopCh:
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+// doProbe probes the container once, returns false if worker should stop
+func (w *worker) doProbe() bool {
+	// container is restarted by kubelet, forget results of old container
+	// and wait initial delay again
+	if restartCount := w.manager.getRestartCount(w.key); restartCount != w.restartCount {
+		w.restartCount = restartCount
+		w.lastResult = Unknown
+		w.resultRun = 0
+		if !w.wait(time.Duration(w.spec.InitialDelaySeconds) * time.Second) {
+			return false
+		}
+	}
+
+	startupKey := w.key
+	startupKey.probeType = Startup
+	started := w.container.StartupProbe == nil || w.manager.getResult(startupKey) == Success
+	if w.probeType == Startup && started {
+		// startup probe is not run once container is started
+		return true
+	}
+	if w.probeType != Startup && !started {
+		// liveness and readiness probe are disabled until container is started
+		return true
+	}
+
+	result, message := w.manager.prober.probe(&w.spec, w.runtimeName, w.podIP)
+	if !w.observe(result) {
+		return true
+	}
+	if w.manager.getResult(w.key) == result {
+		return true
+	}
+
+	logger.KubeletLogger.Printf("[Prober] %s probe of container %s in pod %s: %s %s\n", w.probeType, w.container.Name, w.pod.Name, result, message)
+	w.manager.setResult(w.key, result)
+
+	if result == Failure && (w.probeType == Liveness || w.probeType == Startup) {
+		w.manager.containerFailed(w, message)
+	}
+	return true
+}
+
+// observe records result of a probe, returns true if the result is
+// seen enough times in a row to be reported
+func (w *worker) observe(result Result) bool {
+	if result == w.lastResult {
+		w.resultRun++
+	} else {
+		w.lastResult = result
+		w.resultRun = 1
+	}
+
+	switch result {
+	case Success:
+		return w.resultRun >= int(w.spec.SuccessThreshold)
+	case Failure:
+		return w.resultRun >= int(w.spec.FailureThreshold)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,42 @@
+package prober
+
+import (
+	"minik8s/pkg/api/core"
+	"testing"
+)
+
+func TestWorkerObserve(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     core.Probe
+		results  []Result
+		expected []bool
+	}{
+		{
+			name:     "default thresholds",
+			results:  []Result{Success, Failure, Failure, Failure, Success},
+			expected: []bool{true, false, false, true, true},
+		},
+		{
+			name:     "success threshold",
+			spec:     core.Probe{SuccessThreshold: 2, FailureThreshold: 1},
+			results:  []Result{Success, Failure, Success, Success, Success},
+			expected: []bool{false, true, false, true, true},
+		},
+		{
+			name:     "unknown result is never reported",
+			results:  []Result{Failure, Unknown, Failure, Failure, Failure},
+			expected: []bool{false, false, false, false, true},
+		},
+	}
+
+	for _, test := range tests {
+		w := &worker{spec: test.spec, lastResult: Unknown}
+		setProbeDefaults(&w.spec)
+		for i, result := range test.results {
+			if reported := w.observe(result); reported != test.expected[i] {
+				t.Errorf("%s: observe %v at %d got %v, expected %v", test.name, result, i, reported, test.expected[i])
+			}
+		}
+	}
+}
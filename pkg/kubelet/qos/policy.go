@@ -0,0 +1,60 @@
+package qos
+
+import (
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+)
+
+// oom_score_adj of processes by qos class, the kernel kills processes with
+// higher score first when node runs out of memory
+const (
+	// PodInfraOOMAdj is oom_score_adj of pause containers
+	PodInfraOOMAdj        = -998
+	guaranteedOOMScoreAdj = -997
+	besteffortOOMScoreAdj = 1000
+)
+
+// GetContainerOOMScoreAdjust returns oom_score_adj of container in pod, given
+// memory capacity of node in bytes. Guaranteed containers are killed last, and
+// BestEffort containers first. Burstable containers requesting more memory
+// have lower score, but always higher than Guaranteed and lower than BestEffort.
+func GetContainerOOMScoreAdjust(pod *core.Pod, container *core.Container, memoryCapacity uint64) int {
+	switch GetPodQOS(pod) {
+	case core.PodQOSGuaranteed:
+		return guaranteedOOMScoreAdj
+	case core.PodQOSBestEffort:
+		return besteffortOOMScoreAdj
+	}
+
+	memoryRequest := MemoryRequest(container)
+	if memoryCapacity == 0 {
+		return besteffortOOMScoreAdj - 1
+	}
+	adj := 1000 - int(1000*memoryRequest/memoryCapacity)
+	// a container requesting nearly all memory must still be killed before Guaranteed ones
+	if adj < 1000+guaranteedOOMScoreAdj {
+		return 1000 + guaranteedOOMScoreAdj
+	}
+	// and a container requesting little memory after BestEffort ones
+	if adj == besteffortOOMScoreAdj {
+		return adj - 1
+	}
+	return adj
+}
+
+// MemoryRequest returns memory request of container in bytes, which
+// defaults to its limit
+func MemoryRequest(container *core.Container) uint64 {
+	q, found := container.Resources.Requests[types.ResourceMemory]
+	if !found {
+		q, found = container.Resources.Limits[types.ResourceMemory]
+	}
+	if !found {
+		return 0
+	}
+	v, err := types.ParseQuantity(types.ResourceMemory, q)
+	if err != nil {
+		return 0
+	}
+	return v * 1024 * 1024
+}
@@ -0,0 +1,73 @@
+package qos
+
+import (
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+)
+
+// supportedQoSResources are resources considered when qos class is computed
+var supportedQoSResources = []types.ResourceName{types.ResourceCPU, types.ResourceMemory}
+
+// GetPodQOS returns qos class of pod. A pod is Guaranteed if every container
+// has cpu and memory limits equal to its requests, BestEffort if no container
+// has any cpu or memory request or limit, and Burstable otherwise. Requests
+// of a container default to its limits.
+func GetPodQOS(pod *core.Pod) core.PodQOSClass {
+	requests := make(map[types.ResourceName]uint64)
+	limits := make(map[types.ResourceName]uint64)
+	isGuaranteed := true
+	containers := append(append([]core.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, container := range containers {
+		containerLimits := quantities(container.Resources.Limits)
+		containerRequests := quantities(container.Resources.Requests)
+		for name, q := range containerLimits {
+			if _, found := containerRequests[name]; !found {
+				containerRequests[name] = q
+			}
+		}
+
+		for name, q := range containerRequests {
+			requests[name] += q
+		}
+		for name, q := range containerLimits {
+			limits[name] += q
+		}
+		if len(containerLimits) != len(supportedQoSResources) {
+			isGuaranteed = false
+		}
+	}
+
+	if len(requests) == 0 && len(limits) == 0 {
+		return core.PodQOSBestEffort
+	}
+	if isGuaranteed {
+		for _, name := range supportedQoSResources {
+			if requests[name] != limits[name] {
+				isGuaranteed = false
+				break
+			}
+		}
+	}
+	if isGuaranteed {
+		return core.PodQOSGuaranteed
+	}
+	return core.PodQOSBurstable
+}
+
+// quantities returns nonzero quantities of cpu and memory in list,
+// unrecognized quantities are ignored
+func quantities(list core.ResourceList) map[types.ResourceName]uint64 {
+	res := make(map[types.ResourceName]uint64)
+	for _, name := range supportedQoSResources {
+		q, found := list[name]
+		if !found {
+			continue
+		}
+		v, err := types.ParseQuantity(name, q)
+		if err != nil || v == 0 {
+			continue
+		}
+		res[name] = v
+	}
+	return res
+}
@@ -0,0 +1,92 @@
+package qos
+
+import (
+	"minik8s/pkg/api/core"
+	"testing"
+)
+
+func newContainer(requests, limits core.ResourceList) core.Container {
+	return core.Container{Name: "c", Resources: core.ResourceRequirements{Requests: requests, Limits: limits}}
+}
+
+func TestGetPodQOS(t *testing.T) {
+	full := core.ResourceList{"cpu": "500m", "memory": "256M"}
+	tests := []struct {
+		name       string
+		containers []core.Container
+		init       []core.Container
+		want       core.PodQOSClass
+	}{
+		{
+			name:       "no resources",
+			containers: []core.Container{newContainer(nil, nil), newContainer(nil, nil)},
+			want:       core.PodQOSBestEffort,
+		},
+		{
+			name:       "limits equal requests",
+			containers: []core.Container{newContainer(full, full)},
+			want:       core.PodQOSGuaranteed,
+		},
+		{
+			name:       "requests default to limits",
+			containers: []core.Container{newContainer(nil, full), newContainer(nil, full)},
+			want:       core.PodQOSGuaranteed,
+		},
+		{
+			name:       "requests lower than limits",
+			containers: []core.Container{newContainer(core.ResourceList{"cpu": "100m", "memory": "256M"}, full)},
+			want:       core.PodQOSBurstable,
+		},
+		{
+			name:       "container without memory limit",
+			containers: []core.Container{newContainer(full, full), newContainer(nil, core.ResourceList{"cpu": "1"})},
+			want:       core.PodQOSBurstable,
+		},
+		{
+			name:       "only requests",
+			containers: []core.Container{newContainer(core.ResourceList{"memory": "64M"}, nil)},
+			want:       core.PodQOSBurstable,
+		},
+		{
+			name:       "init container counts",
+			init:       []core.Container{newContainer(core.ResourceList{"cpu": "100m"}, nil)},
+			containers: []core.Container{newContainer(nil, nil)},
+			want:       core.PodQOSBurstable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &core.Pod{}
+			pod.Spec.InitContainers = tt.init
+			pod.Spec.Containers = tt.containers
+			if got := GetPodQOS(pod); got != tt.want {
+				t.Errorf("GetPodQOS() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetContainerOOMScoreAdjust(t *testing.T) {
+	const capacity = 4096 * 1024 * 1024
+	tests := []struct {
+		name     string
+		requests core.ResourceList
+		limits   core.ResourceList
+		want     int
+	}{
+		{name: "guaranteed", limits: core.ResourceList{"cpu": "1", "memory": "1024M"}, want: -997},
+		{name: "besteffort", want: 1000},
+		{name: "burstable by memory request", requests: core.ResourceList{"memory": "1024M"}, want: 750},
+		{name: "burstable without memory request", requests: core.ResourceList{"cpu": "1"}, want: 999},
+		{name: "burstable requesting all memory", requests: core.ResourceList{"memory": "4096M"}, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &core.Pod{}
+			pod.Spec.Containers = []core.Container{newContainer(tt.requests, tt.limits)}
+			if got := GetContainerOOMScoreAdjust(pod, &pod.Spec.Containers[0], capacity); got != tt.want {
+				t.Errorf("GetContainerOOMScoreAdjust() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
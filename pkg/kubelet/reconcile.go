@@ -0,0 +1,183 @@
+package kubelet
+
+import (
+	"context"
+	"log"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+	"minik8s/pkg/kubelet/container/cri"
+	"minik8s/pkg/kubelet/qos"
+	"minik8s/pkg/logger"
+	"strings"
+)
+
+/*---------------------------- Reconcile ----------------------------*/
+
+// runtimePod is sandbox and containers of a pod found in runtime when kubelet
+// starts, which are left by kubelet before restart
+type runtimePod struct {
+	sandbox *cri.SandboxInfo
+	// containers are keyed by name of container in spec of pod
+	containers map[string]cri.ContainerInfo
+}
+
+// listRuntimePods returns sandboxes and containers in runtime by uid of their pods
+func listRuntimePods(ctx context.Context, runtime cri.Client) (map[types.UID]*runtimePod, error) {
+	sandboxes, err := runtime.ListPodSandboxes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := runtime.ListContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := make(map[types.UID]*runtimePod)
+	get := func(uid types.UID) *runtimePod {
+		if _, found := pods[uid]; !found {
+			pods[uid] = &runtimePod{containers: make(map[string]cri.ContainerInfo)}
+		}
+		return pods[uid]
+	}
+	for i := range sandboxes {
+		if sandboxes[i].PodUID != "" {
+			get(sandboxes[i].PodUID).sandbox = &sandboxes[i]
+		}
+	}
+	for _, container := range containers {
+		if container.PodUID != "" {
+			name := strings.TrimPrefix(container.Name, container.PodUID+"-")
+			get(container.PodUID).containers[name] = container
+		}
+	}
+	return pods, nil
+}
+
+// adoptPod takes over sandbox and containers of pod left in runtime, so that pod
+// is not restarted after kubelet restarts. Containers of spec missing in runtime
+// are started, and those not in spec are removed. It returns false if nothing
+// of pod is left, or its sandbox is not ready and is removed, then pod should
+// be created. It should be called with k.lock held
+func (k *kubelet) adoptPod(ctx context.Context, pod *core.Pod) bool {
+	left, found := k.runtimePods[pod.UID]
+	if !found {
+		return false
+	}
+	delete(k.runtimePods, pod.UID)
+	if left.sandbox == nil || !left.sandbox.Ready {
+		logger.KubeletLogger.Printf("Sandbox of pod %s is not ready, remove what is left of it\n", pod.Name)
+		k.removeRuntimePod(ctx, left)
+		return false
+	}
+
+	logger.KubeletLogger.Printf("Adopt pod %s left in runtime\n", pod.Name)
+	pod.Status.QOSClass = qos.GetPodQOS(pod)
+	if err := k.podContainerManager.EnsureExists(pod); err != nil {
+		log.Println("[ERROR]: failed to create cgroup of pod", pod.Name, err.Error())
+	}
+	k.podManager.AddPod(pod)
+
+	inSpec := make(map[string]bool)
+	for _, container := range pod.Spec.InitContainers {
+		inSpec[container.Name] = true
+	}
+	var adopted, missing []core.Container
+	for _, container := range pod.Spec.Containers {
+		inSpec[container.Name] = true
+		if _, found := left.containers[container.Name]; found {
+			adopted = append(adopted, container)
+		} else {
+			missing = append(missing, container)
+		}
+	}
+	for name, container := range left.containers {
+		if !inSpec[name] {
+			k.removeRuntimeContainer(ctx, container)
+			delete(left.containers, name)
+		}
+	}
+
+	// pod is still initializing, init containers are run again from the first,
+	// unless pod has failed in them
+	if len(adopted) == 0 {
+		if isPodFinished(pod) {
+			return true
+		}
+		for _, container := range left.containers {
+			k.removeRuntimeContainer(ctx, container)
+		}
+		go k.startPod(ctx, pod)
+		return true
+	}
+
+	// restarts before kubelet restarts are still counted
+	for _, container := range adopted {
+		state := k.getRestartState(makePodContainerName(pod, container))
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name == container.Name {
+				state.restartCount = status.RestartCount
+				state.lastState = status.LastTerminationState
+			}
+		}
+	}
+	k.addProbes(ctx, pod, adopted)
+	go k.resumePod(ctx, pod, missing)
+	return true
+}
+
+// isPodFinished returns true if pod has succeeded or failed, which is not run again
+func isPodFinished(pod *core.Pod) bool {
+	return pod.Status.Phase == core.PodSucceeded || pod.Status.Phase == core.PodFailed
+}
+
+// resumePod sets up volumes of adopted pod again, and starts its containers
+// missing in runtime, containers left in runtime are watched as they are
+func (k *kubelet) resumePod(ctx context.Context, pod *core.Pod, missing []core.Container) {
+	if !k.setUpVolumes(pod) {
+		return
+	}
+	if len(missing) > 0 {
+		k.startContainers(ctx, pod, missing)
+		return
+	}
+	k.startWatchContainers(ctx, *pod)
+}
+
+// removeOrphanPods removes sandboxes and containers left in runtime whose pods
+// are not adopted, i.e. pods deleted or moved while kubelet is down
+func (k *kubelet) removeOrphanPods(ctx context.Context) {
+	k.lock.Lock()
+	orphans := k.runtimePods
+	k.runtimePods = nil
+	k.lock.Unlock()
+
+	for uid, left := range orphans {
+		logger.KubeletLogger.Printf("Remove orphan pod %s left in runtime\n", uid)
+		k.removeRuntimePod(ctx, left)
+		if err := k.podContainerManager.DestroyByUID(uid); err != nil {
+			log.Println("[ERROR]: failed to remove cgroup of orphan pod", uid, err.Error())
+		}
+	}
+}
+
+// removeRuntimePod removes containers and sandbox of pod left in runtime
+func (k *kubelet) removeRuntimePod(ctx context.Context, left *runtimePod) {
+	for _, container := range left.containers {
+		k.removeRuntimeContainer(ctx, container)
+	}
+	if left.sandbox == nil {
+		return
+	}
+	if err := k.criClient.StopPodSandbox(ctx, left.sandbox.Name); err != nil {
+		log.Println("[ERROR]: failed to stop pod sandbox", left.sandbox.Name, err.Error())
+	}
+	if err := k.criClient.RemovePodSandbox(ctx, left.sandbox.Name); err != nil {
+		log.Println("[ERROR]: failed to remove pod sandbox", left.sandbox.Name, err.Error())
+	}
+}
+
+func (k *kubelet) removeRuntimeContainer(ctx context.Context, container cri.ContainerInfo) {
+	if err := k.criClient.ContainerRemove(ctx, container.Name); err != nil {
+		log.Println("[ERROR]: failed to remove container", container.Name, err.Error())
+	}
+}
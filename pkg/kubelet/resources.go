@@ -0,0 +1,49 @@
+package kubelet
+
+import (
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+	"minik8s/pkg/kubelet/cm"
+	"minik8s/pkg/kubelet/container/cri"
+	"minik8s/pkg/kubelet/qos"
+)
+
+/*---------------------------- Resources ----------------------------*/
+
+// makeContainerResources returns cgroup settings of container, cpu shares are
+// from cpu request, which defaults to limit, cpu quota and memory limit are from
+// limits, and oom_score_adj is by qos class of pod
+func (k *kubelet) makeContainerResources(pod *core.Pod, container *core.Container) cri.Resources {
+	res := cri.Resources{CPUPeriod: cm.DefaultCPUPeriod}
+
+	cpuRequest, cpuRequested := parseResource(container.Resources.Requests, types.ResourceCPU)
+	cpuLimit, cpuLimited := parseResource(container.Resources.Limits, types.ResourceCPU)
+	if !cpuRequested {
+		cpuRequest = cpuLimit
+	}
+	res.CPUShares = int64(cm.MilliCPUToShares(cpuRequest))
+	if cpuLimited {
+		res.CPUQuota = cm.MilliCPUToQuota(cpuLimit, cm.DefaultCPUPeriod)
+	}
+	if memoryLimit, found := parseResource(container.Resources.Limits, types.ResourceMemory); found {
+		res.MemoryLimit = int64(memoryLimit * 1024 * 1024)
+	}
+
+	memoryCapacity, _ := parseResource(k.nodeCapacity, types.ResourceMemory)
+	res.OOMScoreAdj = qos.GetContainerOOMScoreAdjust(pod, container, memoryCapacity*1024*1024)
+	return res
+}
+
+// parseResource returns quantity of resource in list in unit of types.ParseQuantity,
+// and false if it is absent or not recognized
+func parseResource(list core.ResourceList, name types.ResourceName) (uint64, bool) {
+	q, found := list[name]
+	if !found {
+		return 0, false
+	}
+	v, err := types.ParseQuantity(name, q)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
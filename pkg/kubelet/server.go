@@ -0,0 +1,77 @@
+package kubelet
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"log"
+	"minik8s/config"
+	"minik8s/pkg/api"
+	"minik8s/pkg/api/core"
+	"net/http"
+)
+
+/*---------------------------- Kubelet Server ----------------------------*/
+
+// serve runs http server of kubelet, ApiServer proxies requests
+// on containers of pods to the node they run on
+func (k *kubelet) serve() {
+	router := gin.Default()
+
+	// Read logs of container in pod
+	// GET /containerLogs/{pod}/{container}
+	router.GET(api.ContainerLogsURL, k.handleContainerLogs)
+	// Run command in container, upgraded to websocket
+	// GET /exec/{pod}/{container}
+	router.GET(api.ContainerExecURL, k.handleExec)
+	// Attach to main process of container, upgraded to websocket
+	// GET /attach/{pod}/{container}
+	router.GET(api.ContainerAttachURL, k.handleAttach)
+	// Forward one connection to port of pod, upgraded to websocket
+	// GET /portForward/{pod}
+	router.GET(api.PortForwardURL, k.handlePortForward)
+
+	if err := router.Run(config.KubeletPort); err != nil {
+		log.Printf("[Kubelet] Kubelet server exit: %v\n", err)
+	}
+}
+
+// getRequestedPod gets pod in path of request, it responds 404
+// if it is not found on this node
+func (k *kubelet) getRequestedPod(c *gin.Context) (*core.Pod, bool) {
+	pod, found := k.podManager.GetPodByUID(c.Param("pod"))
+	if !found || pod.Spec.NodeName != k.node.Name {
+		c.JSON(http.StatusNotFound, gin.H{"status": "ERR", "error": fmt.Sprintf("pod %s not found on node %s", c.Param("pod"), k.node.Name)})
+		return nil, false
+	}
+	return pod, true
+}
+
+// getRequestedContainer gets pod and container in path of request,
+// it responds 404 if they are not found on this node
+func (k *kubelet) getRequestedContainer(c *gin.Context) (*core.Pod, core.Container, bool) {
+	pod, found := k.getRequestedPod(c)
+	if !found {
+		return nil, core.Container{}, false
+	}
+	container, found := findPodContainer(pod, c.Param("container"))
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"status": "ERR", "error": fmt.Sprintf("container %s is not valid for pod %s", c.Param("container"), pod.Name)})
+		return nil, core.Container{}, false
+	}
+	return pod, container, true
+}
+
+// findPodContainer finds container or init container of pod by name
+func findPodContainer(pod *core.Pod, name string) (core.Container, bool) {
+	for _, container := range pod.Spec.InitContainers {
+		if container.Name == name {
+			return container, true
+		}
+	}
+	for _, container := range pod.Spec.Containers {
+		if container.Name == name {
+			return container, true
+		}
+	}
+	return core.Container{}, false
+}
@@ -0,0 +1,127 @@
+package kubelet
+
+import (
+	"context"
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+	"minik8s/pkg/kubelet/staticpod"
+	"minik8s/pkg/logger"
+	"time"
+)
+
+/*---------------------------- Static Pods ----------------------------*/
+
+// StartStaticPods runs static pods from manifest directory of node, which needs no
+// ApiServer. Static pods are adopted or started at once, so that those left in runtime
+// are not removed as orphans, and manifests are checked again in background. Mirror
+// pods of them are published once ApiServer is reachable.
+func (k *kubelet) StartStaticPods(ctx context.Context) {
+	logger.KubeletLogger.Printf("Start static pods from %s\n", k.staticPodPath)
+	k.updateStaticPods()
+	go k.syncStaticPods(ctx)
+}
+
+// syncStaticPods checks manifest directory periodically, static pods are started when
+// their manifests are added, and killed when manifests are removed. Pod is restarted
+// if its manifest changes, as it then has a different uid.
+func (k *kubelet) syncStaticPods(ctx context.Context) {
+	for {
+		k.updateStaticPods()
+		k.syncMirrorPods()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(config.StaticPodCheckPeriod):
+		}
+	}
+}
+
+func (k *kubelet) updateStaticPods() {
+	pods, err := staticpod.ReadManifests(k.staticPodPath, k.node.Name)
+	if err != nil {
+		logger.KubeletLogger.Printf("Read static pods from %s: %v\n", k.staticPodPath, err)
+		if pods == nil {
+			return
+		}
+	}
+
+	seen := make(map[types.UID]bool)
+	for _, pod := range pods {
+		seen[pod.UID] = true
+		if _, found := k.staticPods[pod.UID]; found {
+			continue
+		}
+		logger.KubeletLogger.Printf("Static pod %s is added\n", pod.Name)
+		k.staticPods[pod.UID] = pod
+		k.handlePodModify(pod)
+	}
+	for uid, pod := range k.staticPods {
+		if seen[uid] {
+			continue
+		}
+		logger.KubeletLogger.Printf("Static pod %s is removed\n", pod.Name)
+		delete(k.staticPods, uid)
+		k.handlePodDelete(pod)
+	}
+}
+
+// syncMirrorPods publishes mirror pods of static pods to ApiServer, and deletes mirror
+// pods whose static pods are removed. Mirror pods deleted by users are published again.
+func (k *kubelet) syncMirrorPods() {
+	list, err := k.podClient.GetAll()
+	if err != nil {
+		logger.KubeletLogger.Printf("List pods for mirror pods failed, ApiServer may not be ready: %v\n", err)
+		return
+	}
+
+	mirrors := make(map[types.UID]*core.Pod)
+	for _, item := range list.GetIApiObjectArr() {
+		p := item.(*core.Pod)
+		if p.Spec.NodeName != k.node.Name || !p.IsMirrorPod() {
+			continue
+		}
+		hash := staticpod.MirrorPodHash(p)
+		_, running := k.staticPods[hash]
+		if _, found := mirrors[hash]; found || !running {
+			logger.KubeletLogger.Printf("Delete mirror pod %s of static pod not running\n", p.Name)
+			if _, _, err = k.podClient.Delete(p.UID); err != nil {
+				logger.KubeletLogger.Printf("Delete mirror pod %s failed: %v\n", p.Name, err)
+			}
+			continue
+		}
+		mirrors[hash] = p
+	}
+
+	mirrorUIDs := make(map[types.UID]types.UID)
+	for uid, pod := range k.staticPods {
+		if mirror, found := mirrors[uid]; found {
+			mirrorUIDs[uid] = mirror.UID
+			continue
+		}
+		_, resp, err := k.podClient.Post(staticpod.NewMirrorPod(pod))
+		if err != nil {
+			logger.KubeletLogger.Printf("Create mirror pod of static pod %s failed: %v\n", pod.Name, err)
+			continue
+		}
+		logger.KubeletLogger.Printf("Mirror pod %s of static pod %s is created\n", resp.UID, pod.Name)
+		mirrorUIDs[uid] = resp.UID
+	}
+
+	k.mirrorLock.Lock()
+	k.mirrorPods = mirrorUIDs
+	k.mirrorLock.Unlock()
+}
+
+// podStatusUID returns uid of pod in ApiServer which status of pod is reported to,
+// it is uid of mirror pod for static pod, and false if mirror pod is not created yet
+func (k *kubelet) podStatusUID(pod *core.Pod) (types.UID, bool) {
+	if !pod.IsStaticPod() {
+		return pod.UID, true
+	}
+	k.mirrorLock.Lock()
+	defer k.mirrorLock.Unlock()
+	uid, found := k.mirrorPods[pod.UID]
+	return uid, found
+}
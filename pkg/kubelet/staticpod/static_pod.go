@@ -0,0 +1,131 @@
+package staticpod
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"minik8s/pkg/api/core"
+	"minik8s/utils"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// manifestExtensions are extensions of manifest files parsed by utils.GetFormJsonData
+var manifestExtensions = map[string]bool{".json": true, ".yaml": true, ".yml": true}
+
+// ReadManifests reads static pods of node from json and yaml files in dir, hidden
+// files and files of other extensions are ignored. Files that fail to parse are
+// skipped and reported in the returned error. No pod is read if dir does not exist.
+func ReadManifests(dir string, nodeName string) ([]*core.Pod, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pods := make([]*core.Pod, 0)
+	var errs []string
+	names := make(map[string]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !manifestExtensions[filepath.Ext(name)] {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		pod, err := readManifest(path, nodeName)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		if other, found := names[pod.Namespace+"/"+pod.Name]; found {
+			errs = append(errs, fmt.Sprintf("%s: pod %s is already defined in %s", path, pod.Name, other))
+			continue
+		}
+		names[pod.Namespace+"/"+pod.Name] = path
+		pods = append(pods, pod)
+	}
+	if len(errs) > 0 {
+		return pods, errors.New(strings.Join(errs, "; "))
+	}
+	return pods, nil
+}
+
+func readManifest(path string, nodeName string) (*core.Pod, error) {
+	data, err := utils.GetFormJsonData(path)
+	if err != nil {
+		return nil, err
+	}
+	pod := &core.Pod{}
+	if err = json.Unmarshal(data, pod); err != nil {
+		return nil, err
+	}
+	if pod.Kind != "" && pod.Kind != "Pod" {
+		return nil, fmt.Errorf("kind %s is not Pod", pod.Kind)
+	}
+	if pod.Name == "" {
+		return nil, errors.New("name of pod is empty")
+	}
+	if len(pod.Spec.Containers) == 0 {
+		return nil, errors.New("pod has no container")
+	}
+	if err = applyDefaults(pod, nodeName); err != nil {
+		return nil, err
+	}
+	return pod, nil
+}
+
+// applyDefaults binds static pod to node, and names it after node as pods of
+// the same manifest on different nodes are different pods. Uid of pod is hash
+// of the pod, so that pod is restarted with a new uid once manifest changes.
+func applyDefaults(pod *core.Pod, nodeName string) error {
+	pod.Name = pod.Name + "-" + nodeName
+	if pod.Namespace == "" {
+		pod.Namespace = "default"
+	}
+	pod.Spec.NodeName = nodeName
+	pod.UID = ""
+	pod.ResourceVersion = ""
+	pod.Status = core.PodStatus{}
+	delete(pod.Annotations, core.ConfigMirrorAnnotationKey)
+
+	data, err := json.Marshal(pod)
+	if err != nil {
+		return err
+	}
+	hash := md5.Sum(data)
+	pod.UID = hex.EncodeToString(hash[:])
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[core.ConfigSourceAnnotationKey] = core.ConfigSourceFile
+	pod.Annotations[core.ConfigHashAnnotationKey] = pod.UID
+	return nil
+}
+
+// NewMirrorPod returns mirror pod of static pod to be published to ApiServer,
+// which has the same spec, and whose uid is assigned by ApiServer
+func NewMirrorPod(pod *core.Pod) *core.Pod {
+	mirror := &core.Pod{
+		TypeMeta:   pod.TypeMeta,
+		ObjectMeta: pod.ObjectMeta,
+		Spec:       pod.Spec,
+	}
+	mirror.UID = ""
+	mirror.ResourceVersion = ""
+	mirror.Annotations = make(map[string]string)
+	for k, v := range pod.Annotations {
+		mirror.Annotations[k] = v
+	}
+	mirror.Annotations[core.ConfigMirrorAnnotationKey] = pod.UID
+	return mirror
+}
+
+// MirrorPodHash returns uid of static pod the mirror pod is of
+func MirrorPodHash(mirror *core.Pod) string {
+	return mirror.Annotations[core.ConfigMirrorAnnotationKey]
+}
@@ -0,0 +1,103 @@
+package staticpod
+
+import (
+	"minik8s/pkg/api/core"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestReadManifests(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"web.json":     `{"kind": "Pod", "metadata": {"name": "web"}, "spec": {"containers": [{"name": "nginx", "image": "nginx"}]}}`,
+		"cache.yaml":   "kind: Pod\nmetadata:\n  name: cache\n  namespace: infra\nspec:\n  containers:\n  - name: redis\n    image: redis\n",
+		"web-copy.yml": "metadata:\n  name: web\nspec:\n  containers:\n  - name: nginx\n    image: nginx\n",
+		"service.json": `{"kind": "Service", "metadata": {"name": "svc"}}`,
+		"invalid.json": `{"metadata": {"name": `,
+		".hidden.json": `{"metadata": {"name": "hidden"}, "spec": {"containers": [{"name": "a", "image": "a"}]}}`,
+		"README.md":    "manifests of static pods",
+		"empty.json":   `{"metadata": {"name": "empty"}}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub.json"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	pods, err := ReadManifests(dir, "node1")
+	if err == nil {
+		t.Errorf("ReadManifests() error = nil, want errors of service.json, invalid.json, empty.json and duplicated web")
+	}
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.Namespace+"/"+pod.Name)
+		if pod.Spec.NodeName != "node1" {
+			t.Errorf("pod %s is bound to %q, want node1", pod.Name, pod.Spec.NodeName)
+		}
+		if !pod.IsStaticPod() || pod.Annotations[core.ConfigHashAnnotationKey] != pod.UID || len(pod.UID) != 32 {
+			t.Errorf("pod %s has uid %q and annotations %v", pod.Name, pod.UID, pod.Annotations)
+		}
+	}
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "default/web-node1" || names[1] != "infra/cache-node1" {
+		t.Errorf("ReadManifests() reads pods %v, want [default/web-node1 infra/cache-node1]", names)
+	}
+
+	// uid is stable until manifest changes
+	again, _ := ReadManifests(dir, "node1")
+	other, _ := ReadManifests(dir, "node2")
+	uids := func(pods []*core.Pod) map[string]string {
+		m := make(map[string]string)
+		for _, pod := range pods {
+			m[pod.Namespace] = pod.UID
+		}
+		return m
+	}
+	if a, b := uids(pods), uids(again); !reflect.DeepEqual(a, b) {
+		t.Errorf("uids of pods change without manifests changed: %v, %v", a, b)
+	}
+	if a, b := uids(pods), uids(other); a["default"] == b["default"] {
+		t.Errorf("pods on different nodes have the same uid %v", a["default"])
+	}
+
+	if pods, err = ReadManifests(filepath.Join(dir, "missing"), "node1"); pods != nil || err != nil {
+		t.Errorf("ReadManifests() of missing dir = %v, %v, want nil, nil", pods, err)
+	}
+}
+
+func TestNewMirrorPod(t *testing.T) {
+	pod := &core.Pod{Spec: core.PodSpec{Containers: []core.Container{{Name: "app", Image: "app"}}}}
+	pod.Name = "app"
+	if err := applyDefaults(pod, "node1"); err != nil {
+		t.Fatal(err)
+	}
+
+	mirror := NewMirrorPod(pod)
+	if !mirror.IsMirrorPod() || MirrorPodHash(mirror) != pod.UID {
+		t.Errorf("NewMirrorPod() has annotations %v, want mirror of %s", mirror.Annotations, pod.UID)
+	}
+	if mirror.UID != "" || mirror.Name != pod.Name || mirror.Spec.NodeName != "node1" {
+		t.Errorf("NewMirrorPod() = %+v", mirror.ObjectMeta)
+	}
+	if pod.IsMirrorPod() {
+		t.Errorf("annotations of static pod are changed by NewMirrorPod()")
+	}
+
+	// mirror annotation in manifest does not change the static pod
+	copied := &core.Pod{Spec: pod.Spec}
+	copied.Name = "app"
+	copied.Annotations = map[string]string{core.ConfigMirrorAnnotationKey: "x"}
+	copied.Spec.NodeName = ""
+	if err := applyDefaults(copied, "node1"); err != nil {
+		t.Fatal(err)
+	}
+	if copied.IsMirrorPod() {
+		t.Errorf("static pod read from manifest is a mirror pod")
+	}
+}
@@ -0,0 +1,200 @@
+package streaming
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"golang.org/x/net/websocket"
+	"io"
+	"sync"
+)
+
+// Streams of exec and attach are multiplexed over one websocket connection,
+// each binary message starts with a byte of the channel it belongs to
+const (
+	StdinChannel byte = iota
+	StdoutChannel
+	StderrChannel
+	// ErrorChannel carries Status sent by server before connection is closed
+	ErrorChannel
+	// ResizeChannel carries TerminalSize sent by client
+	ResizeChannel
+)
+
+// Status of remote command
+const (
+	StatusSuccess = "Success"
+	StatusFailure = "Failure"
+)
+
+// TerminalSize is width and height of terminal in characters
+type TerminalSize struct {
+	Width  uint16 `json:"width"`
+	Height uint16 `json:"height"`
+}
+
+// Status is result of remote command, ExitCode is exit code of
+// exec command, and zero for attach
+type Status struct {
+	Status   string `json:"status"`
+	Message  string `json:"message,omitempty"`
+	ExitCode int    `json:"exitCode"`
+}
+
+// Conn multiplexes channels over websocket connection, it is safe
+// to write channels from different goroutines
+type Conn struct {
+	ws   *websocket.Conn
+	lock sync.Mutex
+}
+
+func NewConn(ws *websocket.Conn) *Conn {
+	return &Conn{ws: ws}
+}
+
+// Dial connects to url of exec or attach, which is ws:// url
+func Dial(url string) (*Conn, error) {
+	ws, err := websocket.Dial(url, "", "http://localhost/")
+	if err != nil {
+		return nil, err
+	}
+	return NewConn(ws), nil
+}
+
+// Write sends data on channel, data of stdin being empty means stdin is closed
+func (c *Conn) Write(channel byte, data []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return websocket.Message.Send(c.ws, append([]byte{channel}, data...))
+}
+
+// Read receives next message and returns its channel and data
+func (c *Conn) Read() (byte, []byte, error) {
+	for {
+		var msg []byte
+		if err := websocket.Message.Receive(c.ws, &msg); err != nil {
+			return 0, nil, err
+		}
+		if len(msg) > 0 {
+			return msg[0], msg[1:], nil
+		}
+	}
+}
+
+func (c *Conn) WriteStatus(status Status) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	return c.Write(ErrorChannel, data)
+}
+
+func (c *Conn) Close() error {
+	return c.ws.Close()
+}
+
+func (c *Conn) WriteSize(size TerminalSize) error {
+	data, err := json.Marshal(size)
+	if err != nil {
+		return err
+	}
+	return c.Write(ResizeChannel, data)
+}
+
+// Writer returns writer of channel
+func (c *Conn) Writer(channel byte) io.Writer {
+	return &channelWriter{conn: c, channel: channel}
+}
+
+type channelWriter struct {
+	conn    *Conn
+	channel byte
+}
+
+func (w *channelWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if err := w.conn.Write(w.channel, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// ServerStreams are streams of remote command on server side, nil
+// streams are not requested by client
+type ServerStreams struct {
+	Stdin  io.ReadCloser
+	Stdout io.Writer
+	Stderr io.Writer
+	Resize <-chan TerminalSize
+}
+
+// ServeStreams reads messages from client in background until connection is
+// closed, which cancels returned context
+func (c *Conn) ServeStreams(ctx context.Context, stdin, stdout, stderr bool) (context.Context, *ServerStreams) {
+	ctx, cancel := context.WithCancel(ctx)
+	streams := &ServerStreams{}
+	var stdinWriter *io.PipeWriter
+	if stdin {
+		streams.Stdin, stdinWriter = io.Pipe()
+	}
+	if stdout {
+		streams.Stdout = c.Writer(StdoutChannel)
+	}
+	if stderr {
+		streams.Stderr = c.Writer(StderrChannel)
+	}
+	resize := make(chan TerminalSize, 1)
+	streams.Resize = resize
+
+	go func() {
+		defer cancel()
+		defer func() {
+			if stdinWriter != nil {
+				_ = stdinWriter.Close()
+			}
+		}()
+		for {
+			channel, data, err := c.Read()
+			if err != nil {
+				return
+			}
+			switch channel {
+			case StdinChannel:
+				if stdinWriter == nil {
+					continue
+				}
+				if len(data) == 0 {
+					_ = stdinWriter.Close()
+					stdinWriter = nil
+					continue
+				}
+				if _, err = stdinWriter.Write(data); err != nil {
+					stdinWriter = nil
+				}
+			case ResizeChannel:
+				size := TerminalSize{}
+				if json.Unmarshal(data, &size) != nil {
+					continue
+				}
+				// only the latest size matters
+				select {
+				case <-resize:
+				default:
+				}
+				resize <- size
+			}
+		}
+	}()
+	return ctx, streams
+}
+
+// ReadStatus parses status sent on ErrorChannel
+func ReadStatus(data []byte) (Status, error) {
+	status := Status{}
+	if err := json.Unmarshal(data, &status); err != nil {
+		return status, errors.New("invalid status of remote command: " + string(data))
+	}
+	return status, nil
+}
@@ -0,0 +1,63 @@
+package streaming
+
+import (
+	"context"
+	"golang.org/x/net/websocket"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStreams(t *testing.T) {
+	server := httptest.NewServer(websocket.Server{Handler: func(ws *websocket.Conn) {
+		conn := NewConn(ws)
+		defer conn.Close()
+		_, streams := conn.ServeStreams(context.Background(), true, true, true)
+
+		size := <-streams.Resize
+		_, _ = io.WriteString(streams.Stderr, "resized")
+		// echo stdin until it is closed
+		n, _ := io.Copy(streams.Stdout, streams.Stdin)
+		_ = conn.WriteStatus(Status{Status: StatusFailure, ExitCode: int(n) + int(size.Width)})
+	}})
+	defer server.Close()
+
+	conn, err := Dial("ws" + strings.TrimPrefix(server.URL, "http"))
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	if err = conn.WriteSize(TerminalSize{Width: 80, Height: 24}); err != nil {
+		t.Fatalf("write size failed: %v", err)
+	}
+	_, _ = io.WriteString(conn.Writer(StdinChannel), "hello")
+	_ = conn.Write(StdinChannel, nil)
+
+	var stdout, stderr string
+	for {
+		channel, data, err := conn.Read()
+		if err != nil {
+			t.Fatalf("connection closed before status: %v", err)
+		}
+		switch channel {
+		case StdoutChannel:
+			stdout += string(data)
+		case StderrChannel:
+			stderr += string(data)
+		case ErrorChannel:
+			status, err := ReadStatus(data)
+			if err != nil {
+				t.Fatalf("read status failed: %v", err)
+			}
+			if stdout != "hello" || stderr != "resized" {
+				t.Errorf("got stdout %q stderr %q, want %q and %q", stdout, stderr, "hello", "resized")
+			}
+			if status.Status != StatusFailure || status.ExitCode != 85 {
+				t.Errorf("got status %+v, want exit code 85", status)
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package volume
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// dataDirName is the symlink to the directory holding current content of volume
+	dataDirName = "..data"
+	// newDataDirName is the temporary symlink renamed to dataDirName on update
+	newDataDirName = "..data_tmp"
+)
+
+// fileProjection is the content and mode of a file projected into volume
+type fileProjection struct {
+	data []byte
+	mode os.FileMode
+}
+
+// writeAtomically writes payload keyed by relative path into dir, so that
+// containers never see a partially updated volume. Files are written into a
+// new hidden directory, then the ..data symlink is switched to it by rename,
+// and each top level path in dir is a symlink into ..data:
+//
+//	<dir>/key -> ..data/key
+//	<dir>/..data -> ..ts_123456
+//	<dir>/..ts_123456/key
+//
+// Nothing is written if content in dir is the same as payload.
+func writeAtomically(dir string, payload map[string]fileProjection) error {
+	for path := range payload {
+		if err := validatePayloadPath(path); err != nil {
+			return err
+		}
+	}
+
+	dataDir := filepath.Join(dir, dataDirName)
+	oldTsDir, err := os.Readlink(dataDir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if oldTsDir != "" {
+		current, err := readPayload(filepath.Join(dir, oldTsDir))
+		if err == nil && payloadEqual(current, payload) {
+			return nil
+		}
+	}
+
+	tsDir, err := os.MkdirTemp(dir, "..ts_")
+	if err != nil {
+		return err
+	}
+	for path, file := range payload {
+		fullPath := filepath.Join(tsDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(fullPath, file.data, file.mode); err != nil {
+			return err
+		}
+		// mode of written file is masked by umask
+		if err := os.Chmod(fullPath, file.mode); err != nil {
+			return err
+		}
+	}
+
+	newDataDir := filepath.Join(dir, newDataDirName)
+	if err := os.Remove(newDataDir); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Symlink(filepath.Base(tsDir), newDataDir); err != nil {
+		return err
+	}
+	if err := os.Rename(newDataDir, dataDir); err != nil {
+		return err
+	}
+
+	if err := updateUserVisiblePaths(dir, payload); err != nil {
+		return err
+	}
+	if oldTsDir != "" {
+		return os.RemoveAll(filepath.Join(dir, oldTsDir))
+	}
+	return nil
+}
+
+// validatePayloadPath checks path is relative, and does not escape or shadow
+// the hidden directories of volume
+func validatePayloadPath(path string) error {
+	if path == "" {
+		return fmt.Errorf("path must not be empty")
+	}
+	if filepath.IsAbs(path) {
+		return fmt.Errorf("path %s must be relative", path)
+	}
+	if strings.HasPrefix(path, "..") {
+		return fmt.Errorf("path %s must not start with '..'", path)
+	}
+	for _, element := range strings.Split(path, string(os.PathSeparator)) {
+		if element == ".." {
+			return fmt.Errorf("path %s must not contain '..'", path)
+		}
+	}
+	return nil
+}
+
+// updateUserVisiblePaths creates symlinks for top level paths of payload,
+// and removes symlinks of paths no longer in payload
+func updateUserVisiblePaths(dir string, payload map[string]fileProjection) error {
+	topLevel := make(map[string]bool)
+	for path := range payload {
+		topLevel[strings.Split(path, string(os.PathSeparator))[0]] = true
+	}
+
+	for name := range topLevel {
+		link := filepath.Join(dir, name)
+		if _, err := os.Lstat(link); err == nil {
+			continue
+		}
+		if err := os.Symlink(filepath.Join(dataDirName, name), link); err != nil {
+			return err
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "..") || topLevel[entry.Name()] {
+			continue
+		}
+		if entry.Type()&os.ModeSymlink != 0 {
+			if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readPayload reads all files under dir keyed by relative path
+func readPayload(dir string) (map[string]fileProjection, error) {
+	payload := make(map[string]fileProjection)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		payload[relative] = fileProjection{data: data, mode: info.Mode().Perm()}
+		return nil
+	})
+	return payload, err
+}
+
+func payloadEqual(a, b map[string]fileProjection) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, fa := range a {
+		fb, found := b[path]
+		if !found || fa.mode != fb.mode || !bytes.Equal(fa.data, fb.data) {
+			return false
+		}
+	}
+	return true
+}
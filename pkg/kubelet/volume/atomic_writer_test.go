@@ -0,0 +1,70 @@
+package volume
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteAtomically(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		payload map[string]fileProjection
+		removed []string
+	}{
+		{
+			name: "initial write",
+			payload: map[string]fileProjection{
+				"a":     {data: []byte("a1"), mode: 0644},
+				"b/b.c": {data: []byte("b1"), mode: 0600},
+			},
+		},
+		{
+			name: "update and remove key",
+			payload: map[string]fileProjection{
+				"a": {data: []byte("a2"), mode: 0644},
+			},
+			removed: []string{"b"},
+		},
+	}
+
+	for _, test := range tests {
+		if err := writeAtomically(dir, test.payload); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		for path, file := range test.payload {
+			data, err := os.ReadFile(filepath.Join(dir, path))
+			if err != nil || string(data) != string(file.data) {
+				t.Errorf("%s: read %s got %q, %v, want %q", test.name, path, data, err, file.data)
+			}
+			info, err := os.Stat(filepath.Join(dir, path))
+			if err != nil || info.Mode().Perm() != file.mode {
+				t.Errorf("%s: mode of %s got %v, %v, want %v", test.name, path, info.Mode().Perm(), err, file.mode)
+			}
+		}
+		for _, path := range test.removed {
+			if _, err := os.Lstat(filepath.Join(dir, path)); !os.IsNotExist(err) {
+				t.Errorf("%s: %s is not removed", test.name, path)
+			}
+		}
+	}
+
+	// only one data directory is kept, and nothing is written if unchanged
+	tsDir, _ := os.Readlink(filepath.Join(dir, dataDirName))
+	matches, _ := filepath.Glob(filepath.Join(dir, "..ts_*"))
+	if len(matches) != 1 {
+		t.Errorf("got data directories %v, want only %s", matches, tsDir)
+	}
+	if err := writeAtomically(dir, tests[len(tests)-1].payload); err != nil {
+		t.Fatal(err)
+	}
+	if current, _ := os.Readlink(filepath.Join(dir, dataDirName)); current != tsDir {
+		t.Errorf("unchanged payload is written again: %s -> %s", tsDir, current)
+	}
+
+	if err := writeAtomically(dir, map[string]fileProjection{"../x": {}}); err == nil {
+		t.Errorf("path escaping volume is accepted")
+	}
+}
@@ -0,0 +1,97 @@
+package volume
+
+import (
+	"fmt"
+	"minik8s/pkg/api/core"
+	"os"
+)
+
+// ConfigMapGetter gets ConfigMap by name, it returns nil without error if not found
+type ConfigMapGetter func(name string) (*core.ConfigMap, error)
+
+// configMapPlugin projects keys of a ConfigMap into files of volume,
+// the files are refreshed after the ConfigMap changes
+type configMapPlugin struct {
+	getConfigMap ConfigMapGetter
+}
+
+func NewConfigMapPlugin(getConfigMap ConfigMapGetter) Plugin {
+	return &configMapPlugin{getConfigMap: getConfigMap}
+}
+
+func (p *configMapPlugin) Name() string {
+	return "configmap"
+}
+
+func (p *configMapPlugin) CanSupport(volume *core.Volume) bool {
+	return volume.ConfigMap != nil
+}
+
+func (p *configMapPlugin) SetUp(pod *core.Pod, volume *core.Volume, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, p.Refresh(pod, volume, dir)
+}
+
+func (p *configMapPlugin) Refresh(pod *core.Pod, volume *core.Volume, dir string) error {
+	source := volume.ConfigMap
+	optional := source.Optional != nil && *source.Optional
+	configMap, err := p.getConfigMap(source.Name)
+	if err != nil {
+		return err
+	}
+	if configMap == nil {
+		if !optional {
+			return fmt.Errorf("configmap %s is not found", source.Name)
+		}
+		configMap = &core.ConfigMap{}
+	}
+
+	data := make(map[string][]byte)
+	for _, key := range configMap.Keys() {
+		data[key], _ = configMap.Get(key)
+	}
+	payload, err := makePayload(data, source.Items, source.DefaultMode, optional)
+	if err != nil {
+		return fmt.Errorf("configmap %s: %v", source.Name, err)
+	}
+	return writeAtomically(dir, payload)
+}
+
+func (p *configMapPlugin) TearDown(pod *core.Pod, volume *core.Volume, dir string) error {
+	return removeVolumeDir(dir)
+}
+
+// makePayload maps data of source object to files, all keys are projected to files
+// of the same name if items is empty, otherwise only keys listed in items are
+func makePayload(data map[string][]byte, items []core.KeyToPath, defaultMode *int32, optional bool) (map[string]fileProjection, error) {
+	mode := os.FileMode(0644)
+	if defaultMode != nil {
+		mode = os.FileMode(*defaultMode) & os.ModePerm
+	}
+
+	payload := make(map[string]fileProjection)
+	if len(items) == 0 {
+		for key, value := range data {
+			payload[key] = fileProjection{data: value, mode: mode}
+		}
+		return payload, nil
+	}
+
+	for _, item := range items {
+		value, found := data[item.Key]
+		if !found {
+			if optional {
+				continue
+			}
+			return nil, fmt.Errorf("key %s is not found", item.Key)
+		}
+		itemMode := mode
+		if item.Mode != nil {
+			itemMode = os.FileMode(*item.Mode) & os.ModePerm
+		}
+		payload[item.Path] = fileProjection{data: value, mode: itemMode}
+	}
+	return payload, nil
+}
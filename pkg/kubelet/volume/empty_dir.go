@@ -0,0 +1,93 @@
+package volume
+
+import (
+	"fmt"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// emptyDirPlugin creates an empty directory in pod directory, or mounts tmpfs on it
+// if medium is memory. A volume without any source is treated as emptyDir.
+type emptyDirPlugin struct{}
+
+func NewEmptyDirPlugin() Plugin {
+	return &emptyDirPlugin{}
+}
+
+func (p *emptyDirPlugin) Name() string {
+	return "empty-dir"
+}
+
+func (p *emptyDirPlugin) CanSupport(volume *core.Volume) bool {
+	return volume.EmptyDir != nil || volume.VolumeSource == core.VolumeSource{}
+}
+
+func (p *emptyDirPlugin) SetUp(pod *core.Pod, volume *core.Volume, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return "", err
+	}
+	if volume.EmptyDir == nil || volume.EmptyDir.Medium != core.StorageMediumMemory {
+		return dir, nil
+	}
+
+	options := ""
+	if volume.EmptyDir.SizeLimit != nil {
+		size, err := types.ParseQuantity(types.ResourceMemory, *volume.EmptyDir.SizeLimit)
+		if err != nil {
+			return "", fmt.Errorf("invalid size limit %v: %v", *volume.EmptyDir.SizeLimit, err)
+		}
+		options = fmt.Sprintf("size=%dm", size)
+	}
+	return dir, mountTmpfs(dir, options)
+}
+
+func (p *emptyDirPlugin) TearDown(pod *core.Pod, volume *core.Volume, dir string) error {
+	return removeVolumeDir(dir)
+}
+
+// mountTmpfs mounts tmpfs on dir if it is not mounted yet
+func mountTmpfs(dir string, options string) error {
+	mounted, err := isMountPoint(dir)
+	if err != nil {
+		return err
+	}
+	if mounted {
+		return nil
+	}
+	if err := syscall.Mount("tmpfs", dir, "tmpfs", 0, options); err != nil {
+		return fmt.Errorf("mount tmpfs on %s failed: %v", dir, err)
+	}
+	return nil
+}
+
+// removeVolumeDir unmounts dir if it is a mount point and removes it
+func removeVolumeDir(dir string) error {
+	mounted, err := isMountPoint(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if mounted {
+		if err := syscall.Unmount(dir, 0); err != nil {
+			return fmt.Errorf("unmount tmpfs on %s failed: %v", dir, err)
+		}
+	}
+	return os.RemoveAll(dir)
+}
+
+// isMountPoint returns true if dir is on a different device from its parent
+func isMountPoint(dir string) (bool, error) {
+	var stat, parentStat syscall.Stat_t
+	if err := syscall.Stat(dir, &stat); err != nil {
+		return false, &os.PathError{Op: "stat", Path: dir, Err: err}
+	}
+	if err := syscall.Stat(filepath.Dir(dir), &parentStat); err != nil {
+		return false, &os.PathError{Op: "stat", Path: filepath.Dir(dir), Err: err}
+	}
+	return stat.Dev != parentStat.Dev, nil
+}
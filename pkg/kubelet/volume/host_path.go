@@ -0,0 +1,93 @@
+package volume
+
+import (
+	"fmt"
+	"minik8s/pkg/api/core"
+	"os"
+	"path/filepath"
+)
+
+// hostPathPlugin mounts an existing file or directory on node, it is checked
+// or created according to the type of host path before mounted
+type hostPathPlugin struct{}
+
+func NewHostPathPlugin() Plugin {
+	return &hostPathPlugin{}
+}
+
+func (p *hostPathPlugin) Name() string {
+	return "host-path"
+}
+
+func (p *hostPathPlugin) CanSupport(volume *core.Volume) bool {
+	return volume.HostPath != nil
+}
+
+func (p *hostPathPlugin) SetUp(pod *core.Pod, volume *core.Volume, dir string) (string, error) {
+	path := volume.HostPath.Path
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("host path %s is not absolute", path)
+	}
+	hostPathType := core.HostPathUnset
+	if volume.HostPath.Type != nil {
+		hostPathType = *volume.HostPath.Type
+	}
+	return path, checkHostPath(path, hostPathType)
+}
+
+func (p *hostPathPlugin) TearDown(pod *core.Pod, volume *core.Volume, dir string) error {
+	// content of host path outlives pod
+	return nil
+}
+
+func checkHostPath(path string, hostPathType core.HostPathType) error {
+	info, err := os.Stat(path)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	switch hostPathType {
+	case core.HostPathUnset:
+		return nil
+	case core.HostPathDirectoryOrCreate:
+		if !exists {
+			return os.MkdirAll(path, 0755)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", path)
+		}
+	case core.HostPathDirectory:
+		if !exists || !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", path)
+		}
+	case core.HostPathFileOrCreate:
+		if !exists {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL, 0644)
+			if err != nil && !os.IsExist(err) {
+				return err
+			}
+			if file != nil {
+				_ = file.Close()
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("%s is not a file", path)
+		}
+	case core.HostPathFile:
+		if !exists || !info.Mode().IsRegular() {
+			return fmt.Errorf("%s is not a file", path)
+		}
+	case core.HostPathSocket:
+		if !exists || info.Mode()&os.ModeSocket == 0 {
+			return fmt.Errorf("%s is not a socket", path)
+		}
+	default:
+		return fmt.Errorf("unknown host path type %s", hostPathType)
+	}
+	return nil
+}
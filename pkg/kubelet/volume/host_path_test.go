@@ -0,0 +1,47 @@
+package volume
+
+import (
+	"minik8s/pkg/api/core"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckHostPath(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "file")
+	if err := os.WriteFile(file, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		hostPathType core.HostPathType
+		wantErr      bool
+	}{
+		{"unset type is not checked", filepath.Join(root, "missing"), core.HostPathUnset, false},
+		{"existing directory", root, core.HostPathDirectory, false},
+		{"missing directory", filepath.Join(root, "missing"), core.HostPathDirectory, true},
+		{"file is not directory", file, core.HostPathDirectory, true},
+		{"create directory", filepath.Join(root, "a", "b"), core.HostPathDirectoryOrCreate, false},
+		{"existing file", file, core.HostPathFile, false},
+		{"directory is not file", root, core.HostPathFile, true},
+		{"create file", filepath.Join(root, "c", "file"), core.HostPathFileOrCreate, false},
+		{"file is not socket", file, core.HostPathSocket, true},
+	}
+
+	for _, test := range tests {
+		err := checkHostPath(test.path, test.hostPathType)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got err %v, wantErr %v", test.name, err, test.wantErr)
+		}
+	}
+
+	if info, err := os.Stat(filepath.Join(root, "a", "b")); err != nil || !info.IsDir() {
+		t.Errorf("directory is not created: %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(root, "c", "file")); err != nil || !info.Mode().IsRegular() {
+		t.Errorf("file is not created: %v", err)
+	}
+}
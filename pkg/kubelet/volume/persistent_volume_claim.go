@@ -0,0 +1,91 @@
+package volume
+
+import (
+	"fmt"
+	"minik8s/pkg/api/core"
+	"os"
+	"path/filepath"
+)
+
+// PersistentVolumeClaimGetter gets PersistentVolumeClaim by name, it returns nil without error if not found
+type PersistentVolumeClaimGetter func(name string) (*core.PersistentVolumeClaim, error)
+
+// PersistentVolumeGetter gets PersistentVolume by name, it returns nil without error if not found
+type PersistentVolumeGetter func(name string) (*core.PersistentVolume, error)
+
+// persistentVolumeClaimPlugin mounts the volume bound to a claim, the volume
+// must be accessible from node. Directories of local-path volumes are created
+// on first use, other volumes must exist on node.
+type persistentVolumeClaimPlugin struct {
+	node      *core.Node
+	getClaim  PersistentVolumeClaimGetter
+	getVolume PersistentVolumeGetter
+}
+
+func NewPersistentVolumeClaimPlugin(node *core.Node, getClaim PersistentVolumeClaimGetter, getVolume PersistentVolumeGetter) Plugin {
+	return &persistentVolumeClaimPlugin{node: node, getClaim: getClaim, getVolume: getVolume}
+}
+
+func (p *persistentVolumeClaimPlugin) Name() string {
+	return "persistent-volume-claim"
+}
+
+func (p *persistentVolumeClaimPlugin) CanSupport(volume *core.Volume) bool {
+	return volume.PersistentVolumeClaim != nil
+}
+
+func (p *persistentVolumeClaimPlugin) SetUp(pod *core.Pod, volume *core.Volume, dir string) (string, error) {
+	claimName := volume.PersistentVolumeClaim.ClaimName
+	claim, err := p.getClaim(claimName)
+	if err != nil {
+		return "", err
+	}
+	if claim == nil {
+		return "", fmt.Errorf("persistentvolumeclaim %s is not found", claimName)
+	}
+	if claim.Spec.VolumeName == "" || claim.Status.Phase != core.ClaimBound {
+		return "", fmt.Errorf("persistentvolumeclaim %s is not bound yet", claimName)
+	}
+
+	pv, err := p.getVolume(claim.Spec.VolumeName)
+	if err != nil {
+		return "", err
+	}
+	if pv == nil {
+		return "", fmt.Errorf("persistentvolume %s of claim %s is not found", claim.Spec.VolumeName, claimName)
+	}
+	if p.node != nil && !pv.MatchNode(p.node) {
+		return "", fmt.Errorf("persistentvolume %s is not accessible from node %s", pv.Name, p.node.Name)
+	}
+	return setUpPersistentVolume(pv)
+}
+
+func (p *persistentVolumeClaimPlugin) TearDown(pod *core.Pod, volume *core.Volume, dir string) error {
+	// content of persistent volume outlives pod
+	return nil
+}
+
+// setUpPersistentVolume checks the path of volume on node and returns it
+func setUpPersistentVolume(pv *core.PersistentVolume) (string, error) {
+	path := pv.Path()
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("path %q of persistentvolume %s is not absolute", path, pv.Name)
+	}
+
+	switch {
+	case pv.IsProvisionedBy(core.LocalPathProvisionerName):
+		// writable by containers running as any user
+		if err := os.MkdirAll(path, 0777); err != nil {
+			return "", err
+		}
+		return path, os.Chmod(path, 0777)
+	case pv.Spec.Local != nil:
+		return path, checkHostPath(path, core.HostPathDirectory)
+	default:
+		hostPathType := core.HostPathUnset
+		if pv.Spec.HostPath.Type != nil {
+			hostPathType = *pv.Spec.HostPath.Type
+		}
+		return path, checkHostPath(path, hostPathType)
+	}
+}
@@ -0,0 +1,63 @@
+package volume
+
+import (
+	"fmt"
+	"minik8s/pkg/api/core"
+	"os"
+)
+
+// SecretGetter gets Secret by name, it returns nil without error if not found
+type SecretGetter func(name string) (*core.Secret, error)
+
+// secretPlugin projects keys of a Secret into files of volume like configMapPlugin,
+// but the files are kept in tmpfs so that secret data is never written to disk
+type secretPlugin struct {
+	getSecret SecretGetter
+}
+
+func NewSecretPlugin(getSecret SecretGetter) Plugin {
+	return &secretPlugin{getSecret: getSecret}
+}
+
+func (p *secretPlugin) Name() string {
+	return "secret"
+}
+
+func (p *secretPlugin) CanSupport(volume *core.Volume) bool {
+	return volume.Secret != nil
+}
+
+func (p *secretPlugin) SetUp(pod *core.Pod, volume *core.Volume, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	if err := mountTmpfs(dir, ""); err != nil {
+		return "", err
+	}
+	return dir, p.Refresh(pod, volume, dir)
+}
+
+func (p *secretPlugin) Refresh(pod *core.Pod, volume *core.Volume, dir string) error {
+	source := volume.Secret
+	optional := source.Optional != nil && *source.Optional
+	secret, err := p.getSecret(source.SecretName)
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		if !optional {
+			return fmt.Errorf("secret %s is not found", source.SecretName)
+		}
+		secret = &core.Secret{}
+	}
+
+	payload, err := makePayload(secret.Data, source.Items, source.DefaultMode, optional)
+	if err != nil {
+		return fmt.Errorf("secret %s: %v", source.SecretName, err)
+	}
+	return writeAtomically(dir, payload)
+}
+
+func (p *secretPlugin) TearDown(pod *core.Pod, volume *core.Volume, dir string) error {
+	return removeVolumeDir(dir)
+}
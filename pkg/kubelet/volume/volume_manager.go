@@ -0,0 +1,140 @@
+package volume
+
+import (
+	"errors"
+	"fmt"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/logger"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUnsupportedVolume is returned for volume whose source no plugin supports,
+// setting it up again never succeeds
+var ErrUnsupportedVolume = errors.New("volume source is not supported")
+
+// Plugin sets up and tears down one type of volume on the node
+type Plugin interface {
+	// Name is the name of plugin, volumes of the plugin are kept in
+	// the directory of this name under the volumes directory of pod
+	Name() string
+	// CanSupport returns true if the plugin handles the volume
+	CanSupport(volume *core.Volume) bool
+	// SetUp makes volume ready on node and returns the host path to be mounted into
+	// containers, dir is the directory reserved for the volume in pod directory.
+	// It should be idempotent since it is retried until all volumes of pod are ready
+	SetUp(pod *core.Pod, volume *core.Volume, dir string) (string, error)
+	// TearDown cleans up the volume after all containers of pod are removed
+	TearDown(pod *core.Pod, volume *core.Volume, dir string) error
+}
+
+// Refresher is implemented by plugins whose volume content is projected from
+// API objects, the content is refreshed periodically after the volume is set up
+type Refresher interface {
+	// Refresh updates content of volume set up in dir
+	Refresh(pod *core.Pod, volume *core.Volume, dir string) error
+}
+
+// Manager sets up volumes of pods before their containers are created,
+// and tears them down after pods are deleted
+type Manager interface {
+	// SetUpPod sets up all volumes of pod, and returns their host paths keyed by volume name
+	SetUpPod(pod *core.Pod) (map[string]string, error)
+	// TearDownPod tears down all volumes of pod and removes the pod directory
+	TearDownPod(pod *core.Pod) error
+	// RefreshPod refreshes volumes of pod supported by Refresher plugins
+	RefreshPod(pod *core.Pod) error
+}
+
+type manager struct {
+	rootDir string
+	plugins []Plugin
+}
+
+// NewManager creates a volume manager keeping pod volumes under rootDir,
+// the first plugin supporting a volume is used for it
+func NewManager(rootDir string, plugins ...Plugin) Manager {
+	return &manager{
+		rootDir: rootDir,
+		plugins: plugins,
+	}
+}
+
+func (m *manager) podDir(pod *core.Pod) string {
+	return filepath.Join(m.rootDir, "pods", pod.UID)
+}
+
+func (m *manager) volumeDir(pod *core.Pod, plugin Plugin, volume *core.Volume) string {
+	return filepath.Join(m.podDir(pod), "volumes", plugin.Name(), volume.Name)
+}
+
+func (m *manager) findPlugin(volume *core.Volume) (Plugin, error) {
+	for _, plugin := range m.plugins {
+		if plugin.CanSupport(volume) {
+			return plugin, nil
+		}
+	}
+	names := make([]string, 0, len(m.plugins))
+	for _, plugin := range m.plugins {
+		names = append(names, plugin.Name())
+	}
+	return nil, fmt.Errorf("volume %s: %w, supported plugins: [%s]", volume.Name, ErrUnsupportedVolume, strings.Join(names, ", "))
+}
+
+func (m *manager) SetUpPod(pod *core.Pod) (map[string]string, error) {
+	paths := make(map[string]string)
+	for i := range pod.Spec.Volumes {
+		volume := &pod.Spec.Volumes[i]
+		plugin, err := m.findPlugin(volume)
+		if err != nil {
+			return nil, err
+		}
+		path, err := plugin.SetUp(pod, volume, m.volumeDir(pod, plugin, volume))
+		if err != nil {
+			return nil, fmt.Errorf("set up %s volume %s failed: %v", plugin.Name(), volume.Name, err)
+		}
+		paths[volume.Name] = path
+	}
+	return paths, nil
+}
+
+func (m *manager) RefreshPod(pod *core.Pod) error {
+	var lastErr error
+	for i := range pod.Spec.Volumes {
+		volume := &pod.Spec.Volumes[i]
+		plugin, err := m.findPlugin(volume)
+		if err != nil {
+			continue
+		}
+		refresher, ok := plugin.(Refresher)
+		if !ok {
+			continue
+		}
+		if err := refresher.Refresh(pod, volume, m.volumeDir(pod, plugin, volume)); err != nil {
+			logger.KubeletLogger.Printf("[Volume] refresh %s volume %s of pod %s failed: %v\n", plugin.Name(), volume.Name, pod.Name, err)
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+func (m *manager) TearDownPod(pod *core.Pod) error {
+	var lastErr error
+	for i := range pod.Spec.Volumes {
+		volume := &pod.Spec.Volumes[i]
+		plugin, err := m.findPlugin(volume)
+		if err != nil {
+			continue
+		}
+		if err := plugin.TearDown(pod, volume, m.volumeDir(pod, plugin, volume)); err != nil {
+			logger.KubeletLogger.Printf("[Volume] tear down %s volume %s of pod %s failed: %v\n", plugin.Name(), volume.Name, pod.Name, err)
+			lastErr = err
+		}
+	}
+	// keep pod directory if any volume is still mounted, or its content would be removed
+	if lastErr != nil {
+		return lastErr
+	}
+	return os.RemoveAll(m.podDir(pod))
+}
@@ -0,0 +1,201 @@
+package kubelet
+
+import (
+	"errors"
+	"fmt"
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/kubelet/container/cri"
+	"minik8s/pkg/kubelet/qos"
+	"minik8s/pkg/kubelet/volume"
+	"minik8s/pkg/logger"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+/*---------------------------- Volumes ----------------------------*/
+
+// Waiting reasons of containers not created yet
+const (
+	// ContainerCreating is the waiting reason of containers whose pod is not ready to start
+	ContainerCreating = "ContainerCreating"
+	// CreateContainerError is the waiting reason of containers failed to be created
+	CreateContainerError = "CreateContainerError"
+)
+
+// UnsupportedVolume is the reason of pod failed for volume source not supported by kubelet
+const UnsupportedVolume = "UnsupportedVolume"
+
+// setUpVolumes sets up volumes of pod before any container is created, it retries
+// until all volumes are ready, and returns false if pod is deleted meanwhile
+func (k *kubelet) setUpVolumes(pod *core.Pod) bool {
+	lastMessage := ""
+	for {
+		k.lock.RLock()
+		_, found := k.podManager.GetPodByUID(pod.UID)
+		k.lock.RUnlock()
+		if !found {
+			return false
+		}
+
+		paths, err := k.volumeManager.SetUpPod(pod)
+		if err == nil {
+			err = validateVolumeMounts(pod, paths)
+		}
+		if err == nil {
+			k.lock.Lock()
+			k.podVolumes[pod.UID] = paths
+			k.lock.Unlock()
+			return true
+		}
+
+		logger.KubeletLogger.Printf("Set up volumes of pod %s failed: %v\n", pod.Name, err)
+		if errors.Is(err, volume.ErrUnsupportedVolume) {
+			// pod never runs, it fails at once instead of retrying
+			k.eventf(pod, core.EventTypeWarning, FailedMountVolume, "Set up volumes failed: %v", err)
+			k.reportPodFailed(pod, UnsupportedVolume, err.Error())
+			return false
+		}
+		if err.Error() != lastMessage {
+			lastMessage = err.Error()
+			k.reportContainersWaiting(pod, ContainerCreating, lastMessage)
+		}
+		k.eventf(pod, core.EventTypeWarning, FailedMountVolume, "Set up volumes failed: %v", err)
+		time.Sleep(config.VolumeSetUpRetryInterval)
+	}
+}
+
+// tearDownVolumes cleans up volumes after all containers of pod are removed
+func (k *kubelet) tearDownVolumes(pod *core.Pod) {
+	if err := k.volumeManager.TearDownPod(pod); err != nil {
+		logger.KubeletLogger.Printf("Tear down volumes of pod %s failed: %v\n", pod.Name, err)
+	}
+}
+
+// validateVolumeMounts checks all volume mounts of pod refer to volumes set up
+func validateVolumeMounts(pod *core.Pod, paths map[string]string) error {
+	containers := append(append([]core.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, container := range containers {
+		for _, m := range container.VolumeMounts {
+			if _, found := paths[m.Name]; !found {
+				return fmt.Errorf("volume %s mounted by container %s is not found", m.Name, container.Name)
+			}
+			if err := validateSubPath(m.SubPath); err != nil {
+				return fmt.Errorf("volume mount %s of container %s: %v", m.Name, container.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateSubPath(subPath string) error {
+	if filepath.IsAbs(subPath) {
+		return fmt.Errorf("sub path %s must be relative", subPath)
+	}
+	for _, element := range strings.Split(subPath, string(os.PathSeparator)) {
+		if element == ".." {
+			return fmt.Errorf("sub path %s must not contain '..'", subPath)
+		}
+	}
+	return nil
+}
+
+// makeMounts resolves volume mounts of container to host paths,
+// it should be called with k.lock held
+func (k *kubelet) makeMounts(pod *core.Pod, container core.Container) ([]cri.Mount, error) {
+	volumes := make(map[string]*core.Volume)
+	for i := range pod.Spec.Volumes {
+		volumes[pod.Spec.Volumes[i].Name] = &pod.Spec.Volumes[i]
+	}
+
+	mounts := make([]cri.Mount, 0, len(container.VolumeMounts))
+	for _, m := range container.VolumeMounts {
+		path, found := k.podVolumes[pod.UID][m.Name]
+		if !found {
+			return nil, fmt.Errorf("volume %s is not found", m.Name)
+		}
+		if m.SubPath != "" {
+			if err := validateSubPath(m.SubPath); err != nil {
+				return nil, err
+			}
+			path = filepath.Join(path, m.SubPath)
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				if err := os.MkdirAll(path, 0755); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		readOnly := m.ReadOnly
+		if volume := volumes[m.Name]; volume != nil && volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ReadOnly {
+			readOnly = true
+		}
+		propagation := core.MountPropagationMode("")
+		if m.MountPropagation != nil {
+			propagation = *m.MountPropagation
+		}
+
+		mounts = append(mounts, cri.Mount{
+			HostPath:      path,
+			ContainerPath: m.MountPath,
+			ReadOnly:      readOnly,
+			Propagation:   propagation,
+		})
+	}
+	return mounts, nil
+}
+
+// reportPodFailed reports pod fails for reason before any container runs
+func (k *kubelet) reportPodFailed(pod *core.Pod, reason string, message string) {
+	uid, found := k.podStatusUID(pod)
+	if !found {
+		return
+	}
+	err := k.updatePodStatus(uid, func(p *core.Pod) {
+		status := &p.Status
+		status.Phase = core.PodFailed
+		status.Reason = reason
+		status.Message = message
+		status.QOSClass = qos.GetPodQOS(pod)
+		if status.StartTime == nil {
+			status.StartTime = pod.Status.StartTime
+		}
+		setPodConditions(status, false, false)
+	})
+	if err != nil {
+		logger.KubeletLogger.Printf("Update status of pod %s error: %v\n", pod.Name, err)
+	}
+}
+
+// reportContainersWaiting reports all containers of pod are waiting for reason
+func (k *kubelet) reportContainersWaiting(pod *core.Pod, reason string, message string) {
+	statuses := make([]core.ContainerStatus, 0, len(pod.Spec.Containers))
+	for _, container := range pod.Spec.Containers {
+		statuses = append(statuses, core.ContainerStatus{
+			Name:    container.Name,
+			State:   core.ContainerState{Waiting: &core.ContainerStateWaiting{Reason: reason, Message: message}},
+			Image:   container.Image,
+			ImageID: container.Image,
+		})
+	}
+
+	uid, found := k.podStatusUID(pod)
+	if !found {
+		return
+	}
+	err := k.updatePodStatus(uid, func(p *core.Pod) {
+		status := &p.Status
+		status.Phase = core.PodPending
+		status.QOSClass = qos.GetPodQOS(pod)
+		status.ContainerStatuses = statuses
+		if status.StartTime == nil {
+			status.StartTime = pod.Status.StartTime
+		}
+		setPodConditions(status, len(pod.Spec.InitContainers) == 0, false)
+	})
+	if err != nil {
+		logger.KubeletLogger.Printf("Update container statuses of pod %s error: %v\n", pod.Name, err)
+	}
+}
@@ -17,11 +17,15 @@ type Manager interface {
 
 type manager struct {
 	services map[types.UID]*core.Service
+	// ip of pods registered as real servers of each service,
+	// service uid -> pod uid -> pod ip
+	endpoints map[types.UID]map[types.UID]string
 }
 
 func New() Manager {
 	return &manager{
-		services: make(map[types.UID]*core.Service),
+		services:  make(map[types.UID]*core.Service),
+		endpoints: make(map[types.UID]map[types.UID]string),
 	}
 }
 
@@ -30,6 +34,7 @@ func (m *manager) CreatSvc(service *core.Service) {
 	if !found {
 		createSvc(service)
 		m.services[service.UID] = service
+		m.endpoints[service.UID] = make(map[types.UID]string)
 		return
 	} else {
 		log.Fatalln("service doesn't support update")
@@ -45,38 +50,49 @@ func (m *manager) DelSvc(service *core.Service) {
 	if err != nil {
 		log.Println(err)
 	}
+	delete(m.services, service.UID)
+	delete(m.endpoints, service.UID)
 }
 
+// HandlePodModify registers pod to services it matches only when pod is ready,
+// and deregisters it once it becomes not ready or its ip changes
 func (m *manager) HandlePodModify(pod *core.Pod) {
-	for label, val := range pod.Labels {
-		for _, svc := range m.services {
-			v, f := svc.Spec.Selector[label]
-			if !f || v != val {
-				continue
-			}
+	for _, svc := range m.services {
+		ready := svc.SelectsPod(pod) && pod.IsReady() && pod.Status.PodIP != ""
+		ip, registered := m.endpoints[svc.UID][pod.UID]
+		if registered && (!ready || ip != pod.Status.PodIP) {
+			m.delEndpoint(svc, pod.UID, ip)
+			registered = false
+		}
+		if ready && !registered {
 			err := ipvs.RegPodToService(*svc, *pod)
 			if err != nil {
 				log.Println(err)
+				continue
 			}
+			m.endpoints[svc.UID][pod.UID] = pod.Status.PodIP
 		}
 	}
-
 }
+
 func (m *manager) HandlePodDel(pod *core.Pod) {
-	for label, val := range pod.Labels {
-		for _, svc := range m.services {
-			v, f := svc.Spec.Selector[label]
-			if !f || v != val {
-				continue
-			}
-			err := ipvs.DelPodToService(*svc, *pod)
-			if err != nil {
-				log.Println(err)
-			}
+	for _, svc := range m.services {
+		if ip, registered := m.endpoints[svc.UID][pod.UID]; registered {
+			m.delEndpoint(svc, pod.UID, ip)
 		}
 	}
 }
 
+func (m *manager) delEndpoint(svc *core.Service, podUID types.UID, ip string) {
+	pod := core.Pod{}
+	pod.Status.PodIP = ip
+	err := ipvs.DelPodToService(*svc, pod)
+	if err != nil {
+		log.Println(err)
+	}
+	delete(m.endpoints[svc.UID], podUID)
+}
+
 func createSvc(service *core.Service) {
 	err := ipvs.AddIpvsServices(*service)
 	if err != nil {
@@ -15,7 +15,10 @@ var KubectlLogger Logger
 var KubeletLogger Logger
 var DNSControllerLogger Logger
 var ServerlessControllerLogger Logger
-var PodControllerLogger Logger
+var NodeLifecycleControllerLogger Logger
+var DisruptionControllerLogger Logger
+var PersistentVolumeControllerLogger Logger
+var EventGCControllerLogger Logger
 
 func init() {
 	ApiServerLogger = utils.NewComponentLogger("ApiServer")
@@ -29,5 +32,8 @@ func init() {
 	GpuServerLogger = utils.NewComponentLogger("GpuServer")
 	DNSControllerLogger = utils.NewComponentLogger("DNSController")
 	ServerlessControllerLogger = utils.NewComponentLogger("ServerlessController")
-	PodControllerLogger = utils.NewComponentLogger("PodController")
+	NodeLifecycleControllerLogger = utils.NewComponentLogger("NodeLifecycleController")
+	DisruptionControllerLogger = utils.NewComponentLogger("DisruptionController")
+	PersistentVolumeControllerLogger = utils.NewComponentLogger("PersistentVolumeController")
+	EventGCControllerLogger = utils.NewComponentLogger("EventGCController")
 }
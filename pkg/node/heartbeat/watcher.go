@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"log"
-	"minik8s/config"
 	"minik8s/pkg/api/core"
 	"minik8s/pkg/api/types"
 	"minik8s/pkg/api/watch"
@@ -13,9 +12,11 @@ import (
 	"minik8s/pkg/apiclient/listwatch"
 	"minik8s/pkg/node"
 	"sync"
-	"time"
 )
 
+// Watcher keeps track of the last heartbeat of each node, and deletes
+// the heartbeat of node when the node is deleted. Node health is
+// monitored by node lifecycle controller based on heartbeats.
 type Watcher interface {
 	Run(ctx context.Context, cancel context.CancelFunc)
 }
@@ -75,49 +76,6 @@ func (w *watcher) Run(ctx context.Context, cancel context.CancelFunc) {
 			log.Printf("[HeartbeatWatcher] watchHeartbeats failed, err: %v\n", err)
 		}
 	}()
-
-	// run worker to delete node whose heartbeat have not been received for long
-	go func() {
-		defer cancel()
-		w.checkHeartbeat(ctx)
-	}()
-}
-
-const defaultWorkerSleepInterval = config.HeartbeatCheckInterval
-
-func (w *watcher) checkHeartbeat(ctx context.Context) {
-
-	// go wait.UntilWithContext(ctx, rsc.worker, time.Second)
-	for {
-		select {
-		case <-ctx.Done():
-			log.Printf("[HeartbeatWatcher] ctx.Done() received, worker of Heartbeat Watcher exit\n")
-			return
-		default:
-
-			log.Printf("[HeartbeatWatcher] checkHeartbeat: start\n")
-
-			// check heartbeat time
-			w.heartbeatMapLock.RLock()
-			for uid, hb := range w.lastHeartbeatMap {
-				if hb.Spec.NodeUID == "" {
-					continue
-				}
-				if time.Since(hb.Status.Timestamp) > config.HeartbeatDeadInterval {
-					log.Printf("[HeartbeatWatcher] checkHeartbeat: node %v dead, last heartbeat time %v, current time %v\n", uid, hb.Status.Timestamp, time.Now())
-					// node dead, delete that node
-					_, _, err := w.nodeClient.Delete(uid)
-					if err != nil {
-						log.Printf("[HeartbeatWatcher] checkHeartbeat: node %v delete failed\n", uid)
-					}
-				}
-			}
-			w.heartbeatMapLock.RUnlock()
-
-			time.Sleep(defaultWorkerSleepInterval)
-		}
-	}
-
 }
 
 func (w *watcher) watchHeartbeats(stopCh <-chan struct{}) error {
@@ -10,6 +10,7 @@ import (
 	client "minik8s/pkg/apiclient/interface"
 	"minik8s/utils"
 	"reflect"
+	"time"
 )
 
 func CreateWorkerNode() *core.Node {
@@ -23,6 +24,37 @@ func CreateWorkerNode() *core.Node {
 	return nc.nodeInfo
 }
 
+// LoadWorkerNode loads worker node from node config without ApiServer, so that
+// kubelet can run static pods of the node before it is registered by RegisterNode
+func LoadWorkerNode() *core.Node {
+	nc := &NodeCreator{
+		nodeInfo: nil,
+		ty:       config.Worker,
+	}
+	nc.loadNode()
+	return nc.nodeInfo
+}
+
+// RegisterNode registers worker node loaded by LoadWorkerNode, it waits until ApiServer
+// is reachable, as ApiServer may be a static pod started by kubelet
+func RegisterNode(n *core.Node) {
+	nodeCli, _ := apiclient.NewRESTClient(types.NodeObjectType)
+	nc := &NodeCreator{
+		nodeClient: nodeCli,
+		nodeInfo:   n,
+		ty:         config.Worker,
+	}
+	for {
+		_, err := nodeCli.GetAll()
+		if err == nil {
+			break
+		}
+		log.Printf("[RegisterNode] wait for ApiServer: %v\n", err)
+		time.Sleep(config.ApiServerRetryInterval)
+	}
+	nc.registerNode(nc.checkNode())
+}
+
 func CreateMasterNode() *core.Node {
 	nodeCli, _ := apiclient.NewRESTClient(types.NodeObjectType)
 	nc := &NodeCreator{
@@ -49,6 +81,12 @@ type NodeCreator struct {
 }
 
 func (nc *NodeCreator) initNode() bool {
+	nc.loadNode()
+	return nc.checkNode()
+}
+
+// loadNode loads node from node config, and generates its name if not set
+func (nc *NodeCreator) loadNode() {
 
 	nc.nodeInfo = config.LoadNodeFromTemplate()
 
@@ -62,29 +100,35 @@ func (nc *NodeCreator) initNode() bool {
 	}
 	if nc.nodeInfo.Name == NameEmpty {
 		nc.nodeInfo.Name = nc.generateNodeName()
-	} else {
-		// check if node name exist
-		nodeList, err := nc.nodeClient.GetAll()
-		if err != nil {
-			panic(err)
-		}
-		nodeItems := nodeList.GetIApiObjectArr()
-		for _, nodeItem := range nodeItems {
-			n := nodeItem.(*core.Node)
-			if n.Name == nc.nodeInfo.Name {
-				if reflect.DeepEqual(n.Spec, nc.nodeInfo.Spec) && reflect.DeepEqual(n.Labels, nc.nodeInfo.Labels) && reflect.DeepEqual(n.TypeMeta, nc.nodeInfo.TypeMeta) {
-					// node same with before
-					log.Printf("[initNode] config same with node(name %v uid %v) before, reuse it\n", n.Name, n.UID)
-					nc.nodeInfo = n
-					return false
-				}
-				if nc.ty == config.Master {
-					// master node exist
-					panic("master exist, can not create another")
-				} else {
-					// node name exist
-					panic(fmt.Sprintf("node name %v exist, can not create another", nc.nodeInfo.Name))
-				}
+	}
+}
+
+// checkNode returns true if node is new, node registered before with the same
+// config is reused, and it panics if node name is taken by another node
+func (nc *NodeCreator) checkNode() bool {
+	// check if node name exist
+	nodeList, err := nc.nodeClient.GetAll()
+	if err != nil {
+		panic(err)
+	}
+	nodeItems := nodeList.GetIApiObjectArr()
+	for _, nodeItem := range nodeItems {
+		n := nodeItem.(*core.Node)
+		if n.Name == nc.nodeInfo.Name {
+			if reflect.DeepEqual(n.Spec, nc.nodeInfo.Spec) && reflect.DeepEqual(n.Labels, nc.nodeInfo.Labels) && reflect.DeepEqual(n.TypeMeta, nc.nodeInfo.TypeMeta) {
+				// node same with before
+				log.Printf("[initNode] config same with node(name %v uid %v) before, reuse it\n", n.Name, n.UID)
+				nc.nodeInfo.SetUID(n.UID)
+				nc.nodeInfo.SetResourceVersion(n.ResourceVersion)
+				nc.nodeInfo.Status = n.Status
+				return false
+			}
+			if nc.ty == config.Master {
+				// master node exist
+				panic("master exist, can not create another")
+			} else {
+				// node name exist
+				panic(fmt.Sprintf("node name %v exist, can not create another", nc.nodeInfo.Name))
 			}
 		}
 	}
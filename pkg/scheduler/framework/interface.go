@@ -0,0 +1,39 @@
+package framework
+
+import (
+	"minik8s/pkg/api/core"
+)
+
+// MaxNodeScore is the maximum score a ScorePlugin is expected to return
+const MaxNodeScore int64 = 100
+
+// MinNodeScore is the minimum score a ScorePlugin is expected to return
+const MinNodeScore int64 = 0
+
+// Plugin is the parent type for all the scheduling framework plugins.
+type Plugin interface {
+	Name() string
+}
+
+// FilterPlugin is an interface for Filter plugins. These plugins are called at the
+// filter extension point for filtering out nodes that cannot run the pod.
+// A nil error means the node fits, otherwise error describes why it does not.
+type FilterPlugin interface {
+	Plugin
+	Filter(pod *core.Pod, nodeInfo *NodeInfo, snapshot *Snapshot) error
+}
+
+// ScorePlugin is an interface that must be implemented by "Score" plugins to rank
+// nodes that passed the filtering phase. Score should be in range of
+// [MinNodeScore, MaxNodeScore], higher score means more preferred node.
+type ScorePlugin interface {
+	Plugin
+	Score(pod *core.Pod, nodeInfo *NodeInfo, snapshot *Snapshot) int64
+}
+
+// PluginFactory is a function that builds a plugin.
+type PluginFactory func() Plugin
+
+// Registry is a collection of all available plugins. The framework uses a
+// registry to enable and initialize configured plugins.
+type Registry map[string]PluginFactory
@@ -0,0 +1,144 @@
+package framework
+
+import (
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/types"
+)
+
+// NodeInfo is node level aggregated information.
+type NodeInfo struct {
+	// Overall node information.
+	Node *core.Node
+
+	// Pods running (or bound) on the node, terminated pods are excluded.
+	Pods []*core.Pod
+}
+
+// Snapshot is a snapshot of cluster state taken at the beginning of a
+// scheduling cycle, it stays unchanged during the whole cycle
+type Snapshot struct {
+	// NodeInfos of all schedulable nodes, in round-robin order
+	NodeInfos []*NodeInfo
+
+	// Claims and Volumes are PersistentVolumeClaims and PersistentVolumes keyed by name
+	Claims  map[string]*core.PersistentVolumeClaim
+	Volumes map[string]*core.PersistentVolume
+}
+
+// NewSnapshot creates a Snapshot from nodes and pods, pods that are not
+// bound to any node in nodes or have terminated are ignored
+func NewSnapshot(nodes []*core.Node, pods []*core.Pod) *Snapshot {
+	s := &Snapshot{
+		NodeInfos: make([]*NodeInfo, 0, len(nodes)),
+	}
+	nodeInfoMap := make(map[string]*NodeInfo, len(nodes))
+	for _, n := range nodes {
+		ni := &NodeInfo{
+			Node: n,
+			Pods: make([]*core.Pod, 0),
+		}
+		nodeInfoMap[n.Name] = ni
+		s.NodeInfos = append(s.NodeInfos, ni)
+	}
+	for _, p := range pods {
+		if p.IsTerminated() {
+			continue
+		}
+		if ni, ok := nodeInfoMap[p.Spec.NodeName]; ok {
+			ni.Pods = append(ni.Pods, p)
+		}
+	}
+	return s
+}
+
+// SetPersistentVolumes adds claims and volumes to snapshot
+func (s *Snapshot) SetPersistentVolumes(claims []*core.PersistentVolumeClaim, volumes []*core.PersistentVolume) {
+	s.Claims = make(map[string]*core.PersistentVolumeClaim, len(claims))
+	for _, c := range claims {
+		s.Claims[c.Name] = c
+	}
+	s.Volumes = make(map[string]*core.PersistentVolume, len(volumes))
+	for _, v := range volumes {
+		s.Volumes[v.Name] = v
+	}
+}
+
+// Get returns NodeInfo of node named nodeName, nil if not found
+func (s *Snapshot) Get(nodeName string) *NodeInfo {
+	for _, ni := range s.NodeInfos {
+		if ni.Node.Name == nodeName {
+			return ni
+		}
+	}
+	return nil
+}
+
+// Resource is a collection of compute resources.
+type Resource struct {
+	// MilliCPU in millicores
+	MilliCPU uint64
+	// Memory in the unit returned by types.ParseQuantity
+	Memory uint64
+	// EphemeralStorage in the unit returned by types.ParseQuantity
+	EphemeralStorage uint64
+	// AllowedPodNumber is number of pods
+	AllowedPodNumber uint64
+}
+
+// NewResource creates a Resource from parsed quantities
+func NewResource(list map[types.ResourceName]uint64) Resource {
+	return Resource{
+		MilliCPU:         list[types.ResourceCPU],
+		Memory:           list[types.ResourceMemory],
+		EphemeralStorage: list[types.ResourceEphemeralStorage],
+		AllowedPodNumber: list[types.ResourcePods],
+	}
+}
+
+// NewResourceFromList creates a Resource from ResourceList, ok is false
+// if list is empty, which means the amount of resources is unknown
+func NewResourceFromList(list core.ResourceList) (res Resource, ok bool) {
+	if len(list) == 0 {
+		return Resource{}, false
+	}
+	parsed := make(map[types.ResourceName]uint64, len(list))
+	for name, q := range list {
+		v, err := types.ParseQuantity(name, q)
+		if err != nil {
+			continue
+		}
+		parsed[name] = v
+	}
+	return NewResource(parsed), true
+}
+
+// Add adds r2 to r
+func (r *Resource) Add(r2 Resource) {
+	r.MilliCPU += r2.MilliCPU
+	r.Memory += r2.Memory
+	r.EphemeralStorage += r2.EphemeralStorage
+	r.AllowedPodNumber += r2.AllowedPodNumber
+}
+
+// GetPodResourceRequest returns resource requests of pod, including those
+// of its init containers, AllowedPodNumber is always 1 for the pod itself
+func GetPodResourceRequest(pod *core.Pod) Resource {
+	res := NewResource(pod.ComputeResourceRequests())
+	res.AllowedPodNumber = 1
+	return res
+}
+
+// Requested returns the total resource requested by pods on the node
+func (n *NodeInfo) Requested() Resource {
+	res := Resource{}
+	for _, p := range n.Pods {
+		res.Add(GetPodResourceRequest(p))
+	}
+	return res
+}
+
+// Allocatable returns resources of the node available for pods,
+// ok is false if the node has not reported it yet
+func (n *NodeInfo) Allocatable() (Resource, bool) {
+	return NewResourceFromList(n.Node.Status.Allocatable)
+}
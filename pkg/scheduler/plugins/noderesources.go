@@ -0,0 +1,95 @@
+package plugins
+
+import (
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/scheduler/framework"
+)
+
+// NodeResourcesLeastAllocated favors nodes with fewer requested resources,
+// which spreads pods across nodes.
+type NodeResourcesLeastAllocated struct{}
+
+func NewNodeResourcesLeastAllocated() framework.Plugin {
+	return &NodeResourcesLeastAllocated{}
+}
+
+func (pl *NodeResourcesLeastAllocated) Name() string {
+	return NodeResourcesLeastAllocatedName
+}
+
+func (pl *NodeResourcesLeastAllocated) Score(pod *core.Pod, nodeInfo *framework.NodeInfo, snapshot *framework.Snapshot) int64 {
+	return framework.MaxNodeScore - int64(nodeLoad(pod, nodeInfo, snapshot)*float64(framework.MaxNodeScore))
+}
+
+// NodeResourcesMostAllocated favors nodes with more requested resources,
+// which packs pods onto as few nodes as possible.
+type NodeResourcesMostAllocated struct{}
+
+func NewNodeResourcesMostAllocated() framework.Plugin {
+	return &NodeResourcesMostAllocated{}
+}
+
+func (pl *NodeResourcesMostAllocated) Name() string {
+	return NodeResourcesMostAllocatedName
+}
+
+func (pl *NodeResourcesMostAllocated) Score(pod *core.Pod, nodeInfo *framework.NodeInfo, snapshot *framework.Snapshot) int64 {
+	return int64(nodeLoad(pod, nodeInfo, snapshot) * float64(framework.MaxNodeScore))
+}
+
+// nodeLoad returns load of node in range [0, 1] after pod is placed on it.
+// Load is the average fraction of requested cpu and memory to allocatable of node.
+// For nodes not reporting allocatable, load is relative to the most loaded node in
+// snapshot, and number of pods is used instead when no pod in snapshot requests resources.
+func nodeLoad(pod *core.Pod, nodeInfo *framework.NodeInfo, snapshot *framework.Snapshot) float64 {
+	req := nodeInfo.Requested()
+	if allocatable, ok := nodeInfo.Allocatable(); ok {
+		req.Add(framework.GetPodResourceRequest(pod))
+		return averageFraction(req, allocatable)
+	}
+
+	maxRequested := framework.Resource{}
+	maxPods := 0
+	for _, ni := range snapshot.NodeInfos {
+		r := ni.Requested()
+		if r.MilliCPU > maxRequested.MilliCPU {
+			maxRequested.MilliCPU = r.MilliCPU
+		}
+		if r.Memory > maxRequested.Memory {
+			maxRequested.Memory = r.Memory
+		}
+		if len(ni.Pods) > maxPods {
+			maxPods = len(ni.Pods)
+		}
+	}
+
+	if maxRequested.MilliCPU == 0 && maxRequested.Memory == 0 {
+		if maxPods == 0 {
+			return 0
+		}
+		return float64(len(nodeInfo.Pods)) / float64(maxPods)
+	}
+	return averageFraction(req, maxRequested)
+}
+
+// averageFraction returns average of requested / total on cpu and memory, capped at 1,
+// resources with zero total are ignored
+func averageFraction(requested, total framework.Resource) float64 {
+	fraction, count := 0.0, 0
+	if total.MilliCPU > 0 {
+		fraction += float64(requested.MilliCPU) / float64(total.MilliCPU)
+		count++
+	}
+	if total.Memory > 0 {
+		fraction += float64(requested.Memory) / float64(total.Memory)
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	fraction = fraction / float64(count)
+	if fraction > 1 {
+		return 1
+	}
+	return fraction
+}
@@ -0,0 +1,43 @@
+package plugins
+
+import (
+	"errors"
+	"fmt"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/scheduler/framework"
+)
+
+// NodeResourcesFit checks if the node has sufficient allocatable resources
+// for requests of the pod, nodes not reporting allocatable always fit.
+type NodeResourcesFit struct{}
+
+func NewNodeResourcesFit() framework.Plugin {
+	return &NodeResourcesFit{}
+}
+
+func (pl *NodeResourcesFit) Name() string {
+	return NodeResourcesFitName
+}
+
+func (pl *NodeResourcesFit) Filter(pod *core.Pod, nodeInfo *framework.NodeInfo, _ *framework.Snapshot) error {
+	allocatable, ok := nodeInfo.Allocatable()
+	if !ok {
+		return nil
+	}
+	requested := nodeInfo.Requested()
+	podRequest := framework.GetPodResourceRequest(pod)
+
+	if allocatable.AllowedPodNumber > 0 && requested.AllowedPodNumber+podRequest.AllowedPodNumber > allocatable.AllowedPodNumber {
+		return errors.New(fmt.Sprintf("too many pods on node %v, allocatable %v", nodeInfo.Node.Name, allocatable.AllowedPodNumber))
+	}
+	if podRequest.MilliCPU > 0 && requested.MilliCPU+podRequest.MilliCPU > allocatable.MilliCPU {
+		return errors.New(fmt.Sprintf("insufficient cpu on node %v, requested %vm + %vm > allocatable %vm", nodeInfo.Node.Name, requested.MilliCPU, podRequest.MilliCPU, allocatable.MilliCPU))
+	}
+	if podRequest.Memory > 0 && requested.Memory+podRequest.Memory > allocatable.Memory {
+		return errors.New(fmt.Sprintf("insufficient memory on node %v, requested %vM + %vM > allocatable %vM", nodeInfo.Node.Name, requested.Memory, podRequest.Memory, allocatable.Memory))
+	}
+	if podRequest.EphemeralStorage > 0 && requested.EphemeralStorage+podRequest.EphemeralStorage > allocatable.EphemeralStorage {
+		return errors.New(fmt.Sprintf("insufficient ephemeral-storage on node %v, requested %vM + %vM > allocatable %vM", nodeInfo.Node.Name, requested.EphemeralStorage, podRequest.EphemeralStorage, allocatable.EphemeralStorage))
+	}
+	return nil
+}
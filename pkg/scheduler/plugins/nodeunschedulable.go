@@ -0,0 +1,34 @@
+package plugins
+
+import (
+	"errors"
+	"fmt"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/scheduler/framework"
+)
+
+// NodeUnschedulable filters out nodes with spec.unschedulable set,
+// unless the pod tolerates the node.kubernetes.io/unschedulable taint.
+type NodeUnschedulable struct{}
+
+func NewNodeUnschedulable() framework.Plugin {
+	return &NodeUnschedulable{}
+}
+
+func (pl *NodeUnschedulable) Name() string {
+	return NodeUnschedulableName
+}
+
+func (pl *NodeUnschedulable) Filter(pod *core.Pod, nodeInfo *framework.NodeInfo, _ *framework.Snapshot) error {
+	if !nodeInfo.Node.Spec.Unschedulable {
+		return nil
+	}
+	taint := &core.Taint{
+		Key:    core.TaintNodeUnschedulable,
+		Effect: core.TaintEffectNoSchedule,
+	}
+	if pod.Spec.FindMatchingToleration(taint) != nil {
+		return nil
+	}
+	return errors.New(fmt.Sprintf("node %v is unschedulable", nodeInfo.Node.Name))
+}
@@ -0,0 +1,56 @@
+package plugins
+
+import (
+	"errors"
+	"fmt"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/meta"
+	"minik8s/pkg/scheduler/framework"
+)
+
+// PodAntiAffinity avoids putting pod on the node where pods matching
+// its anti-affinity label selectors are running.
+// As a FilterPlugin, nodes violating anti-affinity are filtered out;
+// as a ScorePlugin, such nodes are only less preferred, which means
+// anti-affinity is ignored when no node can satisfy it.
+type PodAntiAffinity struct{}
+
+func NewPodAntiAffinity() framework.Plugin {
+	return &PodAntiAffinity{}
+}
+
+func (pl *PodAntiAffinity) Name() string {
+	return PodAntiAffinityName
+}
+
+func (pl *PodAntiAffinity) Filter(pod *core.Pod, nodeInfo *framework.NodeInfo, _ *framework.Snapshot) error {
+	if conflict := pl.conflictPod(pod, nodeInfo); conflict != nil {
+		return errors.New(fmt.Sprintf("pod anti-affinity conflicts with pod %v on node %v", conflict.UID, nodeInfo.Node.Name))
+	}
+	return nil
+}
+
+func (pl *PodAntiAffinity) Score(pod *core.Pod, nodeInfo *framework.NodeInfo, _ *framework.Snapshot) int64 {
+	if pl.conflictPod(pod, nodeInfo) != nil {
+		return framework.MinNodeScore
+	}
+	return framework.MaxNodeScore
+}
+
+// conflictPod returns the first pod on node matching anti-affinity terms of pod, nil if none
+func (pl *PodAntiAffinity) conflictPod(pod *core.Pod, nodeInfo *framework.NodeInfo) *core.Pod {
+	if pod.Spec.Affinity == nil {
+		return nil
+	}
+	for _, existing := range nodeInfo.Pods {
+		if existing.UID == pod.UID {
+			continue
+		}
+		for _, term := range pod.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			if term.LabelSelector != nil && meta.MatchLabelSelector(*term.LabelSelector, existing.Labels) {
+				return existing
+			}
+		}
+	}
+	return nil
+}
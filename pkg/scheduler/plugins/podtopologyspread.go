@@ -0,0 +1,132 @@
+package plugins
+
+import (
+	"errors"
+	"fmt"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/meta"
+	"minik8s/pkg/scheduler/framework"
+)
+
+// PodTopologySpread spreads pods matching label selector of
+// pod.Spec.TopologySpreadConstraints evenly among topology domains,
+// which are nodes sharing the same value of node label topologyKey.
+// As a FilterPlugin, it enforces constraints with DoNotSchedule;
+// as a ScorePlugin, it prefers domains with fewer matching pods for
+// constraints with ScheduleAnyway.
+type PodTopologySpread struct{}
+
+func NewPodTopologySpread() framework.Plugin {
+	return &PodTopologySpread{}
+}
+
+func (pl *PodTopologySpread) Name() string {
+	return PodTopologySpreadName
+}
+
+func (pl *PodTopologySpread) Filter(pod *core.Pod, nodeInfo *framework.NodeInfo, snapshot *framework.Snapshot) error {
+	for _, c := range pod.Spec.TopologySpreadConstraints {
+		if c.WhenUnsatisfiable == core.ScheduleAnyway {
+			continue
+		}
+		domain, ok := nodeInfo.Node.Labels[c.TopologyKey]
+		if !ok {
+			return errors.New(fmt.Sprintf("node %v does not have label %v", nodeInfo.Node.Name, c.TopologyKey))
+		}
+
+		counts := countMatchingPodsPerDomain(pod, c, snapshot)
+		minMatch := -1
+		for _, cnt := range counts {
+			if minMatch == -1 || cnt < minMatch {
+				minMatch = cnt
+			}
+		}
+
+		selfMatch := 0
+		if c.LabelSelector != nil && meta.MatchLabelSelector(*c.LabelSelector, pod.Labels) {
+			selfMatch = 1
+		}
+
+		skew := counts[domain] + selfMatch - minMatch
+		if skew > int(getMaxSkew(c)) {
+			return errors.New(fmt.Sprintf("node %v violates topology spread constraint on %v, skew %v > maxSkew %v", nodeInfo.Node.Name, c.TopologyKey, skew, getMaxSkew(c)))
+		}
+	}
+	return nil
+}
+
+func (pl *PodTopologySpread) Score(pod *core.Pod, nodeInfo *framework.NodeInfo, snapshot *framework.Snapshot) int64 {
+	var total int64 = 0
+	num := 0
+	for _, c := range pod.Spec.TopologySpreadConstraints {
+		if c.WhenUnsatisfiable != core.ScheduleAnyway {
+			continue
+		}
+		num++
+		domain, ok := nodeInfo.Node.Labels[c.TopologyKey]
+		if !ok {
+			continue
+		}
+
+		counts := countMatchingPodsPerDomain(pod, c, snapshot)
+		minMatch, maxMatch := -1, 0
+		for _, cnt := range counts {
+			if minMatch == -1 || cnt < minMatch {
+				minMatch = cnt
+			}
+			if cnt > maxMatch {
+				maxMatch = cnt
+			}
+		}
+		if maxMatch == minMatch {
+			total += framework.MaxNodeScore
+		} else {
+			total += framework.MaxNodeScore * int64(maxMatch-counts[domain]) / int64(maxMatch-minMatch)
+		}
+	}
+	if num == 0 {
+		return framework.MaxNodeScore
+	}
+	return total / int64(num)
+}
+
+// countMatchingPodsPerDomain counts pods matching label selector of c in
+// the same namespace as pod, for every domain of c.TopologyKey in snapshot
+func countMatchingPodsPerDomain(pod *core.Pod, c core.TopologySpreadConstraint, snapshot *framework.Snapshot) map[string]int {
+	counts := make(map[string]int)
+	for _, ni := range snapshot.NodeInfos {
+		domain, ok := ni.Node.Labels[c.TopologyKey]
+		if !ok {
+			continue
+		}
+		if _, exist := counts[domain]; !exist {
+			counts[domain] = 0
+		}
+		if c.LabelSelector == nil {
+			continue
+		}
+		for _, p := range ni.Pods {
+			if p.UID == pod.UID || getNamespace(p) != getNamespace(pod) {
+				continue
+			}
+			if meta.MatchLabelSelector(*c.LabelSelector, p.Labels) {
+				counts[domain]++
+			}
+		}
+	}
+	return counts
+}
+
+func getMaxSkew(c core.TopologySpreadConstraint) int32 {
+	if c.MaxSkew <= 0 {
+		return 1
+	}
+	return c.MaxSkew
+}
+
+func getNamespace(pod *core.Pod) string {
+	if pod.Namespace == "" {
+		return "default"
+	}
+	return pod.Namespace
+}
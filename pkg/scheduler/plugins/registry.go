@@ -0,0 +1,31 @@
+package plugins
+
+import (
+	"minik8s/pkg/scheduler/framework"
+)
+
+// Names of in-tree plugins, used in scheduler profile config
+const (
+	PodAntiAffinityName             = "PodAntiAffinity"
+	PodTopologySpreadName           = "PodTopologySpread"
+	TaintTolerationName             = "TaintToleration"
+	NodeUnschedulableName           = "NodeUnschedulable"
+	NodeResourcesFitName            = "NodeResourcesFit"
+	NodeResourcesLeastAllocatedName = "NodeResourcesLeastAllocated"
+	NodeResourcesMostAllocatedName  = "NodeResourcesMostAllocated"
+	VolumeBindingName               = "VolumeBinding"
+)
+
+// NewInTreeRegistry builds the registry with all the in-tree plugins.
+func NewInTreeRegistry() framework.Registry {
+	return framework.Registry{
+		PodAntiAffinityName:             NewPodAntiAffinity,
+		PodTopologySpreadName:           NewPodTopologySpread,
+		TaintTolerationName:             NewTaintToleration,
+		NodeUnschedulableName:           NewNodeUnschedulable,
+		NodeResourcesFitName:            NewNodeResourcesFit,
+		NodeResourcesLeastAllocatedName: NewNodeResourcesLeastAllocated,
+		NodeResourcesMostAllocatedName:  NewNodeResourcesMostAllocated,
+		VolumeBindingName:               NewVolumeBinding,
+	}
+}
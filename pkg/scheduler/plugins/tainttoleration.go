@@ -0,0 +1,49 @@
+package plugins
+
+import (
+	"errors"
+	"fmt"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/scheduler/framework"
+)
+
+// TaintToleration checks taints of node against tolerations of pod.
+// As a FilterPlugin, nodes with NoSchedule or NoExecute taints not tolerated
+// by pod are filtered out; as a ScorePlugin, nodes with fewer PreferNoSchedule
+// taints not tolerated by pod are preferred.
+type TaintToleration struct{}
+
+func NewTaintToleration() framework.Plugin {
+	return &TaintToleration{}
+}
+
+func (pl *TaintToleration) Name() string {
+	return TaintTolerationName
+}
+
+func (pl *TaintToleration) Filter(pod *core.Pod, nodeInfo *framework.NodeInfo, _ *framework.Snapshot) error {
+	for i := range nodeInfo.Node.Spec.Taints {
+		taint := &nodeInfo.Node.Spec.Taints[i]
+		if taint.Effect != core.TaintEffectNoSchedule && taint.Effect != core.TaintEffectNoExecute {
+			continue
+		}
+		if pod.Spec.FindMatchingToleration(taint) == nil {
+			return errors.New(fmt.Sprintf("node %v has taint {%v: %v} that the pod didn't tolerate", nodeInfo.Node.Name, taint.Key, taint.Effect))
+		}
+	}
+	return nil
+}
+
+func (pl *TaintToleration) Score(pod *core.Pod, nodeInfo *framework.NodeInfo, _ *framework.Snapshot) int64 {
+	intolerable := 0
+	for i := range nodeInfo.Node.Spec.Taints {
+		taint := &nodeInfo.Node.Spec.Taints[i]
+		if taint.Effect != core.TaintEffectPreferNoSchedule {
+			continue
+		}
+		if pod.Spec.FindMatchingToleration(taint) == nil {
+			intolerable++
+		}
+	}
+	return framework.MaxNodeScore / int64(intolerable+1)
+}
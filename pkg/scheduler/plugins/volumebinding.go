@@ -0,0 +1,54 @@
+package plugins
+
+import (
+	"fmt"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/scheduler/framework"
+)
+
+// VolumeBinding filters out nodes from which persistent volumes used by the
+// pod can not be accessed. Pods with bound claims are restricted to nodes
+// satisfying node affinity of the volumes, claims of local-path class are
+// bound after the pod is scheduled, to a volume on the node selected.
+type VolumeBinding struct{}
+
+func NewVolumeBinding() framework.Plugin {
+	return &VolumeBinding{}
+}
+
+func (pl *VolumeBinding) Name() string {
+	return VolumeBindingName
+}
+
+func (pl *VolumeBinding) Filter(pod *core.Pod, nodeInfo *framework.NodeInfo, snapshot *framework.Snapshot) error {
+	node := nodeInfo.Node
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		claimName := volume.PersistentVolumeClaim.ClaimName
+		claim, ok := snapshot.Claims[claimName]
+		if !ok {
+			return fmt.Errorf("persistentvolumeclaim %v not found", claimName)
+		}
+
+		if claim.Spec.VolumeName == "" {
+			if !claim.IsBindingDelayed() {
+				return fmt.Errorf("persistentvolumeclaim %v is not bound", claimName)
+			}
+			if selected := claim.Annotations[core.AnnSelectedNode]; selected != "" && selected != node.Name {
+				return fmt.Errorf("persistentvolumeclaim %v is to be bound on node %v", claimName, selected)
+			}
+			continue
+		}
+
+		pv, ok := snapshot.Volumes[claim.Spec.VolumeName]
+		if !ok {
+			return fmt.Errorf("persistentvolume %v of claim %v not found", claim.Spec.VolumeName, claimName)
+		}
+		if !pv.MatchNode(node) {
+			return fmt.Errorf("node %v does not satisfy node affinity of persistentvolume %v", node.Name, pv.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"minik8s/config"
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/logger"
+	"minik8s/pkg/scheduler/framework"
+	"minik8s/pkg/scheduler/plugins"
+	"minik8s/utils"
+)
+
+// SchedulerConfiguration configures profiles of the scheduler
+type SchedulerConfiguration struct {
+	// Profiles are scheduling profiles, each of them is selected by
+	// pods through pod.Spec.SchedulerName
+	Profiles []ProfileConfig `json:"profiles"`
+}
+
+// ProfileConfig is a scheduling profile.
+type ProfileConfig struct {
+	// SchedulerName is the name of the scheduler associated to this profile.
+	SchedulerName string `json:"schedulerName"`
+	// Plugins specifies the set of plugins that should be enabled.
+	Plugins PluginsConfig `json:"plugins"`
+}
+
+// PluginsConfig includes plugins enabled at each extension point.
+type PluginsConfig struct {
+	// Filter is a list of plugins that should be invoked when filtering out nodes that cannot run the Pod.
+	Filter []PluginConfig `json:"filter,omitempty"`
+	// Score is a list of plugins that should be invoked when ranking nodes that have passed the filtering phase.
+	Score []PluginConfig `json:"score,omitempty"`
+}
+
+// PluginConfig specifies a plugin name and its weight when applicable. Weight is used only for Score plugins.
+type PluginConfig struct {
+	Name   string `json:"name"`
+	Weight int64  `json:"weight,omitempty"`
+}
+
+// Profile is a scheduling profile built from ProfileConfig
+type Profile struct {
+	SchedulerName string
+	FilterPlugins []framework.FilterPlugin
+	ScorePlugins  []weightedScorePlugin
+}
+
+type weightedScorePlugin struct {
+	framework.ScorePlugin
+	weight int64
+}
+
+// DefaultSchedulerConfiguration is used when no config file is given,
+// it keeps the best effort pod anti-affinity of default scheduler
+// and enforces node schedulability, taints, resource fit, topology
+// spread constraints and node affinity of persistent volumes
+func DefaultSchedulerConfiguration() *SchedulerConfiguration {
+	return &SchedulerConfiguration{
+		Profiles: []ProfileConfig{
+			{
+				SchedulerName: core.DefaultSchedulerName,
+				Plugins: PluginsConfig{
+					Filter: []PluginConfig{
+						{Name: plugins.NodeUnschedulableName},
+						{Name: plugins.TaintTolerationName},
+						{Name: plugins.NodeResourcesFitName},
+						{Name: plugins.PodTopologySpreadName},
+						{Name: plugins.VolumeBindingName},
+					},
+					Score: []PluginConfig{
+						{Name: plugins.PodAntiAffinityName, Weight: 1},
+						{Name: plugins.PodTopologySpreadName, Weight: 1},
+						{Name: plugins.TaintTolerationName, Weight: 1},
+					},
+				},
+			},
+		},
+	}
+}
+
+// LoadSchedulerConfiguration reads SchedulerConfiguration from config.SchedulerConfig,
+// DefaultSchedulerConfiguration is returned if it is not set
+func LoadSchedulerConfiguration() (*SchedulerConfiguration, error) {
+	path := config.SchedulerConfig()
+	if path == "" {
+		return DefaultSchedulerConfiguration(), nil
+	}
+
+	file, err := utils.GetFormJsonData(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &SchedulerConfiguration{}
+	err = json.Unmarshal(file, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// NewProfiles builds profiles from cfg with plugins in registry, keyed by scheduler name
+func NewProfiles(cfg *SchedulerConfiguration, registry framework.Registry) (map[string]*Profile, error) {
+	profiles := make(map[string]*Profile, len(cfg.Profiles))
+	for _, pc := range cfg.Profiles {
+		name := pc.SchedulerName
+		if name == "" {
+			name = core.DefaultSchedulerName
+		}
+		if _, exist := profiles[name]; exist {
+			return nil, errors.New(fmt.Sprintf("duplicate profile with scheduler name %v", name))
+		}
+
+		profile := &Profile{SchedulerName: name}
+		for _, plc := range pc.Plugins.Filter {
+			factory, ok := registry[plc.Name]
+			if !ok {
+				return nil, errors.New(fmt.Sprintf("profile %v: plugin %v not found", name, plc.Name))
+			}
+			fp, ok := factory().(framework.FilterPlugin)
+			if !ok {
+				return nil, errors.New(fmt.Sprintf("profile %v: plugin %v does not extend filter", name, plc.Name))
+			}
+			profile.FilterPlugins = append(profile.FilterPlugins, fp)
+		}
+		for _, plc := range pc.Plugins.Score {
+			factory, ok := registry[plc.Name]
+			if !ok {
+				return nil, errors.New(fmt.Sprintf("profile %v: plugin %v not found", name, plc.Name))
+			}
+			sp, ok := factory().(framework.ScorePlugin)
+			if !ok {
+				return nil, errors.New(fmt.Sprintf("profile %v: plugin %v does not extend score", name, plc.Name))
+			}
+			weight := plc.Weight
+			if weight == 0 {
+				weight = 1
+			}
+			profile.ScorePlugins = append(profile.ScorePlugins, weightedScorePlugin{ScorePlugin: sp, weight: weight})
+		}
+
+		logger.SchedulerLogger.Printf("[NewProfiles] profile %v loaded, %v filter plugins, %v score plugins\n", name, len(profile.FilterPlugins), len(profile.ScorePlugins))
+		profiles[name] = profile
+	}
+	return profiles, nil
+}
+
+// SelectNode runs filter plugins and score plugins of profile on nodes in snapshot,
+// returns the node with highest weighted score, the first one in snapshot order
+// wins if more than one nodes have the same score. nil is returned if no node fits.
+func (p *Profile) SelectNode(pod *core.Pod, snapshot *framework.Snapshot) *core.Node {
+	var selected *core.Node = nil
+	var maxScore int64 = -1
+
+	for _, ni := range snapshot.NodeInfos {
+		fits := true
+		for _, fp := range p.FilterPlugins {
+			if err := fp.Filter(pod, ni, snapshot); err != nil {
+				logger.SchedulerLogger.Printf("[SelectNode] node %v filtered by %v: %v\n", ni.Node.Name, fp.Name(), err)
+				fits = false
+				break
+			}
+		}
+		if !fits {
+			continue
+		}
+
+		var score int64 = 0
+		for _, sp := range p.ScorePlugins {
+			score += sp.Score(pod, ni, snapshot) * sp.weight
+		}
+		if score > maxScore {
+			maxScore = score
+			selected = ni.Node
+		}
+	}
+	return selected
+}
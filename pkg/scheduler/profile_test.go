@@ -0,0 +1,292 @@
+package scheduler
+
+import (
+	"minik8s/pkg/api/core"
+	"minik8s/pkg/api/meta"
+	"minik8s/pkg/api/types"
+	"minik8s/pkg/scheduler/framework"
+	"minik8s/pkg/scheduler/plugins"
+	"testing"
+)
+
+func newTestNode(name string) *core.Node {
+	n := &core.Node{}
+	n.Name = name
+	return n
+}
+
+func newTestPod(uid, nodeName string, labels map[string]string, cpu types.Quantity) *core.Pod {
+	p := &core.Pod{}
+	p.UID = types.UID(uid)
+	p.Labels = labels
+	p.Spec.NodeName = nodeName
+	p.Spec.Containers = []core.Container{{
+		Resources: core.ResourceRequirements{
+			Requests: core.ResourceList{types.ResourceCPU: cpu},
+		},
+	}}
+	return p
+}
+
+func newTestProfile(t *testing.T, filter, score []string) *Profile {
+	pc := ProfileConfig{SchedulerName: "test"}
+	for _, name := range filter {
+		pc.Plugins.Filter = append(pc.Plugins.Filter, PluginConfig{Name: name})
+	}
+	for _, name := range score {
+		pc.Plugins.Score = append(pc.Plugins.Score, PluginConfig{Name: name})
+	}
+	profiles, err := NewProfiles(&SchedulerConfiguration{Profiles: []ProfileConfig{pc}}, plugins.NewInTreeRegistry())
+	if err != nil {
+		t.Fatalf("NewProfiles() error = %v", err)
+	}
+	return profiles["test"]
+}
+
+func TestProfileSelectNode(t *testing.T) {
+	nodes := []*core.Node{newTestNode("node1"), newTestNode("node2"), newTestNode("node3")}
+	pods := []*core.Pod{
+		newTestPod("p1", "node1", map[string]string{"app": "large"}, "500m"),
+		newTestPod("p2", "node1", nil, "500m"),
+		newTestPod("p3", "node2", nil, "200m"),
+		newTestPod("p4", "node3", map[string]string{"app": "large"}, "300m"),
+	}
+
+	antiLarge := newTestPod("new", "", nil, "100m")
+	antiLarge.Spec.Affinity = &core.Affinity{
+		PodAntiAffinity: core.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []core.PodAffinityTerm{
+				{LabelSelector: &meta.LabelSelector{MatchLabels: map[string]string{"app": "large"}}},
+			},
+		},
+	}
+	antiAll := newTestPod("new", "", nil, "100m")
+	antiAll.Spec.Affinity = &core.Affinity{
+		PodAntiAffinity: core.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []core.PodAffinityTerm{
+				{LabelSelector: &meta.LabelSelector{}},
+			},
+		},
+	}
+	plain := newTestPod("new", "", nil, "100m")
+
+	tests := []struct {
+		name   string
+		filter []string
+		score  []string
+		pod    *core.Pod
+		want   string
+	}{
+		{name: "round robin", pod: plain, want: "node1"},
+		{name: "least allocated", score: []string{plugins.NodeResourcesLeastAllocatedName}, pod: plain, want: "node2"},
+		{name: "most allocated", score: []string{plugins.NodeResourcesMostAllocatedName}, pod: plain, want: "node1"},
+		{name: "anti-affinity score", score: []string{plugins.PodAntiAffinityName}, pod: antiLarge, want: "node2"},
+		{name: "anti-affinity score unsatisfiable", score: []string{plugins.PodAntiAffinityName}, pod: antiAll, want: "node1"},
+		{name: "anti-affinity filter", filter: []string{plugins.PodAntiAffinityName}, score: []string{plugins.NodeResourcesMostAllocatedName}, pod: antiLarge, want: "node2"},
+		{name: "anti-affinity filter unsatisfiable", filter: []string{plugins.PodAntiAffinityName}, pod: antiAll, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := newTestProfile(t, tt.filter, tt.score)
+			got := profile.SelectNode(tt.pod, framework.NewSnapshot(nodes, pods))
+			gotName := ""
+			if got != nil {
+				gotName = got.Name
+			}
+			if gotName != tt.want {
+				t.Errorf("SelectNode() = %v, want %v", gotName, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfileSelectNodeTopologySpread(t *testing.T) {
+	zone := "topology.kubernetes.io/zone"
+	nodes := []*core.Node{newTestNode("node1"), newTestNode("node2"), newTestNode("node3"), newTestNode("node4")}
+	nodes[0].Labels = map[string]string{zone: "a"}
+	nodes[1].Labels = map[string]string{zone: "a"}
+	nodes[2].Labels = map[string]string{zone: "b"}
+	app := map[string]string{"app": "myapp"}
+	pods := []*core.Pod{
+		newTestPod("p1", "node1", app, "100m"),
+		newTestPod("p2", "node2", app, "100m"),
+		newTestPod("p3", "node3", app, "100m"),
+	}
+
+	newSpreadPod := func(maxSkew int32, action core.UnsatisfiableConstraintAction) *core.Pod {
+		p := newTestPod("new", "", app, "100m")
+		p.Spec.TopologySpreadConstraints = []core.TopologySpreadConstraint{{
+			MaxSkew:           maxSkew,
+			TopologyKey:       zone,
+			WhenUnsatisfiable: action,
+			LabelSelector:     &meta.LabelSelector{MatchLabels: app},
+		}}
+		return p
+	}
+
+	tests := []struct {
+		name   string
+		filter []string
+		score  []string
+		pod    *core.Pod
+		want   string
+	}{
+		{name: "do not schedule", filter: []string{plugins.PodTopologySpreadName}, pod: newSpreadPod(1, core.DoNotSchedule), want: "node3"},
+		{name: "do not schedule max skew 2", filter: []string{plugins.PodTopologySpreadName}, pod: newSpreadPod(2, ""), want: "node1"},
+		{name: "schedule anyway", score: []string{plugins.PodTopologySpreadName}, pod: newSpreadPod(1, core.ScheduleAnyway), want: "node3"},
+		{name: "no constraint", filter: []string{plugins.PodTopologySpreadName}, pod: newTestPod("new", "", app, "100m"), want: "node1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := newTestProfile(t, tt.filter, tt.score)
+			got := profile.SelectNode(tt.pod, framework.NewSnapshot(nodes, pods))
+			gotName := ""
+			if got != nil {
+				gotName = got.Name
+			}
+			if gotName != tt.want {
+				t.Errorf("SelectNode() = %v, want %v", gotName, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfileSelectNodeResourcesFit(t *testing.T) {
+	nodes := []*core.Node{newTestNode("node1"), newTestNode("node2"), newTestNode("node3")}
+	nodes[0].Status.Allocatable = core.ResourceList{types.ResourceCPU: "1000m", types.ResourcePods: "1"}
+	nodes[1].Status.Allocatable = core.ResourceList{types.ResourceCPU: "2000m", types.ResourcePods: "110"}
+	pods := []*core.Pod{
+		newTestPod("p1", "node1", nil, "800m"),
+		newTestPod("p2", "node2", nil, "1000m"),
+		newTestPod("p3", "node3", nil, "100m"),
+	}
+
+	tests := []struct {
+		name   string
+		filter []string
+		score  []string
+		pod    *core.Pod
+		want   string
+	}{
+		{name: "insufficient cpu", filter: []string{plugins.NodeResourcesFitName}, pod: newTestPod("new", "", nil, "500m"), want: "node2"},
+		{name: "too many pods", filter: []string{plugins.NodeResourcesFitName}, pod: newTestPod("new", "", nil, "0"), want: "node2"},
+		{name: "node without allocatable fits", filter: []string{plugins.NodeResourcesFitName}, pod: newTestPod("new", "", nil, "1500m"), want: "node3"},
+		{name: "most allocated by allocatable", score: []string{plugins.NodeResourcesMostAllocatedName}, pod: newTestPod("new", "", nil, "100m"), want: "node1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := newTestProfile(t, tt.filter, tt.score)
+			got := profile.SelectNode(tt.pod, framework.NewSnapshot(nodes, pods))
+			gotName := ""
+			if got != nil {
+				gotName = got.Name
+			}
+			if gotName != tt.want {
+				t.Errorf("SelectNode() = %v, want %v", gotName, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfileSelectNodeTerminatedPods(t *testing.T) {
+	nodes := []*core.Node{newTestNode("node1"), newTestNode("node2")}
+	nodes[0].Status.Allocatable = core.ResourceList{types.ResourceCPU: "1000m", types.ResourcePods: "2"}
+	nodes[1].Status.Allocatable = core.ResourceList{types.ResourceCPU: "1000m", types.ResourcePods: "2"}
+	succeeded := newTestPod("p1", "node1", nil, "1000m")
+	succeeded.Status.Phase = core.PodSucceeded
+	evicted := newTestPod("p2", "node1", nil, "1000m")
+	evicted.Status.Phase = core.PodFailed
+	evicted.Status.Reason = core.PodReasonEvicted
+	running := newTestPod("p3", "node2", nil, "1000m")
+	running.Status.Phase = core.PodRunning
+
+	// node1 is full only if terminated pods are counted
+	profile := newTestProfile(t, []string{plugins.NodeResourcesFitName}, nil)
+	got := profile.SelectNode(newTestPod("new", "", nil, "500m"), framework.NewSnapshot(nodes, []*core.Pod{succeeded, evicted, running}))
+	if got == nil || got.Name != "node1" {
+		t.Errorf("SelectNode() = %v, want node1", got)
+	}
+}
+
+func TestGetPodResourceRequestInitContainers(t *testing.T) {
+	p := newTestPod("p1", "", nil, "200m")
+	p.Spec.Containers = append(p.Spec.Containers, core.Container{
+		Resources: core.ResourceRequirements{Requests: core.ResourceList{types.ResourceCPU: "300m", types.ResourceMemory: "100M"}},
+	})
+	p.Spec.InitContainers = []core.Container{
+		{Resources: core.ResourceRequirements{Requests: core.ResourceList{types.ResourceCPU: "800m"}}},
+		{Resources: core.ResourceRequirements{Limits: core.ResourceList{types.ResourceCPU: "100m", types.ResourceMemory: "50M"}}},
+	}
+
+	// cpu of the largest init container, memory of app containers
+	got := framework.GetPodResourceRequest(p)
+	want := framework.Resource{MilliCPU: 800, Memory: 100, AllowedPodNumber: 1}
+	if got != want {
+		t.Errorf("GetPodResourceRequest() = %+v, want %+v", got, want)
+	}
+}
+
+func TestProfileSelectNodeVolumeBinding(t *testing.T) {
+	nodes := []*core.Node{newTestNode("node1"), newTestNode("node2"), newTestNode("node3")}
+
+	newClaim := func(name, volumeName, class, selectedNode string) *core.PersistentVolumeClaim {
+		c := &core.PersistentVolumeClaim{}
+		c.Name = name
+		c.Spec.VolumeName = volumeName
+		c.Spec.StorageClassName = class
+		if selectedNode != "" {
+			c.Annotations = map[string]string{core.AnnSelectedNode: selectedNode}
+		}
+		return c
+	}
+	localVolume := &core.PersistentVolume{}
+	localVolume.Name = "local"
+	localVolume.Spec.NodeAffinity = core.NewNodeNameAffinity("node2")
+	sharedVolume := &core.PersistentVolume{}
+	sharedVolume.Name = "shared"
+	claims := []*core.PersistentVolumeClaim{
+		newClaim("bound-local", "local", "", ""),
+		newClaim("bound-shared", "shared", "", ""),
+		newClaim("unbound", "", "", ""),
+		newClaim("delayed", "", core.LocalPathStorageClassName, ""),
+		newClaim("delayed-selected", "", core.LocalPathStorageClassName, "node3"),
+	}
+	volumes := []*core.PersistentVolume{localVolume, sharedVolume}
+
+	newPodWithClaim := func(claimName string) *core.Pod {
+		p := newTestPod("new", "", nil, "100m")
+		p.Spec.Volumes = []core.Volume{{
+			Name:         "data",
+			VolumeSource: core.VolumeSource{PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{ClaimName: claimName}},
+		}}
+		return p
+	}
+
+	tests := []struct {
+		name string
+		pod  *core.Pod
+		want string
+	}{
+		{name: "bound local volume", pod: newPodWithClaim("bound-local"), want: "node2"},
+		{name: "bound volume without affinity", pod: newPodWithClaim("bound-shared"), want: "node1"},
+		{name: "unbound claim", pod: newPodWithClaim("unbound"), want: ""},
+		{name: "missing claim", pod: newPodWithClaim("missing"), want: ""},
+		{name: "delayed binding", pod: newPodWithClaim("delayed"), want: "node1"},
+		{name: "delayed binding with node selected", pod: newPodWithClaim("delayed-selected"), want: "node3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := newTestProfile(t, []string{plugins.VolumeBindingName}, nil)
+			snapshot := framework.NewSnapshot(nodes, nil)
+			snapshot.SetPersistentVolumes(claims, volumes)
+			got := profile.SelectNode(tt.pod, snapshot)
+			gotName := ""
+			if got != nil {
+				gotName = got.Name
+			}
+			if gotName != tt.want {
+				t.Errorf("SelectNode() = %v, want %v", gotName, tt.want)
+			}
+		})
+	}
+}
@@ -2,16 +2,20 @@ package scheduler
 
 import (
 	"errors"
+	"fmt"
 	"golang.org/x/net/context"
+	"minik8s/config"
 	"minik8s/pkg/api/core"
-	"minik8s/pkg/api/meta"
 	"minik8s/pkg/api/types"
 	"minik8s/pkg/api/watch"
 	"minik8s/pkg/apiclient"
 	client "minik8s/pkg/apiclient/interface"
 	"minik8s/pkg/apiclient/listwatch"
+	"minik8s/pkg/apiclient/record"
 	"minik8s/pkg/logger"
 	"minik8s/pkg/node"
+	"minik8s/pkg/scheduler/framework"
+	"minik8s/pkg/scheduler/plugins"
 	"minik8s/utils/datastructure"
 	"net/http"
 	"time"
@@ -24,6 +28,8 @@ type Scheduler struct {
 	// Client
 	podClient  client.Interface
 	nodeClient client.Interface
+	pvcClient  client.Interface
+	pvClient   client.Interface
 
 	// ListWatcher
 	podListWatcher  listwatch.ListerWatcher
@@ -37,6 +43,13 @@ type Scheduler struct {
 
 	// nodesQueue holds nodes to be scheduled to for RR
 	nodesQueue datastructure.IConcurrentQueue
+
+	// profiles are scheduling profiles keyed by scheduler name,
+	// pods are scheduled by the profile named pod.Spec.SchedulerName
+	profiles map[string]*Profile
+
+	// recorder records events of pods scheduled or failed to be scheduled
+	recorder record.EventRecorder
 }
 
 func NewScheduler() *Scheduler {
@@ -45,14 +58,31 @@ func NewScheduler() *Scheduler {
 	podListWatcher := listwatch.NewListWatchFromClient(podClient)
 	nodeClient, _ := apiclient.NewRESTClient(types.NodeObjectType)
 	nodeListWatcher := listwatch.NewListWatchFromClient(nodeClient)
+	pvcClient, _ := apiclient.NewRESTClient(types.PersistentVolumeClaimObjectType)
+	pvClient, _ := apiclient.NewRESTClient(types.PersistentVolumeObjectType)
+
+	cfg, err := LoadSchedulerConfiguration()
+	if err != nil {
+		logger.SchedulerLogger.Printf("[NewScheduler] load scheduler config %v failed, err: %v\n", config.SchedulerConfig(), err)
+		panic(err)
+	}
+	profiles, err := NewProfiles(cfg, plugins.NewInTreeRegistry())
+	if err != nil {
+		logger.SchedulerLogger.Printf("[NewScheduler] build profiles failed, err: %v\n", err)
+		panic(err)
+	}
 
 	return &Scheduler{
 		podClient:       podClient,
 		podListWatcher:  podListWatcher,
 		nodeClient:      nodeClient,
 		nodeListWatcher: nodeListWatcher,
+		pvcClient:       pvcClient,
+		pvClient:        pvClient,
 		schedulingQueue: datastructure.NewConcurrentQueue(),
 		nodesQueue:      datastructure.NewConcurrentQueue(),
+		profiles:        profiles,
+		recorder:        record.NewRecorder(core.DefaultSchedulerName, ""),
 	}
 }
 
@@ -118,12 +148,23 @@ func (s *Scheduler) processNextPodToSchedule() bool {
 	nodeBind := s.doSchedule(pod)
 	if nodeBind == nil {
 		logger.SchedulerLogger.Printf("[processNextPodToSchedule] pod %v bind failed\n", pod.UID)
+		s.recorder.Event(pod, core.EventTypeWarning, "FailedScheduling", "no node fits pod")
+		s.setPodUnschedulable(pod)
+		s.enqueuePod(pod)
+		return false
+	}
+
+	// bind claims waiting for the first consumer on node selected
+	if err := s.selectNodeForClaims(pod, nodeBind.Name); err != nil {
+		logger.SchedulerLogger.Printf("[processNextPodToSchedule] select node for claims of pod %v failed, err: %v\n", pod.UID, err)
+		s.recorder.Eventf(pod, core.EventTypeWarning, "FailedScheduling", "select node for claims failed: %v", err)
 		s.enqueuePod(pod)
 		return false
 	}
 
 	// modify pod.Spec.NodeName
 	pod.Spec.NodeName = nodeBind.Name
+	pod.Status.SetCondition(core.PodCondition{Type: core.PodScheduled, Status: core.ConditionTrue})
 
 	// send binding result to apiserver
 	code, _, err := s.podClient.Put(pod.UID, pod)
@@ -132,16 +173,45 @@ func (s *Scheduler) processNextPodToSchedule() bool {
 			podItem, _ := s.podClient.Get(pod.UID)
 			pod = podItem.(*core.Pod)
 			pod.Spec.NodeName = nodeBind.Name
+			pod.Status.SetCondition(core.PodCondition{Type: core.PodScheduled, Status: core.ConditionTrue})
 			code, _, err = s.podClient.Put(pod.UID, pod)
 		}
-		return code == http.StatusOK
+		if code != http.StatusOK {
+			return false
+		}
 	}
 
 	logger.SchedulerLogger.Printf("[processNextPodToSchedule] schedule pod uid %v to node %v\n", pod.UID, nodeBind.Name)
+	s.recorder.Eventf(pod, core.EventTypeNormal, "Scheduled", "Successfully assigned %v/%v to %v", pod.Namespace, pod.Name, nodeBind.Name)
 
 	return true
 }
 
+// setPodUnschedulable reports that no node fits pod by PodScheduled condition,
+// which is only put once until pod is scheduled
+func (s *Scheduler) setPodUnschedulable(pod *core.Pod) {
+	if c := pod.Status.GetCondition(core.PodScheduled); c != nil && c.Status == core.ConditionFalse {
+		return
+	}
+	podItem, err := s.podClient.Get(pod.UID)
+	if err != nil {
+		logger.SchedulerLogger.Printf("[setPodUnschedulable] get pod %v failed, err: %v\n", pod.UID, err)
+		return
+	}
+	latest := podItem.(*core.Pod)
+	latest.Status.SetCondition(core.PodCondition{
+		Type:    core.PodScheduled,
+		Status:  core.ConditionFalse,
+		Reason:  core.PodReasonUnschedulable,
+		Message: "no node fits pod",
+	})
+	if _, _, err = s.podClient.Put(latest.UID, latest); err != nil {
+		logger.SchedulerLogger.Printf("[setPodUnschedulable] put pod %v failed, err: %v\n", pod.UID, err)
+		return
+	}
+	pod.Status.Conditions = latest.Status.Conditions
+}
+
 var (
 	errorStopRequested = errors.New("stop requested")
 )
@@ -192,7 +262,9 @@ func (s *Scheduler) listAndWatchPods(stopCh <-chan struct{}) error {
 	podItems := podsList.GetIApiObjectArr()
 	for _, item := range podItems {
 		pod := item.(*core.Pod)
-		s.enqueuePod(pod)
+		if s.responsibleForPod(pod) {
+			s.enqueuePod(pod)
+		}
 	}
 
 	// start watch pods change
@@ -213,6 +285,20 @@ func (s *Scheduler) listAndWatchPods(stopCh <-chan struct{}) error {
 
 }
 
+// responsibleForPod returns true if pod selects a profile of this scheduler,
+// pods with unknown scheduler name are left to other schedulers, and mirror
+// pods are bound to nodes of their static pods
+func (s *Scheduler) responsibleForPod(pod *core.Pod) bool {
+	if pod.IsMirrorPod() {
+		return false
+	}
+	_, ok := s.profiles[pod.Spec.GetSchedulerName()]
+	if !ok {
+		logger.SchedulerLogger.Printf("[responsibleForPod] pod %v ignored, no profile for scheduler %v\n", pod.UID, pod.Spec.GetSchedulerName())
+	}
+	return ok
+}
+
 func (s *Scheduler) enqueuePod(pod *core.Pod) {
 	s.schedulingQueue.Enqueue(pod)
 	logger.SchedulerLogger.Printf("[enqueuePod] pod %v enqueued\n", pod.UID)
@@ -248,8 +334,10 @@ loop:
 			switch event.Type {
 			case watch.Added:
 				newPod := (event.Object).(*core.Pod)
-				s.enqueuePod(newPod)
-				logger.SchedulerLogger.Printf("[handleWatchPods] new Pod event, handle pod %v created\n", newPod.UID)
+				if s.responsibleForPod(newPod) {
+					s.enqueuePod(newPod)
+					logger.SchedulerLogger.Printf("[handleWatchPods] new Pod event, handle pod %v created\n", newPod.UID)
+				}
 			case watch.Modified:
 				// ignore
 			case watch.Deleted:
@@ -290,9 +378,11 @@ loop:
 			case watch.Modified:
 				newNode := (event.Object).(*core.Node)
 				if newNode.Status.Phase == core.NodeRunning {
-					nodeUID := newNode.GetUID()
-					s.deleteNodeInQueue(nodeUID)
-					s.nodesQueue.Enqueue(newNode)
+					// keep position of node in rr queue, since status
+					// of node is updated by kubelet periodically
+					if !s.updateNodeInQueue(newNode) {
+						s.nodesQueue.Enqueue(newNode)
+					}
 				}
 			case watch.Deleted:
 				oldNode := (event.Object).(*core.Node)
@@ -323,6 +413,18 @@ func (s *Scheduler) deleteNodeInQueue(uid types.UID) bool {
 	return false
 }
 
+func (s *Scheduler) updateNodeInQueue(newNode *core.Node) bool {
+	allNodes := s.nodesQueue.GetContent()
+	for i, n := range allNodes {
+		no := n.(*core.Node)
+		if newNode.UID == no.UID {
+			allNodes[i] = newNode
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Scheduler) getNodeInQueue(name string) *core.Node {
 	allNodes := s.nodesQueue.GetContent()
 	for _, n := range allNodes {
@@ -347,14 +449,7 @@ func (s *Scheduler) doSchedule(newPod *core.Pod) *core.Node {
 	if newPod.Spec.NodeName != "" {
 		return s.doScheduleNodeAffinity(newPod)
 	} else {
-		var nodeScheduled *core.Node = nil
-		if newPod.Spec.Affinity != nil {
-			nodeScheduled = s.doSchedulePodAntiAffinity(newPod)
-		}
-		if nodeScheduled == nil {
-			nodeScheduled = s.doScheduleRR()
-		}
-		return nodeScheduled
+		return s.doScheduleWithProfile(newPod)
 	}
 }
 
@@ -367,22 +462,6 @@ func (s *Scheduler) moveNodeToEnd(nodeName string) {
 	}
 }
 
-func (s *Scheduler) doScheduleRR() *core.Node {
-	if s.nodesQueue.Empty() {
-		logger.SchedulerLogger.Printf("[Scheduler][doSchedule] no nodes registered in queue\n")
-		return nil
-	}
-	item, _ := s.nodesQueue.Dequeue()
-	n := item.(*core.Node)
-	for n.Name == node.NameMaster {
-		s.nodesQueue.Enqueue(item)
-		item, _ = s.nodesQueue.Dequeue()
-		n = item.(*core.Node)
-	}
-	s.nodesQueue.Enqueue(item)
-	return n
-}
-
 // NodeName is a request to schedule this pod onto a specific node. If it is non-empty,
 // the scheduler simply schedules this pod onto that node, assuming that it fits resource
 // requirements.
@@ -390,63 +469,133 @@ func (s *Scheduler) doScheduleNodeAffinity(newPod *core.Pod) *core.Node {
 	return s.getNodeInQueue(newPod.Spec.NodeName)
 }
 
-func (s *Scheduler) doSchedulePodAntiAffinity(newPod *core.Pod) *core.Node {
+// doScheduleWithProfile runs plugins of profile selected by newPod on all nodes except master,
+// nodes are checked in RR order so that nodes with the same score are selected in turn
+func (s *Scheduler) doScheduleWithProfile(newPod *core.Pod) *core.Node {
 
-	logger.SchedulerLogger.Printf("[Scheduler][doSchedulePodAntiAffinity] start scheduling pod %v, uid %v\n", newPod.Name, newPod.UID)
+	profile, ok := s.profiles[newPod.Spec.GetSchedulerName()]
+	if !ok {
+		logger.SchedulerLogger.Printf("[Scheduler][doScheduleWithProfile] no profile for scheduler %v\n", newPod.Spec.GetSchedulerName())
+		return nil
+	}
 
-	newPodAnti := newPod.Spec.Affinity.PodAntiAffinity
-	var nodeNameNotToSchedule []string
+	logger.SchedulerLogger.Printf("[Scheduler][doScheduleWithProfile] start scheduling pod %v, uid %v with profile %v\n", newPod.Name, newPod.UID, profile.SchedulerName)
 
-	// Get all pods scheduled already
-	podList, _ := s.podListWatcher.List()
+	var nodes []*core.Node
+	for _, n := range s.nodesQueue.GetContent() {
+		no := n.(*core.Node)
+		if no.Name != node.NameMaster {
+			nodes = append(nodes, no)
+		}
+	}
+	if len(nodes) == 0 {
+		logger.SchedulerLogger.Printf("[Scheduler][doScheduleWithProfile] no nodes registered in queue\n")
+		return nil
+	}
+
+	var pods []*core.Pod
+	podList, err := s.podListWatcher.List()
+	if err != nil {
+		logger.SchedulerLogger.Printf("[Scheduler][doScheduleWithProfile] list pods failed, err: %v\n", err)
+		return nil
+	}
 	for _, podItem := range podList.GetIApiObjectArr() {
-		pod := podItem.(*core.Pod)
-		if pod.UID == newPod.UID {
+		pods = append(pods, podItem.(*core.Pod))
+	}
+
+	snapshot := framework.NewSnapshot(nodes, pods)
+	if err := s.addPersistentVolumes(snapshot); err != nil {
+		logger.SchedulerLogger.Printf("[Scheduler][doScheduleWithProfile] list persistent volumes failed, err: %v\n", err)
+		return nil
+	}
+	logNodeResources(snapshot)
+
+	nodeScheduled := profile.SelectNode(newPod, snapshot)
+	if nodeScheduled == nil {
+		logger.SchedulerLogger.Printf("[Scheduler][doScheduleWithProfile] no node fits pod %v\n", newPod.UID)
+		return nil
+	}
+
+	// put node scheduled to last of rr queue
+	s.moveNodeToEnd(nodeScheduled.Name)
+	return nodeScheduled
+}
+
+// addPersistentVolumes adds all claims and volumes to snapshot
+func (s *Scheduler) addPersistentVolumes(snapshot *framework.Snapshot) error {
+	claimList, err := s.pvcClient.GetAll()
+	if err != nil {
+		return err
+	}
+	var claims []*core.PersistentVolumeClaim
+	for _, item := range claimList.GetIApiObjectArr() {
+		claims = append(claims, item.(*core.PersistentVolumeClaim))
+	}
+
+	volumeList, err := s.pvClient.GetAll()
+	if err != nil {
+		return err
+	}
+	var volumes []*core.PersistentVolume
+	for _, item := range volumeList.GetIApiObjectArr() {
+		volumes = append(volumes, item.(*core.PersistentVolume))
+	}
+
+	snapshot.SetPersistentVolumes(claims, volumes)
+	return nil
+}
+
+// selectNodeForClaims annotates unbound claims of pod whose binding waits for
+// the first consumer with nodeName, local-path volumes are provisioned for them
+// on the node by PersistentVolumeController
+func (s *Scheduler) selectNodeForClaims(pod *core.Pod, nodeName string) error {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
 			continue
 		}
-		// check affinity
-		for _, podAffinityTerm := range newPodAnti.RequiredDuringSchedulingIgnoredDuringExecution {
-			if podAffinityTerm.LabelSelector != nil {
-				logger.SchedulerLogger.Printf("[Scheduler][doSchedulePodAntiAffinity] new pod label selector: %v, pod label %v\n", (*podAffinityTerm.LabelSelector).MatchLabels, pod.Labels)
-				isMatched := meta.MatchLabelSelector(*podAffinityTerm.LabelSelector, pod.Labels)
-				if isMatched {
-					logger.SchedulerLogger.Printf("[Scheduler][doSchedulePodAntiAffinity] new pod can not schedule to node %v due to pod %v, uid %v\n", pod.Spec.NodeName, pod.Name, pod.UID)
-					nodeNameNotToSchedule = append(nodeNameNotToSchedule, pod.Spec.NodeName)
-				}
+		name := volume.PersistentVolumeClaim.ClaimName
+		for {
+			item, err := s.pvcClient.Get(name)
+			if err != nil {
+				return err
+			}
+			claim := item.(*core.PersistentVolumeClaim)
+			if claim.Name == "" {
+				return errors.New(fmt.Sprintf("persistentvolumeclaim %v not found", name))
+			}
+			if claim.Spec.VolumeName != "" || !claim.IsBindingDelayed() || claim.Annotations[core.AnnSelectedNode] != "" {
+				break
 			}
-		}
-	}
 
-	if len(nodeNameNotToSchedule) == 0 {
-		logger.SchedulerLogger.Printf("[Scheduler][doSchedulePodAntiAffinity] no node can not be schedule, use rr\n")
-		return nil
-	} else {
-		allNodes := s.nodesQueue.GetContent()
-		for _, n := range allNodes {
-			no := n.(*core.Node)
-			notSchedule := false
-			logger.SchedulerLogger.Printf("[Scheduler][doSchedulePodAntiAffinity] checking node %v\n", no.Name)
-			for _, nodeNotSchedule := range nodeNameNotToSchedule {
-				if nodeNotSchedule == no.Name {
-					logger.SchedulerLogger.Printf("[Scheduler][doSchedulePodAntiAffinity] pod anti-affinity: no schedule to node %v\n", no.Name)
-					notSchedule = true
-					break
-				}
+			if claim.Annotations == nil {
+				claim.Annotations = make(map[string]string)
 			}
-			if !notSchedule {
-				// check if is master
-				if no.Name == node.NameMaster {
-					logger.SchedulerLogger.Printf("[Scheduler][doSchedulePodAntiAffinity] current node master\n", no.Name)
-					continue
-				}
-				// do schedule
-				logger.SchedulerLogger.Printf("[Scheduler][doSchedulePodAntiAffinity] pod anti-affinity schedule success to node %v\n", no.Name)
-				// put node scheduled to last of rr queue
-				s.moveNodeToEnd(no.Name)
-				return no
+			claim.Annotations[core.AnnSelectedNode] = nodeName
+			code, _, err := s.pvcClient.Put(name, claim)
+			if err == nil {
+				logger.SchedulerLogger.Printf("[selectNodeForClaims] claim %v of pod %v to be bound on node %v\n", name, pod.UID, nodeName)
+				break
+			}
+			if code != http.StatusConflict {
+				return err
 			}
 		}
 	}
-	logger.SchedulerLogger.Printf("[Scheduler][doSchedulePodAntiAffinity] no nodes left to satisfy pod anti-affinity in queue, use rr instead\n")
 	return nil
 }
+
+// logNodeResources shows requested versus allocatable resources of each node in snapshot
+func logNodeResources(snapshot *framework.Snapshot) {
+	for _, ni := range snapshot.NodeInfos {
+		requested := ni.Requested()
+		allocatable, ok := ni.Allocatable()
+		if !ok {
+			logger.SchedulerLogger.Printf("[Scheduler][logNodeResources] node %v: requested cpu %vm, memory %vM, pods %v, allocatable unknown\n",
+				ni.Node.Name, requested.MilliCPU, requested.Memory, requested.AllowedPodNumber)
+			continue
+		}
+		logger.SchedulerLogger.Printf("[Scheduler][logNodeResources] node %v: cpu %vm/%vm, memory %vM/%vM, ephemeral-storage %vM/%vM, pods %v/%v (requested/allocatable)\n",
+			ni.Node.Name, requested.MilliCPU, allocatable.MilliCPU, requested.Memory, allocatable.Memory,
+			requested.EphemeralStorage, allocatable.EphemeralStorage, requested.AllowedPodNumber, allocatable.AllowedPodNumber)
+	}
+}